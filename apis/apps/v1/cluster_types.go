@@ -197,6 +197,15 @@ type ClusterSpec struct {
 	//
 	// +optional
 	Backup *ClusterBackup `json:"backup,omitempty"`
+
+	// Specifies the maintenance window of the Cluster.
+	//
+	// Disruptive OpsRequests, including "Restart", "Upgrade", "VerticalScaling" and "Switchover",
+	// wait in the "Scheduled" phase until the window opens,
+	// unless they specify their own `maintenanceWindow` or set `force` to true.
+	//
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // ClusterStatus defines the observed state of the Cluster.
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -941,6 +942,8 @@ type ComponentVolume struct {
 	// This precaution helps prevent space depletion while maintaining read-only access.
 	// If the space utilization later falls below this threshold, the system reverts the volume to read-write mode
 	// as defined in `componentDefinition.spec.lifecycleActions.readWrite`, restoring full functionality.
+	// The volume is protected only if the readonly action is defined and the volume is mounted by the containers,
+	// its usage is reported by the kb-agent and checked periodically.
 	//
 	// Note: This field cannot be updated.
 	//
@@ -1384,6 +1387,31 @@ type ComponentLifecycleActions struct {
 	// +optional
 	Switchover *Action `json:"switchover,omitempty"`
 
+	// Defines the procedure to report the replication lag of a replica behind the current leader.
+	//
+	// Use Case:
+	// This action is invoked on each candidate replica to select the most up-to-date one as the new leader,
+	// when a switchover is performed without a designated candidate, either requested by an OpsRequest or
+	// initiated by the controller before the leader is scaled in.
+	// The action is always executed on the replica being checked, the `targetPodSelector` is ignored.
+	//
+	// The container executing this action has access to following environment variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod whose lag is being checked.
+	// - KB_LEADER_POD_NAME: The name of the current leader's pod, which may not be specified (empty).
+	// - KB_LEADER_POD_FQDN: The FQDN of the current leader's pod, which may not be specified (empty).
+	//
+	// Expected action output:
+	// - On Success: A non-negative integer, the lag of the replica behind the leader in an engine-specific unit,
+	//   such as the bytes of the log or the seconds. For the engines exposing only the replication position (e.g., the LSN),
+	//   the action can report the distance to the position of the leader.
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +optional
+	ReplicationLag *Action `json:"replicationLag,omitempty"`
+
 	// Defines the procedure to add a new replica to the replication group.
 	//
 	// This action is initiated after a replica pod becomes ready.
@@ -1454,6 +1482,8 @@ type ComponentLifecycleActions struct {
 	//
 	// Use Case:
 	// This action is invoked when the database's volume capacity nears its upper limit and space is about to be exhausted.
+	// It is also invoked on the current leader before a switchover, and on the writable replicas before the Component
+	// is stopped.
 	//
 	// The container executing this action has access to following environment variables:
 	//
@@ -1473,6 +1503,8 @@ type ComponentLifecycleActions struct {
 	// This action is used to bring back a replica that was previously in a read-only state,
 	// which restricted write operations, to its normal operational state where it can handle
 	// both read and write operations.
+	// It is invoked on the leader if a switchover fails, and on the writable replicas after the Component is started
+	// and all the replicas are ready.
 	//
 	// The container executing this action has access to following environment variables:
 	//
@@ -1525,9 +1557,21 @@ type ComponentLifecycleActions struct {
 
 	// Defines the procedure that update a replica with new configuration.
 	//
-	// Note: This field is immutable once it has been set.
+	// Use Case:
+	// This action is invoked on each replica to apply the dynamic parameters that have been updated, and
+	// it takes precedence over the reload action of the config-manager sidecar.
+	// The result is surfaced in the `Reconfigured` condition of the Component status.
+	//
+	// The container executing this action has access to following environment variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to apply the new configuration.
+	// - KB_CONFIG_SPEC_NAME: The name of the config spec that has been updated.
+	// - KB_CONFIG_PARAMETERS: The updated parameters, encoded as a JSON object of name-value pairs.
+	//
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
 	//
-	// This Action is reserved for future versions.
+	// Note: This field is immutable once it has been set.
 	//
 	// +optional
 	Reconfigure *Action `json:"reconfigure,omitempty"`
@@ -1580,13 +1624,16 @@ type ComponentLifecycleActions struct {
 //     to access context information such as details about pods, components, the overall cluster state,
 //     or database connection credentials.
 //     These variables provide a dynamic and context-aware mechanism for script execution.
-//   - HTTPAction: Performs an HTTP request.
-//     HTTPAction is to be implemented in future version.
-//   - GRPCAction: In future version, Actions will support initiating gRPC calls.
+//   - HTTPAction: Performs an HTTP request against an endpoint exposed by the replica.
+//     The path, headers and body can reference the same context information as templates,
+//     allowing engines that expose admin HTTP endpoints to implement Actions without shell scripts.
+//   - GRPCAction: Initiates a unary gRPC call against a server exposed by the replica.
 //     This allows developers to implement Actions using plugins written in programming language like Go,
 //     providing greater flexibility and extensibility.
 //
-// An action is considered successful on returning 0, or HTTP 200 for status HTTP(s) Actions.
+// Only one of the ways can be specified for an Action.
+//
+// An action is considered successful on returning 0, HTTP 2xx for HTTP(s) Actions, or status OK for gRPC Actions.
 // Any other return value or HTTP status codes indicate failure,
 // and the action may be retried based on the configured retry policy.
 //
@@ -1603,6 +1650,20 @@ type Action struct {
 	// +optional
 	Exec *ExecAction `json:"exec,omitempty"`
 
+	// Defines the HTTP request to perform.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	HTTP *HTTPAction `json:"http,omitempty"`
+
+	// Defines the gRPC call to perform.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	GRPC *GRPCAction `json:"grpc,omitempty"`
+
 	// Specifies the maximum duration in seconds that the Action is allowed to run.
 	//
 	// If the Action does not complete within this time frame, it will be terminated.
@@ -1720,6 +1781,154 @@ type ExecAction struct {
 	Container string `json:"container,omitempty"`
 }
 
+// HTTPAction describes an Action that performs an HTTP request.
+//
+// The request is issued by the kbagent within the target replica,
+// so the host defaults to the loopback address of the Pod.
+//
+// The `path`, the values of `headers` and the `body` are rendered as Go templates before the request is sent.
+// The parameters of the Action (e.g. `KB_SWITCHOVER_CANDIDATE_NAME`) and the environment variables of the kbagent
+// are available in the templates, for example: `{{ .KB_SWITCHOVER_CANDIDATE_NAME }}`.
+type HTTPAction struct {
+	// Specifies the target port for the HTTP request.
+	// It can be specified either as a numeric value in the range of 1 to 65535,
+	// or as a named port of the containers defined in `componentDefinition.spec.runtime`.
+	//
+	// This field cannot be updated.
+	Port intstr.IntOrString `json:"port"`
+
+	// Indicates the server's domain name or IP address. Defaults to the loopback address of the Pod.
+	// Prefer setting the "Host" header in headers when needed.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Designates the protocol used to make the request, such as HTTP or HTTPS.
+	// If not specified, HTTP is used by default.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+
+	// Specifies whether to skip verifying the certificate presented by the server when HTTPS is used,
+	// which is the same as the HTTPS probes of kubelet. Defaults to false.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// Represents the type of HTTP request to be made, such as "GET," "POST," "PUT," etc.
+	// If not specified, "GET" is the default method.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Specifies the endpoint to be requested on the HTTP server.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Allows for the inclusion of custom headers in the request.
+	// HTTP permits the use of repeated headers.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Headers []corev1.HTTPHeader `json:"headers,omitempty"`
+
+	// Specifies the template of the HTTP request body.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Defines the criteria used to select the target Pod(s) for executing the Action.
+	// It has the same semantics as `exec.targetPodSelector`.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	TargetPodSelector TargetPodSelector `json:"targetPodSelector,omitempty"`
+
+	// Used in conjunction with the `targetPodSelector` field to refine the selection of target pod(s) for Action execution.
+	// It has the same semantics as `exec.matchingKey`.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	MatchingKey string `json:"matchingKey,omitempty"`
+}
+
+// GRPCAction describes an Action that performs an unary gRPC call.
+//
+// The call is issued by the kbagent within the target replica, so the host defaults to the loopback address of the Pod.
+// The gRPC server must have the server reflection service enabled, which is used to resolve the method descriptor.
+//
+// The `request` is rendered as a Go template in the same way as the body of HTTPAction,
+// and then decoded as the JSON representation of the request message.
+// The JSON representation of the response message is taken as the output of the Action.
+type GRPCAction struct {
+	// Specifies the target port of the gRPC server.
+	// It can be specified either as a numeric value in the range of 1 to 65535,
+	// or as a named port of the containers defined in `componentDefinition.spec.runtime`.
+	//
+	// This field cannot be updated.
+	Port intstr.IntOrString `json:"port"`
+
+	// Indicates the server's domain name or IP address. Defaults to the loopback address of the Pod.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Specifies the fully-qualified name of the gRPC service, e.g. "mysql.admin.v1.Admin".
+	//
+	// This field cannot be updated.
+	//
+	// +kubebuilder:validation:Required
+	Service string `json:"service"`
+
+	// Specifies the name of the method to call.
+	//
+	// This field cannot be updated.
+	//
+	// +kubebuilder:validation:Required
+	Method string `json:"method"`
+
+	// Specifies the template of the request message, in JSON format.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// Defines the criteria used to select the target Pod(s) for executing the Action.
+	// It has the same semantics as `exec.targetPodSelector`.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	TargetPodSelector TargetPodSelector `json:"targetPodSelector,omitempty"`
+
+	// Used in conjunction with the `targetPodSelector` field to refine the selection of target pod(s) for Action execution.
+	// It has the same semantics as `exec.matchingKey`.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	MatchingKey string `json:"matchingKey,omitempty"`
+}
+
 // TargetPodSelector defines how to select pod(s) to execute an Action.
 // +enum
 // +kubebuilder:validation:Enum={Any,All,Role,Ordinal}
@@ -181,6 +181,18 @@ type ShardingLifecycleActions struct {
 
 	// Specifies the hook to be executed after a shard's creation.
 	//
+	// The ShardProvision Action is executed on the new shard when the sharding is scaled out,
+	// it can be used to add the shard into the sharding, e.g., to rebalance the data.
+	// The precondition of the action is `ComponentReady` by default, and it will be retried until it succeeds.
+	// It is executed on the leader of the shard, or on the replica with the smallest ordinal if there is no leader role,
+	// unless the `targetPodSelector` is specified.
+	//
+	// The container executing this action has access to following variables:
+	//
+	// - KB_SHARDING_NAME: The name of the sharding.
+	// - KB_SHARD_NAME: The name of the shard that has been provisioned.
+	// - KB_SHARD_LIST: Comma-separated list of all shards of the sharding, including the new one.
+	//
 	// Note: This field is immutable once it has been set.
 	//
 	// +optional
@@ -188,6 +200,17 @@ type ShardingLifecycleActions struct {
 
 	// Specifies the hook to be executed prior to terminating a shard.
 	//
+	// The ShardTerminate Action is executed on the shard to be removed when the sharding is scaled in,
+	// it can be used to migrate the data out of the shard.
+	// The shard will not be deleted until the action has completed successfully, it will be retried if failed.
+	// It is executed on the same replica of the shard as the ShardProvision action.
+	//
+	// The container executing this action has access to following variables:
+	//
+	// - KB_SHARDING_NAME: The name of the sharding.
+	// - KB_SHARD_NAME: The name of the shard to be terminated.
+	// - KB_SHARD_LIST: Comma-separated list of the shards that remain in the sharding.
+	//
 	// Note: This field is immutable once it has been set.
 	//
 	// +optional
@@ -18,6 +18,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -81,6 +82,7 @@ const (
 	ConditionTypeProvisioningStarted = "ProvisioningStarted" // ConditionTypeProvisioningStarted the operator starts resource provisioning to create or change the cluster
 	ConditionTypeApplyResources      = "ApplyResources"      // ConditionTypeApplyResources the operator start to apply resources to create or change the cluster
 	ConditionTypeReady               = "Ready"               // ConditionTypeReady all components and shardings are running
+	ConditionTypeReconfigured        = "Reconfigured"        // ConditionTypeReconfigured the result of applying the dynamic parameters by the reconfigure action
 )
 
 type ServiceRef struct {
@@ -696,3 +698,38 @@ type InstanceTemplate struct {
 	// +optional
 	VolumeClaimTemplates []ClusterComponentVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
 }
+
+// MaintenanceWindow defines a recurring time window in which disruptive operations are allowed to start.
+type MaintenanceWindow struct {
+	// Specifies the days of the week on which the window opens.
+	// The window opens every day if it is empty.
+	//
+	// +listType=set
+	// +optional
+	DaysOfWeek []Weekday `json:"daysOfWeek,omitempty"`
+
+	// Specifies the time of the day at which the window opens, in the 24-hour "HH:MM" format.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// Specifies how long the window stays open after `startTime`, e.g. "2h" or "90m".
+	// Operations only start within the window, but are not interrupted when the window closes.
+	//
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// Specifies the time zone of `startTime`, supports only zone offset, with a value range of "-12:59 ~ +13:00".
+	// Defaults to UTC.
+	//
+	// +kubebuilder:validation:Pattern:=`^(\+|\-)(0[0-9]|1[0-3]):([0-5][0-9])$`
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Weekday defines a day of the week.
+//
+// +enum
+// +kubebuilder:validation:Enum={Sunday,Monday,Tuesday,Wednesday,Thursday,Friday,Saturday}
+type Weekday string
@@ -209,6 +209,16 @@ type ReloadAction struct {
 	// +optional
 	AutoTrigger *AutoTrigger `json:"autoTrigger,omitempty"`
 
+	// Sends the updated parameters to an HTTP endpoint exposed by the process to reload it.
+	//
+	// +optional
+	HTTPTrigger *HTTPTrigger `json:"httpTrigger,omitempty"`
+
+	// Executes SQL statements against the database to apply the updated parameters.
+	//
+	// +optional
+	SQLTrigger *SQLTrigger `json:"sqlTrigger,omitempty"`
+
 	// Used to match labels on the pod to determine whether a dynamic reload should be performed.
 	//
 	// In some scenarios, only specific pods (e.g., primary replicas) need to undergo a dynamic reload.
@@ -494,6 +504,93 @@ type AutoTrigger struct {
 	ProcessName string `json:"processName,omitempty"`
 }
 
+// HTTPTrigger sends a request to an HTTP endpoint of the process for each updated parameter.
+//
+// The request is sent by the "config-manager" sidecar to the loopback address of the pod,
+// and the reload is always executed synchronously, reporting the result of each parameter to the controller.
+type HTTPTrigger struct {
+	// Specifies the scheme used to connect to the endpoint, either HTTP or HTTPS.
+	// Defaults to HTTP.
+	//
+	// +kubebuilder:validation:Enum={HTTP,HTTPS}
+	// +kubebuilder:default="HTTP"
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+
+	// Specifies the port of the endpoint.
+	//
+	// +kubebuilder:validation:Required
+	Port int32 `json:"port"`
+
+	// Specifies the path of the endpoint.
+	//
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+
+	// Specifies the HTTP method of the request.
+	// Defaults to POST.
+	//
+	// +kubebuilder:validation:Enum={GET,POST,PUT,PATCH}
+	// +kubebuilder:default="POST"
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Specifies a Go template string used to render the body of the request.
+	// The template is rendered once for each updated parameter, accessing its name and value via `.name` and `.value`.
+	//
+	// If not specified, the body is a JSON object holding the parameter, e.g. `{"max_connections": "1000"}`.
+	//
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+
+	// Specifies the name of the system account of the component.
+	// If set, the credentials of the account are sent with HTTP basic authentication.
+	//
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+}
+
+// SQLTrigger executes a SQL statement against the database for each updated parameter.
+//
+// The statements are executed by the "config-manager" sidecar through the loopback address of the pod,
+// and the reload is always executed synchronously, reporting the result of each parameter to the controller.
+type SQLTrigger struct {
+	// Specifies the type of the database, either `mysql` or `postgresql`.
+	//
+	// +kubebuilder:validation:Enum={mysql,postgresql}
+	// +kubebuilder:validation:Required
+	Engine string `json:"engine"`
+
+	// Specifies the port of the database.
+	//
+	// +kubebuilder:validation:Required
+	Port int32 `json:"port"`
+
+	// Specifies the database to connect to.
+	//
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Specifies a Go template string used to render the statement.
+	// The template is rendered once for each updated parameter, accessing its name and value via `.name` and `.value`.
+	// The name must be a valid SQL identifier, and the value is rendered as a SQL literal: numeric values are kept as is,
+	// and the others are quoted and escaped according to the engine, so the template should not quote the value.
+	//
+	// Example template:
+	//
+	// ```yaml
+	// statementTemplate: "SET GLOBAL {{ .name }} = {{ .value }}"
+	// ```
+	//
+	// +kubebuilder:validation:Required
+	StatementTemplate string `json:"statementTemplate"`
+
+	// Specifies the name of the system account of the component used to connect to the database.
+	//
+	// +kubebuilder:validation:Required
+	AccountName string `json:"accountName"`
+}
+
 // FileFormatConfig specifies the format of the configuration file and any associated parameters
 // that are specific to the chosen format.
 type FileFormatConfig struct {
@@ -515,6 +612,11 @@ type FileFormatConfig struct {
 	// - properties: a file extension mainly used in Java, reference wiki: https://en.wikipedia.org/wiki/.properties
 	// - toml: refers to wiki: https://en.wikipedia.org/wiki/TOML
 	// - props-plus: a file extension mainly used in Java, supports CamelCase(e.g: brokerMaxConnectionsPerIp)
+	// - pg-conf: the PostgreSQL configuration file, e.g. postgresql.conf, supports quoted values and `include` directives
+	// - my-cnf: the MySQL option file, e.g. my.cnf, supports `!include` directives, repeated options and bare flags
+	// - nginx: the block-structured configuration file of nginx, e.g. nginx.conf
+	//
+	// The formats pg-conf, my-cnf and nginx keep the comments and the order of the original file when parameters are updated.
 	//
 	// +kubebuilder:validation:Required
 	Format CfgFileFormat `json:"format"`
@@ -523,7 +625,7 @@ type FileFormatConfig struct {
 // FormatterAction configures format-specific options for different configuration file format.
 // Note: Only one of its members should be specified at any given time.
 type FormatterAction struct {
-	// Holds options specific to the 'ini' file format.
+	// Holds options specific to the 'ini' and 'my-cnf' file formats.
 	//
 	// +optional
 	IniConfig *IniConfig `json:"iniConfig,omitempty"`
@@ -40,7 +40,7 @@ const (
 
 // CfgFileFormat defines formatter of configuration files.
 // +enum
-// +kubebuilder:validation:Enum={xml,ini,yaml,json,hcl,dotenv,toml,properties,redis,props-plus}
+// +kubebuilder:validation:Enum={xml,ini,yaml,json,hcl,dotenv,toml,properties,redis,props-plus,pg-conf,my-cnf,nginx}
 type CfgFileFormat string
 
 const (
@@ -54,6 +54,9 @@ const (
 	Properties     CfgFileFormat = "properties"
 	RedisCfg       CfgFileFormat = "redis"
 	PropertiesPlus CfgFileFormat = "props-plus"
+	PGConf         CfgFileFormat = "pg-conf"
+	MyCnf          CfgFileFormat = "my-cnf"
+	Nginx          CfgFileFormat = "nginx"
 )
 
 // DynamicReloadType defines reload method.
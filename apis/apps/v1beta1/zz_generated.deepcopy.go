@@ -248,6 +248,21 @@ func (in *FormatterAction) DeepCopy() *FormatterAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTrigger) DeepCopyInto(out *HTTPTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTrigger.
+func (in *HTTPTrigger) DeepCopy() *HTTPTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IniConfig) DeepCopyInto(out *IniConfig) {
 	*out = *in
@@ -305,6 +320,16 @@ func (in *ReloadAction) DeepCopyInto(out *ReloadAction) {
 		*out = new(AutoTrigger)
 		**out = **in
 	}
+	if in.HTTPTrigger != nil {
+		in, out := &in.HTTPTrigger, &out.HTTPTrigger
+		*out = new(HTTPTrigger)
+		**out = **in
+	}
+	if in.SQLTrigger != nil {
+		in, out := &in.SQLTrigger, &out.SQLTrigger
+		*out = new(SQLTrigger)
+		**out = **in
+	}
 	if in.TargetPodSelector != nil {
 		in, out := &in.TargetPodSelector, &out.TargetPodSelector
 		*out = new(v1.LabelSelector)
@@ -322,6 +347,21 @@ func (in *ReloadAction) DeepCopy() *ReloadAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLTrigger) DeepCopyInto(out *SQLTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLTrigger.
+func (in *SQLTrigger) DeepCopy() *SQLTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScriptConfig) DeepCopyInto(out *ScriptConfig) {
 	*out = *in
@@ -141,6 +141,13 @@ type BackupStatus struct {
 	// +optional
 	KopiaRepoPath string `json:"kopiaRepoPath,omitempty"`
 
+	// Records the path of the manifest of the backup in the backup repository.
+	// The manifest describes the backup so that the Backup object can be recreated
+	// from the backup repository, refer to BackupRepoCatalogSync for more details.
+	//
+	// +optional
+	ManifestPath string `json:"manifestPath,omitempty"`
+
 	// Records the name of the persistent volume claim used to store the backup data.
 	//
 	// +optional
@@ -187,8 +194,197 @@ type BackupStatus struct {
 	//
 	// +optional
 	Extras []map[string]string `json:"extras,omitempty"`
+
+	// Records the result of the latest verification of this backup.
+	// Refer to BackupVerificationPolicy for more details.
+	//
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
+	// Records the copies of the backup data in the secondary backup repositories.
+	// Refer to BackupReplicationPolicy for more details.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=backupRepoName
+	Replicas []BackupReplicaStatus `json:"replicas,omitempty"`
+
+	// Records the latest decision of the tiered retention policy on this backup.
+	// Refer to BackupRetentionPolicy for more details.
+	//
+	// +optional
+	Retention *BackupRetentionStatus `json:"retention,omitempty"`
+}
+
+// BackupRetentionStatus records the decision of the tiered retention policy on a backup.
+type BackupRetentionStatus struct {
+	// Describes whether the backup is retained or expired.
+	//
+	// +optional
+	Decision BackupRetentionDecision `json:"decision,omitempty"`
+
+	// Records the retention tiers that keep the backup.
+	//
+	// +optional
+	Tiers []BackupRetentionTier `json:"tiers,omitempty"`
+
+	// Records the names of the retained backups that depend on this backup,
+	// such as the incremental backups based on it, or the continuous backups
+	// that need it as the base backup.
+	//
+	// +optional
+	RequiredBy []string `json:"requiredBy,omitempty"`
+
+	// Provides a human-readable message about the decision.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Records the time the decision was last changed.
+	//
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// BackupRetentionDecision describes the decision of the tiered retention policy on a backup.
+// +enum
+// +kubebuilder:validation:Enum={Retained,Expired}
+type BackupRetentionDecision string
+
+const (
+	BackupRetained BackupRetentionDecision = "Retained"
+	BackupExpired  BackupRetentionDecision = "Expired"
+)
+
+// BackupRetentionTier describes a tier of the tiered retention policy.
+// +enum
+// +kubebuilder:validation:Enum={Latest,Hourly,Daily,Weekly,Monthly,Yearly}
+type BackupRetentionTier string
+
+const (
+	RetentionTierLatest  BackupRetentionTier = "Latest"
+	RetentionTierHourly  BackupRetentionTier = "Hourly"
+	RetentionTierDaily   BackupRetentionTier = "Daily"
+	RetentionTierWeekly  BackupRetentionTier = "Weekly"
+	RetentionTierMonthly BackupRetentionTier = "Monthly"
+	RetentionTierYearly  BackupRetentionTier = "Yearly"
+)
+
+// BackupReplicaStatus records a copy of the backup data in a secondary backup repository.
+type BackupReplicaStatus struct {
+	// The name of the backup repository where the copy is stored.
+	//
+	// +kubebuilder:validation:Required
+	BackupRepoName string `json:"backupRepoName"`
+
+	// Describes the phase of the replication.
+	//
+	// +optional
+	Phase BackupReplicaPhase `json:"phase,omitempty"`
+
+	// The directory within the backup repository where the copy is stored.
+	// This is an absolute path within the backup repository.
+	//
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Records the path of the Kopia repository where the copy is stored.
+	//
+	// +optional
+	KopiaRepoPath string `json:"kopiaRepoPath,omitempty"`
+
+	// Records the time the replication was started.
+	//
+	// +optional
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time the replication was completed.
+	//
+	// +optional
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Any error that caused the replication to fail.
+	//
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// BackupReplicaPhase describes the phase of a backup replica.
+// +enum
+// +kubebuilder:validation:Enum={Pending,Running,Completed,Failed}
+type BackupReplicaPhase string
+
+const (
+	BackupReplicaPending   BackupReplicaPhase = "Pending"
+	BackupReplicaRunning   BackupReplicaPhase = "Running"
+	BackupReplicaCompleted BackupReplicaPhase = "Completed"
+	BackupReplicaFailed    BackupReplicaPhase = "Failed"
+)
+
+// BackupVerificationStatus records the verification of a backup by a test restore.
+type BackupVerificationStatus struct {
+	// Describes the phase of the verification.
+	//
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// Describes the stage that the verification is running or stopped at.
+	//
+	// +optional
+	Stage BackupVerificationStage `json:"stage,omitempty"`
+
+	// Specifies the name of the BackupSchedule which triggered the verification.
+	//
+	// +optional
+	BackupScheduleName string `json:"backupScheduleName,omitempty"`
+
+	// Records the time the verification was started.
+	//
+	// +optional
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time the verification was completed.
+	//
+	// +optional
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Provides a human-readable message about the result of the verification.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
+// BackupVerificationPhase describes the phase of a backup verification.
+// +enum
+// +kubebuilder:validation:Enum={Pending,Running,Passed,Failed}
+type BackupVerificationPhase string
+
+const (
+	BackupVerificationPending BackupVerificationPhase = "Pending"
+	BackupVerificationRunning BackupVerificationPhase = "Running"
+	BackupVerificationPassed  BackupVerificationPhase = "Passed"
+	BackupVerificationFailed  BackupVerificationPhase = "Failed"
+)
+
+// BackupVerificationStage describes the stage of a backup verification.
+// +enum
+// +kubebuilder:validation:Enum={PrepareData,StartInstance,PostReady,Validate}
+type BackupVerificationStage string
+
+const (
+	// BackupVerificationStagePrepareData restores the backup into the throwaway volumes.
+	BackupVerificationStagePrepareData BackupVerificationStage = "PrepareData"
+
+	// BackupVerificationStageStartInstance starts the scratch instance and waits for it to be ready.
+	BackupVerificationStageStartInstance BackupVerificationStage = "StartInstance"
+
+	// BackupVerificationStagePostReady performs the postReady actions against the scratch instance.
+	BackupVerificationStagePostReady BackupVerificationStage = "PostReady"
+
+	// BackupVerificationStageValidate runs the validation job against the scratch instance.
+	BackupVerificationStageValidate BackupVerificationStage = "Validate"
+)
+
 // BackupTimeRange records the time range of backed up data, for PITR, this is the
 // time range of recoverable data.
 type BackupTimeRange struct {
@@ -427,3 +623,14 @@ func (r *Backup) GetTimeZone() string {
 	}
 	return ""
 }
+
+// GetReplica gets the replica of the backup in the specified backup repository.
+// Returns nil if the backup is not replicated to the backup repository.
+func (r *Backup) GetReplica(backupRepoName string) *BackupReplicaStatus {
+	for i := range r.Status.Replicas {
+		if r.Status.Replicas[i].BackupRepoName == backupRepoName {
+			return &r.Status.Replicas[i]
+		}
+	}
+	return nil
+}
@@ -79,6 +79,113 @@ type BackupPolicySpec struct {
 	//
 	// +optional
 	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
+
+	// Specifies the policy to replicate the completed backups to other backup repositories
+	// for disaster recovery.
+	// Replication will be disabled if the field is not set.
+	//
+	// +optional
+	Replication *BackupReplicationPolicy `json:"replication,omitempty"`
+
+	// Specifies the tiered retention policy for the scheduled backups of this policy.
+	// It applies to the backup methods whose schedule policy does not specify its own
+	// retention policy.
+	//
+	// +optional
+	Retention *BackupRetentionPolicy `json:"retention,omitempty"`
+}
+
+// BackupReplicationPolicy defines how the completed backups are copied from the primary
+// backup repository to the secondary ones.
+type BackupReplicationPolicy struct {
+	// Specifies the names of the secondary BackupRepos that the backups are copied to.
+	// The BackupRepo where the backup is stored is ignored.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	BackupRepoNames []string `json:"backupRepoNames"`
+
+	// Specifies the backup methods whose backups are replicated.
+	// If not set, the backups of all backup methods are replicated, except those
+	// that only take volume snapshots.
+	//
+	// +optional
+	// +listType=set
+	BackupMethods []string `json:"backupMethods,omitempty"`
+}
+
+// BackupRetentionPolicy defines a tiered (grandfather-father-son) retention policy for
+// the scheduled backups of a backup method.
+//
+// The completed backups are grouped by the hour, day, ISO week, month and year of their
+// completion, and the latest backup of each group is kept by the corresponding tier,
+// limited by the count and the maximum age of the tier. A backup is kept if any tier
+// keeps it, and expired otherwise.
+//
+// A backup that is expired by the tiers is still kept as long as a retained incremental
+// backup is based on it, or a retained continuous backup needs it as the base backup
+// for point-in-time recovery.
+//
+// The expiration of a retained backup is cleared, as the backup is governed by the policy.
+// If the policy is removed, the backup expires after its retention period from then on.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.keepLatest) || has(self.hourly) || has(self.daily) || has(self.weekly) || has(self.monthly) || has(self.yearly)",message="at least one of keepLatest, hourly, daily, weekly, monthly and yearly must be specified"
+type BackupRetentionPolicy struct {
+	// Specifies the number of the latest backups to keep, regardless of the tiers.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLatest *int32 `json:"keepLatest,omitempty"`
+
+	// Specifies how the latest backup of each hour is kept.
+	//
+	// +optional
+	Hourly *RetentionTier `json:"hourly,omitempty"`
+
+	// Specifies how the latest backup of each day is kept.
+	//
+	// +optional
+	Daily *RetentionTier `json:"daily,omitempty"`
+
+	// Specifies how the latest backup of each ISO week is kept.
+	//
+	// +optional
+	Weekly *RetentionTier `json:"weekly,omitempty"`
+
+	// Specifies how the latest backup of each month is kept.
+	//
+	// +optional
+	Monthly *RetentionTier `json:"monthly,omitempty"`
+
+	// Specifies how the latest backup of each year is kept.
+	//
+	// +optional
+	Yearly *RetentionTier `json:"yearly,omitempty"`
+
+	// Specifies the time zone, in the IANA time zone database format, used to determine
+	// the hour, day, week, month and year of a backup. Defaults to UTC.
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// RetentionTier defines how many backups are kept by a retention tier.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.count) || has(self.maxAge)",message="at least one of count and maxAge must be specified"
+type RetentionTier struct {
+	// Specifies the number of the latest periods whose latest backup is kept.
+	// Periods without any backup are not counted.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// Specifies the maximum age of the backups kept by the tier, in the same format
+	// as the RetentionPeriod, for example `90d` or `1y`.
+	//
+	// +optional
+	MaxAge RetentionPeriod `json:"maxAge,omitempty"`
 }
 
 type BackupTarget struct {
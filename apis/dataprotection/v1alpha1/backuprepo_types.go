@@ -76,6 +76,62 @@ type BackupRepoSpec struct {
 	// +kubebuilder:validation:Pattern=`^([a-zA-Z0-9-_]+/?)*$`
 	// +optional
 	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// Specifies the maximum number of the running backups stored in this repository.
+	// When the limit is reached, the built-in backup scheduler postpones the scheduled backups
+	// to this repository until a running backup finishes. The backups created manually are
+	// counted but never postponed.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentBackups *int32 `json:"maxConcurrentBackups,omitempty"`
+
+	// Specifies how the Backup objects are recreated from the backup manifests stored
+	// in the repository, for example, to recover the backups after the Kubernetes cluster is lost.
+	//
+	// +optional
+	CatalogSync *BackupRepoCatalogSync `json:"catalogSync,omitempty"`
+}
+
+// BackupRepoCatalogSync defines how the Backup objects are recreated from the backup
+// manifests stored in the repository.
+//
+// When the manifests are enabled, each completed backup writes a self-describing manifest
+// to its path in the backup repository, the existing backups are backfilled a few at a time.
+// When the catalog sync is enabled, the repository is scanned for the manifests by a job,
+// and the Backups of the allowed namespaces that do not exist in the cluster are recreated
+// in the Completed phase with the `Retain` deletion policy, so that they can be used by Restores.
+// Only the labels and annotations of KubeBlocks are recreated, except the labels of the backup
+// schedules, and an encrypted backup is recreated only if its BackupPolicy exists and uses the
+// same encryption key. Continuous backups are not included in the catalog.
+type BackupRepoCatalogSync struct {
+	// Specifies whether the catalog sync is enabled or not.
+	//
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled"`
+
+	// Specifies whether the manifests of the completed backups stored in the repository are written.
+	// The manifests are required to recreate the backups from the repository, enable it in the
+	// Kubernetes cluster where the backups are taken.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	WriteManifests bool `json:"writeManifests,omitempty"`
+
+	// Specifies the interval between two synchronizations.
+	// If not set, the backups are only synchronized when the catalog sync is enabled
+	// or the spec of the repository is changed.
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Specifies the namespaces whose backups are recreated.
+	// If not set, no backup is recreated.
+	// The backups whose namespace does not exist are skipped.
+	//
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 // BackupRepoStatus defines the observed state of `BackupRepo`.
@@ -121,8 +177,62 @@ type BackupRepoStatus struct {
 	//
 	// +optional
 	IsDefault bool `json:"isDefault,omitempty"`
+
+	// Records the result of the latest catalog synchronization.
+	//
+	// +optional
+	CatalogSync *BackupRepoCatalogSyncStatus `json:"catalogSync,omitempty"`
+}
+
+// BackupRepoCatalogSyncStatus records the result of a catalog synchronization.
+type BackupRepoCatalogSyncStatus struct {
+	// Describes the phase of the synchronization.
+	//
+	// +optional
+	Phase BackupRepoCatalogSyncPhase `json:"phase,omitempty"`
+
+	// Represents the generation of the repository that the synchronization is run for.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Records the time the synchronization was started.
+	//
+	// +optional
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time the synchronization was completed.
+	//
+	// +optional
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Records the number of the backup manifests found in the repository.
+	//
+	// +optional
+	Manifests int32 `json:"manifests,omitempty"`
+
+	// Records the number of the Backups recreated by the synchronization.
+	//
+	// +optional
+	ImportedBackups int32 `json:"importedBackups,omitempty"`
+
+	// Provides a human-readable message about the result of the synchronization.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
+// BackupRepoCatalogSyncPhase describes the phase of a catalog synchronization.
+// +enum
+// +kubebuilder:validation:Enum={Running,Succeeded,Failed}
+type BackupRepoCatalogSyncPhase string
+
+const (
+	CatalogSyncRunning   BackupRepoCatalogSyncPhase = "Running"
+	CatalogSyncSucceeded BackupRepoCatalogSyncPhase = "Succeeded"
+	CatalogSyncFailed    BackupRepoCatalogSyncPhase = "Failed"
+)
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:openapi-gen=true
@@ -164,3 +274,8 @@ func (repo *BackupRepo) AccessByMount() bool {
 func (repo *BackupRepo) AccessByTool() bool {
 	return repo.Spec.AccessMethod == AccessMethodTool
 }
+
+// WritesManifests checks if the manifests of the backups stored in the repository are written.
+func (repo *BackupRepo) WritesManifests() bool {
+	return repo.Spec.CatalogSync != nil && repo.Spec.CatalogSync.WriteManifests
+}
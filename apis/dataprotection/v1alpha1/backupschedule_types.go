@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,6 +42,13 @@ type BackupScheduleSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
 	Schedules []SchedulePolicy `json:"schedules"`
+
+	// Defines a periodic test restore of the backups produced by the schedules.
+	// A completed backup only means the backup workload exited successfully, the verification
+	// restores the backup into throwaway volumes and checks that the data can be served.
+	//
+	// +optional
+	Verification *BackupVerificationPolicy `json:"verification,omitempty"`
 }
 
 type SchedulePolicy struct {
@@ -54,12 +62,39 @@ type SchedulePolicy struct {
 	// +kubebuilder:validation:Required
 	BackupMethod string `json:"backupMethod"`
 
-	// Specifies the cron expression for the schedule. The timezone is in UTC.
+	// Specifies the cron expression for the schedule. The timezone is specified by `timeZone`.
 	// see https://en.wikipedia.org/wiki/Cron.
 	//
 	// +kubebuilder:validation:Required
 	CronExpression string `json:"cronExpression"`
 
+	// Specifies the time zone of the cron expression in the IANA time zone database format,
+	// such as `Asia/Shanghai`. Defaults to UTC.
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Specifies the maximum random delay added to the scheduled time of each run, which spreads
+	// the backups of the schedules with the same cron expression.
+	// The delay of a run is stable across the restarts of the dataprotection manager. It should be
+	// shorter than the interval of the schedule, and is only applied by the built-in scheduler.
+	//
+	// +optional
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+
+	// Specifies how the built-in scheduler handles the missed runs, for example the runs scheduled
+	// while the dataprotection manager is down or postponed by the concurrency limits.
+	//
+	// - `RunOnce`: a single backup is started for all the missed runs.
+	// - `Skip`: the missed runs are skipped, and the schedule waits for the next scheduled time.
+	//
+	// A run is dropped if it can not be started within `startingDeadlineMinutes` of the BackupSchedule.
+	// If `startingDeadlineMinutes` is not set, the deadline is 5 minutes for `Skip` and unlimited for `RunOnce`.
+	// Defaults to `RunOnce`.
+	//
+	// +optional
+	CatchUpPolicy ScheduleCatchUpPolicy `json:"catchUpPolicy,omitempty"`
+
 	// Determines the duration for which the backup should be kept.
 	// KubeBlocks will remove all backups that are older than the RetentionPeriod.
 	// For example, RetentionPeriod of `30d` will keep only the backups of last 30 days.
@@ -76,6 +111,87 @@ type SchedulePolicy struct {
 	// +optional
 	// +kubebuilder:default="7d"
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
+
+	// Specifies the tiered retention policy for the backups created by this schedule policy.
+	// If set, it takes precedence over the retention policy of the BackupPolicy, and the
+	// RetentionPeriod is ignored for the completed backups.
+	//
+	// +optional
+	Retention *BackupRetentionPolicy `json:"retention,omitempty"`
+}
+
+// BackupVerificationPolicy describes how the backups of a BackupSchedule are verified.
+//
+// On every run, the latest completed backup of the backup method that has not been verified yet
+// is restored into throwaway persistent volume claims, a scratch instance is started on them
+// from the pod spec of the backup target, the postReady actions of the ActionSet are performed
+// against the scratch instance, and then the optional validation job is run.
+// The scratch instance is isolated by a NetworkPolicy, and the other members of the workload
+// are removed from its environment variables, so it never joins the workload of the backup target.
+// The result is recorded in `status.verification` of the Backup and all the objects created
+// for the verification are removed afterwards.
+type BackupVerificationPolicy struct {
+	// Specifies whether the backup verification is enabled or not.
+	//
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Specifies the backup method whose backups will be verified.
+	//
+	// +kubebuilder:validation:Required
+	BackupMethod string `json:"backupMethod"`
+
+	// Specifies the cron expression for the verification. The timezone is in UTC.
+	// see https://en.wikipedia.org/wiki/Cron.
+	//
+	// +kubebuilder:validation:Required
+	CronExpression string `json:"cronExpression"`
+
+	// Specifies the name of the StorageClass used by the throwaway persistent volume claims.
+	// If not specified, the StorageClass of the source volumes is used.
+	//
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Specifies the job that validates the data served by the scratch instance.
+	// The job is considered as passed if it completes successfully.
+	//
+	// +optional
+	ValidationJob *BackupValidationJob `json:"validationJob,omitempty"`
+
+	// Specifies the maximum duration in minutes of a verification.
+	// The verification is marked as failed if it is not finished in time.
+	//
+	// +optional
+	// +kubebuilder:default=120
+	// +kubebuilder:validation:Minimum=1
+	TimeoutMinutes *int32 `json:"timeoutMinutes,omitempty"`
+}
+
+// BackupValidationJob describes the job that validates the data restored from a backup.
+type BackupValidationJob struct {
+	// Specifies the image of the validation container.
+	//
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Specifies the commands to be executed by the validation container.
+	// The connection information of the scratch instance is injected as the environment variables
+	// `DP_DB_HOST`, `DP_DB_PORT`, `DP_DB_USER` and `DP_DB_PASSWORD`.
+	//
+	// +kubebuilder:validation:Required
+	Command []string `json:"command"`
+
+	// Specifies the environment variables of the validation container.
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Specifies the resource requirements of the validation container.
+	//
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // BackupScheduleStatus defines the observed state of BackupSchedule.
@@ -101,6 +217,11 @@ type BackupScheduleStatus struct {
 	//
 	// +optional
 	Schedules map[string]ScheduleStatus `json:"schedules,omitempty"`
+
+	// Describes the status of the backup verification.
+	//
+	// +optional
+	Verification *VerificationScheduleStatus `json:"verification,omitempty"`
 }
 
 // BackupSchedulePhase defines the phase of BackupSchedule
@@ -135,8 +256,43 @@ type ScheduleStatus struct {
 	//
 	// +optional
 	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Records the next time the backup is scheduled. It is only reported by the built-in scheduler,
+	// the backup may be started later because of the jitter and the concurrency limits.
+	//
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Records the name of the last backup created by the built-in scheduler.
+	//
+	// +optional
+	LastBackupName string `json:"lastBackupName,omitempty"`
+}
+
+// VerificationScheduleStatus represents the status of the backup verification.
+type VerificationScheduleStatus struct {
+	// Records the last time the verification was scheduled.
+	//
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Records the name of the backup picked by the last verification.
+	// It is empty if there was no backup to verify.
+	//
+	// +optional
+	LastBackupName string `json:"lastBackupName,omitempty"`
 }
 
+// ScheduleCatchUpPolicy defines how the built-in scheduler handles the missed runs.
+// +enum
+// +kubebuilder:validation:Enum={RunOnce,Skip}
+type ScheduleCatchUpPolicy string
+
+const (
+	ScheduleCatchUpRunOnce ScheduleCatchUpPolicy = "RunOnce"
+	ScheduleCatchUpSkip    ScheduleCatchUpPolicy = "Skip"
+)
+
 // SchedulePhase represents the phase of a schedule.
 type SchedulePhase string
 
@@ -100,6 +100,14 @@ type BackupRef struct {
 
 	// Specifies the source target for restoration, identified by its name.
 	SourceTargetName string `json:"sourceTargetName,omitempty"`
+
+	// Specifies the name of the BackupRepo to read the backup data from. It can be the
+	// BackupRepo where the backup is stored, or one that holds a completed replica of the backup.
+	// If not set, the BackupRepo where the backup is stored is used, and a completed replica
+	// is used instead when that BackupRepo is not available.
+	//
+	// +optional
+	BackupRepoName string `json:"backupRepoName,omitempty"`
 }
 
 type RestoreKubeResources struct {
@@ -478,6 +486,11 @@ type RestoreStatus struct {
 	//
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Records the name of the BackupRepo that the backup data is read from.
+	//
+	// +optional
+	BackupRepoName string `json:"backupRepoName,omitempty"`
 }
 
 // +genclient
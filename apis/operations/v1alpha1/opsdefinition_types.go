@@ -77,6 +77,26 @@ type OpsDefinitionSpec struct {
 type PreCondition struct {
 	// Specifies the conditions that must be met for the operation to execute.
 	Rule *Rule `json:"rule,omitempty"`
+
+	// Specifies the conditions that must be met for the operation to execute, with a CEL expression.
+	// Unlike `rule`, it is checked before the OpsRequest leaves the "Pending" phase,
+	// and the expression is compiled when the OpsDefinition is reconciled.
+	//
+	// Available variables that can be referenced in the expression include:
+	//
+	// - `cluster`: The referenced Cluster object.
+	// - `component`: The referenced Component object.
+	// - `pods`: The pods of the Component, each of them has the fields `name`, `role`, `ready`, `available`,
+	//   `phase`, `nodeName` and `labels`.
+	// - `params`: Input parameters, the values are converted according to the types defined in the `parametersSchema`.
+	//
+	// The fields of `cluster`, `component` and `pods` are type-checked when the expression is compiled,
+	// the fields are referenced by their JSON names, such as `cluster.status.phase`.
+	//
+	// For example: `pods.exists(p, p.role == 'primary' && p.ready)`.
+	//
+	// +optional
+	CELRule *CELRule `json:"celRule,omitempty"`
 }
 
 type Rule struct {
@@ -97,6 +117,19 @@ type Rule struct {
 	Message string `json:"message"`
 }
 
+// CELRule defines a rule with a CEL expression.
+type CELRule struct {
+	// Specifies a CEL expression which must be evaluated to `true`.
+	//
+	// +kubebuilder:validation:Required
+	Expression string `json:"expression"`
+
+	// Specifies the error or status message reported if the `expression` does not evaluate to `true`.
+	//
+	// +kubebuilder:validation:Required
+	Message string `json:"message"`
+}
+
 type PodInfoExtractor struct {
 	// Specifies the name of the PodInfoExtractor.
 	//
@@ -225,6 +258,17 @@ type ParametersSchema struct {
 	// +k8s:conversion-gen=false
 	// +optional
 	OpenAPIV3Schema *apiextensionsv1.JSONSchemaProps `json:"openAPIV3Schema,omitempty"`
+
+	// Specifies the CEL rules for validating the parameters, such as the constraints across multiple parameters.
+	// The OpsRequest fails in the "Pending" phase with the message of the rule if any of the rules is not met.
+	//
+	// The parameters can be referenced by the variable `params` in the expressions,
+	// and the values are converted according to the types defined in the `openAPIV3Schema`.
+	//
+	// For example: `!has(params.minReplicas) || !has(params.maxReplicas) || params.minReplicas <= params.maxReplicas`.
+	//
+	// +optional
+	Rules []CELRule `json:"rules,omitempty"`
 }
 
 // OpsAction specifies a custom action defined in OpsDefinition for execution in a "Custom" OpsRequest.
@@ -262,6 +306,27 @@ type OpsAction struct {
 	// +optional
 	Parameters []string `json:"parameters,omitempty"`
 
+	// Specifies an expression to determine whether the OpsAction is executed.
+	// The OpsAction is skipped and marked as succeeded if the expression is rendered as "false".
+	//
+	// The expression is a Go template, the following built-in objects are available:
+	//
+	// - `cluster`: the Cluster object.
+	// - `component`: the Component object.
+	// - `parameters`: the parameters of the OpsRequest, including the parameters from `parametersFrom`.
+	// - `outputs`: the outputs of the earlier OpsActions, indexed by the names of the OpsActions.
+	//
+	// For example: `{{ eq .outputs.check "true" }}`.
+	//
+	// +optional
+	When string `json:"when,omitempty"`
+
+	// Specifies the parameters whose values are taken from the outputs of the earlier OpsActions.
+	// The parameters are handled in the same way as the parameters of the OpsRequest.
+	//
+	// +optional
+	ParametersFrom []OpsActionOutputRef `json:"parametersFrom,omitempty"`
+
 	// Specifies the configuration for a 'workload' action.
 	// This action leads to the creation of a K8s workload, such as a Pod or Job, to execute specified tasks.
 	//
@@ -280,6 +345,90 @@ type OpsAction struct {
 	//
 	// +optional
 	ResourceModifier *OpsResourceModifierAction `json:"resourceModifier,omitempty"`
+
+	// Specifies the configuration for a 'lifecycleAction' action.
+	// It invokes a lifecycle action defined in the ComponentDefinition through kbagent.
+	//
+	// +optional
+	LifecycleAction *OpsLifecycleAction `json:"lifecycleAction,omitempty"`
+
+	// Specifies the configuration for a 'waitFor' action.
+	// It waits until a condition is met.
+	//
+	// +optional
+	WaitFor *OpsWaitForAction `json:"waitFor,omitempty"`
+}
+
+// OpsActionOutputRef references the output of an earlier OpsAction.
+type OpsActionOutputRef struct {
+	// Specifies the name of the parameter.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the name of an earlier OpsAction whose output is used as the value of the parameter.
+	//
+	// The output of an OpsAction is the outputs of its tasks joined by a newline:
+	//
+	// - For 'workload' actions with the type "Pod", it is the termination message of the first container.
+	// - For 'lifecycleAction' actions, it is the output of the lifecycle action, such as the role reported by "roleProbe".
+	//
+	// The value is empty if the OpsAction has no output or is skipped.
+	//
+	// +kubebuilder:validation:Required
+	ActionName string `json:"actionName"`
+}
+
+// OpsLifecycleAction defines a lifecycle action of the ComponentDefinition invoked through kbagent.
+type OpsLifecycleAction struct {
+	// Specifies the name of the lifecycle action defined in `componentDefinition.spec.lifecycleActions`.
+	//
+	// For "switchover", the value of the parameter "candidate" is used as the candidate instance if specified.
+	//
+	// The "switchover", "dataDump" and "dataLoad" actions are invoked in the non-blocking way, and polled on the same pod
+	// until they are done.
+	//
+	// +kubebuilder:validation:Enum={switchover,dataDump,dataLoad,readonly,readwrite,memberJoin,memberLeave,roleProbe}
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies a PodInfoExtractor defined in the `opsDefinition.spec.podInfoExtractors`.
+	// The lifecycle action is invoked on each of the selected pods.
+	//
+	// If not set, the lifecycle action is invoked once, on the pod determined by the target pod selector of the lifecycle action.
+	//
+	// +optional
+	PodInfoExtractorName string `json:"podInfoExtractorName,omitempty"`
+
+	// Specifies the number of retries allowed before marking the action as failed.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	BackoffLimit int32 `json:"backoffLimit,omitempty"`
+
+	// Specifies the maximum duration in seconds that the lifecycle action is allowed to run.
+	// The timeout of the lifecycle action defined in the ComponentDefinition is used if not set.
+	//
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// OpsWaitForAction defines a condition to wait for.
+type OpsWaitForAction struct {
+	// Specifies the condition to wait for, the action succeeds once the expression is rendered as "true".
+	// The expression is a Go template with the same built-in objects as `opsAction.when`.
+	//
+	// For example: `{{ eq .component.status.phase "Running" }}`.
+	//
+	// +kubebuilder:validation:Required
+	Expression string `json:"expression"`
+
+	// Specifies the maximum duration to wait, the action fails if the condition is not met in time.
+	// No limit by default.
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // FailurePolicyType specifies the type of failure policy.
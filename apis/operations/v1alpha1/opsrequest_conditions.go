@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,18 +47,30 @@ const (
 	ConditionTypeExpose             = "Exposing"
 	ConditionTypeBackup             = "Backup"
 	ConditionTypeInstanceRebuilding = "InstancesRebuilding"
+	ConditionTypeInstanceMigrating  = "InstancesMigrating"
 	ConditionTypeCustomOperation    = "CustomOperation"
+	ConditionTypeScheduled          = "Scheduled"
+	ConditionTypePlanned            = "Planned"
+	ConditionTypeRollback           = "Rollback"
 
 	// condition and event reasons
-	ReasonClusterPhaseMismatch  = "ClusterPhaseMismatch"
-	ReasonOpsTypeNotSupported   = "OpsTypeNotSupported"
-	ReasonValidateFailed        = "ValidateFailed"
-	ReasonClusterNotFound       = "ClusterNotFound"
-	ReasonOpsRequestFailed      = "OpsRequestFailed"
-	ReasonOpsCanceling          = "Canceling"
-	ReasonOpsCancelFailed       = "CancelFailed"
-	ReasonOpsCancelSucceed      = "CancelSucceed"
-	ReasonOpsCancelByController = "CancelByController"
+	ReasonClusterPhaseMismatch        = "ClusterPhaseMismatch"
+	ReasonOpsTypeNotSupported         = "OpsTypeNotSupported"
+	ReasonValidateFailed              = "ValidateFailed"
+	ReasonClusterNotFound             = "ClusterNotFound"
+	ReasonOpsRequestFailed            = "OpsRequestFailed"
+	ReasonOpsCanceling                = "Canceling"
+	ReasonOpsCancelFailed             = "CancelFailed"
+	ReasonOpsCancelSucceed            = "CancelSucceed"
+	ReasonOpsCancelByController       = "CancelByController"
+	ReasonWaitForScheduledTime        = "WaitForScheduledTime"
+	ReasonWaitForMaintenance          = "WaitForMaintenanceWindow"
+	ReasonOpsPlanned                  = "OpsRequestPlanned"
+	ReasonOpsCancelledWithRollback    = "CancelledWithRollback"
+	ReasonOpsCancelledWithoutRollback = "CancelledWithoutRollback"
+	ReasonRollbackTriggered           = "RollbackTriggered"
+	ReasonRollbackSucceed             = "RollbackSucceed"
+	ReasonRollbackFailed              = "RollbackFailed"
 )
 
 func (r *OpsRequest) SetStatusCondition(condition metav1.Condition) {
@@ -76,6 +89,45 @@ func NewWaitForProcessingCondition(ops *OpsRequest) *metav1.Condition {
 	}
 }
 
+// NewScheduledCondition the OpsRequest waits for the scheduled time or the maintenance window.
+func NewScheduledCondition(ops *OpsRequest, reason string, startTime time.Time) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeScheduled,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf("OpsRequest: %s is scheduled to start at %s",
+			ops.Name, startTime.UTC().Format(time.RFC3339)),
+	}
+}
+
+// NewPlannedCondition creates a condition that the execution plan of the dry-run OpsRequest has been computed.
+func NewPlannedCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypePlanned,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonOpsPlanned,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf("Successfully computed the execution plan of the OpsRequest: %s in Cluster: %s",
+			ops.Name, ops.Spec.GetClusterName()),
+	}
+}
+
+// NewRollbackCondition creates a condition for the automatic rollback of the OpsRequest.
+func NewRollbackCondition(reason, message string) *metav1.Condition {
+	status := metav1.ConditionFalse
+	if reason == ReasonRollbackSucceed {
+		status = metav1.ConditionTrue
+	}
+	return &metav1.Condition{
+		Type:               ConditionTypeRollback,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	}
+}
+
 // NewCancelingCondition the controller is canceling the OpsRequest
 func NewCancelingCondition(ops *OpsRequest) *metav1.Condition {
 	return &metav1.Condition{
@@ -196,6 +248,17 @@ func NewInstancesRebuildingCondition(ops *OpsRequest) *metav1.Condition {
 	}
 }
 
+// NewInstancesMigratingCondition creates a condition that the operation starts to migrate the instances off the nodes.
+func NewInstancesMigratingCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeInstanceMigrating,
+		Status:             metav1.ConditionTrue,
+		Reason:             "StartToMigrateInstances",
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Start to migrate the instances in Cluster: %s", ops.Spec.GetClusterName()),
+	}
+}
+
 // NewSwitchoveringCondition creates a condition that the operation starts to switchover components
 func NewSwitchoveringCondition(generation int64, message string) *metav1.Condition {
 	return &metav1.Condition{
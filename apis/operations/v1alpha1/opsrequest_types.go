@@ -28,7 +28,9 @@ import (
 
 // OpsRequestSpec defines the desired state of OpsRequest
 //
-// +kubebuilder:validation:XValidation:rule="has(self.cancel) && self.cancel ? (self.type in ['VerticalScaling', 'HorizontalScaling']) : true",message="forbidden to cancel the opsRequest which type not in ['VerticalScaling','HorizontalScaling']"
+// +kubebuilder:validation:XValidation:rule="has(self.cancel) && self.cancel ? (self.type in ['VerticalScaling', 'HorizontalScaling', 'Upgrade', 'Reconfiguring', 'Restart']) : true",message="forbidden to cancel the opsRequest which type not in ['VerticalScaling','HorizontalScaling','Upgrade','Reconfiguring','Restart']"
+// +kubebuilder:validation:XValidation:rule="has(self.dryRun) && self.dryRun ? (self.type in ['VerticalScaling', 'Upgrade', 'Reconfiguring', 'Restart']) : true",message="forbidden to dry-run the opsRequest which type not in ['VerticalScaling','Upgrade','Reconfiguring','Restart']"
+// +kubebuilder:validation:XValidation:rule="has(self.rollout) ? (self.type in ['VerticalScaling', 'Upgrade']) : true",message="forbidden to roll out the opsRequest in stages which type not in ['VerticalScaling','Upgrade']"
 type OpsRequestSpec struct {
 	// Specifies the name of the Cluster resource that this operation is targeting.
 	//
@@ -38,7 +40,10 @@ type OpsRequestSpec struct {
 	// Indicates whether the current operation should be canceled and terminated gracefully if it's in the
 	// "Pending", "Creating", or "Running" state.
 	//
-	// This field applies only to "VerticalScaling" and "HorizontalScaling" opsRequests.
+	// This field applies only to "VerticalScaling", "HorizontalScaling", "Upgrade", "Reconfiguring" and "Restart" opsRequests.
+	// The instances already updated by "VerticalScaling", "HorizontalScaling", "Upgrade" and "Reconfiguring" are rolled back
+	// to the configuration recorded in `status.lastConfiguration`, while the components of a "Restart" opsRequest
+	// that have not started restarting are skipped.
 	//
 	// Note: Setting `cancel` to true is irreversible; further modifications to this field are ineffective.
 	//
@@ -66,7 +71,7 @@ type OpsRequestSpec struct {
 
 	// Specifies the type of this operation. Supported types include "Start", "Stop", "Restart", "Switchover",
 	// "VerticalScaling", "HorizontalScaling", "VolumeExpansion", "Reconfiguring", "Upgrade", "Backup", "Restore",
-	// "Expose", "RebuildInstance", "Custom".
+	// "Expose", "RebuildInstance", "MigrateInstances", "Custom".
 	//
 	// Note: This field is immutable once set.
 	//
@@ -75,13 +80,13 @@ type OpsRequestSpec struct {
 	Type OpsType `json:"type"`
 
 	// Specifies the duration in seconds that an OpsRequest will remain in the system after successfully completing
-	// (when `opsRequest.status.phase` is "Succeed") before automatic deletion.
+	// (when `opsRequest.status.phase` is "Succeed" or "Planned") before automatic deletion.
 	//
 	// +optional
 	TTLSecondsAfterSucceed int32 `json:"ttlSecondsAfterSucceed,omitempty"`
 
 	// Specifies the duration in seconds that an OpsRequest will remain in the system after completion
-	// for any phase other than "Succeed" (e.g., "Failed", "Cancelled", "Aborted") before automatic deletion.
+	// for any phase other than "Succeed" and "Planned" (e.g., "Failed", "Cancelled", "Aborted") before automatic deletion.
 	//
 	// +optional
 	TTLSecondsAfterUnsuccessfulCompletion int32 `json:"ttlSecondsAfterUnsuccessfulCompletion,omitempty"`
@@ -101,6 +106,47 @@ type OpsRequestSpec struct {
 	// +kubebuilder:Minimum=0
 	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 
+	// Specifies the time at which the OpsRequest is allowed to start.
+	// The OpsRequest waits in the "Scheduled" phase until then.
+	//
+	// +optional
+	ScheduledAt *metav1.Time `json:"scheduledAt,omitempty"`
+
+	// Specifies a recurring maintenance window in which the OpsRequest is allowed to start.
+	// The OpsRequest waits in the "Scheduled" phase until the window opens.
+	//
+	// It overrides the maintenance window of the Cluster, which applies only to the disruptive
+	// OpsRequests, including "Restart", "Upgrade", "VerticalScaling" and "Switchover".
+	// The maintenance window of the Cluster is ignored if `force` is true.
+	//
+	// +optional
+	MaintenanceWindow *appsv1.MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// Indicates whether the OpsRequest only computes its execution plan instead of performing the operation.
+	//
+	// The plan lists the affected Components, the instances in the order they will be updated, and the
+	// parameters that require a restart or a dynamic reload. It is recorded in `status.plan`, and the OpsRequest
+	// ends in the "Planned" phase without mutating the Cluster.
+	//
+	// This field applies only to "VerticalScaling", "Upgrade", "Reconfiguring" and "Restart" opsRequests.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.dryRun"
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Specifies a staged rollout for the instances updated by the OpsRequest.
+	//
+	// The instances are updated stage by stage, for example, a few instances or the instances of one instance template
+	// first. After the instances of a stage are updated, the rollout pauses until the gate of the stage passes,
+	// and then continues automatically or waits for a manual approval.
+	// The instances not covered by any stage are updated after the last stage passes.
+	//
+	// This field applies only to "VerticalScaling" and "Upgrade" opsRequests.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.rollout"
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
 	// Exactly one of its members must be set.
 	SpecificOpsRequest `json:",inline"`
 }
@@ -205,6 +251,12 @@ type SpecificOpsRequest struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.rebuildFrom"
 	RebuildFrom []RebuildInstance `json:"rebuildFrom,omitempty"  patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
 
+	// Specifies the parameters to migrate the instances off nodes, e.g., before the maintenance of nodes.
+	//
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.migrateInstances"
+	MigrateInstances *MigrateInstances `json:"migrateInstances,omitempty"`
+
 	// Specifies a custom operation defined by OpsDefinition.
 	//
 	// +optional
@@ -218,6 +270,7 @@ type ComponentOps struct {
 	ComponentName string `json:"componentName"`
 }
 
+// +kubebuilder:validation:XValidation:rule="has(self.sourceFromPeer) ? (has(self.inPlace) && self.inPlace && !has(self.backupName)) : true",message="sourceFromPeer only works with inPlace rebuilding, and can not be specified together with backupName"
 type RebuildInstance struct {
 	// Specifies the name of the Component.
 	ComponentOps `json:",inline"`
@@ -259,6 +312,62 @@ type RebuildInstance struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
 	RestoreEnv []corev1.EnvVar `json:"restoreEnv,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// Specifies to rebuild the instances from a healthy peer instead of a backup.
+	// The data is dumped by the `dataDump` action of the peer and streamed into the `dataLoad` action
+	// of the rebuilding instance through kb-agents directly, both actions must be exec actions.
+	//
+	// It only works with the in-place rebuilding, and can not be specified together with `backupName`.
+	//
+	// +optional
+	SourceFromPeer *RebuildSourceFromPeer `json:"sourceFromPeer,omitempty"`
+}
+
+type RebuildSourceFromPeer struct {
+	// Specifies the name of the instance (Pod) to dump the data from.
+	//
+	// If not set, an available instance other than the ones to rebuild is selected, the non-leader instances are preferred
+	// to take the load off the leader.
+	//
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+}
+
+// MigrateInstances evacuates all the instances of the Cluster from the nodes.
+//
+// The nodes must be cordoned first to keep the migrated instances from being scheduled back.
+// The instances are migrated one at a time for each Component, and the leader is switched over to
+// an instance on other nodes before it is migrated. The migration of the leader fails if the switchover
+// is not done within 5 minutes.
+//
+// Only the instances of the Cluster specified by `clusterName` are migrated, an OpsRequest is needed for each
+// of the Clusters with instances on the nodes to evacuate the nodes. The other Clusters found on the nodes
+// are reported by a warning event of the OpsRequest, and the OpsRequest fails if the instances of the other
+// Clusters are still on the nodes after its own instances are migrated, as the nodes are not evacuated.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.nodeName) != has(self.nodeSelector)",message="exactly one of nodeName and nodeSelector must be specified"
+type MigrateInstances struct {
+	// Specifies the name of the node to evacuate the instances from.
+	//
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Specifies the labels of the nodes to evacuate the instances from.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Specifies how to migrate the instances whose volumes are bound to the nodes, such as the local persistent volumes.
+	// The instances with the volumes that can be attached to other nodes are migrated by recreating the Pods.
+	//
+	// - `RecreatePVC`: rebuilds the instance in place with empty volumes on other nodes,
+	//   the instance is expected to recover its data by itself, e.g., by the replication.
+	// - `RebuildFromPeer`: rebuilds the instance in place with empty volumes on other nodes,
+	//   and loads the data dumped by a healthy peer into it, see `rebuildFrom.sourceFromPeer`.
+	//
+	// +kubebuilder:default=RecreatePVC
+	// +optional
+	LocalVolumePolicy MigrateLocalVolumePolicy `json:"localVolumePolicy,omitempty"`
 }
 
 type Instance struct {
@@ -272,6 +381,7 @@ type Instance struct {
 	TargetNodeName string `json:"targetNodeName,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.candidatePolicy) || self.instanceName == '*'",message="candidatePolicy only works when instanceName is '*'"
 type Switchover struct {
 	// Specifies the name of the Component.
 	ComponentOps `json:",inline"`
@@ -293,6 +403,31 @@ type Switchover struct {
 	//
 	// +kubebuilder:validation:Required
 	InstanceName string `json:"instanceName"`
+
+	// Specifies the policy to select the candidate by the replication lag of the replicas when `instanceName` is "*".
+	//
+	// It takes effect only if the `replicationLag` action is defined by the ComponentDefinition, the most up-to-date
+	// replica is selected as the candidate then, and the switchover is refused if no replica is qualified.
+	// The decision and the lag readings are recorded in `status.components[*].switchoverDecision`.
+	//
+	// +optional
+	CandidatePolicy *SwitchoverCandidatePolicy `json:"candidatePolicy,omitempty"`
+}
+
+// SwitchoverCandidatePolicy defines how to select the candidate of a switchover by the replication lag of the replicas.
+type SwitchoverCandidatePolicy struct {
+	// Specifies the max replication lag of a qualified candidate, in the unit reported by the `replicationLag` action.
+	// The switchover is refused if the lags of all the candidates exceed it.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicationLag *int64 `json:"maxReplicationLag,omitempty"`
+
+	// Specifies whether to prefer the qualified candidates in the same zone as the current leader.
+	//
+	// +kubebuilder:default=true
+	// +optional
+	PreferSameZone *bool `json:"preferSameZone,omitempty"`
 }
 
 // Upgrade defines the parameters for an upgrade operation.
@@ -310,6 +445,32 @@ type Upgrade struct {
 	// +kubebuilder:validation:MaxItems=1024
 	// +optional
 	Components []UpgradeComponent `json:"components,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
+
+	// Specifies the policy to roll back the Components to the previous ComponentDefinition and ServiceVersion
+	// automatically if the upgraded instances are not healthy in time.
+	// The OpsRequest is marked as Failed after the rollback, the rollback is recorded in `status.rollback`.
+	//
+	// +optional
+	RollbackPolicy *UpgradeRollbackPolicy `json:"rollbackPolicy,omitempty"`
+}
+
+// UpgradeRollbackPolicy defines when to roll back the upgrade automatically.
+type UpgradeRollbackPolicy struct {
+	// Specifies the maximum duration for the upgraded instances to be ready after the OpsRequest starts.
+	// For the Components with a serviceable and writable role, an instance must also report the role through
+	// the role probe within the deadline.
+	//
+	// The Components are rolled back if the deadline is exceeded.
+	//
+	// +kubebuilder:validation:Required
+	ReadinessDeadline metav1.Duration `json:"readinessDeadline"`
+
+	// Specifies the maximum duration for the rolled back Components to be healthy again.
+	// The rollback is marked as Failed if the health check does not pass in time.
+	// Defaults to the `readinessDeadline`.
+	//
+	// +optional
+	HealthCheckTimeout *metav1.Duration `json:"healthCheckTimeout,omitempty"`
 }
 
 // +kubebuilder:validation:XValidation:rule="has(self.componentDefinitionName) || has(self.serviceVersion)",message="at least one componentDefinitionName or serviceVersion"
@@ -938,7 +1099,7 @@ type OpsRequestStatus struct {
 	ClusterGeneration int64 `json:"clusterGeneration,omitempty"`
 
 	// Represents the phase of the OpsRequest.
-	// Possible values include "Pending", "Creating", "Running", "Cancelling", "Cancelled", "Failed", "Succeed".
+	// Possible values include "Pending", "Scheduled", "Creating", "Running", "Cancelling", "Cancelled", "Failed", "Succeed".
 	Phase OpsPhase `json:"phase,omitempty"`
 
 	// Represents the progress of the OpsRequest.
@@ -957,6 +1118,10 @@ type OpsRequestStatus struct {
 	// A collection of additional key-value pairs that provide supplementary information for the OpsRequest.
 	Extras []map[string]string `json:"extras,omitempty"`
 
+	// Records the time when the OpsRequest is scheduled to start, it is set while the OpsRequest waits in the "Scheduled" phase.
+	// +optional
+	ScheduledTimestamp metav1.Time `json:"scheduledTimestamp,omitempty"`
+
 	// Records the time when the OpsRequest started processing.
 	// +optional
 	StartTimestamp metav1.Time `json:"startTimestamp,omitempty"`
@@ -973,10 +1138,22 @@ type OpsRequestStatus struct {
 	// +optional
 	ReconfiguringStatusAsComponent map[string]*ReconfiguringStatus `json:"reconfiguringStatusAsComponent,omitempty"`
 
+	// Records the execution plan computed for the OpsRequest if `opsRequest.spec.dryRun` is true.
+	// +optional
+	Plan *OpsPlan `json:"plan,omitempty"`
+
+	// Records the progress of the staged rollout if `opsRequest.spec.rollout` is specified.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Records the automatic rollback of the Upgrade OpsRequest if `opsRequest.spec.upgrade.rollbackPolicy` is specified.
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+
 	// Describes the detailed status of the OpsRequest.
 	// Possible condition types include "Cancelled", "WaitForProgressing", "Validated", "Succeed", "Failed", "Restarting",
 	// "VerticalScaling", "HorizontalScaling", "VolumeExpanding", "Reconfigure", "Switchover", "Stopping", "Starting",
-	// "VersionUpgrading", "Exposing", "Backup", "InstancesRebuilding", "CustomOperation".
+	// "VersionUpgrading", "Exposing", "Backup", "InstancesRebuilding", "CustomOperation", "Scheduled", "Planned".
 	// +optional
 	// +patchMergeKey=type
 	// +patchStrategy=merge
@@ -985,6 +1162,279 @@ type OpsRequestStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// OpsPlan describes what the OpsRequest would do if it were performed.
+type OpsPlan struct {
+	// Lists the Components affected by the OpsRequest.
+	// +optional
+	Components []ComponentPlan `json:"components,omitempty"`
+}
+
+type ComponentPlan struct {
+	// Specifies the name of the Component.
+	ComponentName string `json:"componentName"`
+
+	// Specifies the strategy by which the instances of the Component are updated.
+	// The instances are updated one by one in "Serial", all at once in "Parallel", and in role-priority order
+	// with as many instances as possible at a time in "BestEffortParallel".
+	// +optional
+	UpdateStrategy appsv1.UpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Specifies the action taken on the Component as a whole.
+	Action PlanAction `json:"action"`
+
+	// Lists the instances of the Component in the order they will be updated.
+	// +optional
+	Instances []InstancePlan `json:"instances,omitempty"`
+
+	// Lists the parameters to be updated, only for the "Reconfiguring" OpsRequest.
+	// +optional
+	Parameters []ParameterPlan `json:"parameters,omitempty"`
+
+	// Provides additional information about the plan of the Component.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type InstancePlan struct {
+	// Specifies the name of the instance.
+	Name string `json:"name"`
+
+	// Specifies the role of the instance when the plan is computed.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// Specifies the action taken on the instance.
+	Action PlanAction `json:"action"`
+
+	// Specifies the step in which the instance is updated, starting from 1.
+	// Instances in the same step are updated in parallel, and a step starts after all instances
+	// in the previous step are ready.
+	// +optional
+	Step int32 `json:"step,omitempty"`
+}
+
+// RolloutStrategy defines the stages in which the instances are updated.
+type RolloutStrategy struct {
+	// Specifies the stages of the rollout, which are executed in order.
+	// The instances selected by a stage remain selected in the later stages.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Stages []RolloutStage `json:"stages"`
+}
+
+// RolloutStage defines the instances updated in a stage and the gate to pass before the next stage.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.replicas) || has(self.instanceTemplates)",message="either replicas or instanceTemplates must be specified"
+type RolloutStage struct {
+	// Specifies the name of the stage.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the number or the percentage of the instances of each Component updated by the end of the stage.
+	// The instances are selected in the order they are updated by the InstanceSet, a percentage is rounded up.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+
+	// Specifies the names of the instance templates whose instances are updated by the end of the stage.
+	//
+	// +optional
+	InstanceTemplates []string `json:"instanceTemplates,omitempty"`
+
+	// Specifies the gate to pass before moving to the next stage.
+	// The rollout moves to the next stage once the instances of the stage are updated if it is not specified.
+	//
+	// +optional
+	Gate *RolloutGate `json:"gate,omitempty"`
+}
+
+// RolloutGate defines the checks to pass after the instances of a stage are updated.
+// The checks are performed in the order of soak, action and query.
+type RolloutGate struct {
+	// Specifies the duration to wait after the instances of the stage are updated and available.
+	//
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+
+	// Specifies a lifecycle action called through kbagent on each updated instance.
+	// The check passes if the action succeeds on all the updated instances.
+	//
+	// +optional
+	Action *RolloutActionGate `json:"action,omitempty"`
+
+	// Specifies a Prometheus-style query evaluated against an HTTP endpoint.
+	//
+	// +optional
+	Query *RolloutQueryGate `json:"query,omitempty"`
+
+	// Specifies whether the rollout moves to the next stage automatically after the checks pass,
+	// or waits for a manual approval.
+	//
+	// A stage is approved by adding its name to the comma-separated list in the annotation
+	// "operations.kubeblocks.io/approved-rollout-stages" of the OpsRequest.
+	//
+	// +kubebuilder:default=Automatic
+	// +optional
+	Approval RolloutApproval `json:"approval,omitempty"`
+
+	// Specifies the maximum duration to wait for the checks to pass after the instances of the stage are updated.
+	// The OpsRequest fails if the checks do not pass in time. No limit by default.
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RolloutActionGate defines a lifecycle action used as a check of the rollout gate.
+type RolloutActionGate struct {
+	// Specifies the name of the lifecycle action defined in the ComponentDefinition, the check passes if the action
+	// succeeds on all the updated instances. It fails if the action is not defined in the ComponentDefinition.
+	// The "roleProbe" action also requires each updated instance to report a role if the Component has roles.
+	//
+	// Note that the action is called at each check, the actions that change the state of the instances,
+	// such as "readonly", should be used with care.
+	//
+	// +kubebuilder:validation:Enum={postProvision,preTerminate,roleProbe,replicationLag,memberJoin,memberLeave,readonly,readwrite,dataDump,dataLoad}
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// RolloutQueryGate defines a Prometheus-style query used as a check of the rollout gate.
+type RolloutQueryGate struct {
+	// Specifies the base URL of the Prometheus-compatible HTTP API, such as "http://prometheus-server.monitoring:9090".
+	// It must be one of the endpoints allowed by the "rolloutQueryEndpoints" setting of KubeBlocks,
+	// otherwise the check does not pass.
+	//
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Specifies the instant query to evaluate.
+	// The check passes if the query returns at least one sample and the values of all samples are non-zero.
+	//
+	// +kubebuilder:validation:Required
+	Query string `json:"query"`
+}
+
+// RolloutStatus represents the progress of the staged rollout.
+type RolloutStatus struct {
+	// The name of the current stage, it is empty when all the stages have passed.
+	//
+	// +optional
+	CurrentStage string `json:"currentStage,omitempty"`
+
+	// The status of the stages which have started.
+	//
+	// +optional
+	Stages []RolloutStageStatus `json:"stages,omitempty"`
+}
+
+// RolloutStageStatus represents the progress of a stage of the staged rollout.
+type RolloutStageStatus struct {
+	// The name of the stage.
+	Name string `json:"name"`
+
+	// The phase of the stage.
+	Phase RolloutStagePhase `json:"phase"`
+
+	// The time when the stage started.
+	//
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// The time when the instances of the stage were updated and available.
+	//
+	// +optional
+	UpdatedTime metav1.Time `json:"updatedTime,omitempty"`
+
+	// The time when the stage passed or failed.
+	//
+	// +optional
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+
+	// The result of the gate of the stage.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RollbackStatus represents the automatic rollback of an OpsRequest.
+type RollbackStatus struct {
+	// The phase of the rollback.
+	Phase RollbackPhase `json:"phase"`
+
+	// The reason why the rollback is triggered.
+	//
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// The Components which are rolled back.
+	//
+	// +optional
+	Components []RollbackComponentStatus `json:"components,omitempty"`
+
+	// The time when the rollback started.
+	//
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// The time when the rollback completed.
+	//
+	// +optional
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+
+	// The result of the health check after the rollback.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RollbackComponentStatus represents the versions of a Component before and after the rollback.
+type RollbackComponentStatus struct {
+	// The name of the Component.
+	Name string `json:"name"`
+
+	// The ComponentDefinition before the rollback.
+	//
+	// +optional
+	FromComponentDefinition string `json:"fromComponentDefinition,omitempty"`
+
+	// The ServiceVersion before the rollback.
+	//
+	// +optional
+	FromServiceVersion string `json:"fromServiceVersion,omitempty"`
+
+	// The ComponentDefinition after the rollback.
+	//
+	// +optional
+	ToComponentDefinition string `json:"toComponentDefinition,omitempty"`
+
+	// The ServiceVersion after the rollback.
+	//
+	// +optional
+	ToServiceVersion string `json:"toServiceVersion,omitempty"`
+}
+
+type ParameterPlan struct {
+	// Specifies the name of the configuration template.
+	ConfigSpecName string `json:"configSpecName"`
+
+	// Specifies the configuration file that the parameter belongs to.
+	Key string `json:"key"`
+
+	// Specifies the name of the parameter.
+	Name string `json:"name"`
+
+	// Specifies the new value of the parameter, it is empty if the parameter is removed.
+	// +optional
+	Value *string `json:"value,omitempty"`
+
+	// Specifies whether the parameter takes effect by a dynamic reload or requires a restart.
+	Action PlanAction `json:"action"`
+}
+
 // +kubebuilder:validation:XValidation:rule="has(self.objectKey) || has(self.actionName)", message="at least one objectKey or actionName."
 
 type ProgressStatusDetail struct {
@@ -1043,6 +1493,10 @@ type ActionTask struct {
 	// The count of retry attempts made for this task.
 	// +optional
 	Retries int32 `json:"retries,omitempty"`
+
+	// The output of the task, which can be passed to the later actions by `opsAction.parametersFrom`.
+	// +optional
+	Output string `json:"output,omitempty"`
 }
 
 // LastComponentConfiguration can be used to track and compare the desired state of the Component over time.
@@ -1079,6 +1533,25 @@ type LastComponentConfiguration struct {
 	// Records the name of the ComponentDefinition prior to any changes.
 	// +optional
 	ComponentDefinitionName string `json:"componentDefinitionName,omitempty"`
+
+	// Records the parameters of the configuration templates prior to any changes.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=name
+	Configurations []LastConfigurationItem `json:"configurations,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+}
+
+// LastConfigurationItem records the parameters of a configuration template prior to any changes.
+type LastConfigurationItem struct {
+	// Specifies the name of the configuration template.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Records the parameters of the configuration files, keyed by the file name.
+	// +optional
+	ConfigFileParams map[string]appsv1alpha1.ConfigParams `json:"configFileParams,omitempty"`
 }
 
 type LastConfiguration struct {
@@ -1106,6 +1579,10 @@ type OpsRequestComponentStatus struct {
 	// +optional
 	ProgressDetails []ProgressStatusDetail `json:"progressDetails,omitempty"`
 
+	// Records how the candidate of the switchover is selected by the replication lag of the replicas.
+	// +optional
+	SwitchoverDecision *SwitchoverDecision `json:"switchoverDecision,omitempty"`
+
 	// Provides an explanation for the Component being in its current state.
 	// +kubebuilder:validation:MaxLength=1024
 	// +optional
@@ -1117,6 +1594,43 @@ type OpsRequestComponentStatus struct {
 	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
 }
 
+// SwitchoverDecision records how the candidate of a switchover is selected.
+type SwitchoverDecision struct {
+	// The selected candidate, it is empty if no replica is qualified.
+	// +optional
+	Candidate string `json:"candidate,omitempty"`
+
+	// A human-readable message explaining the decision.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The replication lags reported by the replicas.
+	// +optional
+	Readings []ReplicationLagReading `json:"readings,omitempty"`
+
+	// The time when the decision is made.
+	// +optional
+	DecisionTime metav1.Time `json:"decisionTime,omitempty"`
+}
+
+// ReplicationLagReading is the replication lag reported by a replica.
+type ReplicationLagReading struct {
+	// The name of the replica.
+	InstanceName string `json:"instanceName"`
+
+	// The zone of the node which the replica is running on.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// The replication lag of the replica, it is absent if the lag fails to be reported.
+	// +optional
+	Lag *int64 `json:"lag,omitempty"`
+
+	// The error occurred when reporting the replication lag.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 type PreCheckResult struct {
 	// Indicates whether the preCheck operation passed or failed.
 	// +kubebuilder:validation:Required
@@ -50,7 +50,7 @@ var (
 // IsComplete checks if opsRequest has been completed.
 func (r *OpsRequest) IsComplete(phases ...OpsPhase) bool {
 	completedPhase := func(phase OpsPhase) bool {
-		return slices.Contains([]OpsPhase{OpsCancelledPhase, OpsSucceedPhase, OpsAbortedPhase, OpsFailedPhase}, phase)
+		return slices.Contains([]OpsPhase{OpsCancelledPhase, OpsSucceedPhase, OpsAbortedPhase, OpsFailedPhase, OpsPlannedPhase}, phase)
 	}
 	if len(phases) == 0 {
 		return completedPhase(r.Status.Phase)
@@ -147,6 +147,8 @@ func (r *OpsRequest) ValidateOps(ctx context.Context,
 		return r.validateExpose(ctx, cluster)
 	case RebuildInstanceType:
 		return r.validateRebuildInstance(cluster)
+	case MigrateInstancesType:
+		return r.validateMigrateInstances()
 	}
 	return nil
 }
@@ -193,6 +195,18 @@ func (r *OpsRequest) validateRebuildInstance(cluster *appsv1.Cluster) error {
 	return r.checkComponentExistence(cluster, compOpsList)
 }
 
+// validateMigrateInstances validates spec.migrateInstances
+func (r *OpsRequest) validateMigrateInstances() error {
+	migrateInstances := r.Spec.MigrateInstances
+	if migrateInstances == nil {
+		return notEmptyError("spec.migrateInstances")
+	}
+	if (migrateInstances.NodeName == "") == (len(migrateInstances.NodeSelector) == 0) {
+		return fmt.Errorf("exactly one of spec.migrateInstances.nodeName and spec.migrateInstances.nodeSelector must be specified")
+	}
+	return nil
+}
+
 // validateUpgrade validates spec.restart
 func (r *OpsRequest) validateRestart(cluster *appsv1.Cluster) error {
 	restartList := r.Spec.RestartList
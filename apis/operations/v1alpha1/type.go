@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 )
 
@@ -42,11 +44,12 @@ const (
 
 // OpsPhase defines opsRequest phase.
 // +enum
-// +kubebuilder:validation:Enum={Pending,Creating,Running,Cancelling,Cancelled,Aborted,Failed,Succeed}
+// +kubebuilder:validation:Enum={Pending,Scheduled,Creating,Running,Cancelling,Cancelled,Aborted,Failed,Succeed,Planned}
 type OpsPhase string
 
 const (
 	OpsPendingPhase    OpsPhase = "Pending"
+	OpsScheduledPhase  OpsPhase = "Scheduled"
 	OpsCreatingPhase   OpsPhase = "Creating"
 	OpsRunningPhase    OpsPhase = "Running"
 	OpsCancellingPhase OpsPhase = "Cancelling"
@@ -54,6 +57,7 @@ const (
 	OpsCancelledPhase  OpsPhase = "Cancelled"
 	OpsFailedPhase     OpsPhase = "Failed"
 	OpsAbortedPhase    OpsPhase = "Aborted"
+	OpsPlannedPhase    OpsPhase = "Planned"
 )
 
 // Phase represents the current status of the ClusterDefinition CR.
@@ -72,7 +76,7 @@ const (
 
 // OpsType defines operation types.
 // +enum
-// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,Backup,Restore,RebuildInstance,Custom}
+// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,Backup,Restore,RebuildInstance,MigrateInstances,Custom}
 type OpsType string
 
 const (
@@ -88,8 +92,9 @@ const (
 	ExposeType            OpsType = "Expose"
 	BackupType            OpsType = "Backup"
 	RestoreType           OpsType = "Restore"
-	RebuildInstanceType   OpsType = "RebuildInstance" // RebuildInstance rebuilding an instance is very useful when a node is offline or an instance is unrecoverable.
-	CustomType            OpsType = "Custom"          // use opsDefinition
+	RebuildInstanceType   OpsType = "RebuildInstance"  // RebuildInstance rebuilding an instance is very useful when a node is offline or an instance is unrecoverable.
+	MigrateInstancesType  OpsType = "MigrateInstances" // MigrateInstances evacuates the instances from nodes, e.g., before the maintenance of nodes.
+	CustomType            OpsType = "Custom"           // use opsDefinition
 )
 
 // ProgressStatus defines the status of the opsRequest progress.
@@ -115,6 +120,61 @@ const (
 	SucceedActionTaskStatus    ActionTaskStatus = "Succeed"
 )
 
+// PlanAction defines the action that the operation takes on an instance or a parameter.
+// +enum
+// +kubebuilder:validation:Enum={Restart,Reload,None}
+type PlanAction string
+
+const (
+	RestartPlanAction PlanAction = "Restart"
+	ReloadPlanAction  PlanAction = "Reload"
+	NonePlanAction    PlanAction = "None"
+)
+
+// RolloutApproval defines how the staged rollout moves to the next stage after the gate passes.
+// +enum
+// +kubebuilder:validation:Enum={Automatic,Manual}
+type RolloutApproval string
+
+const (
+	AutomaticRolloutApproval RolloutApproval = "Automatic"
+	ManualRolloutApproval    RolloutApproval = "Manual"
+)
+
+// RolloutStagePhase defines the phase of a stage of the staged rollout.
+// +enum
+// +kubebuilder:validation:Enum={Updating,Gating,WaitingForApproval,Passed,Failed}
+type RolloutStagePhase string
+
+const (
+	RolloutStageUpdatingPhase           RolloutStagePhase = "Updating"
+	RolloutStageGatingPhase             RolloutStagePhase = "Gating"
+	RolloutStageWaitingForApprovalPhase RolloutStagePhase = "WaitingForApproval"
+	RolloutStagePassedPhase             RolloutStagePhase = "Passed"
+	RolloutStageFailedPhase             RolloutStagePhase = "Failed"
+)
+
+// RollbackPhase defines the phase of the automatic rollback.
+// +enum
+// +kubebuilder:validation:Enum={RollingBack,Succeed,Failed}
+type RollbackPhase string
+
+const (
+	RollingBackRollbackPhase RollbackPhase = "RollingBack"
+	SucceedRollbackPhase     RollbackPhase = "Succeed"
+	FailedRollbackPhase      RollbackPhase = "Failed"
+)
+
+// MigrateLocalVolumePolicy defines how to migrate the instances whose volumes are bound to the nodes.
+// +enum
+// +kubebuilder:validation:Enum={RecreatePVC,RebuildFromPeer}
+type MigrateLocalVolumePolicy string
+
+const (
+	RecreatePVCLocalVolumePolicy     MigrateLocalVolumePolicy = "RecreatePVC"
+	RebuildFromPeerLocalVolumePolicy MigrateLocalVolumePolicy = "RebuildFromPeer"
+)
+
 type OpsRequestBehaviour struct {
 	FromClusterPhases []appsv1.ClusterPhase
 	ToClusterPhase    appsv1.ClusterPhase
@@ -129,4 +189,6 @@ type OpsRecorder struct {
 	InQueue bool `json:"inQueue,omitempty"`
 	// indicates that the operation is queued for execution within its own-type scope.
 	QueueBySelf bool `json:"queueBySelf,omitempty"`
+	// the time when the opsRequest is scheduled to start, it is set while the opsRequest waits in the Scheduled phase.
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
 }
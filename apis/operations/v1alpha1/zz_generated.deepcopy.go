@@ -29,6 +29,7 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -77,6 +78,21 @@ func (in *BackupRefSpec) DeepCopy() *BackupRefSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELRule) DeepCopyInto(out *CELRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELRule.
+func (in *CELRule) DeepCopy() *CELRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CELRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompletionProbe) DeepCopyInto(out *CompletionProbe) {
 	*out = *in
@@ -123,6 +139,33 @@ func (in *ComponentOps) DeepCopy() *ComponentOps {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPlan) DeepCopyInto(out *ComponentPlan) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstancePlan, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ParameterPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPlan.
+func (in *ComponentPlan) DeepCopy() *ComponentPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigurationItem) DeepCopyInto(out *ConfigurationItem) {
 	*out = *in
@@ -302,6 +345,21 @@ func (in *Instance) DeepCopy() *Instance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancePlan) DeepCopyInto(out *InstancePlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancePlan.
+func (in *InstancePlan) DeepCopy() *InstancePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReplicasTemplate) DeepCopyInto(out *InstanceReplicasTemplate) {
 	*out = *in
@@ -405,6 +463,13 @@ func (in *LastComponentConfiguration) DeepCopyInto(out *LastComponentConfigurati
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Configurations != nil {
+		in, out := &in.Configurations, &out.Configurations
+		*out = make([]LastConfigurationItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LastComponentConfiguration.
@@ -439,6 +504,28 @@ func (in *LastConfiguration) DeepCopy() *LastConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LastConfigurationItem) DeepCopyInto(out *LastConfigurationItem) {
+	*out = *in
+	if in.ConfigFileParams != nil {
+		in, out := &in.ConfigFileParams, &out.ConfigFileParams
+		*out = make(map[string]appsv1alpha1.ConfigParams, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LastConfigurationItem.
+func (in *LastConfigurationItem) DeepCopy() *LastConfigurationItem {
+	if in == nil {
+		return nil
+	}
+	out := new(LastConfigurationItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchExpressions) DeepCopyInto(out *MatchExpressions) {
 	*out = *in
@@ -454,6 +541,28 @@ func (in *MatchExpressions) DeepCopy() *MatchExpressions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrateInstances) DeepCopyInto(out *MigrateInstances) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrateInstances.
+func (in *MigrateInstances) DeepCopy() *MigrateInstances {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrateInstances)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsAction) DeepCopyInto(out *OpsAction) {
 	*out = *in
@@ -462,6 +571,11 @@ func (in *OpsAction) DeepCopyInto(out *OpsAction) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ParametersFrom != nil {
+		in, out := &in.ParametersFrom, &out.ParametersFrom
+		*out = make([]OpsActionOutputRef, len(*in))
+		copy(*out, *in)
+	}
 	if in.Workload != nil {
 		in, out := &in.Workload, &out.Workload
 		*out = new(OpsWorkloadAction)
@@ -477,6 +591,16 @@ func (in *OpsAction) DeepCopyInto(out *OpsAction) {
 		*out = new(OpsResourceModifierAction)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LifecycleAction != nil {
+		in, out := &in.LifecycleAction, &out.LifecycleAction
+		*out = new(OpsLifecycleAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WaitFor != nil {
+		in, out := &in.WaitFor, &out.WaitFor
+		*out = new(OpsWaitForAction)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsAction.
@@ -489,6 +613,21 @@ func (in *OpsAction) DeepCopy() *OpsAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsActionOutputRef) DeepCopyInto(out *OpsActionOutputRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsActionOutputRef.
+func (in *OpsActionOutputRef) DeepCopy() *OpsActionOutputRef {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsActionOutputRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsDefinition) DeepCopyInto(out *OpsDefinition) {
 	*out = *in
@@ -649,9 +788,55 @@ func (in *OpsExecAction) DeepCopy() *OpsExecAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsLifecycleAction) DeepCopyInto(out *OpsLifecycleAction) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsLifecycleAction.
+func (in *OpsLifecycleAction) DeepCopy() *OpsLifecycleAction {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsLifecycleAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPlan) DeepCopyInto(out *OpsPlan) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPlan.
+func (in *OpsPlan) DeepCopy() *OpsPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRecorder) DeepCopyInto(out *OpsRecorder) {
 	*out = *in
+	if in.ScheduledTime != nil {
+		in, out := &in.ScheduledTime, &out.ScheduledTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRecorder.
@@ -727,6 +912,11 @@ func (in *OpsRequestComponentStatus) DeepCopyInto(out *OpsRequestComponentStatus
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SwitchoverDecision != nil {
+		in, out := &in.SwitchoverDecision, &out.SwitchoverDecision
+		*out = new(SwitchoverDecision)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRequestComponentStatus.
@@ -784,6 +974,20 @@ func (in *OpsRequestSpec) DeepCopyInto(out *OpsRequestSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScheduledAt != nil {
+		in, out := &in.ScheduledAt, &out.ScheduledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(appsv1.MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.SpecificOpsRequest.DeepCopyInto(&out.SpecificOpsRequest)
 }
 
@@ -821,6 +1025,7 @@ func (in *OpsRequestStatus) DeepCopyInto(out *OpsRequestStatus) {
 			}
 		}
 	}
+	in.ScheduledTimestamp.DeepCopyInto(&out.ScheduledTimestamp)
 	in.StartTimestamp.DeepCopyInto(&out.StartTimestamp)
 	in.CompletionTimestamp.DeepCopyInto(&out.CompletionTimestamp)
 	in.CancelTimestamp.DeepCopyInto(&out.CancelTimestamp)
@@ -840,6 +1045,21 @@ func (in *OpsRequestStatus) DeepCopyInto(out *OpsRequestStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(OpsPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -968,6 +1188,26 @@ func (in *OpsVarSource) DeepCopy() *OpsVarSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsWaitForAction) DeepCopyInto(out *OpsWaitForAction) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsWaitForAction.
+func (in *OpsWaitForAction) DeepCopy() *OpsWaitForAction {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsWaitForAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsWorkloadAction) DeepCopyInto(out *OpsWorkloadAction) {
 	*out = *in
@@ -1046,6 +1286,26 @@ func (in *ParameterPair) DeepCopy() *ParameterPair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterPlan) DeepCopyInto(out *ParameterPlan) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterPlan.
+func (in *ParameterPlan) DeepCopy() *ParameterPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParameterSource) DeepCopyInto(out *ParameterSource) {
 	*out = *in
@@ -1078,6 +1338,11 @@ func (in *ParametersSchema) DeepCopyInto(out *ParametersSchema) {
 		in, out := &in.OpenAPIV3Schema, &out.OpenAPIV3Schema
 		*out = (*in).DeepCopy()
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CELRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParametersSchema.
@@ -1178,6 +1443,11 @@ func (in *PreCondition) DeepCopyInto(out *PreCondition) {
 		*out = new(Rule)
 		**out = **in
 	}
+	if in.CELRule != nil {
+		in, out := &in.CELRule, &out.CELRule
+		*out = new(CELRule)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreCondition.
@@ -1228,6 +1498,11 @@ func (in *RebuildInstance) DeepCopyInto(out *RebuildInstance) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SourceFromPeer != nil {
+		in, out := &in.SourceFromPeer, &out.SourceFromPeer
+		*out = new(RebuildSourceFromPeer)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebuildInstance.
@@ -1240,6 +1515,21 @@ func (in *RebuildInstance) DeepCopy() *RebuildInstance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebuildSourceFromPeer) DeepCopyInto(out *RebuildSourceFromPeer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebuildSourceFromPeer.
+func (in *RebuildSourceFromPeer) DeepCopy() *RebuildSourceFromPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(RebuildSourceFromPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Reconfigure) DeepCopyInto(out *Reconfigure) {
 	*out = *in
@@ -1332,6 +1622,26 @@ func (in *ReplicaChanger) DeepCopy() *ReplicaChanger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationLagReading) DeepCopyInto(out *ReplicationLagReading) {
+	*out = *in
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationLagReading.
+func (in *ReplicationLagReading) DeepCopy() *ReplicationLagReading {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationLagReading)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
@@ -1354,6 +1664,200 @@ func (in *Restore) DeepCopy() *Restore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackComponentStatus) DeepCopyInto(out *RollbackComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackComponentStatus.
+func (in *RollbackComponentStatus) DeepCopy() *RollbackComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]RollbackComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutActionGate) DeepCopyInto(out *RolloutActionGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutActionGate.
+func (in *RolloutActionGate) DeepCopy() *RolloutActionGate {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutActionGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutGate) DeepCopyInto(out *RolloutGate) {
+	*out = *in
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(RolloutActionGate)
+		**out = **in
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(RolloutQueryGate)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutGate.
+func (in *RolloutGate) DeepCopy() *RolloutGate {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutQueryGate) DeepCopyInto(out *RolloutQueryGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutQueryGate.
+func (in *RolloutQueryGate) DeepCopy() *RolloutQueryGate {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutQueryGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStage) DeepCopyInto(out *RolloutStage) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.InstanceTemplates != nil {
+		in, out := &in.InstanceTemplates, &out.InstanceTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Gate != nil {
+		in, out := &in.Gate, &out.Gate
+		*out = new(RolloutGate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStage.
+func (in *RolloutStage) DeepCopy() *RolloutStage {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStageStatus) DeepCopyInto(out *RolloutStageStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.UpdatedTime.DeepCopyInto(&out.UpdatedTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStageStatus.
+func (in *RolloutStageStatus) DeepCopy() *RolloutStageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]RolloutStageStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]RolloutStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -1448,7 +1952,9 @@ func (in *SpecificOpsRequest) DeepCopyInto(out *SpecificOpsRequest) {
 	if in.SwitchoverList != nil {
 		in, out := &in.SwitchoverList, &out.SwitchoverList
 		*out = make([]Switchover, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.VerticalScalingList != nil {
 		in, out := &in.VerticalScalingList, &out.VerticalScalingList
@@ -1488,6 +1994,11 @@ func (in *SpecificOpsRequest) DeepCopyInto(out *SpecificOpsRequest) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MigrateInstances != nil {
+		in, out := &in.MigrateInstances, &out.MigrateInstances
+		*out = new(MigrateInstances)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CustomOps != nil {
 		in, out := &in.CustomOps, &out.CustomOps
 		*out = new(CustomOps)
@@ -1509,6 +2020,11 @@ func (in *SpecificOpsRequest) DeepCopy() *SpecificOpsRequest {
 func (in *Switchover) DeepCopyInto(out *Switchover) {
 	*out = *in
 	out.ComponentOps = in.ComponentOps
+	if in.CandidatePolicy != nil {
+		in, out := &in.CandidatePolicy, &out.CandidatePolicy
+		*out = new(SwitchoverCandidatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Switchover.
@@ -1521,6 +2037,54 @@ func (in *Switchover) DeepCopy() *Switchover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverCandidatePolicy) DeepCopyInto(out *SwitchoverCandidatePolicy) {
+	*out = *in
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreferSameZone != nil {
+		in, out := &in.PreferSameZone, &out.PreferSameZone
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverCandidatePolicy.
+func (in *SwitchoverCandidatePolicy) DeepCopy() *SwitchoverCandidatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverCandidatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverDecision) DeepCopyInto(out *SwitchoverDecision) {
+	*out = *in
+	if in.Readings != nil {
+		in, out := &in.Readings, &out.Readings
+		*out = make([]ReplicationLagReading, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.DecisionTime.DeepCopyInto(&out.DecisionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverDecision.
+func (in *SwitchoverDecision) DeepCopy() *SwitchoverDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypedObjectRef) DeepCopyInto(out *TypedObjectRef) {
 	*out = *in
@@ -1587,6 +2151,11 @@ func (in *Upgrade) DeepCopyInto(out *Upgrade) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RollbackPolicy != nil {
+		in, out := &in.RollbackPolicy, &out.RollbackPolicy
+		*out = new(UpgradeRollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
@@ -1625,6 +2194,27 @@ func (in *UpgradeComponent) DeepCopy() *UpgradeComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRollbackPolicy) DeepCopyInto(out *UpgradeRollbackPolicy) {
+	*out = *in
+	out.ReadinessDeadline = in.ReadinessDeadline
+	if in.HealthCheckTimeout != nil {
+		in, out := &in.HealthCheckTimeout, &out.HealthCheckTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRollbackPolicy.
+func (in *UpgradeRollbackPolicy) DeepCopy() *UpgradeRollbackPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeRollbackPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerticalScaling) DeepCopyInto(out *VerticalScaling) {
 	*out = *in
@@ -229,6 +229,14 @@ type InstanceSetSpec struct {
 	// +optional
 	Paused bool `json:"paused,omitempty"`
 
+	// Specifies the instances allowed to be updated to the update revision in the current stage of a staged rollout.
+	// The other instances are kept in their current revision until the stage moves forward or this field is removed.
+	//
+	// This field is maintained by the controller driving the staged rollout, such as the OpsRequest controller.
+	//
+	// +optional
+	RolloutStage *RolloutStage `json:"rolloutStage,omitempty"`
+
 	// Credential used to connect to DB engine
 	//
 	// +optional
@@ -319,6 +327,48 @@ type InstanceSetStatus struct {
 	// TemplatesStatus represents status of each instance generated by InstanceTemplates
 	// +optional
 	TemplatesStatus []InstanceTemplateStatus `json:"templatesStatus,omitempty"`
+
+	// Represents the progress of the current stage of a staged rollout, set if spec.rolloutStage is specified.
+	//
+	// +optional
+	RolloutStage *RolloutStageStatus `json:"rolloutStage,omitempty"`
+}
+
+// RolloutStage specifies the instances allowed to be updated in a stage of a staged rollout.
+type RolloutStage struct {
+	// Specifies the name of the stage.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the number of instances allowed to be updated,
+	// the instances are selected in the order they are updated by the InstanceSet.
+	//
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Specifies the names of the instance templates whose instances are allowed to be updated.
+	//
+	// +optional
+	InstanceTemplates []string `json:"instanceTemplates,omitempty"`
+}
+
+// RolloutStageStatus represents the progress of a stage of a staged rollout.
+type RolloutStageStatus struct {
+	// The name of the stage.
+	Name string `json:"name"`
+
+	// The number of instances allowed to be updated in the stage.
+	Replicas int32 `json:"replicas"`
+
+	// The number of instances allowed to be updated in the stage which have been updated and are available.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// The names of the instances allowed to be updated in the stage.
+	// The instances selected by the earlier stages remain selected, until the rollout stage is removed.
+	//
+	// +optional
+	Instances []string `json:"instances,omitempty"`
 }
 
 // Range represents a range with a start and an end value.
@@ -216,6 +216,11 @@ func (in *InstanceSetSpec) DeepCopyInto(out *InstanceSetSpec) {
 		*out = new(MemberUpdateStrategy)
 		**out = **in
 	}
+	if in.RolloutStage != nil {
+		in, out := &in.RolloutStage, &out.RolloutStage
+		*out = new(RolloutStage)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Credential != nil {
 		in, out := &in.Credential, &out.Credential
 		*out = new(Credential)
@@ -269,6 +274,11 @@ func (in *InstanceSetStatus) DeepCopyInto(out *InstanceSetStatus) {
 		*out = make([]InstanceTemplateStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.RolloutStage != nil {
+		in, out := &in.RolloutStage, &out.RolloutStage
+		*out = new(RolloutStageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSetStatus.
@@ -511,6 +521,51 @@ func (in *RoleProbe) DeepCopy() *RoleProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStage) DeepCopyInto(out *RolloutStage) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceTemplates != nil {
+		in, out := &in.InstanceTemplates, &out.InstanceTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStage.
+func (in *RolloutStage) DeepCopy() *RolloutStage {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStageStatus) DeepCopyInto(out *RolloutStageStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStageStatus.
+func (in *RolloutStageStatus) DeepCopy() *RolloutStageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulingPolicy) DeepCopyInto(out *SchedulingPolicy) {
 	*out = *in
@@ -117,6 +117,9 @@ func init() {
 	viper.SetDefault(dptypes.CfgKeyExecWorkerServiceAccountName, "kubeblocks-dataprotection-exec-worker")
 	viper.SetDefault(dptypes.CfgKeyWorkerServiceAccountAnnotations, "{}")
 	viper.SetDefault(dptypes.CfgKeyWorkerClusterRoleName, "kubeblocks-dataprotection-worker-role")
+	viper.SetDefault(dptypes.CfgKeyBuiltInBackupScheduler, false)
+	viper.SetDefault(dptypes.CfgKeyMaxConcurrentBackups, 0)
+	viper.SetDefault(dptypes.CfgKeyReplicationStagingSize, "20Gi")
 	viper.SetDefault(dptypes.CfgDataProtectionReconcileWorkers, runtime.NumCPU())
 }
 
@@ -37,6 +37,7 @@ import (
 
 	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/server"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -53,9 +54,17 @@ func init() {
 	pflag.StringVar(&serverConfig.Address, "address", "0.0.0.0", "The HTTP Server listen address for kb-agent service.")
 	pflag.StringVar(&serverConfig.UnixDomainSocket, "unix-socket", "", "The path of the Unix Domain Socket for kb-agent service.")
 	pflag.IntVar(&serverConfig.Port, "port", defaultPort, "The HTTP Server listen port for kb-agent service.")
+	pflag.IntVar(&serverConfig.MetricsPort, "metrics-port", 0, "The port to serve the metrics without TLS and authentication, the metrics are served on the service port if it is 0.")
 	pflag.IntVar(&serverConfig.Concurrency, "max-concurrency", defaultMaxConcurrency,
 		fmt.Sprintf("The maximum number of concurrent connections the Server may serve, use the default value %d if <=0.", defaultMaxConcurrency))
 	pflag.BoolVar(&serverConfig.Logging, "api-logging", true, "Enable api logging for kb-agent request.")
+	pflag.StringVar(&serverConfig.TLSCertFile, "tls-cert-file", "", "The certificate file to enable the TLS for kb-agent service.")
+	pflag.StringVar(&serverConfig.TLSKeyFile, "tls-key-file", "", "The private key file to enable the TLS for kb-agent service.")
+	pflag.StringVar(&serverConfig.TLSClientCAFile, "tls-client-ca-file", "", "The CA file to verify the client certificates, the mutual TLS is enabled if it is set.")
+	pflag.StringVar(&serverConfig.TokenFile, "token-file", "", "The file containing the bearer token that the clients must present.")
+
+	pflag.String(service.JournalDirKey, "", "The directory to persist the journal of action executions, the journal is kept in memory only if it is empty.")
+	pflag.Int(service.JournalMaxEntriesKey, service.DefaultJournalMaxEntries, "The maximum number of action executions kept in the journal.")
 }
 
 func main() {
@@ -140,10 +140,13 @@ func init() {
 	viper.SetDefault(constant.KubernetesClusterDomainEnv, constant.DefaultDNSDomain)
 	viper.SetDefault(instanceset.MaxPlainRevisionCount, 1024)
 	viper.SetDefault(instanceset.FeatureGateIgnorePodVerticalScaling, false)
+	viper.SetDefault(instanceset.FeatureGateKBAgentProbeWatch, false)
 	viper.SetDefault(intctrlutil.FeatureGateEnableRuntimeMetrics, false)
 	viper.SetDefault(constant.CfgKBReconcileWorkers, 8)
 	viper.SetDefault(constant.FeatureGateIgnoreConfigTemplateDefaultMode, false)
 	viper.SetDefault(constant.FeatureGateInPlacePodVerticalScaling, false)
+	viper.SetDefault(constant.FeatureGateKBAgentAuthentication, false)
+	viper.SetDefault(constant.FeatureGateKBAgentJournal, false)
 }
 
 type flagName string
@@ -475,11 +478,16 @@ func main() {
 	}
 
 	if viper.GetBool(workloadsFlagKey.viperName()) {
-		if err = (&workloadscontrollers.InstanceSetReconciler{
+		itsReconciler := &workloadscontrollers.InstanceSetReconciler{
 			Client:   client,
 			Scheme:   mgr.GetScheme(),
 			Recorder: mgr.GetEventRecorderFor("instance-set-controller"),
-		}).SetupWithManager(mgr, multiClusterMgr); err != nil {
+		}
+		if viper.GetBool(instanceset.FeatureGateKBAgentProbeWatch) {
+			itsReconciler.ProbeEventWatcher = instanceset.NewProbeEventWatcher(client,
+				itsReconciler.Recorder, ctrl.Log.WithName("probe-event-watcher"))
+		}
+		if err = itsReconciler.SetupWithManager(mgr, multiClusterMgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "InstanceSet")
 			os.Exit(1)
 		}
@@ -21,6 +21,7 @@ package app
 
 import (
 	"context"
+	"errors"
 
 	"go.uber.org/zap"
 
@@ -96,9 +97,28 @@ func (r *reconfigureProxy) OnlineUpgradeParams(ctx context.Context, request *cfg
 		return nil, cfgcore.MakeError("update params is empty.")
 	}
 	if err := r.updater(ctx, request.ConfigSpec, params); err != nil {
-		return nil, err
+		paramsErr := &cfgcm.ParamsUpdateError{}
+		if !errors.As(err, &paramsErr) {
+			return nil, err
+		}
+		return &cfgproto.OnlineUpgradeParamsResponse{
+			ErrMessage: err.Error(),
+			Results:    buildParamResults(params, paramsErr),
+		}, nil
+	}
+	return &cfgproto.OnlineUpgradeParamsResponse{Results: buildParamResults(params, nil)}, nil
+}
+
+func buildParamResults(params map[string]string, paramsErr *cfgcm.ParamsUpdateError) map[string]*cfgproto.ParamUpgradeResult {
+	results := make(map[string]*cfgproto.ParamUpgradeResult, len(params))
+	for name := range params {
+		result := &cfgproto.ParamUpgradeResult{Succeed: true}
+		if paramsErr != nil && paramsErr.Failed[name] != nil {
+			result = &cfgproto.ParamUpgradeResult{ErrMessage: paramsErr.Failed[name].Error()}
+		}
+		results[name] = result
 	}
-	return &cfgproto.OnlineUpgradeParamsResponse{}, nil
+	return results
 }
 
 func (r *reconfigureProxy) initOnlineUpdater(handler cfgcm.ConfigHandler) error {
@@ -68,6 +68,9 @@ type clusterTransformContext struct {
 
 	shardingComps map[string][]*appsv1.ClusterComponentSpec // comp specs for each sharding
 
+	// the shard lifecycle actions in progress for each sharding, mapping from action & shard to the message
+	shardActions map[string]map[string]string
+
 	// TODO: remove this, annotations to be added to components for sharding, mapping with @allComps.
 	annotations map[string]map[string]string
 }
@@ -152,6 +152,8 @@ func (r *ComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			&componentAccountTransformer{},
 			// handle tls volume and cert
 			&componentTLSTransformer{Client: r.Client},
+			// handle the credentials of kb-agent
+			&componentKBAgentTransformer{},
 			// rerender parameters after v-scale and h-scale
 			&componentRelatedParametersTransformer{Client: r.Client},
 			// resolve and build vars for template and Env
@@ -367,6 +367,41 @@ func (r *ComponentDefinitionReconciler) validateReplicaRoles(cli client.Client,
 
 func (r *ComponentDefinitionReconciler) validateLifecycleActions(cli client.Client, reqCtx intctrlutil.RequestCtx,
 	cmpd *appsv1.ComponentDefinition) error {
+	lifecycleActions := cmpd.Spec.LifecycleActions
+	if lifecycleActions == nil {
+		return nil
+	}
+	actions := map[string]*appsv1.Action{
+		"postProvision":    lifecycleActions.PostProvision,
+		"preTerminate":     lifecycleActions.PreTerminate,
+		"switchover":       lifecycleActions.Switchover,
+		"replicationLag":   lifecycleActions.ReplicationLag,
+		"memberJoin":       lifecycleActions.MemberJoin,
+		"memberLeave":      lifecycleActions.MemberLeave,
+		"readonly":         lifecycleActions.Readonly,
+		"readwrite":        lifecycleActions.Readwrite,
+		"dataDump":         lifecycleActions.DataDump,
+		"dataLoad":         lifecycleActions.DataLoad,
+		"reconfigure":      lifecycleActions.Reconfigure,
+		"accountProvision": lifecycleActions.AccountProvision,
+	}
+	if lifecycleActions.RoleProbe != nil {
+		actions["roleProbe"] = &lifecycleActions.RoleProbe.Action
+	}
+	for name, action := range actions {
+		if action == nil {
+			continue
+		}
+		handlers := 0
+		for _, defined := range []bool{action.Exec != nil, action.HTTP != nil, action.GRPC != nil} {
+			if defined {
+				handlers++
+			}
+		}
+		if handlers > 1 {
+			return fmt.Errorf("only one of exec, http and grpc can be specified for the lifecycle action %s", name)
+		}
+	}
 	return nil
 }
 
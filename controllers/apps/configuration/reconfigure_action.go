@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+)
+
+func reconfigureActionDefined(synthesizedComp *component.SynthesizedComponent) bool {
+	return synthesizedComp != nil && synthesizedComp.LifecycleActions != nil && synthesizedComp.LifecycleActions.Reconfigure != nil
+}
+
+// onlineUpdateWithReconfigureAction applies the updated parameters to the replica by the reconfigure lifecycle action,
+// instead of the config-manager sidecar.
+func onlineUpdateWithReconfigureAction(params reconfigureParams) OnlineUpdatePodFunc {
+	return func(pod *corev1.Pod, ctx context.Context, _ createReconfigureClient, configSpec string, updatedParams map[string]string) error {
+		lfa, err := lifecycle.New(params.SynthesizedComponent, pod)
+		if err != nil {
+			return err
+		}
+		return lfa.Reconfigure(ctx, params.Client, nil, configSpec, updatedParams)
+	}
+}
+
+// updateReconfigureActionResult records the result of the reconfigure action on the config ConfigMap, and it is
+// surfaced as the Reconfigured condition of the component by the component controller, which owns the status of it.
+func updateReconfigureActionResult(params reconfigureParams, status ReturnedStatus, err error) error {
+	condition := metav1.Condition{
+		Type: appsv1.ConditionTypeReconfigured,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = appsv1alpha1.ReasonReconfigureFailed
+		condition.Message = fmt.Sprintf("failed to apply the parameters of config %s: %s", params.ConfigSpecName, err.Error())
+	case status.Status == ESNone:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = appsv1alpha1.ReasonReconfigureSucceed
+		condition.Message = fmt.Sprintf("the parameters of config %s have been applied to %d replicas",
+			params.ConfigSpecName, status.SucceedCount)
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = appsv1alpha1.ReasonReconfigureRunning
+		condition.Message = fmt.Sprintf("applying the parameters of config %s, %d/%d replicas are done",
+			params.ConfigSpecName, status.SucceedCount, status.ExpectedCount)
+	}
+	result, err := json.Marshal(condition)
+	if err != nil {
+		return err
+	}
+
+	cm := params.ConfigMap
+	if cm.Annotations[constant.ReconfigureActionResultAnnotationKey] == string(result) {
+		return nil
+	}
+	patch := client.MergeFrom(cm.DeepCopy())
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[constant.ReconfigureActionResultAnnotationKey] = string(result)
+	return params.Client.Patch(params.Ctx.Ctx, cm, patch, inDataContextUnspecified())
+}
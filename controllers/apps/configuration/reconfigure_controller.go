@@ -223,7 +223,7 @@ func (r *ReconfigureReconciler) sync(reqCtx intctrlutil.RequestCtx, configMap *c
 		InstanceSetUnits:         reconcileContext.InstanceSetList,
 		ClusterComponent:         reconcileContext.ClusterComObj,
 		SynthesizedComponent:     reconcileContext.BuiltinComponent,
-		Restart:                  forceRestart || !cfgcm.IsSupportReload(resources.configConstraintObj.Spec.ReloadAction),
+		Restart:                  forceRestart || (!cfgcm.IsSupportReload(resources.configConstraintObj.Spec.ReloadAction) && !reconfigureActionDefined(reconcileContext.BuiltinComponent)),
 		ReconfigureClientFactory: GetClientFactory(),
 	})
 }
@@ -242,7 +242,8 @@ func (r *ReconfigureReconciler) updateConfigCMStatus(reqCtx intctrlutil.RequestC
 }
 
 func (r *ReconfigureReconciler) performUpgrade(params reconfigureParams) (ctrl.Result, error) {
-	policy, err := NewReconfigurePolicy(params.ConfigConstraint, params.ConfigPatch, getUpgradePolicy(params.ConfigMap), params.Restart)
+	policy, err := NewReconfigurePolicy(params.ConfigConstraint, params.ConfigPatch, getUpgradePolicy(params.ConfigMap), params.Restart,
+		reconfigureActionDefined(params.SynthesizedComponent))
 	if err != nil {
 		return intctrlutil.RequeueWithErrorAndRecordEvent(params.ConfigMap, r.Recorder, err, params.Ctx.Log)
 	}
@@ -211,7 +211,7 @@ func (receiver AutoReloadPolicy) GetPolicyName() string {
 	return string(appsv1alpha1.AsyncDynamicReloadPolicy)
 }
 
-func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.ConfigPatchInfo, policy appsv1alpha1.UpgradePolicy, restart bool) (reconfigurePolicy, error) {
+func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.ConfigPatchInfo, policy appsv1alpha1.UpgradePolicy, restart bool, reconfigureAction bool) (reconfigurePolicy, error) {
 	if cfgPatch != nil && !cfgPatch.IsModify {
 		// not walk here
 		return nil, core.MakeError("cfg not modify. [%v]", cfgPatch)
@@ -229,6 +229,8 @@ func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.C
 		case !dynamicUpdate: // static parameters update
 		case configmanager.IsAutoReload(cc.ReloadAction): // if core support hot update, don't need to do anything
 			policy = appsv1alpha1.AsyncDynamicReloadPolicy
+		case reconfigureAction: // call the reconfigure lifecycle action to hot update
+			policy = appsv1alpha1.SyncDynamicReloadPolicy
 		case enableSyncTrigger(cc.ReloadAction): // sync config-manager exec hot update
 			policy = appsv1alpha1.SyncDynamicReloadPolicy
 		default: // config-manager auto trigger to hot update
@@ -266,7 +268,9 @@ func enableSyncTrigger(reloadAction *appsv1beta1.ReloadAction) bool {
 	if reloadAction.ShellTrigger != nil {
 		return !core.IsWatchModuleForShellTrigger(reloadAction.ShellTrigger)
 	}
-	return false
+
+	// the http and sql triggers are always executed synchronously
+	return reloadAction.HTTPTrigger != nil || reloadAction.SQLTrigger != nil
 }
 
 func withSucceed(succeedCount int32) func(status *ReturnedStatus) {
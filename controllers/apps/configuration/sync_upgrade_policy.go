@@ -59,7 +59,16 @@ func (o *syncPolicy) Upgrade(params reconfigureParams) (ReturnedStatus, error) {
 	if err != nil {
 		return makeReturnedStatus(ESFailedAndRetry), err
 	}
-	return sync(params, updatedParameters, pods, funcs)
+	if !reconfigureActionDefined(params.SynthesizedComponent) {
+		return sync(params, updatedParameters, pods, funcs)
+	}
+
+	funcs.OnlineUpdatePodFunc = onlineUpdateWithReconfigureAction(params)
+	status, err := sync(params, updatedParameters, pods, funcs)
+	if err1 := updateReconfigureActionResult(params, status, err); err1 != nil {
+		params.Ctx.Log.Error(err1, "failed to record the result of reconfigure action")
+	}
+	return status, err
 }
 
 func matchLabel(pods []corev1.Pod, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
@@ -20,6 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package configuration
 
 import (
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -28,9 +30,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
 	cfgproto "github.com/apecloud/kubeblocks/pkg/configuration/proto"
 	mock_proto "github.com/apecloud/kubeblocks/pkg/configuration/proto/mocks"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	testutil "github.com/apecloud/kubeblocks/pkg/testutil/k8s"
 )
 
@@ -103,6 +109,61 @@ var _ = Describe("Reconfigure OperatorSyncPolicy", func() {
 		})
 	})
 
+	Context("sync reconfigure policy with reconfigure action test", func() {
+		It("Should success without error", func() {
+			By("prepare reconfigure policy params")
+			mockParam := newMockReconfigureParams("operatorSyncPolicy", k8sMockClient.Client(),
+				withMockInstanceSet(3, nil),
+				withConfigSpec("for_test", map[string]string{"a": "c b e f"}),
+				withConfigConstraintSpec(&appsv1beta1.FileFormatConfig{Format: appsv1beta1.RedisCfg}),
+				withConfigPatch(map[string]string{
+					"a": "c b e f",
+				}),
+				withClusterComponent(3))
+			synthesizedComp := mockParam.SynthesizedComponent
+			synthesizedComp.Namespace = "default"
+			synthesizedComp.ClusterName = mockParam.Cluster.Name
+			synthesizedComp.Name = "test-comp"
+			synthesizedComp.LifecycleActions = &appsv1.ComponentLifecycleActions{
+				Reconfigure: &appsv1.Action{
+					Exec: &appsv1.ExecAction{
+						Command: []string{"/bin/bash", "-c", "reload"},
+					},
+				},
+			}
+			By("mock client get pod caller")
+			k8sMockClient.MockListMethod(testutil.WithListReturned(
+				testutil.WithConstructListReturnedResult(
+					fromPodObjectList(newMockPodsWithInstanceSet(&mockParam.InstanceSetUnits[0], 3,
+						withReadyPod(0, 3)))),
+				testutil.WithAnyTimes()))
+			// patch the pods and the result of reconfigure action on the config ConfigMap
+			k8sMockClient.MockPatchMethod(testutil.WithSucceed(testutil.WithTimes(4)))
+
+			By("mock the reconfigure action caller")
+			agent := kbacli.NewMockClient(k8sMockClient.Controller())
+			agent.EXPECT().Action(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, req proto.ActionRequest) (proto.ActionResponse, error) {
+				Expect(req.Action).Should(Equal("reconfigure"))
+				Expect(req.Parameters["KB_CONFIG_SPEC_NAME"]).Should(Equal("for_test"))
+				return proto.ActionResponse{}, nil
+			}).Times(3)
+			kbacli.SetMockClient(agent, nil)
+			defer kbacli.UnsetMockClient()
+
+			status, err := operatorSyncPolicy.Upgrade(mockParam)
+			Expect(err).Should(Succeed())
+			Expect(status.Status).Should(BeEquivalentTo(ESNone))
+			Expect(status.SucceedCount).Should(BeEquivalentTo(3))
+			Expect(status.ExpectedCount).Should(BeEquivalentTo(3))
+
+			By("check the result of reconfigure action")
+			result := &metav1.Condition{}
+			Expect(json.Unmarshal([]byte(mockParam.ConfigMap.Annotations[constant.ReconfigureActionResultAnnotationKey]), result)).Should(Succeed())
+			Expect(result.Type).Should(Equal(appsv1.ConditionTypeReconfigured))
+			Expect(result.Status).Should(Equal(metav1.ConditionTrue))
+		})
+	})
+
 	Context("sync reconfigure policy with selector test", func() {
 		It("Should success without error", func() {
 			By("check policy name")
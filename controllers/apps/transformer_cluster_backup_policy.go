@@ -310,6 +310,9 @@ func (r *backupPolicyBuilder) buildBackupSchedule(
 		schedules = append(schedules, dpv1alpha1.SchedulePolicy{
 			BackupMethod:    s.BackupMethod,
 			CronExpression:  s.CronExpression,
+			TimeZone:        s.TimeZone,
+			Jitter:          s.Jitter,
+			CatchUpPolicy:   s.CatchUpPolicy,
 			Enabled:         s.Enabled,
 			RetentionPeriod: s.RetentionPeriod,
 		})
@@ -334,6 +337,9 @@ func (r *backupPolicyBuilder) syncBackupSchedule(backupSchedule *dpv1alpha1.Back
 		backupSchedule.Spec.Schedules = append(backupSchedule.Spec.Schedules, dpv1alpha1.SchedulePolicy{
 			BackupMethod:    s.BackupMethod,
 			CronExpression:  s.CronExpression,
+			TimeZone:        s.TimeZone,
+			Jitter:          s.Jitter,
+			CatchUpPolicy:   s.CatchUpPolicy,
 			Enabled:         s.Enabled,
 			RetentionPeriod: s.RetentionPeriod,
 		})
@@ -26,7 +26,9 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +39,7 @@ import (
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	ictrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
@@ -91,6 +94,10 @@ func (t *clusterComponentTransformer) transform(transCtx *clusterTransformContex
 		return err
 	}
 
+	if delayedErr == nil && len(transCtx.shardActions) > 0 {
+		delayedErr = ictrlutil.NewDelayedRequeueError(time.Second*5,
+			fmt.Sprintf("retry later: shard actions of %s are in progress", strings.Join(sets.List(sets.KeySet(transCtx.shardActions)), ",")))
+	}
 	return delayedErr
 }
 
@@ -168,6 +175,9 @@ func checkAllCompsUpToDate(transCtx *clusterTransformContext, cluster *appsv1.Cl
 		if comp.Generation != comp.Status.ObservedGeneration || generation != strconv.FormatInt(cluster.Generation, 10) {
 			return false, nil
 		}
+		if _, ok := comp.Annotations[kbShardProvisionPendingKey]; ok {
+			return false, nil
+		}
 	}
 	return true, nil
 }
@@ -217,6 +227,10 @@ func copyAndMergeComponent(oldCompObj, newCompObj *appsv1.Component) *appsv1.Com
 	return compObjCopy
 }
 
+const (
+	kbShardProvisionPendingKey = "kubeblocks.io/shard-provision-pending"
+)
+
 const (
 	createOp int = 0
 	deleteOp int = 1
@@ -736,19 +750,35 @@ func (h *clusterShardingHandler) update(transCtx *clusterTransformContext, dag *
 
 	// TODO: update strategy
 
-	h.deleteComps(transCtx, dag, runningCompsMap, toDelete)
-	h.updateComps(transCtx, dag, runningCompsMap, protoCompsMap, toUpdate)
-	h.createComps(transCtx, dag, protoCompsMap, toCreate)
+	shards, err3 := h.shardNames(transCtx, protoCompsMap)
+	if err3 != nil {
+		return err3
+	}
+
+	terminated := h.shardTerminate(transCtx, name, runningCompsMap, toDelete, shards)
+	provisioned := h.shardProvision(transCtx, name, runningCompsMap, toUpdate, shards)
+
+	h.deleteComps(transCtx, dag, runningCompsMap, terminated)
+	h.updateComps(transCtx, dag, runningCompsMap, protoCompsMap, toUpdate, provisioned)
+	h.createComps(transCtx, dag, name, protoCompsMap, toCreate)
 
 	return nil
 }
 
 func (h *clusterShardingHandler) createComps(transCtx *clusterTransformContext, dag *graph.DAG,
-	protoComps map[string]*appsv1.Component, createSet sets.Set[string]) {
+	shardingName string, protoComps map[string]*appsv1.Component, createSet sets.Set[string]) {
 	graphCli, _ := transCtx.Client.(model.GraphClient)
+	actions := h.shardingActions(transCtx, shardingName)
 	for name := range createSet {
-		graphCli.Create(dag, protoComps[name])
-		// TODO: shard post-provision
+		comp := protoComps[name]
+		if actions != nil && actions.ShardProvision != nil {
+			// the shard provision action will be called after the shard is ready
+			if comp.Annotations == nil {
+				comp.Annotations = make(map[string]string)
+			}
+			comp.Annotations[kbShardProvisionPendingKey] = trueVal
+		}
+		graphCli.Create(dag, comp)
 	}
 }
 
@@ -756,22 +786,174 @@ func (h *clusterShardingHandler) deleteComps(transCtx *clusterTransformContext,
 	runningComps map[string]*appsv1.Component, deleteSet sets.Set[string]) {
 	graphCli, _ := transCtx.Client.(model.GraphClient)
 	for name := range deleteSet {
-		// TODO: shard pre-terminate
 		h.deleteComp(transCtx, graphCli, dag, runningComps[name], h.scaleIn)
 	}
 }
 
 func (h *clusterShardingHandler) updateComps(transCtx *clusterTransformContext, dag *graph.DAG,
-	runningComps map[string]*appsv1.Component, protoComps map[string]*appsv1.Component, updateSet sets.Set[string]) {
+	runningComps map[string]*appsv1.Component, protoComps map[string]*appsv1.Component, updateSet, provisioned sets.Set[string]) {
 	graphCli, _ := transCtx.Client.(model.GraphClient)
 	for name := range updateSet {
 		running, proto := runningComps[name], protoComps[name]
-		if obj := copyAndMergeComponent(running, proto); obj != nil {
+		obj := copyAndMergeComponent(running, proto)
+		if provisioned.Has(name) {
+			if obj == nil {
+				obj = running.DeepCopy()
+			}
+			delete(obj.Annotations, kbShardProvisionPendingKey)
+		}
+		if obj != nil {
 			graphCli.Update(dag, running, obj)
 		}
 	}
 }
 
+// shardTerminate calls the shard terminate action for the shards to be deleted, and returns the shards that can be deleted.
+// The shards whose action is failed are kept and will be retried in the next round.
+func (h *clusterShardingHandler) shardTerminate(transCtx *clusterTransformContext, shardingName string,
+	runningComps map[string]*appsv1.Component, deleteSet sets.Set[string], shards []string) sets.Set[string] {
+	actions := h.shardingActions(transCtx, shardingName)
+	if actions == nil || actions.ShardTerminate == nil {
+		return deleteSet
+	}
+	terminated := sets.New[string]()
+	for name := range deleteSet {
+		comp := runningComps[name]
+		if !model.IsObjectDeleting(comp) {
+			err := h.callShardAction(transCtx, comp, actions, func(lfa lifecycle.Lifecycle, opts *lifecycle.Options) error {
+				return lfa.ShardTerminate(transCtx.Context, transCtx.Client, opts, shardingName, shards)
+			})
+			if err != nil {
+				h.shardActionInProgress(transCtx, shardingName, "shardTerminate", comp, err)
+				continue
+			}
+		}
+		terminated.Insert(name)
+	}
+	return terminated
+}
+
+// shardProvision calls the shard provision action for the new shards, and returns the shards that have been provisioned.
+func (h *clusterShardingHandler) shardProvision(transCtx *clusterTransformContext, shardingName string,
+	runningComps map[string]*appsv1.Component, updateSet sets.Set[string], shards []string) sets.Set[string] {
+	actions := h.shardingActions(transCtx, shardingName)
+	provisioned := sets.New[string]()
+	for name := range updateSet {
+		comp := runningComps[name]
+		if _, ok := comp.Annotations[kbShardProvisionPendingKey]; !ok {
+			continue
+		}
+		if actions != nil && actions.ShardProvision != nil {
+			err := h.callShardAction(transCtx, comp, actions, func(lfa lifecycle.Lifecycle, opts *lifecycle.Options) error {
+				return lfa.ShardProvision(transCtx.Context, transCtx.Client, opts, shardingName, shards)
+			})
+			if err != nil {
+				h.shardActionInProgress(transCtx, shardingName, "shardProvision", comp, err)
+				continue
+			}
+		}
+		provisioned.Insert(name)
+	}
+	return provisioned
+}
+
+func (h *clusterShardingHandler) shardingActions(transCtx *clusterTransformContext, shardingName string) *appsv1.ShardingLifecycleActions {
+	for _, sharding := range transCtx.shardings {
+		if sharding.Name == shardingName {
+			if shardingDef, ok := transCtx.shardingDefs[sharding.ShardingDef]; ok {
+				return shardingDef.Spec.LifecycleActions
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// callShardAction calls the shard action in the non-blocking way, it returns lifecycle.ErrActionInProgress until the
+// action is done, and the cluster is requeued to check it again.
+func (h *clusterShardingHandler) callShardAction(transCtx *clusterTransformContext,
+	comp *appsv1.Component, actions *appsv1.ShardingLifecycleActions, f func(lfa lifecycle.Lifecycle, opts *lifecycle.Options) error) error {
+	shardName, err := component.ShortName(transCtx.Cluster.Name, comp.Name)
+	if err != nil {
+		return err
+	}
+	pods, err := component.ListOwnedPods(transCtx.Context, transCtx.Client, comp.Namespace, transCtx.Cluster.Name, shardName)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("has no pods to running the shard action")
+	}
+	synthesizedComp := &component.SynthesizedComponent{
+		Namespace:                comp.Namespace,
+		ClusterName:              transCtx.Cluster.Name,
+		Name:                     shardName,
+		FullCompName:             comp.Name,
+		LifecycleActions:         &appsv1.ComponentLifecycleActions{},
+		ShardingLifecycleActions: actions,
+	}
+	if compDef, ok := transCtx.componentDefs[comp.Spec.CompDef]; ok {
+		synthesizedComp.Roles = compDef.Spec.Roles
+	}
+	pod, err := h.shardActionPod(synthesizedComp.Roles, pods)
+	if err != nil {
+		return err
+	}
+	lfa, err := lifecycle.New(synthesizedComp, pod, pods...)
+	if err != nil {
+		return err
+	}
+	return f(lfa, &lifecycle.Options{NonBlocking: pointer.Bool(true)})
+}
+
+// shardActionPod returns the pod to call the shard action if the target pod selector is not specified. It is the leader
+// of shard if the roles are defined, otherwise the pod with the smallest name. The same pod is returned across reconciliations,
+// so the non-blocking action is polled on the pod it was started.
+func (h *clusterShardingHandler) shardActionPod(roles []appsv1.ReplicaRole, pods []*corev1.Pod) (*corev1.Pod, error) {
+	leaderRole := ""
+	for _, role := range roles {
+		if role.Serviceable && role.Writable {
+			leaderRole = role.Name
+			break
+		}
+	}
+	if len(leaderRole) > 0 {
+		for i, pod := range pods {
+			if pod.Labels[constant.RoleLabelKey] == leaderRole {
+				return pods[i], nil
+			}
+		}
+		return nil, fmt.Errorf("has no %s pod to running the shard action", leaderRole)
+	}
+	return slices.MinFunc(pods, func(a, b *corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	}), nil
+}
+
+func (h *clusterShardingHandler) shardActionInProgress(transCtx *clusterTransformContext, shardingName, actionName string,
+	comp *appsv1.Component, err error) {
+	transCtx.Logger.Info(fmt.Sprintf("%s action of sharding component %s is not done: %s", actionName, comp.Name, err.Error()))
+	if transCtx.shardActions == nil {
+		transCtx.shardActions = make(map[string]map[string]string)
+	}
+	if transCtx.shardActions[shardingName] == nil {
+		transCtx.shardActions[shardingName] = make(map[string]string)
+	}
+	transCtx.shardActions[shardingName][fmt.Sprintf("%s/%s", actionName, comp.Name)] = err.Error()
+}
+
+func (h *clusterShardingHandler) shardNames(transCtx *clusterTransformContext, comps map[string]*appsv1.Component) ([]string, error) {
+	names := make([]string, 0)
+	for _, name := range sets.List(sets.KeySet(comps)) {
+		shardName, err := component.ShortName(transCtx.Cluster.Name, name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, shardName)
+	}
+	return names, nil
+}
+
 func (h *clusterShardingHandler) protoComps(transCtx *clusterTransformContext, name string) ([]*appsv1.Component, error) {
 	build := func(sharding *appsv1.ClusterSharding) ([]*appsv1.Component, error) {
 		labels := map[string]string{
@@ -218,10 +218,32 @@ func (t *clusterComponentStatusTransformer) transformShardingStatus(transCtx *cl
 		}
 	}
 	for name := range updateSet {
-		cluster.Status.Shardings[name] = t.buildClusterShardingStatus(transCtx, name, shardingComps[name])
+		status := t.buildClusterShardingStatus(transCtx, name, shardingComps[name])
+		t.shardActionsStatus(transCtx, name, &status)
+		cluster.Status.Shardings[name] = status
 	}
 }
 
+// shardActionsStatus reports the shard lifecycle actions in progress, the sharding is not considered as running until they are done.
+func (t *clusterComponentStatusTransformer) shardActionsStatus(transCtx *clusterTransformContext,
+	shardingName string, status *appsv1.ClusterComponentStatus) {
+	messages := transCtx.shardActions[shardingName]
+	if len(messages) == 0 {
+		return
+	}
+	if status.Phase == appsv1.RunningClusterCompPhase {
+		status.Phase = appsv1.UpdatingClusterCompPhase
+	}
+	message := make(map[string]string)
+	for k, v := range status.Message {
+		message[k] = v
+	}
+	for k, v := range messages {
+		message[k] = v
+	}
+	status.Message = message
+}
+
 func (t *clusterComponentStatusTransformer) buildClusterShardingStatus(transCtx *clusterTransformContext,
 	shardingName string, comps []*appsv1.Component) appsv1.ClusterComponentStatus {
 	var (
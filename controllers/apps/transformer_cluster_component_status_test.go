@@ -690,5 +690,57 @@ var _ = Describe("cluster component status transformer", func() {
 			Expect(transCtx.Cluster.Status.Shardings).Should(HaveKey("sharding2"))
 			Expect(transCtx.Cluster.Status.Shardings["sharding2"].Phase).Should(Equal(appsv1.ClusterComponentPhase("")))
 		})
+
+		It("shard actions in progress", func() {
+			reader := &mockReader{
+				objs: []client.Object{
+					&appsv1.Component{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testCtx.DefaultNamespace,
+							Name:      "test-cluster-sharding1-01",
+							Labels: map[string]string{
+								constant.AppManagedByLabelKey:      constant.AppName,
+								constant.AppInstanceLabelKey:       transCtx.Cluster.Name,
+								constant.KBAppShardingNameLabelKey: "sharding1",
+							},
+						},
+						Status: appsv1.ComponentStatus{
+							Phase: appsv1.RunningClusterCompPhase,
+						},
+					},
+					&appsv1.Component{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testCtx.DefaultNamespace,
+							Name:      "test-cluster-sharding2-01",
+							Labels: map[string]string{
+								constant.AppManagedByLabelKey:      constant.AppName,
+								constant.AppInstanceLabelKey:       transCtx.Cluster.Name,
+								constant.KBAppShardingNameLabelKey: "sharding2",
+							},
+						},
+						Status: appsv1.ComponentStatus{
+							Phase: appsv1.RunningClusterCompPhase,
+						},
+					},
+				},
+			}
+			transCtx.Client = model.NewGraphClient(reader)
+			transCtx.shardActions = map[string]map[string]string{
+				"sharding1": {
+					"shardProvision/test-cluster-sharding1-01": "precondition check error, component is not ready",
+				},
+			}
+
+			transformer := &clusterComponentStatusTransformer{}
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).Should(BeNil())
+			Expect(transCtx.Cluster.Status.Shardings).Should(HaveLen(2))
+			Expect(transCtx.Cluster.Status.Shardings).Should(HaveKey("sharding1"))
+			Expect(transCtx.Cluster.Status.Shardings["sharding1"].Phase).Should(Equal(appsv1.UpdatingClusterCompPhase))
+			Expect(transCtx.Cluster.Status.Shardings["sharding1"].Message).Should(HaveKeyWithValue(
+				"shardProvision/test-cluster-sharding1-01", "precondition check error, component is not ready"))
+			Expect(transCtx.Cluster.Status.Shardings).Should(HaveKey("sharding2"))
+			Expect(transCtx.Cluster.Status.Shardings["sharding2"].Phase).Should(Equal(appsv1.RunningClusterCompPhase))
+		})
 	})
 })
@@ -28,7 +28,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
@@ -1566,4 +1568,199 @@ var _ = Describe("cluster component transformer test", func() {
 			}
 		})
 	})
+
+	Context("shard lifecycle actions", func() {
+		var (
+			shardAction = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n shard"},
+				},
+			}
+		)
+
+		newCluster := func(shards int32) *appsv1.Cluster {
+			return testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, clusterDefName).
+				SetTopology(clusterTopologyNoOrders4Sharding).
+				AddSharding(sharding1aName, "", "").
+				SetShards(shards).
+				GetObject()
+		}
+
+		newShardObj := func(cluster *appsv1.Cluster, shardName string, setters ...func(*appsv1.Component)) *appsv1.Component {
+			labels := map[string]string{
+				constant.KBAppShardingNameLabelKey: sharding1aName,
+			}
+			comp, err := component.BuildComponent(cluster, &appsv1.ClusterComponentSpec{Name: shardName, Replicas: 1}, labels, nil)
+			Expect(err).Should(BeNil())
+			comp.Status.Phase = appsv1.RunningClusterCompPhase
+			for _, setter := range setters {
+				setter(comp)
+			}
+			return comp
+		}
+
+		newShardTransformerNCtx := func(cluster *appsv1.Cluster, actions *appsv1.ShardingLifecycleActions,
+			objs ...client.Object) (graph.Transformer, *clusterTransformContext, *graph.DAG) {
+			graphCli := model.NewGraphClient(&mockReader{objs: objs})
+			transCtx := &clusterTransformContext{
+				Context:     ctx,
+				Client:      graphCli,
+				Logger:      logger,
+				Cluster:     cluster,
+				OrigCluster: cluster.DeepCopy(),
+				clusterDef:  clusterDef,
+				shardingDefs: map[string]*appsv1.ShardingDefinition{
+					shardingDefName: {
+						Spec: appsv1.ShardingDefinitionSpec{
+							LifecycleActions: actions,
+						},
+					},
+				},
+			}
+			normalizeTransformContext(transCtx)
+			return &clusterComponentTransformer{}, transCtx, newDAG(graphCli, cluster)
+		}
+
+		pendingProvision := func(comp *appsv1.Component) {
+			comp.Annotations[kbShardProvisionPendingKey] = "true"
+		}
+
+		It("shard provision - scale out", func() {
+			cluster := newCluster(2)
+			transformer, transCtx, dag := newShardTransformerNCtx(cluster, &appsv1.ShardingLifecycleActions{ShardProvision: shardAction},
+				newShardObj(cluster, sharding1aName+"-01"))
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).Should(BeNil())
+
+			// the new shard is marked as pending to provision
+			graphCli := transCtx.Client.(model.GraphClient)
+			created := 0
+			for _, obj := range graphCli.FindAll(dag, &appsv1.Component{}) {
+				comp := obj.(*appsv1.Component)
+				if graphCli.IsAction(dag, comp, model.ActionCreatePtr()) {
+					created++
+					Expect(comp.Annotations).Should(HaveKeyWithValue(kbShardProvisionPendingKey, "true"))
+				}
+			}
+			Expect(created).Should(Equal(1))
+		})
+
+		It("shard provision - in progress", func() {
+			cluster := newCluster(2)
+			upToDate := func(comp *appsv1.Component) {
+				comp.Annotations[constant.KubeBlocksGenerationKey] = "0"
+			}
+			pending := newShardObj(cluster, sharding1aName+"-02", upToDate, pendingProvision)
+			transformer, transCtx, dag := newShardTransformerNCtx(cluster, &appsv1.ShardingLifecycleActions{ShardProvision: shardAction},
+				newShardObj(cluster, sharding1aName+"-01", upToDate), pending)
+
+			// not up-to-date until the shard is provisioned
+			upToDate2, err := checkAllCompsUpToDate(transCtx, transCtx.Cluster)
+			Expect(err).Should(BeNil())
+			Expect(upToDate2).Should(BeFalse())
+
+			// the shard has no pods to call the action
+			err = transformer.Transform(transCtx, dag)
+			Expect(err).ShouldNot(BeNil())
+			Expect(ictrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring(sharding1aName))
+			Expect(transCtx.shardActions).Should(HaveKey(sharding1aName))
+			Expect(transCtx.shardActions[sharding1aName]).Should(HaveKey("shardProvision/" + pending.Name))
+
+			// the pending annotation is kept
+			graphCli := transCtx.Client.(model.GraphClient)
+			for _, obj := range graphCli.FindAll(dag, &appsv1.Component{}) {
+				comp := obj.(*appsv1.Component)
+				if comp.Name == pending.Name {
+					Expect(comp.Annotations).Should(HaveKey(kbShardProvisionPendingKey))
+				}
+			}
+		})
+
+		It("shard provision - action not defined", func() {
+			cluster := newCluster(2)
+			pending := newShardObj(cluster, sharding1aName+"-02", pendingProvision)
+			transformer, transCtx, dag := newShardTransformerNCtx(cluster, nil,
+				newShardObj(cluster, sharding1aName+"-01"), pending)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).Should(BeNil())
+
+			// the pending annotation is removed
+			graphCli := transCtx.Client.(model.GraphClient)
+			comp := graphCli.FindMatchedVertex(dag, pending)
+			Expect(comp).ShouldNot(BeNil())
+			obj := comp.(*model.ObjectVertex).Obj.(*appsv1.Component)
+			Expect(graphCli.IsAction(dag, obj, model.ActionUpdatePtr())).Should(BeTrue())
+			Expect(obj.Annotations).ShouldNot(HaveKey(kbShardProvisionPendingKey))
+		})
+
+		It("shard terminate - scale in", func() {
+			cluster := newCluster(1)
+			terminating := newShardObj(cluster, sharding1aName+"-02")
+			transformer, transCtx, dag := newShardTransformerNCtx(cluster, &appsv1.ShardingLifecycleActions{ShardTerminate: shardAction},
+				newShardObj(cluster, sharding1aName+"-01"), terminating)
+
+			// the shard has no pods to call the action
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).ShouldNot(BeNil())
+			Expect(ictrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(transCtx.shardActions).Should(HaveKey(sharding1aName))
+			Expect(transCtx.shardActions[sharding1aName]).Should(HaveKey("shardTerminate/" + terminating.Name))
+
+			// the shard is kept
+			graphCli := transCtx.Client.(model.GraphClient)
+			for _, obj := range graphCli.FindAll(dag, &appsv1.Component{}) {
+				comp := obj.(*appsv1.Component)
+				Expect(graphCli.IsAction(dag, comp, model.ActionDeletePtr())).Should(BeFalse())
+			}
+		})
+
+		It("shard terminate - action not defined", func() {
+			cluster := newCluster(1)
+			terminating := newShardObj(cluster, sharding1aName+"-02")
+			transformer, transCtx, dag := newShardTransformerNCtx(cluster, nil,
+				newShardObj(cluster, sharding1aName+"-01"), terminating)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(err).Should(BeNil())
+
+			graphCli := transCtx.Client.(model.GraphClient)
+			vertex := graphCli.FindMatchedVertex(dag, terminating)
+			Expect(vertex).ShouldNot(BeNil())
+			Expect(graphCli.IsAction(dag, vertex.(*model.ObjectVertex).Obj, model.ActionDeletePtr())).Should(BeTrue())
+		})
+
+		It("shard action pod", func() {
+			newPod := func(name, role string) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{constant.RoleLabelKey: role},
+					},
+				}
+			}
+			pods := []*corev1.Pod{newPod("shard-1", "secondary"), newPod("shard-0", "secondary"), newPod("shard-2", "primary")}
+			h := &clusterShardingHandler{}
+
+			By("the pod with the smallest name if no roles defined")
+			pod, err := h.shardActionPod(nil, pods)
+			Expect(err).Should(BeNil())
+			Expect(pod.Name).Should(Equal("shard-0"))
+
+			By("the leader of shard")
+			roles := []appsv1.ReplicaRole{
+				{Name: "primary", Serviceable: true, Writable: true},
+				{Name: "secondary", Serviceable: true},
+			}
+			pod, err = h.shardActionPod(roles, pods)
+			Expect(err).Should(BeNil())
+			Expect(pod.Name).Should(Equal("shard-2"))
+
+			By("the shard has no leader")
+			_, err = h.shardActionPod(roles, pods[:2])
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
 })
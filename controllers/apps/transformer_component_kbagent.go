@@ -0,0 +1,119 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	kbAgentTokenLength = 32
+)
+
+// componentKBAgentTransformer handles the credentials of kb-agent.
+type componentKBAgentTransformer struct{}
+
+var _ graph.Transformer = &componentKBAgentTransformer{}
+
+func (t *componentKBAgentTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+
+	synthesizeComp := transCtx.SynthesizeComponent
+	if !viper.GetBool(constant.FeatureGateKBAgentAuthentication) || synthesizeComp.LifecycleActions == nil {
+		return nil
+	}
+
+	existSecret, err := t.checkTokenSecretExist(ctx, synthesizeComp)
+	if err != nil {
+		return err
+	}
+	secret, err := t.buildTokenSecret(transCtx, synthesizeComp)
+	if err != nil {
+		return err
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	if existSecret == nil {
+		graphCli.Create(dag, secret, inUniversalContext4G())
+		return nil
+	}
+
+	// the token is immutable, just update the metadata if needed
+	existSecretCopy := existSecret.DeepCopy()
+	ctrlutil.MergeMetadataMapInplace(secret.Labels, &existSecretCopy.Labels)
+	ctrlutil.MergeMetadataMapInplace(secret.Annotations, &existSecretCopy.Annotations)
+	if !reflect.DeepEqual(existSecret, existSecretCopy) {
+		graphCli.Update(dag, existSecret, existSecretCopy, inUniversalContext4G())
+	}
+	return nil
+}
+
+func (t *componentKBAgentTransformer) checkTokenSecretExist(ctx graph.TransformContext,
+	synthesizeComp *component.SynthesizedComponent) (*corev1.Secret, error) {
+	secretKey := types.NamespacedName{
+		Namespace: synthesizeComp.Namespace,
+		Name:      constant.GenerateKBAgentTokenSecretName(synthesizeComp.ClusterName, synthesizeComp.Name),
+	}
+	secret := &corev1.Secret{}
+	err := ctx.GetClient().Get(ctx.GetContext(), secretKey, secret)
+	switch {
+	case err == nil:
+		return secret, nil
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func (t *componentKBAgentTransformer) buildTokenSecret(ctx *componentTransformContext,
+	synthesizeComp *component.SynthesizedComponent) (*corev1.Secret, error) {
+	secretName := constant.GenerateKBAgentTokenSecretName(synthesizeComp.ClusterName, synthesizeComp.Name)
+	secret := builder.NewSecretBuilder(synthesizeComp.Namespace, secretName).
+		AddLabelsInMap(constant.GetCompLabels(synthesizeComp.ClusterName, synthesizeComp.Name)).
+		AddLabelsInMap(synthesizeComp.DynamicLabels).
+		AddLabelsInMap(synthesizeComp.StaticLabels).
+		AddAnnotationsInMap(synthesizeComp.DynamicAnnotations).
+		AddAnnotationsInMap(synthesizeComp.StaticAnnotations).
+		PutData(kbagent.TokenSecretKey, []byte(rand.String(kbAgentTokenLength))).
+		SetImmutable(true).
+		GetObject()
+	if err := setCompOwnershipNFinalizer(ctx.Component, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
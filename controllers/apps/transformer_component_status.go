@@ -20,12 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -139,6 +143,11 @@ func (t *componentStatusTransformer) reconcileStatus(transCtx *componentTransfor
 		return err
 	}
 
+	// surface the result of reconfigure action recorded by the configuration controller
+	if err = t.reconcileReconfiguredCondition(transCtx); err != nil {
+		return err
+	}
+
 	// check if the component has failed pod
 	hasFailedPod, messages := t.hasFailedPod()
 
@@ -278,6 +287,61 @@ func (t *componentStatusTransformer) isAllConfigSynced(transCtx *componentTransf
 	return true, nil
 }
 
+// reconcileReconfiguredCondition surfaces the results of reconfigure action, which are recorded on the config ConfigMaps
+// by the configuration controller, as the Reconfigured condition of component. The failed result takes precedence over
+// the running one, and the condition is true only if all the results are succeeded.
+func (t *componentStatusTransformer) reconcileReconfiguredCondition(transCtx *componentTransformContext) error {
+	var (
+		condition *metav1.Condition
+		messages  []string
+	)
+	for _, configSpec := range t.synthesizeComp.ConfigTemplates {
+		cmKey := client.ObjectKey{
+			Namespace: t.cluster.Namespace,
+			Name:      cfgcore.GetComponentCfgName(t.cluster.Name, t.synthesizeComp.Name, configSpec.Name),
+		}
+		cmObj := &corev1.ConfigMap{}
+		if err := t.Client.Get(transCtx.Context, cmKey, cmObj, inDataContext4C()); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		data, ok := cmObj.Annotations[constant.ReconfigureActionResultAnnotationKey]
+		if !ok {
+			continue
+		}
+		result := &metav1.Condition{}
+		if err := json.Unmarshal([]byte(data), result); err != nil {
+			transCtx.Logger.Error(err, fmt.Sprintf("invalid result of reconfigure action on config %s", configSpec.Name))
+			continue
+		}
+		messages = append(messages, result.Message)
+		switch {
+		case condition == nil:
+			condition = result
+		case result.Status != metav1.ConditionTrue && condition.Status == metav1.ConditionTrue:
+			condition = result
+		case result.Reason == appsv1alpha1.ReasonReconfigureFailed && condition.Reason != appsv1alpha1.ReasonReconfigureFailed:
+			condition = result
+		}
+	}
+	if condition == nil {
+		return nil
+	}
+	if condition.Status == metav1.ConditionTrue {
+		condition.Message = strings.Join(messages, "; ")
+	}
+	meta.SetStatusCondition(&t.comp.Status.Conditions, metav1.Condition{
+		Type:               appsv1.ConditionTypeReconfigured,
+		Status:             condition.Status,
+		ObservedGeneration: t.comp.Generation,
+		Reason:             condition.Reason,
+		Message:            condition.Message,
+	})
+	return nil
+}
+
 // isScaleOutFailed checks if the component scale out failed.
 func (t *componentStatusTransformer) isScaleOutFailed(transCtx *componentTransformContext) (bool, error) {
 	if t.runningITS == nil {
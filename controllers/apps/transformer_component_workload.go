@@ -25,7 +25,9 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"golang.org/x/exp/maps"
@@ -44,10 +46,16 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/configuration"
 	"github.com/apecloud/kubeblocks/pkg/controller/factory"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
+const (
+	// volumeProtectionCheckInterval is the interval to check the usage of the volumes protected by the high watermark.
+	volumeProtectionCheckInterval = time.Second * 30
+)
+
 // componentWorkloadTransformer handles component workload generation
 type componentWorkloadTransformer struct {
 	client.Client
@@ -173,11 +181,20 @@ func (t *componentWorkloadTransformer) stopWorkload(protoITS *workloads.Instance
 
 func (t *componentWorkloadTransformer) handleUpdate(reqCtx intctrlutil.RequestCtx, cli model.GraphClient, dag *graph.DAG,
 	cluster *appsv1.Cluster, synthesizeComp *component.SynthesizedComponent, runningITS, protoITS *workloads.InstanceSet) error {
+	var err error
 	if !isCompStopped(synthesizeComp) {
 		// postpone the update of the workload until the component is back to running.
-		if err := t.handleWorkloadUpdate(reqCtx, dag, cluster, synthesizeComp, runningITS, protoITS); err != nil {
+		if err = t.handleWorkloadUpdate(reqCtx, dag, cluster, synthesizeComp, runningITS, protoITS); err != nil {
 			return err
 		}
+		err = t.readwrite4Start(reqCtx, synthesizeComp, runningITS, protoITS)
+		if err == nil {
+			err = t.protectVolumes(reqCtx, synthesizeComp, runningITS, protoITS)
+		} else {
+			keepReadonlyOnVolumeFull(protoITS, sets.New(readonlyOnVolumeFullPods(runningITS)...))
+		}
+	} else {
+		t.readonly4Stop(reqCtx, synthesizeComp, runningITS, protoITS)
 	}
 
 	objCopy := copyAndMergeITS(runningITS, protoITS, synthesizeComp)
@@ -185,6 +202,172 @@ func (t *componentWorkloadTransformer) handleUpdate(reqCtx intctrlutil.RequestCt
 		cli.Update(dag, nil, objCopy, &model.ReplaceIfExistingOption{})
 	}
 
+	return err
+}
+
+// readonly4Stop switches the writable replicas into the read-only state before the workload is stopped,
+// and marks the workload to bring them back to the read-write state once the component is started again.
+func (t *componentWorkloadTransformer) readonly4Stop(reqCtx intctrlutil.RequestCtx,
+	synthesizeComp *component.SynthesizedComponent, runningITS, protoITS *workloads.InstanceSet) {
+	if synthesizeComp.LifecycleActions == nil || synthesizeComp.LifecycleActions.Readonly == nil {
+		return
+	}
+	if _, ok := runningITS.Annotations[constant.ReadonlyOnStopAnnotationKey]; !ok {
+		if runningITS.Spec.Replicas == nil || *runningITS.Spec.Replicas == 0 {
+			return // has been stopped already
+		}
+		// it's a best-effort attempt, the stop should not be blocked by the replicas that are not functioning.
+		err := t.callActionOnWritablePods(reqCtx, synthesizeComp, func(lfa lifecycle.Lifecycle) error {
+			return lfa.Readonly(reqCtx.Ctx, t.Client, nil)
+		})
+		if err != nil {
+			reqCtx.Log.Error(err, "failed to switch the replicas into read-only state before stop")
+		}
+	}
+	if protoITS.Annotations == nil {
+		protoITS.Annotations = map[string]string{}
+	}
+	protoITS.Annotations[constant.ReadonlyOnStopAnnotationKey] = "true"
+}
+
+// readwrite4Start brings the writable replicas back to the read-write state after the workload is started
+// and all the replicas are ready, the mark will be kept until it succeeds.
+func (t *componentWorkloadTransformer) readwrite4Start(reqCtx intctrlutil.RequestCtx,
+	synthesizeComp *component.SynthesizedComponent, runningITS, protoITS *workloads.InstanceSet) error {
+	val, ok := runningITS.Annotations[constant.ReadonlyOnStopAnnotationKey]
+	if !ok {
+		return nil
+	}
+	err := func() error {
+		if runningITS.Spec.Replicas == nil || *runningITS.Spec.Replicas == 0 || !instanceset.IsInstancesReady(runningITS) {
+			return fmt.Errorf("the workload is not ready")
+		}
+		return t.callActionOnWritablePods(reqCtx, synthesizeComp, func(lfa lifecycle.Lifecycle) error {
+			return lfa.Readwrite(reqCtx.Ctx, t.Client, nil)
+		})
+	}()
+	if err == nil {
+		return nil
+	}
+	if protoITS.Annotations == nil {
+		protoITS.Annotations = map[string]string{}
+	}
+	protoITS.Annotations[constant.ReadonlyOnStopAnnotationKey] = val
+	return intctrlutil.NewDelayedRequeueError(time.Second*5,
+		fmt.Sprintf("wait for the replicas back to read-write state after start: %s", err.Error()))
+}
+
+// protectVolumes switches the replica into the read-only state once any of its volumes exceeds the high watermark,
+// and brings it back to the read-write state once all the volumes are under the high watermark again.
+func (t *componentWorkloadTransformer) protectVolumes(reqCtx intctrlutil.RequestCtx,
+	synthesizeComp *component.SynthesizedComponent, runningITS, protoITS *workloads.InstanceSet) error {
+	volumes := component.ProtectedVolumes(synthesizeComp)
+	readonlyPods := sets.New(readonlyOnVolumeFullPods(runningITS)...)
+	if len(volumes) == 0 && len(readonlyPods) == 0 {
+		return nil
+	}
+	defer func() {
+		keepReadonlyOnVolumeFull(protoITS, readonlyPods)
+	}()
+
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, t.Client, synthesizeComp.Namespace, synthesizeComp.ClusterName, synthesizeComp.Name)
+	if err != nil {
+		return err
+	}
+	// forget the replicas that are gone
+	podNames := sets.New[string]()
+	for _, pod := range pods {
+		podNames.Insert(pod.Name)
+	}
+	readonlyPods = readonlyPods.Intersection(podNames)
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || len(pod.Status.PodIP) == 0 {
+			continue
+		}
+		lfa, err := lifecycle.New(synthesizeComp, pod, pods...)
+		if err != nil {
+			return err
+		}
+		usage, err := lfa.VolumeUsage(reqCtx.Ctx, t.Client)
+		if err != nil {
+			reqCtx.Log.Info("failed to query the volume usage, check it later", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+		var full []string
+		for _, vol := range volumes {
+			if usage[vol.Name] >= vol.HighWatermark {
+				full = append(full, fmt.Sprintf("%s(%d%%)", vol.Name, usage[vol.Name]))
+			}
+		}
+		switch {
+		case len(full) > 0 && !readonlyPods.Has(pod.Name):
+			if err = lfa.Readonly(reqCtx.Ctx, t.Client, nil); err != nil {
+				reqCtx.Eventf(pod, corev1.EventTypeWarning, "VolumeFull",
+					"the volumes %s exceed the high watermark, failed to switch into read-only: %s", strings.Join(full, ","), err.Error())
+				continue
+			}
+			readonlyPods.Insert(pod.Name)
+			reqCtx.Eventf(pod, corev1.EventTypeWarning, "VolumeFull",
+				"the volumes %s exceed the high watermark, switched into read-only", strings.Join(full, ","))
+		case len(full) == 0 && readonlyPods.Has(pod.Name):
+			if err = lfa.Readwrite(reqCtx.Ctx, t.Client, nil); err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+				reqCtx.Log.Info("failed to bring the replica back to read-write, retry later", "pod", pod.Name, "error", err.Error())
+				continue
+			}
+			readonlyPods.Delete(pod.Name)
+			reqCtx.Eventf(pod, corev1.EventTypeNormal, "VolumeFreed",
+				"the volumes are under the high watermark, switched back to read-write")
+		}
+	}
+	// the usage is changed out of the knowledge of controller, check it periodically
+	return intctrlutil.NewDelayedRequeueError(volumeProtectionCheckInterval, "check the usage of protected volumes")
+}
+
+func readonlyOnVolumeFullPods(runningITS *workloads.InstanceSet) []string {
+	val := runningITS.Annotations[constant.ReadonlyOnVolumeFullAnnotationKey]
+	if len(val) == 0 {
+		return nil
+	}
+	return strings.Split(val, ",")
+}
+
+func keepReadonlyOnVolumeFull(protoITS *workloads.InstanceSet, readonlyPods sets.Set[string]) {
+	if len(readonlyPods) == 0 {
+		return
+	}
+	if protoITS.Annotations == nil {
+		protoITS.Annotations = map[string]string{}
+	}
+	protoITS.Annotations[constant.ReadonlyOnVolumeFullAnnotationKey] = strings.Join(sets.List(readonlyPods), ",")
+}
+
+func (t *componentWorkloadTransformer) callActionOnWritablePods(reqCtx intctrlutil.RequestCtx,
+	synthesizeComp *component.SynthesizedComponent, call func(lfa lifecycle.Lifecycle) error) error {
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, t.Client, synthesizeComp.Namespace, synthesizeComp.ClusterName, synthesizeComp.Name)
+	if err != nil {
+		return err
+	}
+	writable := pods
+	if len(synthesizeComp.Roles) > 0 {
+		writable = slices.DeleteFunc(slices.Clone(pods), func(pod *corev1.Pod) bool {
+			return !slices.ContainsFunc(synthesizeComp.Roles, func(role appsv1.ReplicaRole) bool {
+				return role.Writable && pod.Labels[constant.RoleLabelKey] == role.Name
+			})
+		})
+	}
+	if len(writable) == 0 {
+		return fmt.Errorf("has no writable replica")
+	}
+	for _, pod := range writable {
+		lfa, err := lifecycle.New(synthesizeComp, pod, pods...)
+		if err != nil {
+			return err
+		}
+		if err = call(lfa); err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -313,6 +496,9 @@ func copyAndMergeITS(oldITS, newITS *workloads.InstanceSet, synthesizeComp *comp
 			return strings.HasPrefix(k, "monitor.kubeblocks.io")
 		})
 	}
+	// the readonly-on-stop and readonly-on-volume-full annotations are maintained by the proto
+	delete(itsObjCopy.Annotations, constant.ReadonlyOnStopAnnotationKey)
+	delete(itsObjCopy.Annotations, constant.ReadonlyOnVolumeFullAnnotationKey)
 	mergeMetadataMap(itsObjCopy.Annotations, &itsProto.Annotations)
 	itsObjCopy.Annotations = itsProto.Annotations
 
@@ -647,7 +833,17 @@ func (r *componentWorkloadOps) leaveMember4ScaleIn() error {
 			return nil
 		}
 		// if HA functionality is not enabled, no need to switchover
-		err := lfa.Switchover(r.reqCtx.Ctx, r.cli, nil, "")
+		if r.synthesizeComp.LifecycleActions == nil || r.synthesizeComp.LifecycleActions.Switchover == nil {
+			return nil
+		}
+		candidate, err := r.switchoverCandidate(pods)
+		if err != nil {
+			return err
+		}
+		// stop writes on the leaving leader before the switchover, it will be brought back if the switchover fails
+		err = lifecycle.WithReadonly(r.reqCtx.Ctx, r.cli, lfa, func() error {
+			return lfa.Switchover(r.reqCtx.Ctx, r.cli, nil, candidate)
+		})
 		if err != nil && errors.Is(err, lifecycle.ErrActionNotDefined) {
 			return nil
 		}
@@ -694,6 +890,40 @@ func (r *componentWorkloadOps) leaveMember4ScaleIn() error {
 	return err // TODO: use requeue-after
 }
 
+// switchoverCandidate selects the most up-to-date replica which is not scaled in as the candidate of switchover,
+// it returns an empty candidate to let the engine choose if the replicationLag action is not defined or no replica
+// is qualified, the latter is reported by a warning event to not block the scale-in.
+func (r *componentWorkloadOps) switchoverCandidate(pods []*corev1.Pod) (string, error) {
+	policy := lifecycle.CandidatePolicy{PreferSameZone: true}
+	if val, ok := r.synthesizeComp.Annotations[constant.SwitchoverMaxReplicationLagAnnotationKey]; ok {
+		maxLag, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid annotation %s: %s", constant.SwitchoverMaxReplicationLagAnnotationKey, val)
+		}
+		policy.MaxLag = &maxLag
+	}
+	for _, pod := range pods {
+		if _, ok := r.desiredCompPodNameSet[pod.Name]; !ok {
+			policy.Excluded = append(policy.Excluded, pod.Name)
+		}
+	}
+	decision, err := lifecycle.SelectSwitchoverCandidate(r.reqCtx.Ctx, r.cli, r.synthesizeComp, pods, policy)
+	if errors.Is(err, lifecycle.ErrActionNotDefined) {
+		return "", nil
+	}
+	if errors.Is(err, lifecycle.ErrNoQualifiedCandidate) {
+		r.reqCtx.Eventf(r.cluster, corev1.EventTypeWarning, "SwitchoverCandidate",
+			"no qualified switchover candidate of component %s, let the engine choose: %s", r.synthesizeComp.Name, decision.Message)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	r.reqCtx.Eventf(r.cluster, corev1.EventTypeNormal, "SwitchoverCandidate",
+		"select %s as the switchover candidate of component %s: %s", decision.Candidate, r.synthesizeComp.Name, decision.Message)
+	return decision.Candidate, nil
+}
+
 func (r *componentWorkloadOps) deletePVCs4ScaleIn(itsObj *workloads.InstanceSet) error {
 	graphCli := model.NewGraphClient(r.cli)
 	for _, podName := range r.runningItsPodNames {
@@ -74,6 +74,7 @@ type BackupReconciler struct {
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/finalizers,verbs=update
 
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots/finalizers,verbs=update;patch
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotclasses,verbs=get;list;watch;create;update;patch;delete
@@ -82,6 +83,10 @@ type BackupReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=restores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=update
@@ -118,6 +123,11 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	switch backup.Status.Phase {
 	case "", dpv1alpha1.BackupPhaseNew:
+		if backup.Annotations[dptypes.CatalogBackupRepoAnnotationKey] != "" {
+			// the backup is recreated from the catalog of the backup repo, and its status
+			// will be restored by the BackupRepoController.
+			return intctrlutil.Reconciled()
+		}
 		return r.handleNewPhase(reqCtx, backup)
 	case dpv1alpha1.BackupPhaseRunning:
 		return r.handleRunningPhase(reqCtx, backup)
@@ -148,6 +158,8 @@ func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
+		Owns(&dpv1alpha1.Restore{}).
+		Owns(&corev1.Pod{}).
 		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterBackupPods)).
 		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.parseBackupJob))
 
@@ -221,11 +233,7 @@ func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, back
 	}
 	deleter.WorkerServiceAccount = saName
 
-	status, err := deleter.DeleteBackupFiles(backup)
-	switch status {
-	case dpbackup.DeletionStatusSucceeded:
-		return deleteBackup()
-	case dpbackup.DeletionStatusFailed:
+	recordFailure := func(err error) error {
 		failureReason := err.Error()
 		if backup.Status.FailureReason == failureReason {
 			return nil
@@ -234,6 +242,27 @@ func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, back
 		backup.Status.FailureReason = failureReason
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "DeleteBackupFilesFailed", failureReason)
 		return r.Status().Patch(reqCtx.Ctx, backup, backupPatch)
+	}
+
+	// delete the files of the replicas first, they are copied from the backup files.
+	for i := range backup.Status.Replicas {
+		status, err := deleter.DeleteReplicaFiles(backup, &backup.Status.Replicas[i])
+		switch status {
+		case dpbackup.DeletionStatusSucceeded:
+			continue
+		case dpbackup.DeletionStatusFailed:
+			return recordFailure(err)
+		}
+		// wait for the deletion job completed
+		return err
+	}
+
+	status, err := deleter.DeleteBackupFiles(backup)
+	switch status {
+	case dpbackup.DeletionStatusSucceeded:
+		return deleteBackup()
+	case dpbackup.DeletionStatusFailed:
+		return recordFailure(err)
 	case dpbackup.DeletionStatusDeleting,
 		dpbackup.DeletionStatusUnknown:
 		// wait for the deletion job completed
@@ -252,6 +281,27 @@ func (r *BackupReconciler) handleDeletingPhase(reqCtx intctrlutil.RequestCtx, ba
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	}
 
+	// the throwaway volume claims of the verification are not owned by the backup.
+	if backup.Status.Verification != nil {
+		verifier := &dpbackup.Verifier{RequestCtx: reqCtx, Client: r.Client, Backup: backup}
+		if err := verifier.Cleanup(); err != nil {
+			return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+
+	writer := &dpbackup.ManifestWriter{RequestCtx: reqCtx, Client: r.Client, Backup: backup}
+	if err := writer.Cleanup(); err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+
+	// stop copying the backup before deleting the files of the replicas.
+	if len(backup.Status.Replicas) > 0 {
+		replicator := &dpbackup.Replicator{RequestCtx: reqCtx, Client: r.Client, Backup: backup}
+		if err := replicator.Cleanup(); err != nil {
+			return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+
 	if backup.Spec.DeletionPolicy == dpv1alpha1.BackupDeletionPolicyRetain {
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "Retain", "can not delete the backup if deletionPolicy is Retain")
 		return intctrlutil.Reconciled()
@@ -692,6 +742,185 @@ func (r *BackupReconciler) handleCompletedPhase(
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 	}
 
+	manifestPending, err := r.writeManifest(reqCtx, backup)
+	if err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+
+	if err := r.handleReplication(reqCtx, backup); err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+
+	if dpbackup.IsVerificationInProgress(backup) {
+		return r.handleVerification(reqCtx, backup)
+	}
+	if dpbackup.IsReplicationInProgress(backup) {
+		return intctrlutil.RequeueAfter(replicationCheckInterval, reqCtx.Log, "")
+	}
+	if manifestPending {
+		return intctrlutil.RequeueAfter(manifestCheckInterval, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// writeManifest writes the manifest of the completed backup to the backup repository if the
+// backup repository enables it, so that the backup can be recreated from the backup repository.
+// It returns true if the writing is pending.
+func (r *BackupReconciler) writeManifest(
+	reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (bool, error) {
+	if !dpbackup.NeedsManifest(backup) {
+		return false, nil
+	}
+	// the worker service account is only ensured for the backup repos that enable the manifests.
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client,
+		client.ObjectKey{Name: backup.Status.BackupRepoName}, backupRepo)
+	if err != nil || !exists || !backupRepo.WritesManifests() {
+		return false, err
+	}
+	original := backup.DeepCopy()
+	writer := &dpbackup.ManifestWriter{
+		RequestCtx: reqCtx,
+		Client:     r.Client,
+		Scheme:     r.Scheme,
+		Backup:     backup,
+	}
+	if writer.WorkerServiceAccount, err = EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace, nil); err != nil {
+		return false, err
+	}
+	done, err := writer.Write()
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			// the manifest is not necessary for the backup itself, do not block the backup.
+			r.Recorder.Event(backup, corev1.EventTypeWarning, "WriteManifestFailed", err.Error())
+			return false, nil
+		}
+		return false, err
+	}
+	if backup.Status.ManifestPath != original.Status.ManifestPath {
+		return false, r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original))
+	}
+	return !done, nil
+}
+
+// handleReplication copies the completed backup to the secondary backup repositories
+// by the replication policy of the backup policy.
+func (r *BackupReconciler) handleReplication(
+	reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) error {
+	var policy *dpv1alpha1.BackupReplicationPolicy
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	policyKey := client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.BackupPolicyName}
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client, policyKey, backupPolicy)
+	if err != nil {
+		return err
+	}
+	if exists {
+		policy = backupPolicy.Spec.Replication
+	}
+	if !dpbackup.NeedsReplication(backup, policy) {
+		return nil
+	}
+
+	original := backup.DeepCopy()
+	replicator := &dpbackup.Replicator{
+		RequestCtx: reqCtx,
+		Client:     r.Client,
+		Scheme:     r.Scheme,
+		Backup:     backup,
+		Policy:     policy,
+	}
+	if replicator.WorkerServiceAccount, err = EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace, nil); err != nil {
+		return err
+	}
+	waitRepoName, err := replicator.Replicate()
+
+	// wait for the BackupRepoController to prepare the backup repo in the namespace of the backup.
+	if waitRepoName != backup.Labels[dataProtectionWaitReplicaRepoKey] {
+		patch := client.MergeFrom(backup.DeepCopy())
+		if waitRepoName == "" {
+			delete(backup.Labels, dataProtectionWaitReplicaRepoKey)
+		} else {
+			if backup.Labels == nil {
+				backup.Labels = map[string]string{}
+			}
+			backup.Labels[dataProtectionWaitReplicaRepoKey] = waitRepoName
+		}
+		// the status is not changed by patching the object meta.
+		status := backup.Status.DeepCopy()
+		if patchErr := r.Client.Patch(reqCtx.Ctx, backup, patch); patchErr != nil {
+			return patchErr
+		}
+		backup.Status = *status
+	}
+	if !reflect.DeepEqual(original.Status, backup.Status) {
+		if patchErr := r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original)); patchErr != nil {
+			return patchErr
+		}
+		for _, replica := range backup.Status.Replicas {
+			origin := original.GetReplica(replica.BackupRepoName)
+			if origin != nil && origin.Phase == replica.Phase {
+				continue
+			}
+			switch replica.Phase {
+			case dpv1alpha1.BackupReplicaCompleted:
+				r.Recorder.Eventf(backup, corev1.EventTypeNormal, "ReplicationCompleted",
+					"the backup is replicated to backup repo %s", replica.BackupRepoName)
+			case dpv1alpha1.BackupReplicaFailed:
+				r.Recorder.Eventf(backup, corev1.EventTypeWarning, "ReplicationFailed",
+					"failed to replicate the backup to backup repo %s: %s", replica.BackupRepoName, replica.FailureReason)
+			}
+		}
+	}
+	return err
+}
+
+// handleVerification verifies the completed backup by a test restore.
+func (r *BackupReconciler) handleVerification(
+	reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (ctrl.Result, error) {
+	original := backup.DeepCopy()
+	verifier := &dpbackup.Verifier{
+		RequestCtx: reqCtx,
+		Client:     r.Client,
+		Scheme:     r.Scheme,
+		Recorder:   r.Recorder,
+		Backup:     backup,
+	}
+	backupSchedule := &dpv1alpha1.BackupSchedule{}
+	scheduleKey := client.ObjectKey{Namespace: backup.Namespace, Name: backup.Status.Verification.BackupScheduleName}
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client, scheduleKey, backupSchedule)
+	if err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+	if exists {
+		verifier.Policy = backupSchedule.Spec.Verification
+	}
+	if verifier.WorkerServiceAccount, err = EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace, nil); err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+
+	err = verifier.Verify()
+	if !reflect.DeepEqual(original.Status, backup.Status) {
+		if patchErr := r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original)); patchErr != nil {
+			return intctrlutil.RequeueWithError(patchErr, reqCtx.Log, "")
+		}
+	}
+	if err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	}
+	status := backup.Status.Verification
+	switch status.Phase {
+	case dpv1alpha1.BackupVerificationPassed:
+		r.Recorder.Event(backup, corev1.EventTypeNormal, "VerificationPassed", status.Message)
+	case dpv1alpha1.BackupVerificationFailed:
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "VerificationFailed", status.Message)
+	default:
+		// the verification is driven by the owned restores, pod and job, requeue
+		// periodically to check whether the verification is timed out.
+		return intctrlutil.RequeueAfter(verificationCheckInterval, reqCtx.Log, "")
+	}
 	return intctrlutil.Reconciled()
 }
 
@@ -63,6 +63,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
@@ -75,12 +76,18 @@ const (
 	defaultPreCheckTimeout = 15 * time.Minute
 	defaultCheckInterval   = 1 * time.Minute
 
-	preCheckContainerName = "pre-check"
+	preCheckContainerName    = "pre-check"
+	catalogSyncContainerName = "catalog-sync"
+
+	// the max size of the logs of the catalog sync job
+	catalogSyncLogLimit = 64 * 1024 * 1024
 )
 
 var (
 	// for testing
 	wallClock clock.Clock = &clock.RealClock{}
+
+	errUntrustedEncryptionConfig = errors.New("the encryption config of the backup is not trusted")
 )
 
 type reconcileContext struct {
@@ -123,6 +130,10 @@ func (r *reconcileContext) preCheckResourceName() string {
 	return cutName(fmt.Sprintf("pre-check-%s-%s", r.repo.UID[:8], r.repo.Name))
 }
 
+func (r *reconcileContext) catalogSyncResourceName() string {
+	return cutName(fmt.Sprintf("catalog-sync-%s-%s", r.repo.UID[:8], r.repo.Name))
+}
+
 // BackupRepoReconciler reconciles a BackupRepo object
 type BackupRepoReconciler struct {
 	client.Client
@@ -143,8 +154,9 @@ type BackupRepoReconciler struct {
 // create or watch StorageProviders
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=storageproviders,verbs=create;get;list;watch
 
-// watch or update Backups
-// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;update;patch
+// watch or update Backups, and create Backups from the catalog of the repo
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
 
 // watch or update Restores
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=restores,verbs=get;list;watch;update;patch
@@ -286,6 +298,20 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return checkedRequeueWithError(err, reqCtx.Log,
 				"check associated restores failed")
 		}
+
+		// check backups replicated to the repo, to create PVC in their namespaces
+		if err = r.prepareForReplicaBackups(reconCtx); err != nil {
+			return checkedRequeueWithError(err, reqCtx.Log,
+				"check replica backups failed")
+		}
+
+		// recreate the backups from the manifests stored in the repo
+		requeueAfter, err := r.syncCatalog(reconCtx)
+		if err != nil {
+			return checkedRequeueWithError(err, reqCtx.Log,
+				"failed to sync the catalog")
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	return ctrl.Result{}, nil
@@ -1130,6 +1156,336 @@ func (r *BackupRepoReconciler) prepareForAssociatedRestores(reconCtx *reconcileC
 	return retErr
 }
 
+func (r *BackupRepoReconciler) prepareForReplicaBackups(reconCtx *reconcileContext) error {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(reconCtx.Ctx, backupList, client.MatchingLabels{
+		dataProtectionWaitReplicaRepoKey: reconCtx.repo.Name,
+	}, multicluster.InControlContext()); err != nil {
+		return err
+	}
+	// return any error to reconcile the repo
+	var retErr error
+	for idx := range backupList.Items {
+		backup := &backupList.Items[idx]
+		err := r.prepareBackupRepoInNamespace(reconCtx, backup.Namespace)
+		if retErr == nil {
+			retErr = err
+		}
+		if err == nil {
+			patch := client.MergeFrom(backup.DeepCopy())
+			delete(backup.Labels, dataProtectionWaitReplicaRepoKey)
+			if err = r.Client.Patch(reconCtx.Ctx, backup, patch, multicluster.InControlContext()); err != nil {
+				reconCtx.Log.Error(err, "failed to patch backup",
+					"backup", client.ObjectKeyFromObject(backup))
+				retErr = err
+			}
+		}
+	}
+	return retErr
+}
+
+// syncCatalog recreates the Backups from the manifests stored in the repo if the catalog
+// sync is enabled. The repo is scanned by a job in the namespace of the controller, and
+// the manifests are collected from the logs of the job. It returns the duration after
+// which the next synchronization should be run.
+func (r *BackupRepoReconciler) syncCatalog(reconCtx *reconcileContext) (time.Duration, error) {
+	repo := reconCtx.repo
+	namespace := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	job := &batchv1.Job{}
+	jobKey := client.ObjectKey{Namespace: namespace, Name: reconCtx.catalogSyncResourceName()}
+	exists := true
+	if err := r.Client.Get(reconCtx.Ctx, jobKey, job, multicluster.InControlContext()); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		exists = false
+	}
+	if repo.Spec.CatalogSync == nil || !repo.Spec.CatalogSync.Enabled {
+		if exists {
+			return 0, intctrlutil.BackgroundDeleteObject(r.Client, reconCtx.Ctx, job, multicluster.InControlContext())
+		}
+		return 0, nil
+	}
+
+	original := repo.DeepCopy()
+	now := metav1.Time{Time: wallClock.Now()}
+	if !exists {
+		due, requeueAfter := isCatalogSyncDue(repo, now.Time)
+		if !due {
+			return requeueAfter, nil
+		}
+		if err := r.createCatalogSyncJob(reconCtx, jobKey); err != nil {
+			return 0, err
+		}
+		repo.Status.CatalogSync = &dpv1alpha1.BackupRepoCatalogSyncStatus{
+			Phase:              dpv1alpha1.CatalogSyncRunning,
+			ObservedGeneration: repo.Generation,
+			StartTimestamp:     &now,
+		}
+		return 0, r.Client.Status().Patch(reconCtx.Ctx, repo, client.MergeFrom(original))
+	}
+
+	_, finishedType, failureReason := utils.IsJobFinished(job)
+	if finishedType == "" {
+		return 0, nil
+	}
+	if repo.Status.CatalogSync == nil {
+		repo.Status.CatalogSync = &dpv1alpha1.BackupRepoCatalogSyncStatus{ObservedGeneration: repo.Generation}
+	}
+	status := repo.Status.CatalogSync
+	status.CompletionTimestamp = &now
+	if finishedType == batchv1.JobFailed {
+		status.Phase = dpv1alpha1.CatalogSyncFailed
+		status.Message = fmt.Sprintf("catalog sync job failed: %s", failureReason)
+	} else if err := r.importCatalog(reconCtx, job, status); err != nil {
+		return 0, err
+	}
+	if err := r.Client.Status().Patch(reconCtx.Ctx, repo, client.MergeFrom(original)); err != nil {
+		return 0, err
+	}
+	if err := intctrlutil.BackgroundDeleteObject(r.Client, reconCtx.Ctx, job, multicluster.InControlContext()); err != nil {
+		return 0, err
+	}
+	_, requeueAfter := isCatalogSyncDue(repo, now.Time)
+	return requeueAfter, nil
+}
+
+// isCatalogSyncDue checks if the catalog of the repo should be synchronized now,
+// otherwise returns the duration after which the next synchronization is due.
+func isCatalogSyncDue(repo *dpv1alpha1.BackupRepo, now time.Time) (bool, time.Duration) {
+	status := repo.Status.CatalogSync
+	if status == nil || status.ObservedGeneration != repo.Generation || status.CompletionTimestamp == nil {
+		return true, 0
+	}
+	interval := repo.Spec.CatalogSync.Interval
+	if interval == nil || interval.Duration <= 0 {
+		return false, 0
+	}
+	next := status.CompletionTimestamp.Add(interval.Duration)
+	if !now.Before(next) {
+		return true, 0
+	}
+	return false, next.Sub(now)
+}
+
+func (r *BackupRepoReconciler) createCatalogSyncJob(reconCtx *reconcileContext, jobKey client.ObjectKey) error {
+	repo := reconCtx.repo
+	saName, err := EnsureWorkerServiceAccount(reconCtx.RequestCtx, r.Client, jobKey.Namespace, r.MultiClusterMgr)
+	if err != nil {
+		return err
+	}
+	// prepare the PVC or the tool config secret of the repo in the namespace of the job
+	switch {
+	case repo.AccessByMount():
+		_, err = r.createRepoPVC(reconCtx, repo.Status.BackupPVCName, jobKey.Namespace, nil, multicluster.InControlContext())
+	case repo.AccessByTool():
+		_, err = r.createToolConfigSecret(reconCtx, repo.Status.ToolConfigSecretName, jobKey.Namespace, nil, multicluster.InControlContext())
+	default:
+		err = fmt.Errorf("unknown access method: %s", repo.Spec.AccessMethod)
+	}
+	if err != nil {
+		return err
+	}
+
+	runAsUser := int64(0)
+	container := corev1.Container{
+		Name:            catalogSyncContainerName,
+		Image:           viper.GetString(constant.KBToolsImage),
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		Command:         []string{"sh", "-c", dpbackup.BuildScanManifestsScript(filepath.Join("/", repo.Spec.PathPrefix))},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolptr.False(),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+	job := &batchv1.Job{}
+	job.Name = jobKey.Name
+	job.Namespace = jobKey.Namespace
+	_, err = createObjectIfNotExist(reconCtx.Ctx, r.Client, job, func() error {
+		job.Spec = batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers:         []corev1.Container{container},
+					ServiceAccountName: saName,
+				},
+			},
+			BackoffLimit: pointer.Int32(2),
+		}
+		job.Labels = map[string]string{
+			dataProtectionBackupRepoKey: repo.Name,
+		}
+		if err := utils.AddTolerations(&job.Spec.Template.Spec); err != nil {
+			return err
+		}
+		utils.InjectDatasafed(&job.Spec.Template.Spec, repo, dpbackup.RepoVolumeMountPath, nil, "")
+		return controllerutil.SetControllerReference(repo, job, r.Scheme)
+	}, multicluster.InControlContext())
+	return err
+}
+
+// importCatalog recreates the Backups from the manifests output by the catalog sync job,
+// and records the result in the status.
+func (r *BackupRepoReconciler) importCatalog(reconCtx *reconcileContext,
+	job *batchv1.Job, status *dpv1alpha1.BackupRepoCatalogSyncStatus) error {
+	entries, parseErrs, err := r.readCatalog(reconCtx, job)
+	if errors.Is(err, dpbackup.ErrCatalogTruncated) {
+		status.Phase = dpv1alpha1.CatalogSyncFailed
+		status.Message = err.Error()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var (
+		imported         int32
+		notAllowed       int
+		skippedNS        []string
+		untrustedBackups []string
+		failedBackups    []string
+	)
+	sync := reconCtx.repo.Spec.CatalogSync
+	for _, entry := range entries {
+		m := entry.Manifest
+		// only the backups of the allowed namespaces are recreated.
+		if !slices.Contains(sync.Namespaces, m.Namespace) {
+			notAllowed++
+			continue
+		}
+		created, err := r.importBackup(reconCtx, &entry)
+		switch {
+		case apierrors.IsNotFound(err):
+			// the namespace of the backup does not exist
+			if !slices.Contains(skippedNS, m.Namespace) {
+				skippedNS = append(skippedNS, m.Namespace)
+			}
+		case errors.Is(err, errUntrustedEncryptionConfig):
+			untrustedBackups = append(untrustedBackups, fmt.Sprintf("%s/%s", m.Namespace, m.Name))
+		case err != nil:
+			reconCtx.Log.Error(err, "failed to recreate backup from the manifest", "manifest", entry.Path)
+			failedBackups = append(failedBackups, fmt.Sprintf("%s/%s", m.Namespace, m.Name))
+		case created:
+			imported++
+		}
+	}
+
+	status.Phase = dpv1alpha1.CatalogSyncSucceeded
+	status.Manifests = int32(len(entries))
+	status.ImportedBackups = imported
+	var messages []string
+	if len(parseErrs) > 0 {
+		messages = append(messages, fmt.Sprintf("%d invalid manifests are skipped, the first error: %s",
+			len(parseErrs), parseErrs[0].Error()))
+	}
+	if notAllowed > 0 {
+		messages = append(messages, fmt.Sprintf("%d backups are skipped since the namespaces are not allowed", notAllowed))
+	}
+	if len(skippedNS) > 0 {
+		messages = append(messages, fmt.Sprintf("the backups are skipped since the namespaces do not exist: %s",
+			strings.Join(skippedNS, ", ")))
+	}
+	if len(untrustedBackups) > 0 {
+		messages = append(messages, fmt.Sprintf("the encrypted backups are skipped since their BackupPolicies "+
+			"do not use the same encryption key: %s", strings.Join(untrustedBackups, ", ")))
+	}
+	if len(failedBackups) > 0 {
+		status.Phase = dpv1alpha1.CatalogSyncFailed
+		messages = append(messages, fmt.Sprintf("failed to recreate the backups: %s", strings.Join(failedBackups, ", ")))
+	}
+	status.Message = strings.Join(messages, "; ")
+	return nil
+}
+
+// importBackup recreates the Backup described by the manifest if it does not exist,
+// and returns true if the Backup is recreated.
+func (r *BackupRepoReconciler) importBackup(reconCtx *reconcileContext, entry *dpbackup.CatalogEntry) (bool, error) {
+	repo := reconCtx.repo
+	target := entry.Manifest.ToBackup(repo, entry.Path)
+	for _, key := range []string{dataProtectionWaitRepoPreparationKey, dataProtectionWaitReplicaRepoKey} {
+		delete(target.Labels, key)
+	}
+	target.Labels[dataProtectionBackupRepoKey] = repo.Name
+	if err := r.checkCatalogEncryptionConfig(reconCtx, target); err != nil {
+		return false, err
+	}
+
+	backup := &dpv1alpha1.Backup{}
+	exists, err := intctrlutil.CheckResourceExists(reconCtx.Ctx, r.Client, client.ObjectKeyFromObject(target), backup)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		// restore the status of the backup recreated by the last synchronization if it failed to.
+		if backup.Annotations[dptypes.CatalogBackupRepoAnnotationKey] != repo.Name || backup.Status.Phase != "" {
+			return false, nil
+		}
+	} else {
+		backup = target.DeepCopy()
+		backup.Status = dpv1alpha1.BackupStatus{}
+		if err = r.Client.Create(reconCtx.Ctx, backup); err != nil {
+			return false, err
+		}
+	}
+	backup.Status = target.Status
+	if err = r.Client.Status().Update(reconCtx.Ctx, backup); err != nil {
+		return false, err
+	}
+	reconCtx.Log.Info("recreated backup from the manifest", "backup", client.ObjectKeyFromObject(backup),
+		"manifest", entry.Path)
+	return true, nil
+}
+
+// checkCatalogEncryptionConfig checks if the encryption config of the Backup recreated from the catalog
+// can be trusted, that is, the BackupPolicy of the Backup uses the same encryption key. Otherwise, the
+// manifest could make the restores read any secret in the namespace as the encryption key.
+func (r *BackupRepoReconciler) checkCatalogEncryptionConfig(reconCtx *reconcileContext, backup *dpv1alpha1.Backup) error {
+	encryptionConfig := backup.Status.EncryptionConfig
+	if encryptionConfig == nil {
+		return nil
+	}
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	exists, err := intctrlutil.CheckResourceExists(reconCtx.Ctx, r.Client,
+		client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.BackupPolicyName}, backupPolicy)
+	if err != nil {
+		return err
+	}
+	if !exists || backupPolicy.Spec.EncryptionConfig == nil ||
+		!reflect.DeepEqual(backupPolicy.Spec.EncryptionConfig.PassPhraseSecretKeyRef, encryptionConfig.PassPhraseSecretKeyRef) {
+		return errUntrustedEncryptionConfig
+	}
+	return nil
+}
+
+// readCatalog reads the manifests from the logs of the succeeded pod of the catalog sync job.
+func (r *BackupRepoReconciler) readCatalog(reconCtx *reconcileContext,
+	job *batchv1.Job) ([]dpbackup.CatalogEntry, []error, error) {
+	podList, err := utils.GetAssociatedPodsOfJob(reconCtx.Ctx, r.Client, job.Namespace, job.Name,
+		multicluster.InControlContext())
+	if err != nil {
+		return nil, nil, err
+	}
+	typedCli, err := corev1client.NewForConfig(r.RestConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		req := typedCli.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: catalogSyncContainerName,
+		})
+		stream, err := req.Stream(reconCtx.Ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer stream.Close()
+		return dpbackup.ParseCatalogOutput(io.LimitReader(stream, catalogSyncLogLimit))
+	}
+	return nil, nil, fmt.Errorf("no succeeded pod is found for the catalog sync job %s", job.Name)
+}
+
 func (r *BackupRepoReconciler) createRepoPVC(reconCtx *reconcileContext,
 	name, namespace string, extraAnnos map[string]string, mcOpt *multicluster.ClientOption) (*corev1.PersistentVolumeClaim, error) {
 
@@ -1422,14 +1778,21 @@ func (r *BackupRepoReconciler) deleteSecrets(reqCtx intctrlutil.RequestCtx, repo
 
 func (r *BackupRepoReconciler) mapBackupToRepo(ctx context.Context, obj client.Object) []ctrl.Request {
 	backup := obj.(*dpv1alpha1.Backup)
+	var requests []ctrl.Request
+	// the Backup is replicated to the BackupRepo, but it's not ready for the namespace.
+	if replicaRepoName := backup.Labels[dataProtectionWaitReplicaRepoKey]; replicaRepoName != "" {
+		requests = append(requests, ctrl.Request{
+			NamespacedName: client.ObjectKey{Name: replicaRepoName},
+		})
+	}
 	repoName, ok := backup.Labels[dataProtectionBackupRepoKey]
 	if !ok {
-		return nil
+		return requests
 	}
 	// ignore failed backups
 	if backup.Status.Phase == dpv1alpha1.BackupPhaseFailed &&
 		backup.Labels[dptypes.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous) {
-		return nil
+		return requests
 	}
 	// we should reconcile the BackupRepo when:
 	//   1. the Backup needs to use the BackupRepo, but it's not ready for the namespace.
@@ -1437,11 +1800,11 @@ func (r *BackupRepoReconciler) mapBackupToRepo(ctx context.Context, obj client.O
 	shouldReconcileRepo := backup.Labels[dataProtectionWaitRepoPreparationKey] == trueVal ||
 		!backup.DeletionTimestamp.IsZero()
 	if shouldReconcileRepo {
-		return []ctrl.Request{{
+		requests = append(requests, ctrl.Request{
 			NamespacedName: client.ObjectKey{Name: repoName},
-		}}
+		})
 	}
-	return nil
+	return requests
 }
 
 func (r *BackupRepoReconciler) mapRestoreToRepo(ctx context.Context, obj client.Object) []ctrl.Request {
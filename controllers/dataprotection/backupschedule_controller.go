@@ -22,6 +22,7 @@ package dataprotection
 import (
 	"context"
 	"reflect"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
@@ -47,12 +48,18 @@ type BackupScheduleReconciler struct {
 	client.Client
 	Scheme   *k8sruntime.Scheme
 	Recorder record.EventRecorder
+
+	// limiter limits the concurrency of the backups created by the built-in scheduler.
+	limiter *dpbackup.BackupConcurrencyLimiter
 }
 
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuprepos,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=cronjobs/status,verbs=get
 // +kubebuilder:rbac:groups=batch,resources=cronjobs/finalizers,verbs=update;patch
@@ -84,18 +91,27 @@ func (r *BackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return *res, err
 	}
 
-	if err = r.handleSchedule(reqCtx, backupSchedule); err != nil {
+	requeueAfter, err := r.handleSchedule(reqCtx, backupSchedule)
+	if err != nil {
 		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeRequeue) {
 			return intctrlutil.RequeueAfter(reconcileInterval, reqCtx.Log, "")
 		}
 		return r.patchStatusFailed(reqCtx, backupSchedule, "HandleBackupScheduleFailed", err)
 	}
 
-	return r.patchStatusAvailable(reqCtx, original, backupSchedule)
+	result, err := r.patchStatusAvailable(reqCtx, original, backupSchedule)
+	if err != nil || requeueAfter == 0 {
+		return result, err
+	}
+	// requeue to start the next backup or verification in time.
+	return intctrlutil.RequeueAfter(requeueAfter, reqCtx.Log, "")
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.limiter == nil {
+		r.limiter = dpbackup.NewBackupConcurrencyLimiter()
+	}
 	b := intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&dpv1alpha1.BackupSchedule{})
 
@@ -176,21 +192,23 @@ func (r *BackupScheduleReconciler) patchStatusFailed(reqCtx intctrlutil.RequestC
 	return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 }
 
-// handleSchedule handles backup schedules for different backup method.
+// handleSchedule handles backup schedules for different backup method, and returns
+// the duration until the next backup scheduled by the built-in scheduler or the next
+// backup verification, whichever comes first.
 func (r *BackupScheduleReconciler) handleSchedule(
 	reqCtx intctrlutil.RequestCtx,
-	backupSchedule *dpv1alpha1.BackupSchedule) error {
+	backupSchedule *dpv1alpha1.BackupSchedule) (time.Duration, error) {
 	backupPolicy, err := dputils.GetBackupPolicyByName(reqCtx, r.Client, backupSchedule.Spec.BackupPolicyName)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if err = r.patchScheduleMetadata(reqCtx, backupSchedule); err != nil {
-		return err
+		return 0, err
 	}
 	// TODO: update the mcMgr param
 	saName, err := EnsureWorkerServiceAccount(reqCtx, r.Client, backupSchedule.Namespace, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	scheduler := dpbackup.Scheduler{
 		RequestCtx:           reqCtx,
@@ -199,8 +217,20 @@ func (r *BackupScheduleReconciler) handleSchedule(
 		Client:               r.Client,
 		Scheme:               r.Scheme,
 		WorkerServiceAccount: saName,
+		Limiter:              r.limiter,
+	}
+	nextBackup, err := scheduler.Schedule()
+	if err != nil {
+		return 0, err
+	}
+	nextVerification, err := scheduler.ScheduleVerification()
+	if err != nil {
+		return 0, err
+	}
+	if nextBackup == 0 || (nextVerification > 0 && nextVerification < nextBackup) {
+		return nextVerification, nil
 	}
-	return scheduler.Schedule()
+	return nextBackup, nil
 }
 
 func (r *BackupScheduleReconciler) patchScheduleMetadata(
@@ -219,9 +249,10 @@ func (r *BackupScheduleReconciler) patchScheduleMetadata(
 
 func (r *BackupScheduleReconciler) parseBackup(ctx context.Context, object client.Object) []reconcile.Request {
 	backup := object.(*dpv1alpha1.Backup)
-	backupScheduleName := dptypes.BackupScheduleLabelKey
-	if backup.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) &&
-		backupScheduleName != "" {
+	backupScheduleName := backup.Labels[dptypes.BackupScheduleLabelKey]
+	// the built-in scheduler records the completion time of the scheduled backups.
+	if (backup.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) ||
+		dpbackup.IsBuiltInSchedulerEnabled()) && backupScheduleName != "" {
 		return []reconcile.Request{
 			{
 				NamespacedName: types.NamespacedName{
@@ -21,10 +21,12 @@ package dataprotection
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -35,6 +37,7 @@ import (
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -43,17 +46,21 @@ import (
 // GCReconciler garbage collection reconciler, which periodically deletes expired backups.
 type GCReconciler struct {
 	client.Client
-	Recorder  record.EventRecorder
-	clock     clock.WithTickerAndDelayedExecution
-	frequency time.Duration
+	Recorder    record.EventRecorder
+	clock       clock.WithTickerAndDelayedExecution
+	frequency   time.Duration
+	evaluations *dpbackup.RetentionEvaluations
 }
 
 func NewGCReconciler(mgr ctrl.Manager) *GCReconciler {
+	frequency := getGCFrequency()
 	return &GCReconciler{
 		Client:    mgr.GetClient(),
 		Recorder:  mgr.GetEventRecorderFor("gc-controller"),
 		clock:     clock.RealClock{},
-		frequency: getGCFrequency(),
+		frequency: frequency,
+		// all the backups are enqueued at once in a GC cycle, reuse the evaluations of the retention policies in the cycle.
+		evaluations: &dpbackup.RetentionEvaluations{TTL: frequency / 2},
 	}
 }
 
@@ -69,7 +76,9 @@ func (r *GCReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;delete
-// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies,verbs=get
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // delete expired backups.
@@ -101,6 +110,17 @@ func (r *GCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	reqCtx.Log = reqCtx.Log.WithValues("expiration", backup.Status.Expiration)
 
 	now := r.clock.Now()
+	retentionPolicy, err := r.getRetentionPolicy(reqCtx, backup)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if retentionPolicy != nil {
+		return r.applyRetentionPolicy(reqCtx, backup, retentionPolicy, now)
+	}
+	if backup.Status.Retention != nil {
+		return r.releaseRetention(reqCtx, backup, now)
+	}
+
 	if backup.Status.Expiration == nil || backup.Status.Expiration.After(now) {
 		reqCtx.Log.V(1).Info("backup is not expired yet, skipping")
 		return intctrlutil.Reconciled()
@@ -116,6 +136,179 @@ func (r *GCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	return intctrlutil.Reconciled()
 }
 
+// getRetentionPolicy gets the tiered retention policy that governs the backup.
+// The policy of the schedule policy takes precedence over the one of the backup policy.
+// Returns nil if the backup is not a completed scheduled backup, or no retention policy
+// is specified.
+func (r *GCReconciler) getRetentionPolicy(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (*dpv1alpha1.BackupRetentionPolicy, error) {
+	if !isRetentionGoverned(backup) {
+		return nil, nil
+	}
+	if scheduleName := backup.Labels[dptypes.BackupScheduleLabelKey]; scheduleName != "" {
+		backupSchedule := &dpv1alpha1.BackupSchedule{}
+		exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client,
+			client.ObjectKey{Namespace: backup.Namespace, Name: scheduleName}, backupSchedule)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			schedulePolicy := dpbackup.GetSchedulePolicyByMethod(backupSchedule, backup.Spec.BackupMethod)
+			if schedulePolicy != nil && schedulePolicy.Retention != nil {
+				return schedulePolicy.Retention, nil
+			}
+		}
+	}
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client,
+		client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.BackupPolicyName}, backupPolicy)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return backupPolicy.Spec.Retention, nil
+}
+
+// applyRetentionPolicy evaluates the tiered retention policy on the scheduled backups
+// of the same backup policy and backup method, records the decision on the backup,
+// and deletes the backup if it is expired. The policy is evaluated once for all the
+// backups it governs in a GC cycle, and the expiration of a retained backup is cleared,
+// as it is governed by the policy instead.
+func (r *GCReconciler) applyRetentionPolicy(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup,
+	retentionPolicy *dpv1alpha1.BackupRetentionPolicy,
+	now time.Time) (ctrl.Result, error) {
+	key := strings.Join([]string{backup.Namespace, backup.Spec.BackupPolicyName, backup.Spec.BackupMethod}, "/")
+	statuses, err := r.evaluations.Get(key, now, func() (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+		return r.evaluateRetentionPolicy(reqCtx, backup, retentionPolicy, now)
+	})
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			r.Recorder.Event(backup, corev1.EventTypeWarning, "InvalidRetentionPolicy", err.Error())
+			return intctrlutil.Reconciled()
+		}
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	status, ok := statuses[backup.Name]
+	if !ok {
+		return intctrlutil.Reconciled()
+	}
+	status = status.DeepCopy()
+
+	if status.Decision == dpv1alpha1.BackupExpired {
+		// the evaluation may be stale, do not delete the backup that is still the parent of others.
+		children, err := r.listChildBackups(reqCtx, backup)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if len(children) > 0 {
+			reqCtx.Log.Info("backup is expired but still the parent of other backups, skipping", "children", children)
+			r.evaluations.Invalidate(key)
+			return intctrlutil.Reconciled()
+		}
+	}
+
+	patch := client.MergeFrom(backup.DeepCopy())
+	changed := dpbackup.UpdateRetentionStatus(backup, status, now)
+	expired := status.Decision == dpv1alpha1.BackupExpired
+	switch {
+	case expired && (backup.Status.Expiration == nil || backup.Status.Expiration.After(now)):
+		backup.Status.Expiration = &metav1.Time{Time: now}
+		changed = true
+	case !expired && backup.Status.Expiration != nil:
+		backup.Status.Expiration = nil
+		changed = true
+	}
+	if changed {
+		if err = r.Status().Patch(reqCtx.Ctx, backup, patch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+	if !expired {
+		reqCtx.Log.V(1).Info("backup is retained by the retention policy, skipping", "message", status.Message)
+		return intctrlutil.Reconciled()
+	}
+
+	reqCtx.Log.Info("backup is expired by the retention policy, delete it", "message", status.Message)
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "BackupExpired", status.Message)
+	if err = intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, backup); err != nil {
+		reqCtx.Log.Error(err, "failed to delete backup")
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "RemoveExpiredBackupsFailed", err.Error())
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// listChildBackups returns the names of the backups that are based on the backup and not being deleted.
+func (r *GCReconciler) listChildBackups(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) ([]string, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.List(reqCtx.Ctx, backupList, client.InNamespace(backup.Namespace)); err != nil {
+		return nil, err
+	}
+	var children []string
+	for _, b := range backupList.Items {
+		if b.Spec.ParentBackupName == backup.Name && b.DeletionTimestamp.IsZero() {
+			children = append(children, b.Name)
+		}
+	}
+	return children, nil
+}
+
+// evaluateRetentionPolicy evaluates the tiered retention policy on the scheduled backups of the same
+// backup policy and backup method as the backup.
+func (r *GCReconciler) evaluateRetentionPolicy(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup,
+	retentionPolicy *dpv1alpha1.BackupRetentionPolicy,
+	now time.Time) (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.List(reqCtx.Ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return nil, err
+	}
+	evaluator := &dpbackup.RetentionEvaluator{
+		Policy: retentionPolicy,
+		Now:    now,
+	}
+	for i := range backupList.Items {
+		b := &backupList.Items[i]
+		if isRetentionGoverned(b) && b.Spec.BackupMethod == backup.Spec.BackupMethod {
+			evaluator.Backups = append(evaluator.Backups, b)
+		} else {
+			evaluator.Dependents = append(evaluator.Dependents, b)
+		}
+	}
+	statuses, err := evaluator.Evaluate()
+	if err != nil {
+		return nil, intctrlutil.NewFatalError(err.Error())
+	}
+	return statuses, nil
+}
+
+// releaseRetention releases the backup that was governed by a retention policy which is removed,
+// the retention status is removed and the backup expires after the retention period from now on,
+// instead of being deleted at once by a stale expiration.
+func (r *GCReconciler) releaseRetention(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup, now time.Time) (ctrl.Result, error) {
+	patch := client.MergeFrom(backup.DeepCopy())
+	backup.Status.Retention = nil
+	backup.Status.Expiration = nil
+	if duration, err := backup.Spec.RetentionPeriod.ToDuration(); err == nil && duration > 0 {
+		backup.Status.Expiration = &metav1.Time{Time: now.Add(duration)}
+	}
+	reqCtx.Log.Info("the retention policy of the backup is removed, release it", "expiration", backup.Status.Expiration)
+	if err := r.Status().Patch(reqCtx.Ctx, backup, patch); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// isRetentionGoverned checks if the backup is a completed scheduled backup that
+// can be governed by a tiered retention policy.
+func isRetentionGoverned(backup *dpv1alpha1.Backup) bool {
+	return backup.DeletionTimestamp.IsZero() &&
+		backup.Status.Phase == dpv1alpha1.BackupPhaseCompleted &&
+		backup.Labels[dptypes.AutoBackupLabelKey] == "true" &&
+		backup.Labels[dptypes.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous)
+}
+
 func getGCFrequency() time.Duration {
 	gcFrequencySeconds := viper.GetInt(dptypes.CfgKeyGCFrequencySeconds)
 	if gcFrequencySeconds > 0 {
@@ -152,8 +152,15 @@ func CheckBackupRepoForRestore(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		return "", nil
 	}
 
-	restoreNamespace := restore.Namespace
-	repoName := backup.Status.BackupRepoName
+	repoName := restore.Spec.Backup.BackupRepoName
+	if repoName != "" {
+		if backup.Status.BackupRepoName != repoName && !hasCompletedReplica(backup, repoName) {
+			return "", intctrlutil.NewFatalError(fmt.Sprintf(`backup "%s" has no completed replica in backup repo %s`,
+				backupName, repoName))
+		}
+	} else {
+		repoName = selectBackupRepoForRestore(reqCtx, cli, backup)
+	}
 	repo := &dpv1alpha1.BackupRepo{}
 	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: repoName}, repo); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -161,40 +168,37 @@ func CheckBackupRepoForRestore(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		}
 		return repoName, err
 	}
-	if repo.Status.Phase != dpv1alpha1.BackupRepoReady {
-		return repoName, dperrors.NewBackupRepoIsNotReady(repo.Name)
-	}
-	switch {
-	case repo.AccessByMount():
-		pvcName := repo.Status.BackupPVCName
-		if pvcName == "" {
-			return repoName, intctrlutil.NewFatalError(fmt.Sprintf("BackupPVCName is empty in BackupRepo %s", repoName))
-		}
-		pvc := &corev1.PersistentVolumeClaim{}
-		pvcKey := client.ObjectKey{Namespace: restoreNamespace, Name: pvcName}
-		if err := cli.Get(reqCtx.Ctx, pvcKey, pvc); err != nil {
-			if apierrors.IsNotFound(err) {
-				return repoName, intctrlutil.NewErrorf(dperrors.ErrorTypeWaitForBackupRepoPreparation,
-					"backup repo %s is not ready in the namespace %s", repoName, restoreNamespace)
-			}
-			return repoName, err
-		}
-	case repo.AccessByTool():
-		toolConfigSecretName := repo.Status.ToolConfigSecretName
-		if toolConfigSecretName == "" {
-			return repoName, intctrlutil.NewFatalError(fmt.Sprintf("ToolConfigSecretName is empty in BackupRepo %s", repoName))
+	return repoName, utils.CheckBackupRepoInNamespace(reqCtx.Ctx, cli, repo, restore.Namespace)
+}
+
+// selectBackupRepoForRestore selects the backup repo to read the backup data from.
+// The backup repo where the backup is stored is preferred, and the first ready backup repo
+// with a completed replica is used if it's unavailable.
+func selectBackupRepoForRestore(reqCtx intctrlutil.RequestCtx, cli client.Client, backup *dpv1alpha1.Backup) string {
+	isRepoReady := func(repoName string) bool {
+		repo := &dpv1alpha1.BackupRepo{}
+		if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: repoName}, repo); err != nil {
+			return false
 		}
-		secret := &corev1.Secret{}
-		secretKey := client.ObjectKey{Namespace: restoreNamespace, Name: toolConfigSecretName}
-		if err := cli.Get(reqCtx.Ctx, secretKey, secret); err != nil {
-			if apierrors.IsNotFound(err) {
-				return repoName, intctrlutil.NewErrorf(dperrors.ErrorTypeWaitForBackupRepoPreparation,
-					"backup repo %s is not ready in the namespace %s", repoName, restoreNamespace)
-			}
-			return repoName, err
+		return repo.Status.Phase == dpv1alpha1.BackupRepoReady
+	}
+	primary := backup.Status.BackupRepoName
+	if isRepoReady(primary) {
+		return primary
+	}
+	for _, replica := range backup.Status.Replicas {
+		if replica.Phase == dpv1alpha1.BackupReplicaCompleted && isRepoReady(replica.BackupRepoName) {
+			reqCtx.Log.Info("backup repo is unavailable, restore from the replica",
+				"backupRepo", primary, "replicaBackupRepo", replica.BackupRepoName)
+			return replica.BackupRepoName
 		}
 	}
-	return repoName, nil
+	return primary
+}
+
+func hasCompletedReplica(backup *dpv1alpha1.Backup, repoName string) bool {
+	replica := backup.GetReplica(repoName)
+	return replica != nil && replica.Phase == dpv1alpha1.BackupReplicaCompleted
 }
 
 func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dpv1alpha1.Restore) (ctrl.Result, error) {
@@ -229,6 +233,7 @@ func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dp
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	default:
 		dprestore.SetRestoreCheckBackupRepoCondition(restore, dprestore.ReasonCheckBackupRepoSuccessfully, "")
+		restore.Status.BackupRepoName = repoName
 	}
 	if !reflect.DeepEqual(restore.ObjectMeta, oldRestore.ObjectMeta) {
 		if err := r.Client.Patch(reqCtx.Ctx, restore, patch); err != nil {
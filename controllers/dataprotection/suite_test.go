@@ -48,6 +48,7 @@ import (
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/testutil"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -247,5 +248,7 @@ func mockGCReconciler(mgr ctrl.Manager) *GCReconciler {
 		Recorder:  mgr.GetEventRecorderFor("gc-controller"),
 		clock:     fakeClock,
 		frequency: time.Duration(1) * time.Second,
+		// the fake clock is stepped by the tests, do not reuse the evaluations of the retention policies.
+		evaluations: &dpbackup.RetentionEvaluations{},
 	}
 }
@@ -23,6 +23,8 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 )
 
 const (
@@ -32,8 +34,9 @@ const (
 const (
 
 	// label keys
-	dataProtectionBackupRepoKey          = "dataprotection.kubeblocks.io/backup-repo-name"
+	dataProtectionBackupRepoKey          = dptypes.BackupRepoLabelKey
 	dataProtectionWaitRepoPreparationKey = "dataprotection.kubeblocks.io/wait-repo-preparation"
+	dataProtectionWaitReplicaRepoKey     = "dataprotection.kubeblocks.io/wait-replica-repo-preparation"
 	dataProtectionIsToolConfigKey        = "dataprotection.kubeblocks.io/is-tool-config"
 
 	// annotation keys
@@ -95,3 +98,9 @@ const (
 )
 
 var reconcileInterval = time.Second
+
+var (
+	verificationCheckInterval = 30 * time.Second
+	replicationCheckInterval  = 30 * time.Second
+	manifestCheckInterval     = 30 * time.Second
+)
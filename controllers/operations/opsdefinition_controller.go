@@ -21,9 +21,11 @@ package operations
 
 import (
 	"context"
+	"fmt"
 	"text/template"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,6 +33,7 @@ import (
 
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/operations/custom"
 )
 
 // OpsDefinitionReconciler reconciles a OpsDefinition object
@@ -81,6 +84,22 @@ func (r *OpsDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// compile the CEL expressions.
+	if err = r.checkCELRules(opsDef); err != nil {
+		if patchErr := r.updateStatusUnavailable(reqCtx, opsDef, err); patchErr != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return intctrlutil.Reconciled()
+	}
+
+	// check the expressions and the references of the actions.
+	if err = r.checkActions(opsDef); err != nil {
+		if patchErr := r.updateStatusUnavailable(reqCtx, opsDef, err); patchErr != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return intctrlutil.Reconciled()
+	}
+
 	// TODO: check serviceKind, connectionCredentialName and serviceName
 	statusPatch := client.MergeFrom(opsDef.DeepCopy())
 	opsDef.Status.ObservedGeneration = opsDef.Generation
@@ -92,6 +111,52 @@ func (r *OpsDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return intctrlutil.Reconciled()
 }
 
+// checkCELRules compiles the CEL expressions of the preConditions and the parameters rules.
+func (r *OpsDefinitionReconciler) checkCELRules(opsDef *opsv1alpha1.OpsDefinition) error {
+	for _, v := range opsDef.Spec.PreConditions {
+		if v.CELRule == nil {
+			continue
+		}
+		if _, err := custom.CompilePreConditionCELExpression(v.CELRule.Expression); err != nil {
+			return fmt.Errorf(`invalid CEL expression of the preCondition "%s": %s`, v.CELRule.Expression, err.Error())
+		}
+	}
+	if opsDef.Spec.ParametersSchema == nil {
+		return nil
+	}
+	for _, rule := range opsDef.Spec.ParametersSchema.Rules {
+		if _, err := custom.CompileParametersCELExpression(rule.Expression); err != nil {
+			return fmt.Errorf(`invalid CEL expression of the parameters rule "%s": %s`, rule.Expression, err.Error())
+		}
+	}
+	return nil
+}
+
+// checkActions checks the go templates of the actions, and the parametersFrom of an action can only reference the earlier actions.
+func (r *OpsDefinitionReconciler) checkActions(opsDef *opsv1alpha1.OpsDefinition) error {
+	earlierActions := sets.New[string]()
+	for _, action := range opsDef.Spec.Actions {
+		if action.When != "" {
+			if _, err := template.New("opsDefTemplate").Parse(action.When); err != nil {
+				return fmt.Errorf(`invalid "when" expression of the action "%s": %s`, action.Name, err.Error())
+			}
+		}
+		if action.WaitFor != nil {
+			if _, err := template.New("opsDefTemplate").Parse(action.WaitFor.Expression); err != nil {
+				return fmt.Errorf(`invalid "waitFor" expression of the action "%s": %s`, action.Name, err.Error())
+			}
+		}
+		for _, ref := range action.ParametersFrom {
+			if !earlierActions.Has(ref.ActionName) {
+				return fmt.Errorf(`the parameter "%s" of the action "%s" references the action "%s" which is not an earlier action`,
+					ref.Name, action.Name, ref.ActionName)
+			}
+		}
+		earlierActions.Insert(action.Name)
+	}
+	return nil
+}
+
 func (r *OpsDefinitionReconciler) updateStatusUnavailable(reqCtx intctrlutil.RequestCtx, opsDef *opsv1alpha1.OpsDefinition, err error) error {
 	statusPatch := client.MergeFrom(opsDef.DeepCopy())
 	opsDef.Status.Phase = opsv1alpha1.UnavailablePhase
@@ -61,6 +61,46 @@ var _ = Describe("OpsDefinition Controller", func() {
 			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(g Gomega, opsD *opsv1alpha1.OpsDefinition) {
 				g.Expect(opsD.Status.Phase).Should(Equal(opsv1alpha1.AvailablePhase))
 			}))
+
+			By("reference the output of a later action")
+			Expect(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(opsD *opsv1alpha1.OpsDefinition) {
+				opsD.Spec.Actions[0].ParametersFrom = []opsv1alpha1.OpsActionOutputRef{
+					{Name: "output", ActionName: "check"},
+				}
+				opsD.Spec.Actions = append(opsD.Spec.Actions, opsv1alpha1.OpsAction{
+					Name:    "check",
+					WaitFor: &opsv1alpha1.OpsWaitForAction{Expression: `{{ eq .component.status.phase "Running" }}`},
+				})
+			})()).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(g Gomega, opsD *opsv1alpha1.OpsDefinition) {
+				g.Expect(opsD.Status.Phase).Should(Equal(opsv1alpha1.UnavailablePhase))
+			})).Should(Succeed())
+		})
+
+		It("Test OpsDefinition with CEL rules", func() {
+			opsDef := testapps.CreateCustomizedObj(&testCtx, "resources/mysql-opsdefinition-sql.yaml",
+				&opsv1alpha1.OpsDefinition{}, testCtx.UseDefaultNamespace(), func(opsD *opsv1alpha1.OpsDefinition) {
+					opsD.Spec.PreConditions = append(opsD.Spec.PreConditions, opsv1alpha1.PreCondition{
+						CELRule: &opsv1alpha1.CELRule{
+							Expression: "pods.exists(p, p.role == 'leader' && p.ready)",
+							Message:    "the leader is not ready",
+						},
+					})
+				})
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(g Gomega, opsD *opsv1alpha1.OpsDefinition) {
+				g.Expect(opsD.Status.Phase).Should(Equal(opsv1alpha1.AvailablePhase))
+			})).Should(Succeed())
+
+			By("the parameters rule does not compile")
+			Expect(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(opsD *opsv1alpha1.OpsDefinition) {
+				opsD.Spec.ParametersSchema.Rules = []opsv1alpha1.CELRule{
+					{Expression: "size(params.sql) > ", Message: "sql is required"},
+				}
+			})()).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(g Gomega, opsD *opsv1alpha1.OpsDefinition) {
+				g.Expect(opsD.Status.Phase).Should(Equal(opsv1alpha1.UnavailablePhase))
+				g.Expect(opsD.Status.Message).Should(ContainSubstring("invalid CEL expression"))
+			})).Should(Succeed())
 		})
 	})
 
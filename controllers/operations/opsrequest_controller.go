@@ -64,6 +64,7 @@ type OpsRequestReconciler struct {
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -124,7 +125,10 @@ func (r *OpsRequestReconciler) fetchOpsRequest(reqCtx intctrlutil.RequestCtx, op
 
 // handleDeletion handles the delete event of the OpsRequest.
 func (r *OpsRequestReconciler) handleDeletion(reqCtx intctrlutil.RequestCtx, opsRes *operations.OpsResource) (*ctrl.Result, error) {
-	if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsRunningPhase && !opsRes.Cluster.IsDeleting() {
+	// a running OpsRequest with the staged rollout may wait for the gates or the approvals endlessly, so it is not
+	// kept running after being deleted.
+	if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsRunningPhase && !opsRes.Cluster.IsDeleting() &&
+		(opsRes.OpsRequest.Spec.Rollout == nil || opsRes.OpsRequest.DeletionTimestamp.IsZero()) {
 		return nil, nil
 	}
 	if opsRes.Cluster.IsDeleting() && opsRes.OpsRequest.DeletionTimestamp.IsZero() {
@@ -139,6 +143,9 @@ func (r *OpsRequestReconciler) handleDeletion(reqCtx intctrlutil.RequestCtx, ops
 		if err := r.deleteCreatedPodsInKBNamespace(reqCtx, opsRes.OpsRequest); err != nil {
 			return nil, err
 		}
+		if err := operations.ReleaseRolloutStage(reqCtx.Ctx, r.Client, opsRes); err != nil {
+			return nil, err
+		}
 		return nil, operations.DequeueOpsRequestInClusterAnnotation(reqCtx.Ctx, r.Client, opsRes)
 	})
 }
@@ -186,11 +193,11 @@ func (r *OpsRequestReconciler) handleOpsRequestByPhase(reqCtx intctrlutil.Reques
 			return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
 		}
 		return intctrlutil.ResultToP(intctrlutil.Reconciled())
-	case opsv1alpha1.OpsPendingPhase, opsv1alpha1.OpsCreatingPhase:
+	case opsv1alpha1.OpsPendingPhase, opsv1alpha1.OpsScheduledPhase, opsv1alpha1.OpsCreatingPhase:
 		return r.doOpsRequestAction(reqCtx, opsRes)
 	case opsv1alpha1.OpsRunningPhase, opsv1alpha1.OpsCancellingPhase:
 		return r.reconcileStatusDuringRunningOrCanceling(reqCtx, opsRes)
-	case opsv1alpha1.OpsSucceedPhase:
+	case opsv1alpha1.OpsSucceedPhase, opsv1alpha1.OpsPlannedPhase:
 		return r.handleSucceedOpsRequest(reqCtx, opsRes.OpsRequest)
 	default:
 		return r.handleUnsuccessfulCompletionOpsRequest(reqCtx, opsRes)
@@ -206,7 +213,7 @@ func (r *OpsRequestReconciler) handleCancelSignal(reqCtx intctrlutil.RequestCtx,
 	if opsRequest.IsComplete() || opsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
 		return nil, nil
 	}
-	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase {
+	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase || opsRequest.Status.Phase == opsv1alpha1.OpsScheduledPhase {
 		return &ctrl.Result{}, operations.PatchOpsStatus(reqCtx.Ctx, r.Client, opsRes, opsv1alpha1.OpsCancelledPhase)
 	}
 	opsBehaviour := operations.GetOpsManager().OpsMap[opsRequest.Spec.Type]
@@ -232,7 +239,7 @@ func (r *OpsRequestReconciler) handleCancelSignal(reqCtx intctrlutil.RequestCtx,
 	return intctrlutil.ResultToP(intctrlutil.Reconciled())
 }
 
-// handleSucceedOpsRequest the opsRequest will be deleted after one hour when status.phase is Succeed
+// handleSucceedOpsRequest the opsRequest will be deleted after one hour when status.phase is Succeed or Planned
 func (r *OpsRequestReconciler) handleSucceedOpsRequest(reqCtx intctrlutil.RequestCtx, opsRequest *opsv1alpha1.OpsRequest) (*ctrl.Result, error) {
 	if err := r.annotateRelatedOps(reqCtx, opsRequest); err != nil {
 		return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
@@ -392,6 +399,10 @@ func (r *OpsRequestReconciler) getRunningOpsRequestsFromCluster(cluster *appsv1.
 	}
 	for i := range opsRequestSlice {
 		ops := opsRequestSlice[i]
+		if ops.ScheduledTime != nil {
+			// the scheduled opsRequest is requeued by itself until the scheduled time arrives.
+			continue
+		}
 		if !ops.InQueue {
 			// append running opsRequest
 			requests = append(requests, reconcile.Request{
@@ -46,6 +46,8 @@ type InstanceSetReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// ProbeEventWatcher subscribes the probe events of pods if it is set.
+	ProbeEventWatcher *instanceset.ProbeEventWatcher
 }
 
 // +kubebuilder:rbac:groups=workloads.kubeblocks.io,resources=instancesets,verbs=get;list;watch;create;update;patch;delete
@@ -82,6 +84,7 @@ func (r *InstanceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	res, err := kubebuilderx.NewController(ctx, r.Client, req, r.Recorder, logger).
 		Prepare(instanceset.NewTreeLoader()).
 		Do(instanceset.NewFixMetaReconciler()).
+		Do(instanceset.NewProbeWatchReconciler(r.ProbeEventWatcher)).
 		Do(instanceset.NewDeletionReconciler()).
 		Do(instanceset.NewStatusReconciler()).
 		Do(instanceset.NewRevisionUpdateReconciler()).
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strconv"
 
 	"github.com/imdario/mergo"
@@ -90,6 +91,7 @@ func BuildConfigManagerContainerParams(cli client.Client, ctx context.Context, m
 			return err
 		}
 	}
+	buildAccountEnvs(managerParams)
 	downwardAPIVolumes := buildDownwardAPIVolumes(managerParams)
 	allVolumeMounts = append(allVolumeMounts, downwardAPIVolumes...)
 	managerParams.Volumes = append(managerParams.Volumes, downwardAPIVolumes...)
@@ -107,6 +109,8 @@ func getWatchedVolume(volumeDirs []corev1.VolumeMount, buildParams []ConfigSpecM
 				return core.IsWatchModuleForTplTrigger(param.ReloadAction.TPLScriptTrigger)
 			case appsv1beta1.ShellType:
 				return core.IsWatchModuleForShellTrigger(param.ReloadAction.ShellTrigger)
+			case appsv1beta1.HTTPType, appsv1beta1.SQLType:
+				return false
 			default:
 				return true
 			}
@@ -158,6 +162,48 @@ func buildLazyRenderedConfig(cli client.Client, ctx context.Context, param *Conf
 	return nil
 }
 
+// buildAccountEnvs injects the credentials of the system accounts referenced by the http and sql triggers into the sidecar.
+func buildAccountEnvs(params *CfgManagerBuildParams) {
+	accountName := func(action *appsv1beta1.ReloadAction) string {
+		switch {
+		case action == nil:
+			return ""
+		case action.HTTPTrigger != nil:
+			return action.HTTPTrigger.AccountName
+		case action.SQLTrigger != nil:
+			return action.SQLTrigger.AccountName
+		}
+		return ""
+	}
+	secretKeyRef := func(secretName, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: key,
+			},
+		}
+	}
+
+	for _, buildParam := range params.ConfigSpecsBuildParams {
+		name := accountName(buildParam.ReloadAction)
+		if name == "" {
+			continue
+		}
+		secretName := constant.GenerateAccountSecretName(params.Cluster.Name, params.ComponentName, name)
+		for _, key := range []string{constant.AccountNameForSecret, constant.AccountPasswdForSecret} {
+			env := corev1.EnvVar{
+				Name:      AccountEnvName(name, key),
+				ValueFrom: secretKeyRef(secretName, key),
+			}
+			if !slices.ContainsFunc(params.Envs, func(e corev1.EnvVar) bool { return e.Name == env.Name }) {
+				params.Envs = append(params.Envs, env)
+			}
+		}
+	}
+}
+
 func buildDownwardAPIVolumes(params *CfgManagerBuildParams) []corev1.VolumeMount {
 	for _, buildParam := range params.ConfigSpecsBuildParams {
 		for _, info := range buildParam.DownwardAPIOptions {
@@ -571,6 +571,19 @@ func CreateCombinedHandler(config string, backupPath string) (ConfigHandler, err
 		)
 	}
 
+	httpHandler := func(httpTrigger *appsv1beta1.HTTPTrigger, configMeta ConfigSpecInfo) (ConfigHandler, error) {
+		if httpTrigger == nil {
+			return nil, cfgcore.MakeError("http trigger is nil")
+		}
+		return CreateHTTPTriggerHandler(configMeta.ConfigSpec.Name, configMeta.MountPoint, httpTrigger)
+	}
+	sqlHandler := func(sqlTrigger *appsv1beta1.SQLTrigger, configMeta ConfigSpecInfo) (ConfigHandler, error) {
+		if sqlTrigger == nil {
+			return nil, cfgcore.MakeError("sql trigger is nil")
+		}
+		return CreateSQLTriggerHandler(configMeta.ConfigSpec.Name, configMeta.MountPoint, sqlTrigger)
+	}
+
 	var h ConfigHandler
 	var handlerMetas []ConfigSpecInfo
 	err := cfgutil.FromYamlConfig(config, &handlerMetas)
@@ -595,6 +608,10 @@ func CreateCombinedHandler(config string, backupPath string) (ConfigHandler, err
 			h, err = signalHandler(configMeta.ReloadAction.UnixSignalTrigger, configMeta.MountPoint)
 		case appsv1beta1.TPLScriptType:
 			h, err = tplHandler(configMeta.ReloadAction.TPLScriptTrigger, configMeta, tmpPath)
+		case appsv1beta1.HTTPType:
+			h, err = httpHandler(configMeta.ReloadAction.HTTPTrigger, configMeta)
+		case appsv1beta1.SQLType:
+			h, err = sqlHandler(configMeta.ReloadAction.SQLTrigger, configMeta)
 		}
 		if err != nil {
 			return nil, err
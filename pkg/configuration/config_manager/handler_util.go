@@ -72,7 +72,9 @@ func isValidReloadPolicy(reload appsv1beta1.ReloadAction) bool {
 	return reload.AutoTrigger != nil ||
 		reload.ShellTrigger != nil ||
 		reload.TPLScriptTrigger != nil ||
-		reload.UnixSignalTrigger != nil
+		reload.UnixSignalTrigger != nil ||
+		reload.HTTPTrigger != nil ||
+		reload.SQLTrigger != nil
 }
 
 func IsAutoReload(reload *appsv1beta1.ReloadAction) bool {
@@ -89,6 +91,10 @@ func FromReloadTypeConfig(reloadAction *appsv1beta1.ReloadAction) appsv1beta1.Dy
 		return appsv1beta1.TPLScriptType
 	case reloadAction.AutoTrigger != nil:
 		return appsv1beta1.AutoType
+	case reloadAction.HTTPTrigger != nil:
+		return appsv1beta1.HTTPType
+	case reloadAction.SQLTrigger != nil:
+		return appsv1beta1.SQLType
 	}
 	return ""
 }
@@ -103,10 +109,39 @@ func ValidateReloadOptions(reloadAction *appsv1beta1.ReloadAction, cli client.Cl
 		return checkTPLScriptTrigger(reloadAction.TPLScriptTrigger, cli, ctx)
 	case reloadAction.AutoTrigger != nil:
 		return nil
+	case reloadAction.HTTPTrigger != nil:
+		return checkHTTPTrigger(reloadAction.HTTPTrigger)
+	case reloadAction.SQLTrigger != nil:
+		return checkSQLTrigger(reloadAction.SQLTrigger)
 	}
 	return core.MakeError("require special reload type!")
 }
 
+func checkHTTPTrigger(options *appsv1beta1.HTTPTrigger) error {
+	if options.Port <= 0 {
+		return core.MakeError("invalid port of http trigger: %d", options.Port)
+	}
+	if options.BodyTemplate != "" {
+		if err := checkTPLScript("http-trigger-body", options.BodyTemplate); err != nil {
+			return core.WrapError(err, "invalid body template of http trigger")
+		}
+	}
+	return nil
+}
+
+func checkSQLTrigger(options *appsv1beta1.SQLTrigger) error {
+	if options.Port <= 0 {
+		return core.MakeError("invalid port of sql trigger: %d", options.Port)
+	}
+	if options.AccountName == "" {
+		return core.MakeError("required account name of sql trigger")
+	}
+	if err := checkTPLScript("sql-trigger-statement", options.StatementTemplate); err != nil {
+		return core.WrapError(err, "invalid statement template of sql trigger")
+	}
+	return nil
+}
+
 func checkTPLScriptTrigger(options *appsv1beta1.TPLScriptTrigger, cli client.Client, ctx context.Context) error {
 	cm := corev1.ConfigMap{}
 	return cli.Get(ctx, client.ObjectKey{
@@ -198,7 +233,7 @@ func GetSupportReloadConfigSpecs(configSpecs []appsv1.ComponentConfigSpec, cli c
 // As a result, the config-manager cannot detect configuration changes and does not support dynamic parameter updates for such configurations.
 // Therefore, async-type ConfigSpecs with subpath volume mounts need to be removed.
 //
-// 2. Sync mode: For sync mode (regardless of the reload action type - TPLScriptType trigger or ShellType trigger), the controller directly watches
+// 2. Sync mode: For sync mode (regardless of the reload action type - TPLScriptType, ShellType, HTTPType or SQLType trigger), the controller directly watches
 // the ConfigMap changes and actively invokes the reload action.
 //
 // Both async and sync types need to pass the ConfigSpecs to the config-manager.
@@ -224,5 +259,7 @@ func isSubPathMount(v *corev1.VolumeMount) bool {
 func isSyncReloadAction(meta ConfigSpecInfo) bool {
 	// If synchronous reloadAction is supported, kubelet limitations can be ignored.
 	return meta.ReloadType == appsv1beta1.TPLScriptType && !core.IsWatchModuleForTplTrigger(meta.TPLScriptTrigger) ||
-		meta.ReloadType == appsv1beta1.ShellType && !core.IsWatchModuleForShellTrigger(meta.ShellTrigger)
+		meta.ReloadType == appsv1beta1.ShellType && !core.IsWatchModuleForShellTrigger(meta.ShellTrigger) ||
+		meta.ReloadType == appsv1beta1.HTTPType ||
+		meta.ReloadType == appsv1beta1.SQLType
 }
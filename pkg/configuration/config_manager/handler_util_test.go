@@ -373,6 +373,21 @@ var _ = Describe("Handler Util Test", func() {
 				}})))
 		})
 
+		It("TestHTTPAndSQLTrigger", func() {
+			Expect(appsv1beta1.HTTPType).Should(BeEquivalentTo(FromReloadTypeConfig(&appsv1beta1.ReloadAction{
+				HTTPTrigger: &appsv1beta1.HTTPTrigger{
+					Port: 8080,
+					Path: "/reload",
+				}})))
+			Expect(appsv1beta1.SQLType).Should(BeEquivalentTo(FromReloadTypeConfig(&appsv1beta1.ReloadAction{
+				SQLTrigger: &appsv1beta1.SQLTrigger{
+					Engine:            "mysql",
+					Port:              3306,
+					StatementTemplate: "SET GLOBAL {{ .name }} = {{ .value }}",
+					AccountName:       "root",
+				}})))
+		})
+
 		It("TestInvalidTrigger", func() {
 			Expect("").Should(BeEquivalentTo(FromReloadTypeConfig(&appsv1beta1.ReloadAction{})))
 		})
@@ -0,0 +1,300 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/gotemplate"
+)
+
+const (
+	postgresql = "postgresql"
+
+	// the reload endpoints are served by the containers of the same pod
+	reloadHost = "127.0.0.1"
+
+	maxHTTPResponseSize = 1024
+)
+
+var (
+	// sqlIdentifierRegex matches the names of parameters, including the qualified ones, e.g., auto_explain.log_min_duration.
+	sqlIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)*$`)
+	sqlNumericRegex    = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+)
+
+// ParamsUpdateError records the parameters that failed to be applied by an online update.
+type ParamsUpdateError struct {
+	Failed map[string]error
+}
+
+func (e *ParamsUpdateError) Error() string {
+	messages := make([]string, 0, len(e.Failed))
+	for _, name := range sets.List(sets.KeySet(e.Failed)) {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, e.Failed[name].Error()))
+	}
+	return fmt.Sprintf("failed to update parameters: [%s]", strings.Join(messages, "; "))
+}
+
+// AccountEnvName returns the name of the env in the config-manager sidecar that holds the given secret key of a system account.
+func AccountEnvName(accountName, key string) string {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(accountName))
+	return fmt.Sprintf("KB_ACCOUNT_%s_%s", name, strings.ToUpper(key))
+}
+
+func accountCredential(accountName string) (string, string, error) {
+	username := os.Getenv(AccountEnvName(accountName, constant.AccountNameForSecret))
+	password := os.Getenv(AccountEnvName(accountName, constant.AccountPasswdForSecret))
+	if username == "" {
+		return "", "", cfgcore.MakeError("credential of the account [%s] is not found", accountName)
+	}
+	return username, password, nil
+}
+
+// updateParamsOneByOne applies the updated parameters one at a time, and collects the failed ones instead of giving up at the first failure.
+func updateParamsOneByOne(updatedParams map[string]string, update func(name, value string) error) error {
+	failed := make(map[string]error)
+	for _, name := range sets.List(sets.KeySet(updatedParams)) {
+		if err := update(name, updatedParams[name]); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to update parameter: %s", name))
+			failed[name] = err
+		}
+	}
+	if len(failed) != 0 {
+		return &ParamsUpdateError{Failed: failed}
+	}
+	return nil
+}
+
+func renderParamTemplate(ctx context.Context, tpl, name, value string) (string, error) {
+	values := gotemplate.TplValues{
+		"name":  name,
+		"value": value,
+	}
+	engine := gotemplate.NewTplEngine(&values, nil, "render-param-template", nil, ctx)
+	rendered, err := engine.Render(tpl)
+	return strings.TrimSpace(rendered), err
+}
+
+type httpTriggerHandler struct {
+	configVolumeHandleMeta
+
+	trigger *appsv1beta1.HTTPTrigger
+	url     string
+	client  *http.Client
+}
+
+func (h *httpTriggerHandler) OnlineUpdate(ctx context.Context, _ string, updatedParams map[string]string) error {
+	logger.Info(fmt.Sprintf("updated parameters: %v", updatedParams))
+	return updateParamsOneByOne(updatedParams, func(name, value string) error {
+		body, err := h.buildBody(ctx, name, value)
+		if err != nil {
+			return err
+		}
+		return h.send(ctx, body)
+	})
+}
+
+func (h *httpTriggerHandler) buildBody(ctx context.Context, name, value string) (string, error) {
+	if h.trigger.BodyTemplate != "" {
+		return renderParamTemplate(ctx, h.trigger.BodyTemplate, name, value)
+	}
+	b, err := json.Marshal(map[string]string{name: value})
+	return string(b), err
+}
+
+func (h *httpTriggerHandler) send(ctx context.Context, body string) error {
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, h.method(), h.url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.trigger.AccountName != "" {
+		username, password, err := accountCredential(h.trigger.AccountName)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(username, password)
+	}
+
+	rsp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(rsp.Body, maxHTTPResponseSize))
+		return cfgcore.MakeError("unexpected response status: %s, body: %s", rsp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (h *httpTriggerHandler) method() string {
+	if h.trigger.Method == "" {
+		return http.MethodPost
+	}
+	return h.trigger.Method
+}
+
+func CreateHTTPTriggerHandler(name string, mountPoint string, trigger *appsv1beta1.HTTPTrigger) (ConfigHandler, error) {
+	if err := checkHTTPTrigger(trigger); err != nil {
+		return nil, err
+	}
+	scheme := strings.ToLower(string(trigger.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	endpoint := url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(reloadHost, strconv.Itoa(int(trigger.Port))),
+		Path:   trigger.Path,
+	}
+	return &httpTriggerHandler{
+		configVolumeHandleMeta: createConfigVolumeMeta(name, appsv1beta1.HTTPType, []string{mountPoint}, nil),
+		trigger:                trigger,
+		url:                    endpoint.String(),
+		client: &http.Client{
+			Transport: &http.Transport{
+				// the endpoint is served on the loopback address, the certificate of the server is not verified
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+	}, nil
+}
+
+type sqlTriggerHandler struct {
+	configVolumeHandleMeta
+
+	trigger *appsv1beta1.SQLTrigger
+}
+
+func (h *sqlTriggerHandler) OnlineUpdate(ctx context.Context, _ string, updatedParams map[string]string) error {
+	logger.Info(fmt.Sprintf("updated parameters: %v", updatedParams))
+	dsn, err := h.dsn()
+	if err != nil {
+		return err
+	}
+	commandChannel, err := newCommandChannel(ctx, h.trigger.Engine, dsn)
+	if err != nil {
+		return err
+	}
+	defer commandChannel.Close()
+
+	return updateParamsOneByOne(updatedParams, func(name, value string) error {
+		statement, err := h.renderStatement(ctx, name, value)
+		if err != nil {
+			return err
+		}
+		r, err := commandChannel.ExecCommand(ctx, statement)
+		logger.V(1).Info(fmt.Sprintf("sql: [%s], result: [%v], err: [%+v]", statement, r, err))
+		return err
+	})
+}
+
+// renderStatement renders the statement of parameter, the name must be a valid identifier and the value is rendered as
+// a SQL literal of the engine, so the statement can't be injected by the parameters.
+func (h *sqlTriggerHandler) renderStatement(ctx context.Context, name, value string) (string, error) {
+	if !sqlIdentifierRegex.MatchString(name) {
+		return "", cfgcore.MakeError("invalid parameter name: [%s]", name)
+	}
+	literal, err := sqlLiteral(h.trigger.Engine, value)
+	if err != nil {
+		return "", err
+	}
+	return renderParamTemplate(ctx, h.trigger.StatementTemplate, name, literal)
+}
+
+// sqlLiteral returns the numeric value as is, and quotes the others as string literals with the escaping rules of engine.
+func sqlLiteral(engine, value string) (string, error) {
+	if strings.ContainsRune(value, 0) {
+		return "", cfgcore.MakeError("invalid parameter value: contains NUL character")
+	}
+	if sqlNumericRegex.MatchString(value) {
+		return value, nil
+	}
+	switch strings.ToLower(engine) {
+	case mysql:
+		// the backslash is an escape character in the string literals of MySQL, unless NO_BACKSLASH_ESCAPES is enabled
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(value) + "'", nil
+	case postgresql:
+		// the standard_conforming_strings is on by default, the backslash is an ordinary character
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", nil
+	}
+	return "", cfgcore.MakeError("not supported sql engine: [%s]", engine)
+}
+
+func (h *sqlTriggerHandler) dsn() (string, error) {
+	username, password, err := accountCredential(h.trigger.AccountName)
+	if err != nil {
+		return "", err
+	}
+	host := net.JoinHostPort(reloadHost, strconv.Itoa(int(h.trigger.Port)))
+	switch strings.ToLower(h.trigger.Engine) {
+	case mysql:
+		config := mysqldriver.NewConfig()
+		config.User = username
+		config.Passwd = password
+		config.Net = "tcp"
+		config.Addr = host
+		config.DBName = h.trigger.Database
+		return config.FormatDSN(), nil
+	case postgresql:
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(username, password),
+			Host:     host,
+			Path:     h.trigger.Database,
+			RawQuery: "sslmode=disable",
+		}
+		return dsn.String(), nil
+	}
+	return "", cfgcore.MakeError("not supported sql engine: [%s]", h.trigger.Engine)
+}
+
+func CreateSQLTriggerHandler(name string, mountPoint string, trigger *appsv1beta1.SQLTrigger) (ConfigHandler, error) {
+	if err := checkSQLTrigger(trigger); err != nil {
+		return nil, err
+	}
+	return &sqlTriggerHandler{
+		configVolumeHandleMeta: createConfigVolumeMeta(name, appsv1beta1.SQLType, []string{mountPoint}, nil),
+		trigger:                trigger,
+	}, nil
+}
@@ -31,6 +31,7 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -70,6 +71,8 @@ func NewCommandChannel(ctx context.Context, dataType, dsn string) (DynamicParamU
 		return newMysqlConnection(ctx, dsn)
 	case patroni:
 		return newPGPatroniConnection(dsn)
+	case postgresql:
+		return newPostgresConnection(ctx, dsn)
 	default:
 		// TODO mock db begin support dapper
 	}
@@ -95,6 +98,15 @@ func newMysqlConnection(ctx context.Context, dsn string) (DynamicParamUpdater, e
 	return newDynamicParamUpdater(ctx, db)
 }
 
+func newPostgresConnection(ctx context.Context, dsn string) (DynamicParamUpdater, error) {
+	logger.V(1).Info("connecting postgresql.")
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, cfgcore.WrapError(err, "failed to opening connection to postgresql.")
+	}
+	return newDynamicParamUpdater(ctx, db)
+}
+
 func newDynamicParamUpdater(ctx context.Context, db *sql.DB) (DynamicParamUpdater, error) {
 	logger.V(1).Info("connecting mysql.")
 	db.SetMaxOpenConns(1)
@@ -317,7 +317,7 @@ var _ = Describe("ReloadUtil Test", func() {
 			_, err := engine.Render(fmt.Sprintf("{{- patchParams $.arg0 \"%s\" \"%s\" }}", baseFile, targetFile))
 			Expect(err).Should(Succeed())
 			b, _ := os.ReadFile(targetFile)
-			Expect("[test]\na=1\nb=2\nkey1=128M\nkey2=512M\n").Should(BeEquivalentTo(string(b)))
+			Expect("[test]\na = 1\nb = 2\nkey1 = 128M\nkey2 = 512M\n").Should(BeEquivalentTo(string(b)))
 		})
 	})
 
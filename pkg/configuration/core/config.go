@@ -82,6 +82,7 @@ func init() {
 
 		meta.v[0] = v
 		meta.indexer[meta.name] = v
+		meta.trackContent(v, string(option.RawData), option.CfgType)
 		return &meta, nil
 	}
 
@@ -120,6 +121,7 @@ func init() {
 			}
 			meta.indexer[fileName] = v
 			meta.v[index] = v
+			meta.trackContent(v, content, option.CfgType)
 			index++
 		}
 		return &meta, nil
@@ -139,6 +141,16 @@ type cfgWrapper struct {
 	// indexer   map[string]*viper.Viper
 	indexer map[string]unstructured.ConfigObject
 	v       []unstructured.ConfigObject
+
+	format appsv1beta1.CfgFileFormat
+	// patches records the original content and the merged parameters of each config file,
+	// which are used to patch the original content in place.
+	patches map[unstructured.ConfigObject]*contentPatch
+}
+
+type contentPatch struct {
+	content string
+	params  map[string]interface{}
 }
 
 type dataConfig struct {
@@ -185,6 +197,9 @@ func (c *cfgWrapper) MergeFrom(params map[string]interface{}, option CfgOpOption
 		if err != nil {
 			return err
 		}
+		if patch, ok := c.patches[cfg]; ok {
+			patch.params[c.generateKey(paramKey, option)] = paramValue
+		}
 	}
 	return nil
 }
@@ -192,7 +207,7 @@ func (c *cfgWrapper) MergeFrom(params map[string]interface{}, option CfgOpOption
 func (c *cfgWrapper) ToCfgContent() (map[string]string, error) {
 	fileContents := make(map[string]string, c.fileCount)
 	for fileName, v := range c.indexer {
-		content, err := v.Marshal()
+		content, err := c.marshal(v)
 		if err != nil {
 			return nil, err
 		}
@@ -201,6 +216,28 @@ func (c *cfgWrapper) ToCfgContent() (map[string]string, error) {
 	return fileContents, nil
 }
 
+func (c *cfgWrapper) trackContent(v unstructured.ConfigObject, content string, format appsv1beta1.CfgFileFormat) {
+	if c.patches == nil {
+		c.patches = make(map[unstructured.ConfigObject]*contentPatch)
+	}
+	c.format = format
+	c.patches[v] = &contentPatch{
+		content: content,
+		params:  make(map[string]interface{}),
+	}
+}
+
+// marshal generates the content of the config file, the updated parameters are patched into the original content
+// to keep the comments, key order and formatting, and the whole file is rendered only if the format cannot be patched in place.
+func (c *cfgWrapper) marshal(v unstructured.ConfigObject) (string, error) {
+	if patch, ok := c.patches[v]; ok && len(patch.params) > 0 {
+		if content, err := patchConfigInPlace(c.format, patch.content, patch.params); err == nil {
+			return content, nil
+		}
+	}
+	return v.Marshal()
+}
+
 type ConfigPatchInfo struct {
 	IsModify bool
 	// new config
@@ -231,7 +268,7 @@ func NewCfgOptions(filename string, options ...Option) CfgOpOption {
 
 func WithFormatterConfig(formatConfig *appsv1beta1.FileFormatConfig) Option {
 	return func(ctx *CfgOpOption) {
-		if formatConfig.Format == appsv1beta1.Ini && formatConfig.IniConfig != nil {
+		if hasIniSection(formatConfig) {
 			ctx.IniContext = &IniContext{
 				SectionName: formatConfig.IniConfig.SectionName,
 			}
@@ -240,12 +277,17 @@ func WithFormatterConfig(formatConfig *appsv1beta1.FileFormatConfig) Option {
 }
 
 func NestedPrefixField(formatConfig *appsv1beta1.FileFormatConfig) string {
-	if formatConfig != nil && formatConfig.Format == appsv1beta1.Ini && formatConfig.IniConfig != nil {
+	if formatConfig != nil && hasIniSection(formatConfig) {
 		return formatConfig.IniConfig.SectionName
 	}
 	return ""
 }
 
+// hasIniSection reports whether the parameters are placed in a section of the config file, both ini and my-cnf formats have sections.
+func hasIniSection(formatConfig *appsv1beta1.FileFormatConfig) bool {
+	return (formatConfig.Format == appsv1beta1.Ini || formatConfig.Format == appsv1beta1.MyCnf) && formatConfig.IniConfig != nil
+}
+
 func (c *cfgWrapper) Query(jsonpath string, option CfgOpOption) ([]byte, error) {
 	if option.AllSearch && c.fileCount > 1 {
 		return c.queryAllCfg(jsonpath, option)
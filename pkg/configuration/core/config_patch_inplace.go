@@ -0,0 +1,365 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	"github.com/apecloud/kubeblocks/pkg/unstructured"
+)
+
+// lineSyntax describes the line syntax of a config format whose files can be patched in place.
+type lineSyntax interface {
+	// topSection is the name of the section holding the parameters before the first section header.
+	topSection() string
+	isComment(line string) bool
+	parseSection(line string) (string, bool)
+	// parseParam parses a parameter line, if the value spans multiple lines,
+	// more reports whether the value continues after the given following line.
+	parseParam(line string) (span paramSpan, more func(next string) bool, ok bool)
+	// match reports whether the parameter line in the section defines the key.
+	match(section string, span paramSpan, key string) bool
+	// placeKey chooses the section and the name of a new parameter.
+	placeKey(key string, sections []string) (section, name string)
+	updateParam(line string, span paramSpan, key, value string) (string, error)
+	newParam(name, value string, like *paramSpan) (string, error)
+	newSection(name string) (string, error)
+}
+
+type paramSpan struct {
+	indent string
+	name   string
+	// sep is the text between the name and the value.
+	sep string
+	// value is the raw text of the value, [valueStart, valueEnd) is its offset in the line.
+	value      string
+	valueStart int
+	valueEnd   int
+	// args are the tokens of the line, used by the formats without a name-value separator.
+	args []string
+}
+
+type textParam struct {
+	span      paramSpan
+	section   string
+	first     int
+	last      int
+	multiline bool
+}
+
+type textSection struct {
+	name   string
+	header int
+	params []*textParam
+}
+
+func inPlaceSyntax(format appsv1beta1.CfgFileFormat) lineSyntax {
+	switch format {
+	case appsv1beta1.Ini:
+		return iniSyntax{}
+	case appsv1beta1.Properties:
+		return propertiesSyntax{foldCase: true}
+	case appsv1beta1.PropertiesPlus:
+		return propertiesSyntax{}
+	case appsv1beta1.RedisCfg:
+		return redisSyntax{}
+	case appsv1beta1.TOML:
+		return tomlSyntax{}
+	default:
+		return nil
+	}
+}
+
+// patchConfigInPlace applies the updated parameters to the original config text,
+// only the lines of the changed parameters are rewritten, so that comments, key order and formatting are preserved.
+// An error is returned if the format or the text cannot be patched in place, the caller should render the whole file instead.
+func patchConfigInPlace(format appsv1beta1.CfgFileFormat, content string, params map[string]interface{}) (string, error) {
+	syntax := inPlaceSyntax(format)
+	if syntax == nil {
+		return "", MakeError("the config format[%s] does not support in-place patching", format)
+	}
+	patched, err := patchConfigText(syntax, content, params)
+	if err != nil {
+		return "", err
+	}
+	if err := checkPatchedConfig(format, content, patched, params); err != nil {
+		return "", err
+	}
+	return patched, nil
+}
+
+func patchConfigText(syntax lineSyntax, content string, params map[string]interface{}) (string, error) {
+	var lines []string
+	trailingNewline := strings.HasSuffix(content, "\n")
+	if strings.TrimSpace(content) != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	sections, err := scanConfigText(syntax, lines)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		edits        = make(map[int]string)
+		removed      = make(map[int]bool)
+		insertAfter  = make(map[int][]string)
+		insertBefore = make(map[int][]string)
+		newSections  = make(map[string][]string)
+		sectionOrder []string
+	)
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		param, err := findTextParam(syntax, sections, key, removed)
+		if err != nil {
+			return "", err
+		}
+		if params[key] == nil {
+			if param != nil {
+				for i := param.first; i <= param.last; i++ {
+					removed[i] = true
+				}
+			}
+			continue
+		}
+		value, err := toScalarString(params[key])
+		if err != nil {
+			return "", err
+		}
+		if param != nil {
+			if param.multiline {
+				return "", MakeError("the value of parameter[%s] spans multiple lines", key)
+			}
+			if edits[param.first], err = syntax.updateParam(lines[param.first], param.span, key, value); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		sectionName, name := syntax.placeKey(key, sectionNames(sections))
+		section := lastSection(sections, sectionName)
+		line, err := syntax.newParam(name, value, likeParam(section, sections))
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case section == nil:
+			if _, ok := newSections[sectionName]; !ok {
+				sectionOrder = append(sectionOrder, sectionName)
+			}
+			newSections[sectionName] = append(newSections[sectionName], line)
+		case len(section.params) > 0:
+			anchor := section.params[len(section.params)-1].last
+			insertAfter[anchor] = append(insertAfter[anchor], line)
+		case section.header >= 0:
+			insertAfter[section.header] = append(insertAfter[section.header], line)
+		default:
+			// the top section is empty, insert the parameter before the first section header.
+			anchor := len(lines)
+			if len(sections) > 1 {
+				anchor = sections[1].header
+			}
+			insertBefore[anchor] = append(insertBefore[anchor], line)
+		}
+	}
+
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		out = append(out, insertBefore[i]...)
+		if edited, ok := edits[i]; ok {
+			line = edited
+		}
+		if !removed[i] {
+			out = append(out, line)
+		}
+		out = append(out, insertAfter[i]...)
+	}
+	out = append(out, insertBefore[len(lines)]...)
+	for _, name := range sectionOrder {
+		header, err := syntax.newSection(name)
+		if err != nil {
+			return "", err
+		}
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, header)
+		out = append(out, newSections[name]...)
+	}
+
+	patched := strings.Join(out, "\n")
+	if len(out) > 0 && trailingNewline {
+		patched += "\n"
+	}
+	return patched, nil
+}
+
+func scanConfigText(syntax lineSyntax, lines []string) ([]*textSection, error) {
+	current := &textSection{name: syntax.topSection(), header: -1}
+	sections := []*textSection{current}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || syntax.isComment(line) {
+			continue
+		}
+		if name, ok := syntax.parseSection(line); ok {
+			current = &textSection{name: name, header: i}
+			sections = append(sections, current)
+			continue
+		}
+		span, more, ok := syntax.parseParam(lines[i])
+		if !ok {
+			return nil, MakeError("failed to parse config line: %d", i+1)
+		}
+		param := &textParam{span: span, section: current.name, first: i, last: i, multiline: more != nil}
+		for more != nil && param.last+1 < len(lines) {
+			param.last++
+			if !more(lines[param.last]) {
+				break
+			}
+		}
+		current.params = append(current.params, param)
+		i = param.last
+	}
+	return sections, nil
+}
+
+func findTextParam(syntax lineSyntax, sections []*textSection, key string, removed map[int]bool) (*textParam, error) {
+	var found *textParam
+	for _, section := range sections {
+		for _, param := range section.params {
+			if removed[param.first] || !syntax.match(param.section, param.span, key) {
+				continue
+			}
+			if found != nil {
+				return nil, MakeError("the parameter[%s] is defined more than once", key)
+			}
+			found = param
+		}
+	}
+	return found, nil
+}
+
+func sectionNames(sections []*textSection) []string {
+	names := make([]string, 0, len(sections))
+	for _, section := range sections {
+		names = append(names, section.name)
+	}
+	return names
+}
+
+func lastSection(sections []*textSection, name string) *textSection {
+	for i := len(sections) - 1; i >= 0; i-- {
+		if sections[i].name == name {
+			return sections[i]
+		}
+	}
+	return nil
+}
+
+// likeParam returns a parameter whose style is followed by the new parameter.
+func likeParam(section *textSection, sections []*textSection) *paramSpan {
+	if section != nil && len(section.params) > 0 {
+		return &section.params[len(section.params)-1].span
+	}
+	for _, s := range sections {
+		if len(s.params) > 0 {
+			return &s.params[0].span
+		}
+	}
+	return nil
+}
+
+func toScalarString(value interface{}) (string, error) {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		return "", MakeError("the value[%v] is not a scalar", value)
+	}
+	v, err := cast.ToStringE(value)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(v, "\r\n") {
+		return "", MakeError("the value[%s] contains a line break", v)
+	}
+	return v, nil
+}
+
+// checkPatchedConfig makes sure that the patched text has exactly the parameters of the original text with the updates applied.
+func checkPatchedConfig(format appsv1beta1.CfgFileFormat, content, patched string, params map[string]interface{}) error {
+	base, err := unstructured.LoadConfig("base", content, format)
+	if err != nil {
+		return err
+	}
+	target, err := unstructured.LoadConfig("patched", patched, format)
+	if err != nil {
+		return WrapError(err, "failed to load the patched config")
+	}
+
+	foldKey := func(key string) string { return key }
+	switch format {
+	case appsv1beta1.Ini, appsv1beta1.Properties, appsv1beta1.TOML:
+		// viper keys are case-insensitive.
+		foldKey = strings.ToLower
+	}
+	if format == appsv1beta1.Ini {
+		foldKey = func(key string) string {
+			if !strings.Contains(key, unstructured.DelimiterDot) {
+				key = iniDefaultSection + unstructured.DelimiterDot + key
+			}
+			return strings.ToLower(key)
+		}
+	}
+
+	expected := flattenParameters(base.GetAllParameters())
+	for key, value := range params {
+		if value == nil {
+			delete(expected, foldKey(key))
+		} else {
+			expected[foldKey(key)] = cast.ToString(value)
+		}
+	}
+	if !reflect.DeepEqual(expected, flattenParameters(target.GetAllParameters())) {
+		return MakeError("the patched config does not match the updated parameters")
+	}
+	return nil
+}
+
+func flattenParameters(params map[string]interface{}) map[string]string {
+	r := make(map[string]string, len(params))
+	for _, pair := range flattenMap(params, "") {
+		if pair.Value != nil {
+			r[pair.Key] = *pair.Value
+		} else {
+			r[pair.Key] = ""
+		}
+	}
+	return r
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+func TestPatchConfigInPlace(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  appsv1beta1.CfgFileFormat
+		content string
+		params  map[string]interface{}
+		want    string
+		wantErr bool
+	}{{
+		name:   "ini",
+		format: appsv1beta1.Ini,
+		content: `# global settings
+[client]
+port = 3306
+
+[mysqld]
+# the max connections
+max_connections = 100 ; default
+datadir=/data/mysql
+tmpdir = /tmp
+`,
+		params: map[string]interface{}{
+			"mysqld.max_connections": "500",
+			"mysqld.tmpdir":          nil,
+			"mysqld.innodb_buffer":   "1G",
+			"mysqld_safe.pid_file":   "/tmp/mysql.pid",
+		},
+		want: `# global settings
+[client]
+port = 3306
+
+[mysqld]
+# the max connections
+max_connections = 500 ; default
+datadir=/data/mysql
+innodb_buffer = 1G
+
+[mysqld_safe]
+pid_file = /tmp/mysql.pid
+`,
+	}, {
+		name:   "properties",
+		format: appsv1beta1.PropertiesPlus,
+		content: `# zookeeper
+tickTime: 2000
+dataDir = /data/zk
+! servers
+server.1=zk-0:2888:3888
+`,
+		params: map[string]interface{}{
+			"tickTime":  "3000",
+			"server.1":  nil,
+			"initLimit": "10",
+		},
+		want: `# zookeeper
+tickTime: 3000
+dataDir = /data/zk
+! servers
+initLimit=10
+`,
+	}, {
+		name:   "redis",
+		format: appsv1beta1.RedisCfg,
+		content: `# snapshot
+save 900 1
+save 300 10
+
+  maxmemory 1gb
+`,
+		params: map[string]interface{}{
+			"save 300":         "20",
+			"maxmemory":        "2gb",
+			"maxmemory-policy": "allkeys-lru",
+		},
+		want: `# snapshot
+save 900 1
+save 300 20
+
+  maxmemory 2gb
+  maxmemory-policy allkeys-lru
+`,
+	}, {
+		name:   "toml",
+		format: appsv1beta1.TOML,
+		content: `# top
+title = "config"
+timeout = 10
+
+[server]
+host = 'localhost' # bind address
+ports = [
+  8000,
+  8001,
+]
+
+[server.tls]
+enabled = false
+`,
+		params: map[string]interface{}{
+			"title":              "new config",
+			"timeout":            "30",
+			"server.host":        "0.0.0.0",
+			"server.tls.enabled": "true",
+			"server.tls.cert":    "/etc/tls.crt",
+			"log.level":          "info",
+		},
+		want: `# top
+title = "new config"
+timeout = 30
+
+[server]
+host = '0.0.0.0' # bind address
+ports = [
+  8000,
+  8001,
+]
+
+[server.tls]
+enabled = true
+cert = "/etc/tls.crt"
+
+[log]
+level = "info"
+`,
+	}, {
+		name:    "multiline value",
+		format:  appsv1beta1.TOML,
+		content: "ports = [\n  8000,\n]\n",
+		params:  map[string]interface{}{"ports": "8001"},
+		wantErr: true,
+	}, {
+		name:    "duplicated parameter",
+		format:  appsv1beta1.RedisCfg,
+		content: "save 900 1\nsave 300 10\n",
+		params:  map[string]interface{}{"save": "60 1"},
+		wantErr: true,
+	}, {
+		name:    "unsupported format",
+		format:  appsv1beta1.YAML,
+		content: "a: b\n",
+		params:  map[string]interface{}{"a": "c"},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchConfigInPlace(tt.format, tt.content, tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyConfigPatchFallback(t *testing.T) {
+	// the array value cannot be patched in place, the whole file is rendered.
+	got, err := ApplyConfigPatch([]byte("# ports\nports = [\n  8000,\n]\n"), map[string]*string{
+		"ports": nil,
+		"host":  func() *string { s := "localhost"; return &s }(),
+	}, &appsv1beta1.FileFormatConfig{Format: appsv1beta1.TOML})
+	require.NoError(t, err)
+	require.Contains(t, got, "host = 'localhost'")
+	require.NotContains(t, got, "# ports")
+}
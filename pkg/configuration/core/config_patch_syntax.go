@@ -0,0 +1,498 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apecloud/kubeblocks/pkg/unstructured"
+)
+
+const iniDefaultSection = "default"
+
+type iniSyntax struct{}
+
+func (iniSyntax) topSection() string {
+	return iniDefaultSection
+}
+
+func (iniSyntax) isComment(line string) bool {
+	return line[0] == ';' || line[0] == '#'
+}
+
+func (iniSyntax) parseSection(line string) (string, bool) {
+	if line[0] != '[' {
+		return "", false
+	}
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(line[1:end])), true
+}
+
+func (iniSyntax) parseParam(line string) (paramSpan, func(string) bool, bool) {
+	span, ok := splitParamLine(line, "=:", false)
+	if !ok || span.name == "" {
+		return span, nil, false
+	}
+	span.name = strings.Trim(span.name, "`\"")
+	span.valueEnd = span.valueStart + inlineCommentIndex(line[span.valueStart:], ";#")
+	span.value = line[span.valueStart:span.valueEnd]
+	if strings.HasPrefix(span.value, `"""`) && !strings.Contains(span.value[3:], `"""`) {
+		return span, func(next string) bool { return !strings.Contains(next, `"""`) }, true
+	}
+	return span, nil, true
+}
+
+func (iniSyntax) match(section string, span paramSpan, key string) bool {
+	if !strings.Contains(key, unstructured.DelimiterDot) {
+		key = iniDefaultSection + unstructured.DelimiterDot + key
+	}
+	return strings.EqualFold(section+unstructured.DelimiterDot+span.name, key)
+}
+
+func (iniSyntax) placeKey(key string, _ []string) (string, string) {
+	section, name, ok := strings.Cut(key, unstructured.DelimiterDot)
+	if !ok {
+		return iniDefaultSection, key
+	}
+	return strings.ToLower(section), name
+}
+
+func (iniSyntax) updateParam(line string, span paramSpan, _, value string) (string, error) {
+	return line[:span.valueStart] + value + line[span.valueEnd:], nil
+}
+
+func (iniSyntax) newParam(name, value string, like *paramSpan) (string, error) {
+	return newParamLine(name, value, " = ", like), nil
+}
+
+func (iniSyntax) newSection(name string) (string, error) {
+	return "[" + name + "]", nil
+}
+
+type propertiesSyntax struct {
+	// foldCase is set for the viper properties, whose keys are case-insensitive.
+	foldCase bool
+}
+
+func (propertiesSyntax) topSection() string {
+	return ""
+}
+
+func (propertiesSyntax) isComment(line string) bool {
+	return line[0] == '#' || line[0] == '!'
+}
+
+func (propertiesSyntax) parseSection(string) (string, bool) {
+	return "", false
+}
+
+func (propertiesSyntax) parseParam(line string) (paramSpan, func(string) bool, bool) {
+	span, ok := splitParamLine(line, "=:", true)
+	if !ok || span.name == "" {
+		return span, nil, false
+	}
+	span.name = unescapePropertiesKey(span.name)
+	if hasLineContinuation(line) {
+		return span, hasLineContinuation, true
+	}
+	return span, nil, true
+}
+
+func (p propertiesSyntax) match(_ string, span paramSpan, key string) bool {
+	if p.foldCase {
+		return strings.EqualFold(span.name, key)
+	}
+	return span.name == key
+}
+
+func (propertiesSyntax) placeKey(key string, _ []string) (string, string) {
+	return "", key
+}
+
+func (propertiesSyntax) updateParam(line string, span paramSpan, key, value string) (string, error) {
+	if strings.TrimLeftFunc(value, unicode.IsSpace) != value || hasLineContinuation(value) {
+		return "", MakeError("the value of parameter[%s] cannot be written in place", key)
+	}
+	return line[:span.valueStart] + value, nil
+}
+
+func (p propertiesSyntax) newParam(name, value string, like *paramSpan) (string, error) {
+	if strings.ContainsAny(name, " \t=:#!\\") {
+		return "", MakeError("the parameter[%s] cannot be written in place", name)
+	}
+	line := newParamLine(name, "", "=", like)
+	return p.updateParam(line, paramSpan{valueStart: len(line)}, name, value)
+}
+
+func (propertiesSyntax) newSection(name string) (string, error) {
+	return "", MakeError("properties does not support section[%s]", name)
+}
+
+type redisSyntax struct{}
+
+func (redisSyntax) topSection() string {
+	return ""
+}
+
+func (redisSyntax) isComment(line string) bool {
+	return line[0] == '#'
+}
+
+func (redisSyntax) parseSection(string) (string, bool) {
+	return "", false
+}
+
+func (redisSyntax) parseParam(line string) (paramSpan, func(string) bool, bool) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return paramSpan{}, nil, false
+	}
+	return paramSpan{
+		indent: leadingSpaces(line),
+		name:   args[0],
+		sep:    " ",
+		args:   args,
+	}, nil, true
+}
+
+func (redisSyntax) match(_ string, span paramSpan, key string) bool {
+	keys := strings.Split(key, " ")
+	if len(keys) > len(span.args) {
+		return false
+	}
+	for i, k := range keys {
+		if span.args[i] != k {
+			return false
+		}
+	}
+	return true
+}
+
+func (redisSyntax) placeKey(key string, _ []string) (string, string) {
+	return "", key
+}
+
+func (redisSyntax) updateParam(_ string, span paramSpan, key, value string) (string, error) {
+	return span.indent + key + " " + value, nil
+}
+
+func (redisSyntax) newParam(name, value string, like *paramSpan) (string, error) {
+	return newParamLine(name, value, " ", like), nil
+}
+
+func (redisSyntax) newSection(name string) (string, error) {
+	return "", MakeError("redis config does not support section[%s]", name)
+}
+
+// arrayTablePrefix marks the parameters of a toml array of tables, which are never patched in place.
+const arrayTablePrefix = "[["
+
+type tomlSyntax struct{}
+
+func (tomlSyntax) topSection() string {
+	return ""
+}
+
+func (tomlSyntax) isComment(line string) bool {
+	return line[0] == '#'
+}
+
+func (tomlSyntax) parseSection(line string) (string, bool) {
+	if line[0] != '[' {
+		return "", false
+	}
+	if strings.HasPrefix(line, arrayTablePrefix) {
+		return arrayTablePrefix + line, true
+	}
+	header := line[:inlineCommentIndex(line, "#")]
+	end := strings.LastIndexByte(header, ']')
+	if end < 0 {
+		return "", false
+	}
+	return normalizeTOMLKey(header[1:end]), true
+}
+
+func (tomlSyntax) parseParam(line string) (paramSpan, func(string) bool, bool) {
+	sep := quotedIndexByte(line, '=')
+	if sep < 0 {
+		return paramSpan{}, nil, false
+	}
+	span := paramSpan{
+		indent: leadingSpaces(line),
+		name:   normalizeTOMLKey(line[:sep]),
+	}
+	span.valueStart = sep + 1
+	for span.valueStart < len(line) && (line[span.valueStart] == ' ' || line[span.valueStart] == '\t') {
+		span.valueStart++
+	}
+	span.sep = line[len(strings.TrimRightFunc(line[:sep], unicode.IsSpace)):span.valueStart]
+	span.name = strings.TrimSpace(span.name)
+	value := line[span.valueStart:]
+	span.valueEnd = span.valueStart + inlineCommentIndex(value, "#")
+	span.value = line[span.valueStart:span.valueEnd]
+
+	for _, delim := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, delim) && !strings.Contains(value[3:], delim) {
+			return span, func(next string) bool { return !strings.Contains(next, delim) }, true
+		}
+	}
+	if depth := bracketDepth(value, 0); depth > 0 {
+		return span, func(next string) bool {
+			depth = bracketDepth(next, depth)
+			return depth > 0
+		}, true
+	}
+	return span, nil, true
+}
+
+func (tomlSyntax) match(section string, span paramSpan, key string) bool {
+	if strings.HasPrefix(section, arrayTablePrefix) {
+		return false
+	}
+	if section != "" {
+		return strings.EqualFold(section+unstructured.DelimiterDot+span.name, key)
+	}
+	return strings.EqualFold(span.name, key)
+}
+
+func (tomlSyntax) placeKey(key string, sections []string) (string, string) {
+	section := ""
+	for _, s := range sections {
+		if len(s) > len(section) && !strings.HasPrefix(s, arrayTablePrefix) && strings.HasPrefix(strings.ToLower(key), s+unstructured.DelimiterDot) {
+			section = s
+		}
+	}
+	if section != "" {
+		return section, key[len(section)+1:]
+	}
+	if pos := strings.LastIndex(key, unstructured.DelimiterDot); pos > 0 {
+		return strings.ToLower(key[:pos]), key[pos+1:]
+	}
+	return "", key
+}
+
+func (tomlSyntax) updateParam(line string, span paramSpan, _, value string) (string, error) {
+	return line[:span.valueStart] + formatTOMLValue(value, span.value) + line[span.valueEnd:], nil
+}
+
+func (tomlSyntax) newParam(name, value string, like *paramSpan) (string, error) {
+	if !isTOMLBareKey(name) {
+		return "", MakeError("the parameter[%s] cannot be written in place", name)
+	}
+	return newParamLine(name, formatTOMLValue(value, ""), " = ", like), nil
+}
+
+func (tomlSyntax) newSection(name string) (string, error) {
+	for _, part := range strings.Split(name, unstructured.DelimiterDot) {
+		if !isTOMLBareKey(part) {
+			return "", MakeError("the table[%s] cannot be written in place", name)
+		}
+	}
+	return "[" + name + "]", nil
+}
+
+// splitParamLine splits a "name <sep> value" line, if spaceSep is set, the name can also be terminated by spaces.
+func splitParamLine(line string, seps string, spaceSep bool) (paramSpan, bool) {
+	span := paramSpan{indent: leadingSpaces(line)}
+	pos := len(span.indent)
+	end := pos
+	for ; end < len(line); end++ {
+		c := line[end]
+		if c == '\\' && spaceSep {
+			end++
+			continue
+		}
+		if strings.IndexByte(seps, c) >= 0 || (spaceSep && (c == ' ' || c == '\t')) {
+			break
+		}
+	}
+	if end >= len(line) && !spaceSep {
+		return span, false
+	}
+	span.name = strings.TrimSpace(line[pos:min(end, len(line))])
+
+	start := end
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	if start < len(line) && strings.IndexByte(seps, line[start]) >= 0 {
+		start++
+		for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+			start++
+		}
+	}
+	start = min(start, len(line))
+	nameEnd := len(span.indent) + len(strings.TrimRightFunc(line[len(span.indent):min(end, len(line))], unicode.IsSpace))
+	span.sep = line[nameEnd:start]
+	span.valueStart = start
+	span.valueEnd = len(line)
+	span.value = line[start:]
+	return span, true
+}
+
+func newParamLine(name, value, sep string, like *paramSpan) string {
+	indent := ""
+	if like != nil {
+		indent = like.indent
+		if like.sep != "" {
+			sep = like.sep
+		}
+	}
+	return indent + name + sep + value
+}
+
+func leadingSpaces(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// inlineCommentIndex returns the end of the value before an inline comment, which starts with a space followed by one of the markers.
+func inlineCommentIndex(value string, markers string) int {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.IndexByte(markers, c) >= 0 && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return len(strings.TrimRight(value[:i], " \t"))
+		}
+	}
+	return len(strings.TrimRight(value, " \t"))
+}
+
+// quotedIndexByte returns the index of the first c outside the quoted strings.
+func quotedIndexByte(s string, c byte) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == '\\' && quote == '"' {
+				i++
+			} else if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+func hasLineContinuation(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, "\\"))
+	return n%2 == 1
+}
+
+func unescapePropertiesKey(key string) string {
+	if !strings.Contains(key, "\\") {
+		return key
+	}
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		if key[i] == '\\' && i+1 < len(key) {
+			i++
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+func normalizeTOMLKey(key string) string {
+	parts := strings.Split(key, unstructured.DelimiterDot)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if unquoted, err := strconv.Unquote(part); err == nil {
+			part = unquoted
+		} else {
+			part = strings.Trim(part, "'")
+		}
+		parts[i] = part
+	}
+	return strings.ToLower(strings.Join(parts, unstructured.DelimiterDot))
+}
+
+// bracketDepth returns the nesting depth of the arrays and inline tables after the line.
+func bracketDepth(line string, depth int) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return depth
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+func isTOMLBareKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if !(c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTOMLValue renders the value as a toml literal, the quote style of the original value is kept.
+func formatTOMLValue(value string, original string) string {
+	switch {
+	case strings.HasPrefix(original, "'") && !strings.HasPrefix(original, "'''") && !strings.Contains(value, "'"):
+		return "'" + value + "'"
+	case strings.HasPrefix(original, `"`):
+		return strconv.Quote(value)
+	case value == "true" || value == "false":
+		return value
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") && !strings.ContainsAny(value, "xXpP") {
+		return value
+	}
+	return strconv.Quote(value)
+}
@@ -113,8 +113,7 @@ func ApplyConfigPatch(baseCfg []byte, updatedParameters map[string]*string, form
 	if err != nil {
 		return "", err
 	}
-	mergedConfig := configWrapper.getConfigObject(mergedOptions)
-	return mergedConfig.Marshal()
+	return configWrapper.marshal(configWrapper.getConfigObject(mergedOptions))
 }
 
 func NeedReloadVolume(config appsv1.ComponentConfigSpec) bool {
@@ -44,6 +44,18 @@ const (
 	// The mutation check is only applied to the fields that are declared as immutable.
 	SkipImmutableCheckAnnotationKey = "apps.kubeblocks.io/skip-immutable-check"
 
+	// ReadonlyOnStopAnnotationKey marks the replicas of a stopped workload have been switched into the read-only state,
+	// and they should be brought back to the read-write state once the workload is started again.
+	ReadonlyOnStopAnnotationKey = "apps.kubeblocks.io/readonly-on-stop"
+
+	// ReadonlyOnVolumeFullAnnotationKey records the replicas that have been switched into the read-only state since
+	// their volumes exceed the high watermark, they are brought back to the read-write state once the volumes are freed.
+	ReadonlyOnVolumeFullAnnotationKey = "apps.kubeblocks.io/readonly-on-volume-full"
+
+	// SwitchoverMaxReplicationLagAnnotationKey specifies the max replication lag of the candidate, when the controller
+	// switches over the leader by the replication lag of the replicas, e.g., before the leader is scaled in.
+	SwitchoverMaxReplicationLagAnnotationKey = "apps.kubeblocks.io/switchover-max-replication-lag"
+
 	// NodeSelectorOnceAnnotationKey adds nodeSelector in podSpec for one pod exactly once
 	NodeSelectorOnceAnnotationKey = "workloads.kubeblocks.io/node-selector-once"
 )
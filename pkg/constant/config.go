@@ -57,6 +57,10 @@ const (
 	KBParameterUpdateSourceAnnotationKey        = "config.kubeblocks.io/reconfigure-source"
 	UpgradeRestartAnnotationKey                 = "config.kubeblocks.io/restart"
 	ConfigAppliedVersionAnnotationKey           = "config.kubeblocks.io/config-applied-version"
+
+	// ReconfigureActionResultAnnotationKey records the result of the reconfigure lifecycle action on the config ConfigMap,
+	// which is surfaced as the Reconfigured condition of the component.
+	ReconfigureActionResultAnnotationKey = "config.kubeblocks.io/reconfigure-action-result"
 )
 
 const (
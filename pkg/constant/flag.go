@@ -36,4 +36,12 @@ const (
 	// FeatureGateInPlacePodVerticalScaling specifies to enable in-place pod vertical scaling
 	// NOTE: This feature depends on the InPlacePodVerticalScaling feature of the K8s cluster in which the KubeBlocks runs.
 	FeatureGateInPlacePodVerticalScaling = "IN_PLACE_POD_VERTICAL_SCALING"
+
+	// FeatureGateKBAgentAuthentication specifies to enable the authentication of the kb-agent service,
+	// the kb-agent requires a bearer token, and the mutual TLS if the TLS of component is enabled.
+	FeatureGateKBAgentAuthentication = "KBAGENT_AUTHENTICATION"
+
+	// FeatureGateKBAgentJournal specifies to persist the journal of action executions of the kb-agent in a volume,
+	// enabling it restarts the pods of the existing clusters to add the volume.
+	FeatureGateKBAgentJournal = "KBAGENT_JOURNAL"
 )
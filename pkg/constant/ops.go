@@ -32,4 +32,5 @@ const (
 	DisableHAAnnotationKey             = "operations.kubeblocks.io/disable-ha"
 	RelatedOpsAnnotationKey            = "operations.kubeblocks.io/related-ops"
 	OpsDependentOnSuccessfulOpsAnnoKey = "operations.kubeblocks.io/dependent-on-successful-ops" // OpsDependentOnSuccessfulOpsAnnoKey wait for the dependent ops to succeed before executing the current ops. If it fails, this ops will also fail.
+	ApprovedRolloutStagesAnnotationKey = "operations.kubeblocks.io/approved-rollout-stages"     // ApprovedRolloutStagesAnnotationKey the comma-separated names of the rollout stages approved to move to the next stage.
 )
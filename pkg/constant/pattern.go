@@ -35,6 +35,11 @@ func GenerateAccountSecretName(clusterName, compName, name string) string {
 	return fmt.Sprintf("%s-%s-account-%s", clusterName, compName, replacedName)
 }
 
+// GenerateKBAgentTokenSecretName generates the secret name of the kb-agent token.
+func GenerateKBAgentTokenSecretName(clusterName, compName string) string {
+	return fmt.Sprintf("%s-%s-kbagent-token", clusterName, compName)
+}
+
 // GenerateClusterServiceName generates the service name for cluster.
 func GenerateClusterServiceName(clusterName, svcName string) string {
 	if len(svcName) > 0 {
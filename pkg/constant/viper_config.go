@@ -32,6 +32,7 @@ const (
 	CfgHostPortConfigMapName            = "HOST_PORT_CM_NAME"
 	CfgHostPortIncludeRanges            = "HOST_PORT_INCLUDE_RANGES"
 	CfgHostPortExcludeRanges            = "HOST_PORT_EXCLUDE_RANGES"
+	CfgKeyRolloutQueryEndpoints         = "ROLLOUT_QUERY_ENDPOINTS" // the comma-separated endpoints allowed by the query gates of the staged rollouts
 
 	// addon config keys
 	CfgKeyAddonJobTTL        = "ADDON_JOB_TTL"
@@ -204,6 +204,7 @@ func actionsToResolveImage(compDef *appsv1.ComponentDefinition) map[string]*apps
 		normalize("postProvision"):    compDef.Spec.LifecycleActions.PostProvision,
 		normalize("preTerminate"):     compDef.Spec.LifecycleActions.PreTerminate,
 		normalize("switchover"):       compDef.Spec.LifecycleActions.Switchover,
+		normalize("replicationLag"):   compDef.Spec.LifecycleActions.ReplicationLag,
 		normalize("memberJoin"):       compDef.Spec.LifecycleActions.MemberJoin,
 		normalize("memberLeave"):      compDef.Spec.LifecycleActions.MemberLeave,
 		normalize("readonly"):         compDef.Spec.LifecycleActions.Readonly,
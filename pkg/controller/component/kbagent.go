@@ -22,6 +22,8 @@ package component
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,10 +43,16 @@ const (
 	kbAgentCommand              = "/bin/kbagent"
 	kbAgentSharedMountPath      = "/kubeblocks"
 	kbAgentCommandOnSharedMount = "/kubeblocks/kbagent"
+	kbAgentJournalVolumeName    = "kbagent-journal"
+	kbAgentJournalMountPath     = "/var/lib/kbagent"
+	kbAgentJournalDir           = kbAgentJournalMountPath + "/journal"
+	kbAgentTokenMountPath       = "/var/run/kbagent"
+	kbAgentVolumeMountPath      = "/kbagent-volumes"
 
 	minAvailablePort   = 1025
 	maxAvailablePort   = 65535
 	kbAgentDefaultPort = 3501
+	kbAgentMetricsPort = 3502
 )
 
 var (
@@ -63,9 +71,12 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 
 	httpPort := 0
 	for _, port := range c.Ports {
-		if port.Name == kbagent.DefaultPortName {
+		switch port.Name {
+		case kbagent.DefaultPortName:
 			httpPort = int(port.ContainerPort)
-			break
+		case kbagent.MetricsPortName:
+			// update metrics port in args
+			updateKBAgentPortArg(c, kbagent.MetricsPortFlag, int(port.ContainerPort))
 		}
 	}
 	if httpPort == 0 {
@@ -73,12 +84,7 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 	}
 
 	// update port in args
-	for i, arg := range c.Args {
-		if arg == "--port" {
-			c.Args[i+1] = strconv.Itoa(httpPort)
-			break
-		}
-	}
+	updateKBAgentPortArg(c, "--port", httpPort)
 
 	// update startup probe
 	if c.StartupProbe != nil && c.StartupProbe.TCPSocket != nil {
@@ -88,6 +94,15 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 	synthesizedComp.PodSpec.Containers[idx] = *c
 }
 
+func updateKBAgentPortArg(c *corev1.Container, flag string, port int) {
+	for i, arg := range c.Args {
+		if arg == flag && i+1 < len(c.Args) {
+			c.Args[i+1] = strconv.Itoa(port)
+			break
+		}
+	}
+}
+
 func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 	if synthesizedComp.LifecycleActions == nil {
 		return nil
@@ -125,24 +140,102 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 	if err = adaptKBAgentIfCustomImageNContainerDefined(synthesizedComp, container); err != nil {
 		return err
 	}
+	buildKBAgentVolumeMounts(synthesizedComp, container)
+
+	// the journal is kept in memory unless the feature is enabled, adding the volume to the pods of
+	// the existing clusters would restart them.
+	if viper.GetBool(constant.FeatureGateKBAgentJournal) {
+		buildKBAgentJournal(synthesizedComp, container)
+	}
+	if viper.GetBool(constant.FeatureGateKBAgentAuthentication) {
+		if err = buildKBAgentMetricsPort(synthesizedComp, container); err != nil {
+			return err
+		}
+		buildKBAgentAuthentication(synthesizedComp, container)
+	}
 
 	// set kb-agent container ports to host network
 	if synthesizedComp.HostNetwork != nil {
 		if synthesizedComp.HostNetwork.ContainerPorts == nil {
 			synthesizedComp.HostNetwork.ContainerPorts = make([]appsv1.HostNetworkContainerPort, 0)
 		}
+		portNames := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			portNames = append(portNames, port.Name)
+		}
 		synthesizedComp.HostNetwork.ContainerPorts = append(
 			synthesizedComp.HostNetwork.ContainerPorts,
 			appsv1.HostNetworkContainerPort{
 				Container: container.Name,
-				Ports:     []string{kbagent.DefaultPortName},
+				Ports:     portNames,
 			})
 	}
-
 	synthesizedComp.PodSpec.Containers = append(synthesizedComp.PodSpec.Containers, *container)
 	return nil
 }
 
+// buildKBAgentJournal persists the journal of action executions, so it survives the restarts of kb-agent container.
+func buildKBAgentJournal(synthesizedComp *SynthesizedComponent, container *corev1.Container) {
+	container.Args = append(container.Args, "--journal-dir", kbAgentJournalDir)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      kbAgentJournalVolumeName,
+		MountPath: kbAgentJournalMountPath,
+	})
+	synthesizedComp.PodSpec.Volumes = append(synthesizedComp.PodSpec.Volumes, corev1.Volume{
+		Name: kbAgentJournalVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+}
+
+// buildKBAgentMetricsPort serves the metrics on a dedicated port, which is exempted from the authentication
+// of kb-agent, so the metrics can be scraped without the token and client certificates.
+func buildKBAgentMetricsPort(synthesizedComp *SynthesizedComponent, container *corev1.Container) error {
+	containers := append(slices.Clone(synthesizedComp.PodSpec.Containers), *container)
+	ports, err := getAvailablePorts(containers, []int32{int32(kbAgentMetricsPort)})
+	if err != nil {
+		return err
+	}
+	container.Args = append(container.Args, kbagent.MetricsPortFlag, strconv.Itoa(int(ports[0])))
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		ContainerPort: ports[0],
+		Name:          kbagent.MetricsPortName,
+		Protocol:      "TCP",
+	})
+	return nil
+}
+
+// buildKBAgentAuthentication requires the clients of kb-agent to present the bearer token generated for the component,
+// and enables the mutual TLS with the certificates of component if the TLS is enabled.
+func buildKBAgentAuthentication(synthesizedComp *SynthesizedComponent, container *corev1.Container) {
+	container.Args = append(container.Args, "--token-file", filepath.Join(kbAgentTokenMountPath, kbagent.TokenSecretKey))
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      kbagent.TokenVolumeName,
+		MountPath: kbAgentTokenMountPath,
+		ReadOnly:  true,
+	})
+	mode := int32(0400)
+	synthesizedComp.PodSpec.Volumes = append(synthesizedComp.PodSpec.Volumes, corev1.Volume{
+		Name: kbagent.TokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  constant.GenerateKBAgentTokenSecretName(synthesizedComp.ClusterName, synthesizedComp.Name),
+				Items:       []corev1.KeyToPath{{Key: kbagent.TokenSecretKey, Path: kbagent.TokenSecretKey}},
+				DefaultMode: &mode,
+			},
+		},
+	})
+
+	// the TLS volume is mounted into all containers, including kb-agent
+	if synthesizedComp.TLSConfig != nil && synthesizedComp.TLSConfig.Enable {
+		container.Args = append(container.Args,
+			kbagent.TLSCertFileFlag, filepath.Join(constant.MountPath, constant.CertName),
+			"--tls-key-file", filepath.Join(constant.MountPath, constant.KeyName),
+			"--tls-client-ca-file", filepath.Join(constant.MountPath, constant.CAName))
+	}
+}
+
 func mergedActionEnv4KBAgent(synthesizedComp *SynthesizedComponent) []corev1.EnvVar {
 	env := make([]corev1.EnvVar, 0)
 	envSet := sets.New[string]()
@@ -162,6 +255,7 @@ func mergedActionEnv4KBAgent(synthesizedComp *SynthesizedComponent) []corev1.Env
 		synthesizedComp.LifecycleActions.PostProvision,
 		synthesizedComp.LifecycleActions.PreTerminate,
 		synthesizedComp.LifecycleActions.Switchover,
+		synthesizedComp.LifecycleActions.ReplicationLag,
 		synthesizedComp.LifecycleActions.MemberJoin,
 		synthesizedComp.LifecycleActions.MemberLeave,
 		synthesizedComp.LifecycleActions.Readonly,
@@ -173,6 +267,8 @@ func mergedActionEnv4KBAgent(synthesizedComp *SynthesizedComponent) []corev1.Env
 	} {
 		checkedAppend(action)
 	}
+	checkedAppend(shardProvisionAction(synthesizedComp))
+	checkedAppend(shardTerminateAction(synthesizedComp))
 	if synthesizedComp.LifecycleActions.RoleProbe != nil {
 		checkedAppend(&synthesizedComp.LifecycleActions.RoleProbe.Action)
 	}
@@ -186,59 +282,148 @@ func buildKBAgentStartupEnvs(synthesizedComp *SynthesizedComponent) ([]corev1.En
 		probes  []proto.Probe
 	)
 
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.PostProvision, "postProvision"); a != nil {
-		actions = append(actions, *a)
-	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.PreTerminate, "preTerminate"); a != nil {
-		actions = append(actions, *a)
-	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Switchover, "switchover"); a != nil {
-		actions = append(actions, *a)
-	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.MemberJoin, "memberJoin"); a != nil {
-		actions = append(actions, *a)
-	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.MemberLeave, "memberLeave"); a != nil {
-		actions = append(actions, *a)
-	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Readonly, "readonly"); a != nil {
-		actions = append(actions, *a)
+	for _, item := range []struct {
+		action *appsv1.Action
+		name   string
+	}{
+		{synthesizedComp.LifecycleActions.PostProvision, "postProvision"},
+		{synthesizedComp.LifecycleActions.PreTerminate, "preTerminate"},
+		{synthesizedComp.LifecycleActions.Switchover, "switchover"},
+		{synthesizedComp.LifecycleActions.ReplicationLag, "replicationLag"},
+		{synthesizedComp.LifecycleActions.MemberJoin, "memberJoin"},
+		{synthesizedComp.LifecycleActions.MemberLeave, "memberLeave"},
+		{synthesizedComp.LifecycleActions.Readonly, "readonly"},
+		{synthesizedComp.LifecycleActions.Readwrite, "readwrite"},
+		{synthesizedComp.LifecycleActions.DataDump, "dataDump"},
+		{synthesizedComp.LifecycleActions.DataLoad, "dataLoad"},
+		{synthesizedComp.LifecycleActions.Reconfigure, "reconfigure"},
+		{synthesizedComp.LifecycleActions.AccountProvision, "accountProvision"},
+		{shardProvisionAction(synthesizedComp), "shardProvision"},
+		{shardTerminateAction(synthesizedComp), "shardTerminate"},
+	} {
+		a, err := buildAction4KBAgent(synthesizedComp, item.action, item.name)
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			actions = append(actions, *a)
+		}
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Readwrite, "readwrite"); a != nil {
-		actions = append(actions, *a)
+
+	a, p, err := buildProbe4KBAgent(synthesizedComp, synthesizedComp.LifecycleActions.RoleProbe, "roleProbe")
+	if err != nil {
+		return nil, err
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.DataDump, "dataDump"); a != nil {
+	if a != nil && p != nil {
 		actions = append(actions, *a)
+		probes = append(probes, *p)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.DataLoad, "dataLoad"); a != nil {
-		actions = append(actions, *a)
+
+	return kbagent.BuildStartupEnv(actions, probes, buildVolumes4KBAgent(synthesizedComp))
+}
+
+// ProtectedVolumes returns the volumes whose usage is watched against the high watermark, they are switched into
+// the read-only state by the readonly action when the usage exceeds the high watermark.
+func ProtectedVolumes(synthesizedComp *SynthesizedComponent) []appsv1.ComponentVolume {
+	if synthesizedComp.LifecycleActions == nil || synthesizedComp.LifecycleActions.Readonly == nil {
+		return nil
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Reconfigure, "reconfigure"); a != nil {
-		actions = append(actions, *a)
+	volumes := make([]appsv1.ComponentVolume, 0)
+	for _, vol := range synthesizedComp.Volumes {
+		if vol.HighWatermark <= 0 {
+			continue
+		}
+		// only the volumes mounted by the containers of engine can be protected
+		if slices.ContainsFunc(synthesizedComp.PodSpec.Containers, func(c corev1.Container) bool {
+			return !IsKBAgentContainer(&c) && slices.ContainsFunc(c.VolumeMounts, func(m corev1.VolumeMount) bool {
+				return m.Name == vol.Name
+			})
+		}) {
+			volumes = append(volumes, vol)
+		}
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.AccountProvision, "accountProvision"); a != nil {
-		actions = append(actions, *a)
+	return volumes
+}
+
+func buildVolumes4KBAgent(synthesizedComp *SynthesizedComponent) []proto.Volume {
+	var volumes []proto.Volume
+	for _, vol := range ProtectedVolumes(synthesizedComp) {
+		volumes = append(volumes, proto.Volume{
+			Name:      vol.Name,
+			MountPath: filepath.Join(kbAgentVolumeMountPath, vol.Name),
+		})
 	}
+	return volumes
+}
 
-	if a, p := buildProbe4KBAgent(synthesizedComp.LifecycleActions.RoleProbe, "roleProbe"); a != nil && p != nil {
-		actions = append(actions, *a)
-		probes = append(probes, *p)
+// buildKBAgentVolumeMounts mounts the protected volumes into kb-agent in read-only mode, so their usage can be reported.
+func buildKBAgentVolumeMounts(synthesizedComp *SynthesizedComponent, container *corev1.Container) {
+	for _, vol := range buildVolumes4KBAgent(synthesizedComp) {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      vol.Name,
+			MountPath: vol.MountPath,
+			ReadOnly:  true,
+		})
 	}
+}
 
-	return kbagent.BuildStartupEnv(actions, probes)
+func shardProvisionAction(synthesizedComp *SynthesizedComponent) *appsv1.Action {
+	if synthesizedComp.ShardingLifecycleActions == nil {
+		return nil
+	}
+	return synthesizedComp.ShardingLifecycleActions.ShardProvision
 }
 
-func buildAction4KBAgent(action *appsv1.Action, name string) *proto.Action {
-	if action == nil || action.Exec == nil {
+func shardTerminateAction(synthesizedComp *SynthesizedComponent) *appsv1.Action {
+	if synthesizedComp.ShardingLifecycleActions == nil {
 		return nil
 	}
+	return synthesizedComp.ShardingLifecycleActions.ShardTerminate
+}
+
+func buildAction4KBAgent(synthesizedComp *SynthesizedComponent, action *appsv1.Action, name string) (*proto.Action, error) {
+	if action == nil || (action.Exec == nil && action.HTTP == nil && action.GRPC == nil) {
+		return nil, nil
+	}
 	a := &proto.Action{
-		Name: name,
-		Exec: &proto.ExecAction{
+		Name:           name,
+		TimeoutSeconds: action.TimeoutSeconds,
+	}
+	switch {
+	case action.Exec != nil:
+		a.Exec = &proto.ExecAction{
 			Commands: action.Exec.Command,
 			Args:     action.Exec.Args,
-		},
-		TimeoutSeconds: action.TimeoutSeconds,
+		}
+	case action.HTTP != nil:
+		port, err := resolveActionPort(synthesizedComp, action.HTTP.Port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port of http action %s: %s", name, err.Error())
+		}
+		a.HTTP = &proto.HTTPAction{
+			Scheme:             string(action.HTTP.Scheme),
+			Host:               action.HTTP.Host,
+			Port:               port,
+			Method:             action.HTTP.Method,
+			Path:               action.HTTP.Path,
+			Body:               action.HTTP.Body,
+			InsecureSkipVerify: action.HTTP.InsecureSkipVerify,
+		}
+		for _, h := range action.HTTP.Headers {
+			a.HTTP.Headers = append(a.HTTP.Headers, proto.HTTPHeader{Name: h.Name, Value: h.Value})
+		}
+	case action.GRPC != nil:
+		port, err := resolveActionPort(synthesizedComp, action.GRPC.Port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port of grpc action %s: %s", name, err.Error())
+		}
+		a.GRPC = &proto.GRPCAction{
+			Host:    action.GRPC.Host,
+			Port:    port,
+			Service: action.GRPC.Service,
+			Method:  action.GRPC.Method,
+			Request: action.GRPC.Request,
+		}
 	}
 	if action.RetryPolicy != nil {
 		a.RetryPolicy = &proto.RetryPolicy{
@@ -246,14 +431,17 @@ func buildAction4KBAgent(action *appsv1.Action, name string) *proto.Action {
 			RetryInterval: action.RetryPolicy.RetryInterval,
 		}
 	}
-	return a
+	return a, nil
 }
 
-func buildProbe4KBAgent(probe *appsv1.Probe, name string) (*proto.Action, *proto.Probe) {
-	if probe == nil || probe.Exec == nil {
-		return nil, nil
+func buildProbe4KBAgent(synthesizedComp *SynthesizedComponent, probe *appsv1.Probe, name string) (*proto.Action, *proto.Probe, error) {
+	if probe == nil {
+		return nil, nil, nil
+	}
+	a, err := buildAction4KBAgent(synthesizedComp, &probe.Action, name)
+	if err != nil || a == nil {
+		return nil, nil, err
 	}
-	a := buildAction4KBAgent(&probe.Action, name)
 	p := &proto.Probe{
 		Action:              name,
 		InitialDelaySeconds: probe.InitialDelaySeconds,
@@ -262,7 +450,25 @@ func buildProbe4KBAgent(probe *appsv1.Probe, name string) (*proto.Action, *proto
 		FailureThreshold:    probe.FailureThreshold,
 		ReportPeriodSeconds: nil, // TODO: impl
 	}
-	return a, p
+	return a, p, nil
+}
+
+// resolveActionPort resolves the port of HTTP and gRPC actions, the named port is looked up in the runtime containers.
+func resolveActionPort(synthesizedComp *SynthesizedComponent, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		if port.IntVal < 1 || port.IntVal > maxAvailablePort {
+			return 0, fmt.Errorf("port %d is out of range", port.IntVal)
+		}
+		return port.IntVal, nil
+	}
+	for _, c := range synthesizedComp.PodSpec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("named port %s is not found", port.StrVal)
 }
 
 func adaptKBAgentIfCustomImageNContainerDefined(synthesizedComp *SynthesizedComponent, container *corev1.Container) error {
@@ -298,6 +504,7 @@ func customExecActionImageNContainer(synthesizedComp *SynthesizedComponent) (str
 		synthesizedComp.LifecycleActions.PostProvision,
 		synthesizedComp.LifecycleActions.PreTerminate,
 		synthesizedComp.LifecycleActions.Switchover,
+		synthesizedComp.LifecycleActions.ReplicationLag,
 		synthesizedComp.LifecycleActions.MemberJoin,
 		synthesizedComp.LifecycleActions.MemberLeave,
 		synthesizedComp.LifecycleActions.Readonly,
@@ -306,6 +513,8 @@ func customExecActionImageNContainer(synthesizedComp *SynthesizedComponent) (str
 		synthesizedComp.LifecycleActions.DataLoad,
 		synthesizedComp.LifecycleActions.Reconfigure,
 		synthesizedComp.LifecycleActions.AccountProvision,
+		shardProvisionAction(synthesizedComp),
+		shardTerminateAction(synthesizedComp),
 	}
 	if synthesizedComp.LifecycleActions.RoleProbe != nil && synthesizedComp.LifecycleActions.RoleProbe.Exec != nil {
 		actions = append(actions, &synthesizedComp.LifecycleActions.RoleProbe.Action)
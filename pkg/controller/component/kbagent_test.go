@@ -146,6 +146,59 @@ var _ = Describe("kb-agent", func() {
 			Expect(c.Ports[0].ContainerPort).Should(Equal(int32(kbAgentDefaultPort + 1)))
 		})
 
+		It("authentication - disabled", func() {
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Args).ShouldNot(ContainElement("--token-file"))
+		})
+
+		It("authentication", func() {
+			viperx.Set(constant.FeatureGateKBAgentAuthentication, true)
+			defer viperx.Set(constant.FeatureGateKBAgentAuthentication, false)
+
+			synthesizedComp.ClusterName = "test-cluster"
+			synthesizedComp.Name = "test-comp"
+			synthesizedComp.TLSConfig = &appsv1.TLSConfig{Enable: true}
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Args).Should(ContainElements("--token-file", "--tls-cert-file", "--tls-key-file", "--tls-client-ca-file"))
+			Expect(c.VolumeMounts).Should(ContainElement(HaveField("Name", kbagent.TokenVolumeName)))
+			Expect(synthesizedComp.PodSpec.Volumes).Should(ContainElement(HaveField("VolumeSource.Secret.SecretName", "test-cluster-test-comp-kbagent-token")))
+			Expect(kbagent.IsTLSEnabled(&corev1.Pod{Spec: *synthesizedComp.PodSpec})).Should(BeTrue())
+			Expect(c.Args).Should(ContainElements(kbagent.MetricsPortFlag, "3502"))
+			Expect(c.Ports).Should(ContainElement(HaveField("Name", kbagent.MetricsPortName)))
+		})
+
+		It("journal - disabled", func() {
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Args).ShouldNot(ContainElement("--journal-dir"))
+			Expect(synthesizedComp.PodSpec.Volumes).ShouldNot(ContainElement(HaveField("Name", kbAgentJournalVolumeName)))
+		})
+
+		It("journal", func() {
+			viperx.Set(constant.FeatureGateKBAgentJournal, true)
+			defer viperx.Set(constant.FeatureGateKBAgentJournal, false)
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Args).Should(ContainElements("--journal-dir", kbAgentJournalDir))
+			Expect(c.VolumeMounts).Should(ContainElement(HaveField("Name", kbAgentJournalVolumeName)))
+			Expect(synthesizedComp.PodSpec.Volumes).Should(ContainElement(HaveField("Name", kbAgentJournalVolumeName)))
+		})
+
 		It("startup env", func() {
 			err := buildKBAgentContainer(synthesizedComp)
 			Expect(err).Should(BeNil())
@@ -155,6 +208,41 @@ var _ = Describe("kb-agent", func() {
 			Expect(c.Env).Should(HaveLen(6))
 		})
 
+		It("volume protection", func() {
+			synthesizedComp.PodSpec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+				{Name: "data", MountPath: "/data"},
+				{Name: "log", MountPath: "/log"},
+			}
+			synthesizedComp.Volumes = []appsv1.ComponentVolume{
+				{Name: "data", HighWatermark: 90},
+				{Name: "log"},
+				{Name: "not-mounted", HighWatermark: 90},
+			}
+
+			By("the readonly action is not defined")
+			Expect(ProtectedVolumes(synthesizedComp)).Should(BeEmpty())
+
+			synthesizedComp.LifecycleActions.Readonly = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"echo", "readonly"},
+				},
+			}
+			Expect(ProtectedVolumes(synthesizedComp)).Should(HaveExactElements(HaveField("Name", "data")))
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.VolumeMounts).Should(ContainElement(corev1.VolumeMount{
+				Name:      "data",
+				MountPath: kbAgentVolumeMountPath + "/data",
+				ReadOnly:  true,
+			}))
+			Expect(c.VolumeMounts).ShouldNot(ContainElement(HaveField("Name", "log")))
+			Expect(c.Env).Should(ContainElement(HaveField("Name", "KB_AGENT_VOLUME")))
+		})
+
 		It("action env", func() {
 			env := []corev1.EnvVar{
 				{
@@ -182,6 +270,31 @@ var _ = Describe("kb-agent", func() {
 			Expect(reflect.DeepEqual(c.Env[1], env[1])).Should(BeTrue())
 		})
 
+		It("shard actions", func() {
+			synthesizedComp.ShardingLifecycleActions = &appsv1.ShardingLifecycleActions{
+				ShardProvision: &appsv1.Action{
+					Exec: &appsv1.ExecAction{
+						Command: []string{"/bin/bash", "-c", "echo -n shard-provision"},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "SHARD_ENV",
+								Value: "shard",
+							},
+						},
+					},
+				},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Env).Should(HaveLen(7))
+			Expect(c.Env).Should(ContainElement(HaveField("Name", "SHARD_ENV")))
+			Expect(c.Env).Should(ContainElement(And(HaveField("Name", "KB_AGENT_ACTION"), HaveField("Value", ContainSubstring(`"name":"shardProvision"`)))))
+		})
+
 		It("custom image", func() {
 			image := "custom-image"
 			synthesizedComp.LifecycleActions.PostProvision.Exec.Image = image
@@ -0,0 +1,173 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+)
+
+var ErrNoQualifiedCandidate = errors.New("no qualified candidate")
+
+// CandidatePolicy defines how to select the candidate of a switchover by the replication lag of the replicas.
+type CandidatePolicy struct {
+	// MaxLag is the max replication lag of a qualified candidate, nil means no limit.
+	MaxLag *int64
+	// PreferSameZone prefers the qualified candidates in the same zone as the current leader.
+	PreferSameZone bool
+	// Excluded are the replicas that can't be the candidate, e.g., the ones to be scaled in.
+	Excluded []string
+}
+
+// LagReading is the replication lag reported by a candidate.
+type LagReading struct {
+	PodName string
+	Zone    string
+	Lag     *int64
+	Error   error
+}
+
+// CandidateDecision records how the candidate of a switchover is selected.
+type CandidateDecision struct {
+	// Candidate is the selected candidate, it is empty if no candidate is qualified.
+	Candidate string
+	Message   string
+	Readings  []LagReading
+}
+
+// SelectSwitchoverCandidate selects the most up-to-date replica as the candidate of switchover, the replicas in the same
+// zone as the current leader are preferred if the policy asks.
+//
+// The replication lag of each ready replica other than the leader and the excluded ones is reported by the replicationLag action, it returns
+// ErrActionNotDefined if the action is not defined, and ErrNoQualifiedCandidate along with the decision if no replica
+// is qualified, e.g., all the lags exceed the max lag of the policy.
+func SelectSwitchoverCandidate(ctx context.Context, cli client.Reader, synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod, policy CandidatePolicy) (*CandidateDecision, error) {
+	if synthesizedComp.LifecycleActions == nil || !actionDefined(synthesizedComp.LifecycleActions.ReplicationLag) {
+		return nil, errors.Wrap(ErrActionNotDefined, (&replicationLag{}).name())
+	}
+	var leader *corev1.Pod
+	if role, err := leaderRole(synthesizedComp.Roles); err == nil {
+		for i, pod := range pods {
+			if pod.Labels[constant.RoleLabelKey] == role {
+				leader = pods[i]
+			}
+		}
+	}
+	var readings []LagReading
+	for i, pod := range pods {
+		if (leader != nil && pod.Name == leader.Name) || slices.Contains(policy.Excluded, pod.Name) ||
+			!pod.DeletionTimestamp.IsZero() || !podutils.IsPodReady(pod) {
+			continue
+		}
+		reading := LagReading{PodName: pod.Name, Zone: podZone(ctx, cli, pod)}
+		lfa, err := New(synthesizedComp, pods[i], pods...)
+		if err != nil {
+			return nil, err
+		}
+		lag, err := lfa.ReplicationLag(ctx, cli, nil)
+		if err != nil {
+			reading.Error = err
+		} else {
+			reading.Lag = &lag
+		}
+		readings = append(readings, reading)
+	}
+	leaderZone := ""
+	if leader != nil {
+		leaderZone = podZone(ctx, cli, leader)
+	}
+	decision := rankCandidates(readings, leaderZone, policy)
+	if decision.Candidate == "" {
+		return decision, errors.Wrap(ErrNoQualifiedCandidate, decision.Message)
+	}
+	return decision, nil
+}
+
+// rankCandidates selects the qualified candidate with the minimum lag, the ones in the same zone as the leader
+// take precedence if the policy prefers, and the name breaks the tie.
+func rankCandidates(readings []LagReading, leaderZone string, policy CandidatePolicy) *CandidateDecision {
+	decision := &CandidateDecision{Readings: readings}
+	var qualified []LagReading
+	for _, reading := range readings {
+		if reading.Lag == nil || (policy.MaxLag != nil && *reading.Lag > *policy.MaxLag) {
+			continue
+		}
+		qualified = append(qualified, reading)
+	}
+	if len(qualified) == 0 {
+		switch {
+		case len(readings) == 0:
+			decision.Message = "no ready replica to switch over to"
+		case policy.MaxLag != nil:
+			decision.Message = fmt.Sprintf("the replication lags of all the candidates are unknown or exceed %d", *policy.MaxLag)
+		default:
+			decision.Message = "the replication lags of all the candidates are unknown"
+		}
+		return decision
+	}
+	sameZone := func(reading LagReading) bool {
+		return policy.PreferSameZone && leaderZone != "" && reading.Zone == leaderZone
+	}
+	slices.SortFunc(qualified, func(a, b LagReading) int {
+		if sameZone(a) != sameZone(b) {
+			if sameZone(a) {
+				return -1
+			}
+			return 1
+		}
+		if *a.Lag != *b.Lag {
+			if *a.Lag < *b.Lag {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.PodName, b.PodName)
+	})
+	selected := qualified[0]
+	decision.Candidate = selected.PodName
+	decision.Message = fmt.Sprintf("select the replica %s with the replication lag %d", selected.PodName, *selected.Lag)
+	if sameZone(selected) {
+		decision.Message += fmt.Sprintf(" in the same zone %s as the leader", leaderZone)
+	}
+	return decision
+}
+
+// podZone returns the zone of the node which the pod is running on, it is empty if unknown.
+func podZone(ctx context.Context, cli client.Reader, pod *corev1.Pod) string {
+	if pod.Spec.NodeName == "" {
+		return ""
+	}
+	node := &corev1.Node{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+		return ""
+	}
+	return node.Labels[corev1.LabelTopologyZone]
+}
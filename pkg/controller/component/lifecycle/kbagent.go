@@ -23,10 +23,13 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
@@ -88,6 +91,58 @@ func (a *kbagent) Switchover(ctx context.Context, cli client.Reader, opts *Optio
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Switchover, lfa, opts))
 }
 
+func (a *kbagent) ReplicationLag(ctx context.Context, cli client.Reader, opts *Options) (int64, error) {
+	spec := a.synthesizedComp.LifecycleActions.ReplicationLag
+	lfa := &replicationLag{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+		leader:      a.leader(),
+	}
+	if !actionDefined(spec) {
+		return 0, errors.Wrap(ErrActionNotDefined, lfa.name())
+	}
+	if err := a.precondition(ctx, cli, spec); err != nil {
+		return 0, err
+	}
+	req, err := a.buildActionRequest(ctx, cli, lfa, opts)
+	if err != nil {
+		return 0, err
+	}
+	// the lag is a per-replica reading, always check the replica itself regardless of the pod selector
+	agent, err := a.agentClient(ctx, cli, a.pod, lfa)
+	if err != nil || agent == nil {
+		return 0, err
+	}
+	rsp, err := agent.Action(ctx, *req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "http error occurred when executing action %s at pod %s", lfa.name(), a.pod.Name)
+	}
+	if len(rsp.Error) > 0 {
+		return 0, a.formatError(lfa, rsp)
+	}
+	lag, err := strconv.ParseInt(strings.TrimSpace(string(rsp.Output)), 10, 64)
+	if err != nil || lag < 0 {
+		return 0, errors.Wrapf(ErrActionFailed, "action: %s, invalid output: %q", lfa.name(), string(rsp.Output))
+	}
+	return lag, nil
+}
+
+// leader returns the pod which has the writable and serviceable role, or nil if there is none.
+func (a *kbagent) leader() *corev1.Pod {
+	role, err := leaderRole(a.synthesizedComp.Roles)
+	if err != nil {
+		return nil
+	}
+	for i, pod := range a.pods {
+		if pod.Labels[constant.RoleLabelKey] == role {
+			return a.pods[i]
+		}
+	}
+	return nil
+}
+
 func (a *kbagent) MemberJoin(ctx context.Context, cli client.Reader, opts *Options) error {
 	lfa := &memberJoin{
 		namespace:   a.synthesizedComp.Namespace,
@@ -108,6 +163,45 @@ func (a *kbagent) MemberLeave(ctx context.Context, cli client.Reader, opts *Opti
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.MemberLeave, lfa, opts))
 }
 
+func (a *kbagent) Readonly(ctx context.Context, cli client.Reader, opts *Options) error {
+	lfa := &readonly{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Readonly, lfa, opts))
+}
+
+func (a *kbagent) Readwrite(ctx context.Context, cli client.Reader, opts *Options) error {
+	lfa := &readwrite{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Readwrite, lfa, opts))
+}
+
+func (a *kbagent) VolumeUsage(ctx context.Context, cli client.Reader) (map[string]int, error) {
+	lfa := &volumeUsage{}
+	agent, err := a.agentClient(ctx, cli, a.pod, lfa)
+	if err != nil || agent == nil {
+		return nil, err
+	}
+	volumes, err := agent.VolumeUsage(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query the volume usage at pod %s", a.pod.Name)
+	}
+	usage := make(map[string]int)
+	for _, v := range volumes {
+		if v.TotalBytes > 0 {
+			usage[v.Name] = int(v.UsedBytes * 100 / v.TotalBytes)
+		}
+	}
+	return usage, nil
+}
+
 func (a *kbagent) DataDump(ctx context.Context, cli client.Reader, opts *Options) error {
 	lfa := &dataDump{}
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.DataDump, lfa, opts))
@@ -118,6 +212,82 @@ func (a *kbagent) DataLoad(ctx context.Context, cli client.Reader, opts *Options
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.DataLoad, lfa, opts))
 }
 
+func (a *kbagent) DataLoadFrom(ctx context.Context, cli client.Reader, opts *Options, source *corev1.Pod) (int64, error) {
+	dump, load := a.synthesizedComp.LifecycleActions.DataDump, a.synthesizedComp.LifecycleActions.DataLoad
+	lfa := &dataLoad{}
+	if !actionDefined(dump) {
+		return 0, errors.Wrap(ErrActionNotDefined, (&dataDump{}).name())
+	}
+	if !actionDefined(load) {
+		return 0, errors.Wrap(ErrActionNotDefined, lfa.name())
+	}
+	if dump.Exec == nil || load.Exec == nil {
+		return 0, errors.Wrap(ErrActionNotImplemented, "only exec actions can be streamed between replicas")
+	}
+	if err := a.precondition(ctx, cli, load); err != nil {
+		return 0, err
+	}
+
+	req, err := a.buildActionRequest(ctx, cli, lfa, opts)
+	if err != nil {
+		return 0, err
+	}
+	// the parameters of dataDump are built with the same component, they are the same for all replicas
+	dumpParameters, err := a.parameters(ctx, cli, &dataDump{})
+	if err != nil {
+		return 0, err
+	}
+	host, port, err := a.serverEndpoint(source)
+	if err != nil {
+		return 0, errors.Wrapf(err, "pod %s is unavailable to dump data", source.Name)
+	}
+	sourceCreds, err := kbacli.NewCredentials4Pod(ctx, cli, source)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to load the credentials of pod %s to dump data", source.Name)
+	}
+	req.InputFrom = &proto.ActionInputSource{
+		Host:       host,
+		Port:       port,
+		Action:     (&dataDump{}).name(),
+		Parameters: dumpParameters,
+	}
+	if sourceCreds != nil {
+		req.InputFrom.ServerName = sourceCreds.ServerName
+	}
+
+	agent, err := a.agentClient(ctx, cli, a.pod, lfa)
+	if err != nil || agent == nil {
+		return 0, err
+	}
+	rsp, err := agent.Action(ctx, *req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "http error occurred when executing action %s at pod %s", lfa.name(), a.pod.Name)
+	}
+	var transferred int64
+	if len(rsp.ID) > 0 {
+		// the progress is best-effort
+		if execution, err := agent.ActionExecution(ctx, rsp.ID); err == nil {
+			transferred = execution.InputBytes
+		}
+	}
+	if len(rsp.Error) > 0 {
+		return transferred, a.formatError(lfa, rsp)
+	}
+	return transferred, nil
+}
+
+func (a *kbagent) Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configSpec string, parameters map[string]string) error {
+	lfa := &reconfigure{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+		configSpec:  configSpec,
+		params:      parameters,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Reconfigure, lfa, opts))
+}
+
 func (a *kbagent) AccountProvision(ctx context.Context, cli client.Reader, opts *Options, statement, user, password string) error {
 	lfa := &accountProvision{
 		statement: statement,
@@ -127,12 +297,47 @@ func (a *kbagent) AccountProvision(ctx context.Context, cli client.Reader, opts
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.AccountProvision, lfa, opts))
 }
 
+func (a *kbagent) ShardProvision(ctx context.Context, cli client.Reader, opts *Options, shardingName string, shards []string) error {
+	lfa := &shardProvision{
+		namespace:    a.synthesizedComp.Namespace,
+		clusterName:  a.synthesizedComp.ClusterName,
+		compName:     a.synthesizedComp.Name,
+		shardingName: shardingName,
+		shards:       shards,
+	}
+	var action *appsv1.Action
+	if a.synthesizedComp.ShardingLifecycleActions != nil {
+		action = a.synthesizedComp.ShardingLifecycleActions.ShardProvision
+	}
+	if actionDefined(action) && action.PreCondition == nil {
+		// the shard provision action will not be executed until the shard is ready by default
+		action = action.DeepCopy()
+		action.PreCondition = ptr.To(appsv1.ComponentReadyPreConditionType)
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, action, lfa, opts))
+}
+
+func (a *kbagent) ShardTerminate(ctx context.Context, cli client.Reader, opts *Options, shardingName string, shards []string) error {
+	lfa := &shardTerminate{
+		namespace:    a.synthesizedComp.Namespace,
+		clusterName:  a.synthesizedComp.ClusterName,
+		compName:     a.synthesizedComp.Name,
+		shardingName: shardingName,
+		shards:       shards,
+	}
+	var action *appsv1.Action
+	if a.synthesizedComp.ShardingLifecycleActions != nil {
+		action = a.synthesizedComp.ShardingLifecycleActions.ShardTerminate
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, action, lfa, opts))
+}
+
 func (a *kbagent) ignoreOutput(_ []byte, err error) error {
 	return err
 }
 
 func (a *kbagent) checkedCallAction(ctx context.Context, cli client.Reader, spec *appsv1.Action, lfa lifecycleAction, opts *Options) ([]byte, error) {
-	if spec == nil || spec.Exec == nil {
+	if !actionDefined(spec) {
 		return nil, errors.Wrap(ErrActionNotDefined, lfa.name())
 	}
 	if err := a.precondition(ctx, cli, spec); err != nil {
@@ -143,7 +348,7 @@ func (a *kbagent) checkedCallAction(ctx context.Context, cli client.Reader, spec
 }
 
 func (a *kbagent) checkedCallProbe(ctx context.Context, cli client.Reader, spec *appsv1.Probe, lfa lifecycleAction, opts *Options) ([]byte, error) {
-	if spec == nil || spec.Exec == nil {
+	if spec == nil || !actionDefined(&spec.Action) {
 		return nil, errors.Wrap(ErrActionNotDefined, lfa.name())
 	}
 	return a.checkedCallAction(ctx, cli, &spec.Action, lfa, opts)
@@ -212,7 +417,7 @@ func (a *kbagent) callAction(ctx context.Context, cli client.Reader, spec *appsv
 	if err1 != nil {
 		return nil, err1
 	}
-	return a.callActionWithSelector(ctx, spec, lfa, req)
+	return a.callActionWithSelector(ctx, cli, spec, lfa, req)
 }
 
 func (a *kbagent) buildActionRequest(ctx context.Context, cli client.Reader, lfa lifecycleAction, opts *Options) (*proto.ActionRequest, error) {
@@ -268,7 +473,7 @@ func (a *kbagent) templateVarsParameters() (map[string]string, error) {
 	return m, nil
 }
 
-func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1.Action, lfa lifecycleAction, req *proto.ActionRequest) ([]byte, error) {
+func (a *kbagent) callActionWithSelector(ctx context.Context, cli client.Reader, spec *appsv1.Action, lfa lifecycleAction, req *proto.ActionRequest) ([]byte, error) {
 	pods, err := a.selectTargetPods(spec)
 	if err != nil {
 		return nil, err
@@ -282,18 +487,14 @@ func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1.Actio
 	//  - timeout
 	var output []byte
 	for _, pod := range pods {
-		host, port, err := a.serverEndpoint(pod)
+		agent, err := a.agentClient(ctx, cli, pod, lfa)
 		if err != nil {
-			return nil, errors.Wrapf(err, "pod %s is unavailable to execute action %s", pod.Name, lfa.name())
+			return nil, err
 		}
-		cli, err := kbacli.NewClient(host, port)
-		if err != nil {
-			return nil, err // mock client error
-		}
-		if cli == nil {
+		if agent == nil {
 			continue // not kb-agent container and port defined, for test only
 		}
-		rsp, err := cli.Action(ctx, *req)
+		rsp, err := agent.Action(ctx, *req)
 		if err != nil {
 			return nil, errors.Wrapf(err, "http error occurred when executing action %s at pod %s", lfa.name(), pod.Name)
 		}
@@ -308,8 +509,22 @@ func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1.Actio
 	return output, nil
 }
 
+// agentClient returns the client of kb-agent in the pod, it is nil if the pod has no kb-agent defined.
+func (a *kbagent) agentClient(ctx context.Context, cli client.Reader, pod *corev1.Pod, lfa lifecycleAction) (kbacli.Client, error) {
+	host, port, err := a.serverEndpoint(pod)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pod %s is unavailable to execute action %s", pod.Name, lfa.name())
+	}
+	creds, err := kbacli.NewCredentials4Pod(ctx, cli, pod)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the credentials of pod %s to execute action %s", pod.Name, lfa.name())
+	}
+	return kbacli.NewClient(host, port, creds) // the error is returned by the mock client
+}
+
 func (a *kbagent) selectTargetPods(spec *appsv1.Action) ([]*corev1.Pod, error) {
-	if spec.Exec == nil || len(spec.Exec.TargetPodSelector) == 0 {
+	selector, matchingKey := targetPodSelector(spec)
+	if len(selector) == 0 {
 		return []*corev1.Pod{a.pod}, nil
 	}
 
@@ -323,7 +538,7 @@ func (a *kbagent) selectTargetPods(spec *appsv1.Action) ([]*corev1.Pod, error) {
 	}
 
 	podsWithRole := func() []*corev1.Pod {
-		roleName := spec.Exec.MatchingKey
+		roleName := matchingKey
 		var pods []*corev1.Pod
 		for i, pod := range a.pods {
 			if len(pod.Labels) != 0 {
@@ -335,7 +550,7 @@ func (a *kbagent) selectTargetPods(spec *appsv1.Action) ([]*corev1.Pod, error) {
 		return pods
 	}
 
-	switch spec.Exec.TargetPodSelector {
+	switch selector {
 	case appsv1.AnyReplica:
 		return anyPod(), nil
 	case appsv1.AllReplicas:
@@ -345,7 +560,24 @@ func (a *kbagent) selectTargetPods(spec *appsv1.Action) ([]*corev1.Pod, error) {
 	case appsv1.OrdinalSelector:
 		return nil, fmt.Errorf("ordinal selector is not supported")
 	default:
-		return nil, fmt.Errorf("unknown pod selector: %s", spec.Exec.TargetPodSelector)
+		return nil, fmt.Errorf("unknown pod selector: %s", selector)
+	}
+}
+
+func actionDefined(spec *appsv1.Action) bool {
+	return spec != nil && (spec.Exec != nil || spec.HTTP != nil || spec.GRPC != nil)
+}
+
+func targetPodSelector(spec *appsv1.Action) (appsv1.TargetPodSelector, string) {
+	switch {
+	case spec.Exec != nil:
+		return spec.Exec.TargetPodSelector, spec.Exec.MatchingKey
+	case spec.HTTP != nil:
+		return spec.HTTP.TargetPodSelector, spec.HTTP.MatchingKey
+	case spec.GRPC != nil:
+		return spec.GRPC.TargetPodSelector, spec.GRPC.MatchingKey
+	default:
+		return "", ""
 	}
 }
 
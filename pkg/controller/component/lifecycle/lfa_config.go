@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+)
+
+const (
+	reconfigureConfigSpecVar = "KB_CONFIG_SPEC_NAME"
+	reconfigureParametersVar = "KB_CONFIG_PARAMETERS"
+)
+
+type reconfigure struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+	configSpec  string
+	params      map[string]string
+}
+
+var _ lifecycleAction = &reconfigure{}
+
+func (a *reconfigure) name() string {
+	return "reconfigure"
+}
+
+func (a *reconfigure) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to apply the new configuration.
+	// - KB_CONFIG_SPEC_NAME: The name of the config spec that has been updated.
+	// - KB_CONFIG_PARAMETERS: The updated parameters, encoded as a JSON object of name-value pairs.
+	params, err := json.Marshal(a.params)
+	if err != nil {
+		return nil, err
+	}
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	return map[string]string{
+		podFQDNVar:               component.PodFQDN(a.namespace, compName, a.pod.Name),
+		reconfigureConfigSpecVar: a.configSpec,
+		reconfigureParametersVar: string(params),
+	}, nil
+}
@@ -38,6 +38,9 @@ const (
 	joinMemberPodNameVar    = "KB_JOIN_MEMBER_POD_NAME"
 	leaveMemberPodFQDNVar   = "KB_LEAVE_MEMBER_POD_FQDN"
 	leaveMemberPodNameVar   = "KB_LEAVE_MEMBER_POD_NAME"
+	podFQDNVar              = "KB_POD_FQDN"
+	leaderPodNameVar        = "KB_LEADER_POD_NAME"
+	leaderPodFQDNVar        = "KB_LEADER_POD_FQDN"
 )
 
 type roleProbe struct{}
@@ -83,6 +86,37 @@ func (a *switchover) parameters(ctx context.Context, cli client.Reader) (map[str
 	return m, nil
 }
 
+type replicationLag struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+	leader      *corev1.Pod
+}
+
+var _ lifecycleAction = &replicationLag{}
+
+func (a *replicationLag) name() string {
+	return "replicationLag"
+}
+
+func (a *replicationLag) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod whose lag is being checked.
+	// - KB_LEADER_POD_NAME: The name of the current leader's pod, which may not be specified (empty).
+	// - KB_LEADER_POD_FQDN: The FQDN of the current leader's pod, which may not be specified (empty).
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	m := map[string]string{
+		podFQDNVar: component.PodFQDN(a.namespace, compName, a.pod.Name),
+	}
+	if a.leader != nil {
+		m[leaderPodNameVar] = a.leader.Name
+		m[leaderPodFQDNVar] = component.PodFQDN(a.namespace, compName, a.leader.Name)
+	}
+	return m, nil
+}
+
 type memberJoin struct {
 	namespace   string
 	clusterName string
@@ -133,6 +167,65 @@ func (a *memberLeave) parameters(ctx context.Context, cli client.Reader) (map[st
 	}, nil
 }
 
+type readonly struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+}
+
+var _ lifecycleAction = &readonly{}
+
+func (a *readonly) name() string {
+	return "readonly"
+}
+
+func (a *readonly) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to switch into the read-only state.
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	return map[string]string{
+		podFQDNVar: component.PodFQDN(a.namespace, compName, a.pod.Name),
+	}, nil
+}
+
+type readwrite struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+}
+
+var _ lifecycleAction = &readwrite{}
+
+func (a *readwrite) name() string {
+	return "readwrite"
+}
+
+func (a *readwrite) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to bring back to the read-write state.
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	return map[string]string{
+		podFQDNVar: component.PodFQDN(a.namespace, compName, a.pod.Name),
+	}, nil
+}
+
+// volumeUsage is not an action defined by the component, it is served by kb-agent itself.
+type volumeUsage struct{}
+
+var _ lifecycleAction = &volumeUsage{}
+
+func (a *volumeUsage) name() string {
+	return "volumeUsage"
+}
+
+func (a *volumeUsage) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	return nil, nil
+}
+
 ////////// hack for legacy Addons //////////
 // The container executing this action has access to following variables:
 //
@@ -142,9 +235,7 @@ func (a *memberLeave) parameters(ctx context.Context, cli client.Reader) (map[st
 
 func hackParameters4Switchover(ctx context.Context, cli client.Reader, namespace, clusterName, compName string, roles []appsv1.ReplicaRole) (map[string]string, error) {
 	const (
-		leaderPodName = "KB_LEADER_POD_NAME"
-		leaderPodFQDN = "KB_LEADER_POD_FQDN"
-		leaderPodIP   = "KB_LEADER_POD_IP"
+		leaderPodIP = "KB_LEADER_POD_IP"
 	)
 
 	role, err := leaderRole(roles)
@@ -165,9 +256,9 @@ func hackParameters4Switchover(ctx context.Context, cli client.Reader, namespace
 
 	pod := pods[0]
 	return map[string]string{
-		leaderPodName: pod.Name,
-		leaderPodFQDN: component.PodFQDN(namespace, constant.GenerateClusterComponentName(clusterName, compName), pod.Name),
-		leaderPodIP:   pod.Status.PodIP,
+		leaderPodNameVar: pod.Name,
+		leaderPodFQDNVar: component.PodFQDN(namespace, constant.GenerateClusterComponentName(clusterName, compName), pod.Name),
+		leaderPodIP:      pod.Status.PodIP,
 	}, nil
 }
 
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	shardingNameVar = "KB_SHARDING_NAME"
+	shardNameVar    = "KB_SHARD_NAME"
+	shardListVar    = "KB_SHARD_LIST"
+)
+
+type shardProvision struct {
+	namespace    string
+	clusterName  string
+	compName     string
+	shardingName string
+	shards       []string
+}
+
+var _ lifecycleAction = &shardProvision{}
+
+func (a *shardProvision) name() string {
+	return "shardProvision"
+}
+
+func (a *shardProvision) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_SHARDING_NAME: The name of the sharding.
+	// - KB_SHARD_NAME: The name of the shard that has been provisioned.
+	// - KB_SHARD_LIST: Comma-separated list of all shards of the sharding, including the new one.
+	return shardParameters(a.shardingName, a.compName, a.shards), nil
+}
+
+type shardTerminate struct {
+	namespace    string
+	clusterName  string
+	compName     string
+	shardingName string
+	shards       []string
+}
+
+var _ lifecycleAction = &shardTerminate{}
+
+func (a *shardTerminate) name() string {
+	return "shardTerminate"
+}
+
+func (a *shardTerminate) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_SHARDING_NAME: The name of the sharding.
+	// - KB_SHARD_NAME: The name of the shard to be terminated.
+	// - KB_SHARD_LIST: Comma-separated list of the shards that remain in the sharding.
+	return shardParameters(a.shardingName, a.compName, a.shards), nil
+}
+
+func shardParameters(shardingName, shardName string, shards []string) map[string]string {
+	return map[string]string{
+		shardingNameVar: shardingName,
+		shardNameVar:    shardName,
+		shardListVar:    strings.Join(shards, ","),
+	}
+}
@@ -21,7 +21,9 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,21 +47,39 @@ type Lifecycle interface {
 
 	Switchover(ctx context.Context, cli client.Reader, opts *Options, candidate string) error
 
+	// ReplicationLag returns the replication lag of the replica behind the current leader, in an engine-specific unit.
+	ReplicationLag(ctx context.Context, cli client.Reader, opts *Options) (int64, error)
+
 	MemberJoin(ctx context.Context, cli client.Reader, opts *Options) error
 
 	MemberLeave(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readonly(ctx context.Context, cli client.Reader, opts *Options) error
+	// Readonly is called on the current leader before switchover, on the writable replicas before stopping,
+	// and on the replica whose volume exceeds the high watermark.
+	Readonly(ctx context.Context, cli client.Reader, opts *Options) error
+
+	// Readwrite is the reverse of Readonly, it is called once the replica is started again or its volumes are
+	// back under the high watermark.
+	Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
+	// VolumeUsage returns the usage of the protected volumes of the replica, in percentage and keyed by the volume name.
+	VolumeUsage(ctx context.Context, cli client.Reader) (map[string]int, error)
 
 	DataDump(ctx context.Context, cli client.Reader, opts *Options) error
 
 	DataLoad(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Reconfigure(ctx context.Context, cli client.Reader, opts *Options) error
+	// DataLoadFrom loads the data dumped by the replica @source, the output of dataDump is streamed into dataLoad by
+	// the kb-agents directly. It returns the number of bytes transferred so far, which is the progress if in progress.
+	DataLoadFrom(ctx context.Context, cli client.Reader, opts *Options, source *corev1.Pod) (int64, error)
+
+	Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configSpec string, parameters map[string]string) error
 
 	AccountProvision(ctx context.Context, cli client.Reader, opts *Options, statement, user, password string) error
+
+	ShardProvision(ctx context.Context, cli client.Reader, opts *Options, shardingName string, shards []string) error
+
+	ShardTerminate(ctx context.Context, cli client.Reader, opts *Options, shardingName string, shards []string) error
 }
 
 func New(synthesizedComp *component.SynthesizedComponent, pod *corev1.Pod, pods ...*corev1.Pod) (Lifecycle, error) {
@@ -78,3 +98,83 @@ func New(synthesizedComp *component.SynthesizedComponent, pod *corev1.Pod, pods
 		pod:             pod,
 	}, nil
 }
+
+// ActionDefined checks whether the action, which can be called by its name through CallAction, is defined.
+func ActionDefined(synthesizedComp *component.SynthesizedComponent, name string) bool {
+	if synthesizedComp.LifecycleActions == nil {
+		return false
+	}
+	actions := synthesizedComp.LifecycleActions
+	switch name {
+	case "postProvision":
+		return actionDefined(actions.PostProvision)
+	case "preTerminate":
+		return actionDefined(actions.PreTerminate)
+	case "roleProbe":
+		return actions.RoleProbe != nil && actionDefined(&actions.RoleProbe.Action)
+	case "replicationLag":
+		return actionDefined(actions.ReplicationLag)
+	case "memberJoin":
+		return actionDefined(actions.MemberJoin)
+	case "memberLeave":
+		return actionDefined(actions.MemberLeave)
+	case "readonly":
+		return actionDefined(actions.Readonly)
+	case "readwrite":
+		return actionDefined(actions.Readwrite)
+	case "dataDump":
+		return actionDefined(actions.DataDump)
+	case "dataLoad":
+		return actionDefined(actions.DataLoad)
+	default:
+		return false
+	}
+}
+
+// CallAction calls the action by its name in the ComponentDefinition, only the actions that take no arguments
+// other than the options are supported. The output is returned for the roleProbe and replicationLag actions.
+func CallAction(ctx context.Context, cli client.Reader, lfa Lifecycle, opts *Options, name string) ([]byte, error) {
+	switch name {
+	case "postProvision":
+		return nil, lfa.PostProvision(ctx, cli, opts)
+	case "preTerminate":
+		return nil, lfa.PreTerminate(ctx, cli, opts)
+	case "roleProbe":
+		return lfa.RoleProbe(ctx, cli, opts)
+	case "replicationLag":
+		lag, err := lfa.ReplicationLag(ctx, cli, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatInt(lag, 10)), nil
+	case "memberJoin":
+		return nil, lfa.MemberJoin(ctx, cli, opts)
+	case "memberLeave":
+		return nil, lfa.MemberLeave(ctx, cli, opts)
+	case "readonly":
+		return nil, lfa.Readonly(ctx, cli, opts)
+	case "readwrite":
+		return nil, lfa.Readwrite(ctx, cli, opts)
+	case "dataDump":
+		return nil, lfa.DataDump(ctx, cli, opts)
+	case "dataLoad":
+		return nil, lfa.DataLoad(ctx, cli, opts)
+	default:
+		return nil, fmt.Errorf("%w: the action %s can not be called by name", ErrActionNotImplemented, name)
+	}
+}
+
+// WithReadonly switches the replica into the read-only state before calling @f, and brings it back to
+// the read-write state if @f fails. Both actions are optional, @f is called directly if they are not defined.
+func WithReadonly(ctx context.Context, cli client.Reader, lfa Lifecycle, f func() error) error {
+	if err := lfa.Readonly(ctx, cli, nil); err != nil && !errors.Is(err, ErrActionNotDefined) {
+		return err
+	}
+	if err := f(); err != nil {
+		if err1 := lfa.Readwrite(ctx, cli, nil); err1 != nil && !errors.Is(err1, ErrActionNotDefined) {
+			return fmt.Errorf("%w, and failed to bring the replica back to read-write: %s", err, err1.Error())
+		}
+		return err
+	}
+	return nil
+}
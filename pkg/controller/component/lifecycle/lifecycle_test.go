@@ -33,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
@@ -396,6 +397,335 @@ var _ = Describe("lifecycle", func() {
 			Expect(err).Should(BeNil())
 		})
 
+		It("readonly & readwrite parameters", func() {
+			action := &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n readonly"},
+				},
+			}
+			synthesizedComp.LifecycleActions.Readonly = action
+			synthesizedComp.LifecycleActions.Readwrite = action
+			pods[0].Name = "pod-0"
+
+			lifecycle, err := New(synthesizedComp, pods[0], pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			fqdn := component.PodFQDN(synthesizedComp.Namespace,
+				constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name), pods[0].Name)
+			actions := make([]string, 0)
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					actions = append(actions, req.Action)
+					Expect(req.Parameters).ShouldNot(BeNil())
+					Expect(req.Parameters[podFQDNVar]).Should(Equal(fqdn))
+					return proto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+
+			Expect(lifecycle.Readonly(ctx, k8sClient, nil)).Should(Succeed())
+			Expect(lifecycle.Readwrite(ctx, k8sClient, nil)).Should(Succeed())
+			Expect(actions).Should(Equal([]string{"readonly", "readwrite"}))
+		})
+
+		It("reconfigure parameters", func() {
+			synthesizedComp.LifecycleActions.Reconfigure = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n reconfigure"},
+				},
+			}
+			pods[0].Name = "pod-0"
+
+			lifecycle, err := New(synthesizedComp, pods[0], pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("reconfigure"))
+					Expect(req.Parameters).ShouldNot(BeNil())
+					Expect(req.Parameters[podFQDNVar]).ShouldNot(BeEmpty())
+					Expect(req.Parameters[reconfigureConfigSpecVar]).Should(Equal("config"))
+					Expect(req.Parameters[reconfigureParametersVar]).Should(Equal(`{"max_connections":"1000"}`))
+					return proto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+
+			err = lifecycle.Reconfigure(ctx, k8sClient, nil, "config", map[string]string{"max_connections": "1000"})
+			Expect(err).Should(BeNil())
+		})
+
+		It("shard provision & terminate parameters", func() {
+			synthesizedComp.ShardingLifecycleActions = &appsv1.ShardingLifecycleActions{
+				ShardProvision: &appsv1.Action{
+					Exec: &appsv1.ExecAction{
+						Command: []string{"/bin/bash", "-c", "echo -n shard-provision"},
+					},
+					PreCondition: ptr.To(appsv1.ImmediatelyPreConditionType),
+				},
+				ShardTerminate: &appsv1.Action{
+					Exec: &appsv1.ExecAction{
+						Command: []string{"/bin/bash", "-c", "echo -n shard-terminate"},
+					},
+				},
+			}
+
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			actions := make([]string, 0)
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					actions = append(actions, req.Action)
+					Expect(req.Parameters).ShouldNot(BeNil())
+					Expect(req.Parameters[shardingNameVar]).Should(Equal("sharding"))
+					Expect(req.Parameters[shardNameVar]).Should(Equal(synthesizedComp.Name))
+					Expect(req.Parameters[shardListVar]).Should(Equal("shard-0,shard-1"))
+					return proto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+
+			shards := []string{"shard-0", "shard-1"}
+			Expect(lifecycle.ShardProvision(ctx, k8sClient, nil, "sharding", shards)).Should(Succeed())
+			Expect(lifecycle.ShardTerminate(ctx, k8sClient, nil, "sharding", shards)).Should(Succeed())
+			Expect(actions).Should(Equal([]string{"shardProvision", "shardTerminate"}))
+		})
+
+		It("shard actions not defined", func() {
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			err = lifecycle.ShardProvision(ctx, k8sClient, nil, "sharding", nil)
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+			err = lifecycle.ShardTerminate(ctx, k8sClient, nil, "sharding", nil)
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+		})
+
+		It("data load from peer", func() {
+			synthesizedComp.LifecycleActions.DataDump = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "mysqldump"},
+				},
+			}
+			synthesizedComp.LifecycleActions.DataLoad = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "mysql"},
+				},
+			}
+			source := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "source",
+				},
+			}
+
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("dataLoad"))
+					Expect(req.InputFrom).ShouldNot(BeNil())
+					Expect(req.InputFrom.Action).Should(Equal("dataDump"))
+					return proto.ActionResponse{
+						ID:      "id",
+						Error:   proto.Error2Type(proto.ErrInProgress),
+						Message: "in progress",
+					}, nil
+				}).Times(1)
+				recorder.ActionExecution(gomock.Any(), "id").Return(proto.ActionExecution{ID: "id", InputBytes: 1024}, nil).Times(1)
+			})
+
+			transferred, err := lifecycle.DataLoadFrom(ctx, k8sClient, &Options{NonBlocking: ptr.To(true)}, source)
+			Expect(errors.Is(err, ErrActionInProgress)).Should(BeTrue())
+			Expect(transferred).Should(Equal(int64(1024)))
+		})
+
+		It("data load from peer - not defined", func() {
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			_, err = lifecycle.DataLoadFrom(ctx, k8sClient, nil, &corev1.Pod{})
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+		})
+
+		It("replication lag", func() {
+			synthesizedComp.LifecycleActions.ReplicationLag = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command:           []string{"/bin/bash", "-c", "echo -n 42"},
+					TargetPodSelector: appsv1.AnyReplica,
+				},
+			}
+			synthesizedComp.Roles = []appsv1.ReplicaRole{
+				{Name: "leader", Serviceable: true, Writable: true},
+				{Name: "follower", Serviceable: true},
+			}
+			pods = []*corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "pod-0", Labels: map[string]string{constant.RoleLabelKey: "leader"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Labels: map[string]string{constant.RoleLabelKey: "follower"}}},
+			}
+
+			lifecycle, err := New(synthesizedComp, pods[1], pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			output := "42\n"
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("replicationLag"))
+					Expect(req.Parameters[podFQDNVar]).Should(ContainSubstring("pod-1"))
+					Expect(req.Parameters[leaderPodNameVar]).Should(Equal("pod-0"))
+					Expect(req.Parameters[leaderPodFQDNVar]).Should(ContainSubstring("pod-0"))
+					return proto.ActionResponse{Output: []byte(output)}, nil
+				}).AnyTimes()
+			})
+
+			lag, err := lifecycle.ReplicationLag(ctx, k8sClient, nil)
+			Expect(err).Should(BeNil())
+			Expect(lag).Should(Equal(int64(42)))
+
+			By("invalid output")
+			output = "unknown"
+			_, err = lifecycle.ReplicationLag(ctx, k8sClient, nil)
+			Expect(errors.Is(err, ErrActionFailed)).Should(BeTrue())
+		})
+
+		It("replication lag - not defined", func() {
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			_, err = lifecycle.ReplicationLag(ctx, k8sClient, nil)
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+
+			_, err = SelectSwitchoverCandidate(ctx, k8sClient, synthesizedComp, pods, CandidatePolicy{})
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+		})
+
+		It("call action by name", func() {
+			synthesizedComp.LifecycleActions.ReplicationLag = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n 42"},
+				},
+			}
+			Expect(ActionDefined(synthesizedComp, "replicationLag")).Should(BeTrue())
+			Expect(ActionDefined(synthesizedComp, "readwrite")).Should(BeFalse())
+			Expect(ActionDefined(synthesizedComp, "switchover")).Should(BeFalse())
+
+			lifecycle, err := New(synthesizedComp, pods[0], pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("replicationLag"))
+					return proto.ActionResponse{Output: []byte("42")}, nil
+				}).AnyTimes()
+			})
+
+			output, err := CallAction(ctx, k8sClient, lifecycle, nil, "replicationLag")
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(Equal("42"))
+
+			_, err = CallAction(ctx, k8sClient, lifecycle, nil, "readwrite")
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+
+			_, err = CallAction(ctx, k8sClient, lifecycle, nil, "switchover")
+			Expect(errors.Is(err, ErrActionNotImplemented)).Should(BeTrue())
+		})
+
+		It("volume usage", func() {
+			lifecycle, err := New(synthesizedComp, pods[0], pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.VolumeUsage(gomock.Any()).Return([]proto.VolumeUsage{
+					{Name: "data", UsedBytes: 85, TotalBytes: 100},
+					{Name: "log", UsedBytes: 0, TotalBytes: 0},
+				}, nil).AnyTimes()
+			})
+
+			usage, err := lifecycle.VolumeUsage(ctx, k8sClient)
+			Expect(err).Should(BeNil())
+			Expect(usage).Should(Equal(map[string]int{"data": 85}))
+		})
+
+		It("select switchover candidate", func() {
+			synthesizedComp.LifecycleActions.ReplicationLag = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n 0"},
+				},
+			}
+			synthesizedComp.Roles = []appsv1.ReplicaRole{
+				{Name: "leader", Serviceable: true, Writable: true},
+				{Name: "follower", Serviceable: true},
+			}
+			newPod := func(name, role string, ready bool) *corev1.Pod {
+				status := corev1.ConditionFalse
+				if ready {
+					status = corev1.ConditionTrue
+				}
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{constant.RoleLabelKey: role}},
+					Status: corev1.PodStatus{
+						Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+					},
+				}
+			}
+			pods = []*corev1.Pod{
+				newPod("pod-0", "leader", true),
+				newPod("pod-1", "follower", true),
+				newPod("pod-2", "follower", true),
+				newPod("pod-3", "follower", false),
+			}
+			lags := map[string]string{"pod-1": "100", "pod-2": "10"}
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					for name, lag := range lags {
+						if strings.HasPrefix(req.Parameters[podFQDNVar], name+".") {
+							return proto.ActionResponse{Output: []byte(lag)}, nil
+						}
+					}
+					return proto.ActionResponse{}, fmt.Errorf("unexpected pod: %s", req.Parameters[podFQDNVar])
+				}).AnyTimes()
+			})
+
+			decision, err := SelectSwitchoverCandidate(ctx, k8sClient, synthesizedComp, pods, CandidatePolicy{})
+			Expect(err).Should(BeNil())
+			Expect(decision.Candidate).Should(Equal("pod-2"))
+			Expect(decision.Readings).Should(HaveLen(2))
+
+			By("exclude the replicas to scale in")
+			decision, err = SelectSwitchoverCandidate(ctx, k8sClient, synthesizedComp, pods, CandidatePolicy{Excluded: []string{"pod-2"}})
+			Expect(err).Should(BeNil())
+			Expect(decision.Candidate).Should(Equal("pod-1"))
+
+			By("refuse if all the lags exceed the max lag")
+			decision, err = SelectSwitchoverCandidate(ctx, k8sClient, synthesizedComp, pods, CandidatePolicy{MaxLag: ptr.To(int64(5))})
+			Expect(errors.Is(err, ErrNoQualifiedCandidate)).Should(BeTrue())
+			Expect(decision.Candidate).Should(BeEmpty())
+			Expect(decision.Readings).Should(HaveLen(2))
+		})
+
+		It("rank switchover candidates", func() {
+			readings := []LagReading{
+				{PodName: "pod-1", Zone: "zone-a", Lag: ptr.To(int64(10))},
+				{PodName: "pod-2", Zone: "zone-b", Lag: ptr.To(int64(1))},
+				{PodName: "pod-3", Zone: "zone-a", Error: ErrActionFailed},
+				{PodName: "pod-4", Zone: "zone-b", Lag: ptr.To(int64(1))},
+			}
+			Expect(rankCandidates(readings, "zone-a", CandidatePolicy{PreferSameZone: true}).Candidate).Should(Equal("pod-1"))
+			Expect(rankCandidates(readings, "zone-a", CandidatePolicy{}).Candidate).Should(Equal("pod-2"))
+			Expect(rankCandidates(readings, "", CandidatePolicy{PreferSameZone: true}).Candidate).Should(Equal("pod-2"))
+			Expect(rankCandidates(readings, "zone-a", CandidatePolicy{PreferSameZone: true, MaxLag: ptr.To(int64(5))}).Candidate).Should(Equal("pod-2"))
+			Expect(rankCandidates(readings, "zone-a", CandidatePolicy{MaxLag: ptr.To(int64(0))}).Candidate).Should(BeEmpty())
+		})
+
 		It("template vars", func() {
 			key := "TEMPLATE_VAR1"
 			val := "template-vars1"
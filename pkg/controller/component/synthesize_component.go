@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -141,6 +142,10 @@ func BuildSynthesizedComponent(ctx context.Context, cli client.Reader,
 	// build runtimeClassName
 	buildRuntimeClassName(synthesizeComp, comp)
 
+	if err = buildShardingLifecycleActions(ctx, cli, synthesizeComp, comp); err != nil {
+		return nil, errors.Wrap(err, "build sharding lifecycle actions failed")
+	}
+
 	if err = buildKBAgentContainer(synthesizeComp); err != nil {
 		return nil, errors.Wrap(err, "build kb-agent container failed")
 	}
@@ -185,6 +190,27 @@ func buildComp2CompDefs(ctx context.Context, cli client.Reader, cluster *appsv1.
 	return mapping, nil
 }
 
+// buildShardingLifecycleActions loads the shard actions from the sharding definition if the component is a shard,
+// they are called by the cluster controller but executed by the kb-agent of the shard.
+func buildShardingLifecycleActions(ctx context.Context, cli client.Reader, synthesizeComp *SynthesizedComponent, comp *appsv1.Component) error {
+	shardingDefName := comp.Labels[constant.ShardingDefLabelKey]
+	if len(shardingDefName) == 0 || cli == nil {
+		return nil
+	}
+	shardingDef := &appsv1.ShardingDefinition{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: shardingDefName}, shardingDef); err != nil {
+		return err
+	}
+	actions := shardingDef.Spec.LifecycleActions
+	if actions != nil && (actions.ShardProvision != nil || actions.ShardTerminate != nil) {
+		synthesizeComp.ShardingLifecycleActions = &appsv1.ShardingLifecycleActions{
+			ShardProvision: actions.ShardProvision,
+			ShardTerminate: actions.ShardTerminate,
+		}
+	}
+	return nil
+}
+
 func mergeUserDefinedEnv(synthesizedComp *SynthesizedComponent, comp *appsv1.Component) error {
 	if comp == nil || len(comp.Spec.Env) == 0 {
 		return nil
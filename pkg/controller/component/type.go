@@ -67,6 +67,7 @@ type SynthesizedComponent struct {
 	PodUpdatePolicy                  *kbappsv1.PodUpdatePolicyType          `json:"podUpdatePolicy,omitempty"`
 	PolicyRules                      []rbacv1.PolicyRule                    `json:"policyRules,omitempty"`
 	LifecycleActions                 *kbappsv1.ComponentLifecycleActions    `json:"lifecycleActions,omitempty"`
+	ShardingLifecycleActions         *kbappsv1.ShardingLifecycleActions     `json:"shardingLifecycleActions,omitempty"` // the shard actions of the sharding that the component belongs to
 	SystemAccounts                   []kbappsv1.SystemAccount               `json:"systemAccounts,omitempty"`
 	Volumes                          []kbappsv1.ComponentVolume             `json:"volumes,omitempty"`
 	HostNetwork                      *kbappsv1.HostNetwork                  `json:"hostNetwork,omitempty"`
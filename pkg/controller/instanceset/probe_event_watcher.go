@@ -0,0 +1,236 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+const (
+	roleProbeEventReason = "roleProbe"
+
+	// probeWatchRetryInterval is the interval to re-subscribe the probe events after the stream is broken.
+	probeWatchRetryInterval = 5 * time.Second
+	// probeWatchIdleTimeout is the timeout to consider the stream broken if nothing received,
+	// kb-agent sends keepalive comments periodically, which are counted.
+	probeWatchIdleTimeout = time.Minute
+)
+
+// ProbeEventWatcher subscribes the probe events streamed by the kb-agent of pods, and handles the role probe events
+// in the same way as the Kubernetes events, which are kept as the fallback when no one is watching.
+type ProbeEventWatcher struct {
+	cli      client.Client
+	recorder record.EventRecorder
+	logger   logr.Logger
+
+	lock sync.Mutex
+	// watches holds the cancel functions of watches, keyed by the InstanceSet and the UID of pods.
+	watches map[types.NamespacedName]map[types.UID]context.CancelFunc
+}
+
+func NewProbeEventWatcher(cli client.Client, recorder record.EventRecorder, logger logr.Logger) *ProbeEventWatcher {
+	return &ProbeEventWatcher{
+		cli:      cli,
+		recorder: recorder,
+		logger:   logger,
+		watches:  make(map[types.NamespacedName]map[types.UID]context.CancelFunc),
+	}
+}
+
+// Sync starts to watch the pods of the InstanceSet not watched yet, and stops the watches of pods not in the list.
+func (w *ProbeEventWatcher) Sync(its types.NamespacedName, pods []*corev1.Pod) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	watches, ok := w.watches[its]
+	if !ok {
+		watches = make(map[types.UID]context.CancelFunc)
+		w.watches[its] = watches
+	}
+
+	expected := make(map[types.UID]*corev1.Pod)
+	for i, pod := range pods {
+		if watchable(pod) {
+			expected[pod.UID] = pods[i]
+		}
+	}
+	for uid, cancel := range watches {
+		if _, ok := expected[uid]; !ok {
+			cancel()
+			delete(watches, uid)
+		}
+	}
+	for uid, pod := range expected {
+		if _, ok := watches[uid]; !ok {
+			ctx, cancel := context.WithCancel(context.Background())
+			watches[uid] = cancel
+			go w.watch(ctx, its, pod.DeepCopy())
+		}
+	}
+	if len(watches) == 0 {
+		delete(w.watches, its)
+	}
+}
+
+func watchable(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || len(pod.Status.PodIP) == 0 {
+		return false
+	}
+	_, err := intctrlutil.GetPortByName(*pod, kbagent.ContainerName, kbagent.DefaultPortName)
+	return err == nil
+}
+
+func (w *ProbeEventWatcher) watch(ctx context.Context, its types.NamespacedName, pod *corev1.Pod) {
+	logger := w.logger.WithValues("pod", client.ObjectKeyFromObject(pod))
+	defer w.forget(its, pod.UID)
+
+	for {
+		if err := w.watchOnce(ctx, logger, pod); err != nil {
+			logger.Info("watch probe events failed, will retry later", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(probeWatchRetryInterval):
+		}
+		// stop watching if the pod is gone
+		current := &corev1.Pod{}
+		if err := w.cli.Get(ctx, client.ObjectKeyFromObject(pod), current, inDataContextUnspecified()); err != nil {
+			if apierrors.IsNotFound(err) {
+				return
+			}
+			continue
+		}
+		if current.UID != pod.UID || !watchable(current) {
+			return
+		}
+	}
+}
+
+func (w *ProbeEventWatcher) watchOnce(ctx context.Context, logger logr.Logger, pod *corev1.Pod) error {
+	port, err := intctrlutil.GetPortByName(*pod, kbagent.ContainerName, kbagent.DefaultPortName)
+	if err != nil {
+		return err
+	}
+	creds, err := kbacli.NewCredentials4Pod(ctx, w.cli, pod)
+	if err != nil {
+		return err
+	}
+	cli, err := kbacli.NewClient(pod.Status.PodIP, port, creds)
+	if err != nil || cli == nil {
+		return err
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := cli.WatchProbeEvents(watchCtx)
+	if err != nil {
+		return err
+	}
+	logger.Info("probe events watch started")
+
+	idle := time.NewTimer(probeWatchIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-idle.C:
+			return errors.New("no probe event received in time")
+		case event, ok := <-events:
+			if !ok {
+				return errors.New("probe events stream closed")
+			}
+			idle.Reset(probeWatchIdleTimeout)
+			if err := w.handle(ctx, logger, pod, event); err != nil {
+				logger.Error(err, "handle probe event failed", "probe", event.Probe)
+			}
+		}
+	}
+}
+
+// handle transforms the probe event into a Kubernetes event of pod, and handles it as the role changed event.
+func (w *ProbeEventWatcher) handle(ctx context.Context, logger logr.Logger, pod *corev1.Pod, probeEvent proto.ProbeEvent) error {
+	if probeEvent.Probe != roleProbeEventReason {
+		return nil
+	}
+	message, err := json.Marshal(&probeEvent)
+	if err != nil {
+		return err
+	}
+	eventTime := metav1.NowMicro()
+	if probeEvent.Time != nil {
+		eventTime = metav1.NewMicroTime(*probeEvent.Time)
+	}
+	event := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+			FieldPath: "spec.containers{kbagent}",
+		},
+		Reason:              probeEvent.Probe,
+		Message:             string(message),
+		EventTime:           eventTime,
+		ReportingController: "kbagent",
+	}
+	event = (&PodRoleEventHandler{}).transformKBAgentProbeEvent(logger, event)
+
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Log:      logger,
+		Recorder: w.recorder,
+	}
+	_, err = handleRoleChangedEvent(w.cli, reqCtx, w.recorder, event)
+	return err
+}
+
+func (w *ProbeEventWatcher) forget(its types.NamespacedName, uid types.UID) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if watches, ok := w.watches[its]; ok {
+		if cancel, ok := watches[uid]; ok {
+			cancel()
+			delete(watches, uid)
+		}
+		if len(watches) == 0 {
+			delete(w.watches, its)
+		}
+	}
+}
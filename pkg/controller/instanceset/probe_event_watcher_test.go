@@ -0,0 +1,159 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("probe event watcher test", func() {
+	var (
+		watcher *ProbeEventWatcher
+		itsKey  = types.NamespacedName{Namespace: namespace, Name: name}
+	)
+
+	newPod := func(ordinal int, ip string) *corev1.Pod {
+		pod := builder.NewPodBuilder(namespace, getPodName(name, ordinal)).
+			SetUID(types.UID(getPodName(name, ordinal))).
+			AddContainer(corev1.Container{
+				Name:  kbagent.ContainerName,
+				Ports: []corev1.ContainerPort{{Name: kbagent.DefaultPortName, ContainerPort: 3501}},
+			}).
+			GetObject()
+		pod.Status.PodIP = ip
+		return pod
+	}
+
+	BeforeEach(func() {
+		watcher = NewProbeEventWatcher(k8sMock, nil, logger)
+	})
+
+	AfterEach(func() {
+		watcher.Sync(itsKey, nil)
+		kbacli.UnsetMockClient()
+	})
+
+	Context("Sync function", func() {
+		It("should watch the watchable pods only", func() {
+			var connected atomic.Int32
+			mockCli := kbacli.NewMockClient(controller)
+			mockCli.EXPECT().WatchProbeEvents(gomock.Any()).DoAndReturn(func(ctx context.Context) (<-chan proto.ProbeEvent, error) {
+				connected.Add(1)
+				events := make(chan proto.ProbeEvent)
+				go func() {
+					<-ctx.Done()
+					close(events)
+				}()
+				return events, nil
+			}).AnyTimes()
+			kbacli.SetMockClient(mockCli, nil)
+
+			watched := func() []types.UID {
+				watcher.lock.Lock()
+				defer watcher.lock.Unlock()
+				var uids []types.UID
+				for uid := range watcher.watches[itsKey] {
+					uids = append(uids, uid)
+				}
+				return uids
+			}
+
+			pod0, pod1 := newPod(0, "10.0.0.1"), newPod(1, "")
+			watcher.Sync(itsKey, []*corev1.Pod{pod0, pod1})
+			Expect(watched()).Should(ConsistOf(pod0.UID))
+			Eventually(connected.Load).Should(Equal(int32(1)))
+
+			pod1.Status.PodIP = "10.0.0.2"
+			watcher.Sync(itsKey, []*corev1.Pod{pod0, pod1})
+			Expect(watched()).Should(ConsistOf(pod0.UID, pod1.UID))
+			Eventually(connected.Load).Should(Equal(int32(2)))
+
+			watcher.Sync(itsKey, []*corev1.Pod{pod1})
+			Expect(watched()).Should(ConsistOf(pod1.UID))
+
+			watcher.Sync(itsKey, nil)
+			Expect(watched()).Should(BeEmpty())
+		})
+	})
+
+	Context("handle function", func() {
+		It("should update the role of pod", func() {
+			pod := newPod(0, "10.0.0.1")
+			role := workloads.ReplicaRole{
+				Name:       "leader",
+				AccessMode: workloads.ReadWriteMode,
+				IsLeader:   true,
+				CanVote:    true,
+			}
+			k8sMock.EXPECT().
+				Get(gomock.Any(), gomock.Any(), &corev1.Pod{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, objKey client.ObjectKey, p *corev1.Pod, _ ...client.GetOption) error {
+					p.Namespace = objKey.Namespace
+					p.Name = objKey.Name
+					p.UID = pod.UID
+					p.Labels = map[string]string{
+						constant.AppInstanceLabelKey: name,
+						WorkloadsInstanceLabelKey:    name,
+					}
+					return nil
+				}).Times(1)
+			k8sMock.EXPECT().
+				Get(gomock.Any(), gomock.Any(), &workloads.InstanceSet{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, objKey client.ObjectKey, its *workloads.InstanceSet, _ ...client.GetOption) error {
+					its.Namespace = objKey.Namespace
+					its.Name = objKey.Name
+					its.Spec.Roles = []workloads.ReplicaRole{role}
+					return nil
+				}).Times(1)
+			k8sMock.EXPECT().
+				Patch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, pd *corev1.Pod, patch client.Patch, _ ...client.PatchOption) error {
+					Expect(pd.Labels[RoleLabelKey]).Should(Equal(role.Name))
+					Expect(pd.Labels[AccessModeLabelKey]).Should(BeEquivalentTo(role.AccessMode))
+					return nil
+				}).Times(1)
+
+			now := time.Now()
+			event := proto.ProbeEvent{Probe: roleProbeEventReason, Output: []byte(role.Name), Time: &now}
+			Expect(watcher.handle(ctx, logger, pod, event)).Should(Succeed())
+
+			By("ignore the events of other probes")
+			event.Probe = "healthyProbe"
+			Expect(watcher.handle(ctx, logger, pod, event)).Should(Succeed())
+		})
+	})
+})
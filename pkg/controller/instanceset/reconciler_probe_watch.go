@@ -0,0 +1,60 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+// probeWatchReconciler keeps the subscriptions of probe events in sync with the pods
+type probeWatchReconciler struct {
+	watcher *ProbeEventWatcher
+}
+
+var _ kubebuilderx.Reconciler = &probeWatchReconciler{}
+
+func NewProbeWatchReconciler(watcher *ProbeEventWatcher) kubebuilderx.Reconciler {
+	return &probeWatchReconciler{watcher: watcher}
+}
+
+func (r *probeWatchReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *kubebuilderx.CheckResult {
+	if r.watcher == nil || tree.GetRoot() == nil {
+		return kubebuilderx.ConditionUnsatisfied
+	}
+	return kubebuilderx.ConditionSatisfied
+}
+
+func (r *probeWatchReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
+	its, _ := tree.GetRoot().(*workloads.InstanceSet)
+	var pods []*corev1.Pod
+	// the roles are determined by the role probe only
+	if !model.IsObjectDeleting(its) && len(its.Spec.Roles) > 0 {
+		for _, object := range tree.List(&corev1.Pod{}) {
+			pods = append(pods, object.(*corev1.Pod))
+		}
+	}
+	r.watcher.Sync(client.ObjectKeyFromObject(its), pods)
+	return kubebuilderx.Continue, nil
+}
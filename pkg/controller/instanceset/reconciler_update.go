@@ -21,6 +21,7 @@ package instanceset
 
 import (
 	"fmt"
+	"slices"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -94,6 +95,7 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	// 3. do update
 	// do nothing if UpdateStrategyType is 'OnDelete'
 	if its.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType {
+		its.Status.RolloutStage = nil
 		return kubebuilderx.Continue, nil
 	}
 
@@ -127,6 +129,8 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	priorities := ComposeRolePriorityMap(its.Spec.Roles)
 	isBlocked := false
 	sortObjects(oldPodList, priorities, false)
+	rolloutInstances := getRolloutStageInstances(its, oldPodList, nameToTemplateMap)
+	its.Status.RolloutStage = buildRolloutStageStatus(its, oldPodList, rolloutInstances)
 	for _, pod := range oldPodList {
 		if updatingPods >= updateCount || updatingPods >= unavailable {
 			break
@@ -134,6 +138,10 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 		if updatedPods >= partition {
 			break
 		}
+		// keep the instances not allowed by the current rollout stage in their current revision.
+		if rolloutInstances != nil && !rolloutInstances.Has(pod.Name) {
+			continue
+		}
 
 		if !isHealthy(pod) {
 			tree.Logger.Info(fmt.Sprintf("InstanceSet %s/%s blocks on scale-in as the pod %s is not healthy", its.Namespace, its.Name, pod.Name))
@@ -180,6 +188,68 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	return kubebuilderx.Continue, nil
 }
 
+// getRolloutStageInstances returns the names of the instances allowed to be updated in the current rollout stage,
+// nil means that all the instances are allowed. The pods are expected to be sorted in the update order.
+// The instances recorded in the status remain selected, so the selection doesn't change when the pods are reordered
+// during the update.
+func getRolloutStageInstances(its *workloads.InstanceSet, pods []*corev1.Pod, nameToTemplateMap map[string]*instanceTemplateExt) sets.Set[string] {
+	stage := its.Spec.RolloutStage
+	if stage == nil {
+		return nil
+	}
+	inStageTemplates := func(name string) bool {
+		template, ok := nameToTemplateMap[name]
+		return ok && template.Name != "" && slices.Contains(stage.InstanceTemplates, template.Name)
+	}
+	instances := sets.New[string]()
+	selected := int32(0)
+	if its.Status.RolloutStage != nil {
+		for _, name := range its.Status.RolloutStage.Instances {
+			if _, ok := nameToTemplateMap[name]; !ok {
+				continue
+			}
+			instances.Insert(name)
+			if !inStageTemplates(name) {
+				selected++
+			}
+		}
+	}
+	for _, pod := range pods {
+		if instances.Has(pod.Name) {
+			continue
+		}
+		if inStageTemplates(pod.Name) {
+			instances.Insert(pod.Name)
+			continue
+		}
+		if stage.Replicas != nil && selected < *stage.Replicas {
+			instances.Insert(pod.Name)
+			selected++
+		}
+	}
+	return instances
+}
+
+func buildRolloutStageStatus(its *workloads.InstanceSet, pods []*corev1.Pod, instances sets.Set[string]) *workloads.RolloutStageStatus {
+	if its.Spec.RolloutStage == nil {
+		return nil
+	}
+	status := &workloads.RolloutStageStatus{
+		Name:      its.Spec.RolloutStage.Name,
+		Replicas:  int32(instances.Len()),
+		Instances: sets.List(instances),
+	}
+	for _, pod := range pods {
+		if !instances.Has(pod.Name) {
+			continue
+		}
+		if updated, _ := IsPodUpdated(its, pod); updated && isRunningAndAvailable(pod, its.Spec.MinReadySeconds) {
+			status.UpdatedReplicas++
+		}
+	}
+	return status
+}
+
 func buildBlockedCondition(its *workloads.InstanceSet, message string) *metav1.Condition {
 	return &metav1.Condition{
 		Type:               string(workloads.InstanceUpdateRestricted),
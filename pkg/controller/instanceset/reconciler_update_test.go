@@ -194,6 +194,78 @@ var _ = Describe("update reconciler test", func() {
 			Expect(res).Should(Equal(kubebuilderx.Continue))
 			expectUpdatedPods(partitionTree, []string{"bar-foo-0"})
 
+			By("reconcile with RolloutStage and MaxUnavailable=2")
+			rolloutTree, err := tree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root, ok = rolloutTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					MaxUnavailable: &maxUnavailable,
+				},
+			}
+			root.Spec.RolloutStage = &workloads.RolloutStage{
+				Name:              "canary",
+				InstanceTemplates: []string{generateNameFoo},
+			}
+			// order: bar-hello-0, bar-foo-1, bar-foo-0, bar-3, bar-2, bar-1, bar-0
+			// expected: bar-foo-1, bar-foo-0 being deleted, bar-hello-0 is not allowed by the stage
+			res, err = reconciler.Reconcile(rolloutTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(rolloutTree, []string{"bar-foo-1", "bar-foo-0"})
+			Expect(root.Status.RolloutStage).ShouldNot(BeNil())
+			Expect(root.Status.RolloutStage.Name).Should(Equal("canary"))
+			Expect(root.Status.RolloutStage.Replicas).Should(BeEquivalentTo(2))
+			Expect(root.Status.RolloutStage.UpdatedReplicas).Should(BeEquivalentTo(0))
+
+			By("reconcile with RolloutStage of one replica")
+			rolloutTree, err = tree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root, ok = rolloutTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					MaxUnavailable: &maxUnavailable,
+				},
+			}
+			stageReplicas := int32(1)
+			root.Spec.RolloutStage = &workloads.RolloutStage{
+				Name:     "canary",
+				Replicas: &stageReplicas,
+			}
+			res, err = reconciler.Reconcile(rolloutTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(rolloutTree, []string{"bar-hello-0"})
+			Expect(root.Status.RolloutStage.Replicas).Should(BeEquivalentTo(1))
+			Expect(root.Status.RolloutStage.Instances).Should(Equal([]string{"bar-hello-0"}))
+
+			By("reconcile with the instances selected before")
+			rolloutTree, err = tree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root, ok = rolloutTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					MaxUnavailable: &maxUnavailable,
+				},
+			}
+			root.Spec.RolloutStage = &workloads.RolloutStage{
+				Name:     "canary",
+				Replicas: &stageReplicas,
+			}
+			root.Status.RolloutStage = &workloads.RolloutStageStatus{
+				Name:      "canary",
+				Replicas:  1,
+				Instances: []string{"bar-1", "bar-gone"},
+			}
+			res, err = reconciler.Reconcile(rolloutTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(rolloutTree, []string{"bar-1"})
+			Expect(root.Status.RolloutStage.Instances).Should(Equal([]string{"bar-1"}))
+
 			By("reconcile with UpdateStrategy='OnDelete'")
 			onDeleteTree, err := tree.DeepCopy()
 			Expect(err).Should(BeNil())
@@ -73,6 +73,10 @@ const (
 
 	FeatureGateIgnorePodVerticalScaling = "IGNORE_POD_VERTICAL_SCALING"
 
+	// FeatureGateKBAgentProbeWatch specifies to subscribe the probe events streamed by kb-agent,
+	// rather than relying on the Kubernetes events only.
+	FeatureGateKBAgentProbeWatch = "KBAGENT_PROBE_WATCH"
+
 	finalizer = "instanceset.workloads.kubeblocks.io/finalizer"
 )
 
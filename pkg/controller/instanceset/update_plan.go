@@ -21,8 +21,10 @@ package instanceset
 
 import (
 	"errors"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
@@ -242,3 +244,90 @@ func NewUpdatePlan(its workloads.InstanceSet, pods []*corev1.Pod, isPodUpdated f
 		isPodUpdated: isPodUpdated,
 	}
 }
+
+// BuildUpdateSteps simulates the update of the pods and groups them into the steps in which they will be updated,
+// the pods in the same step are updated in parallel.
+// The order follows the spec.memberUpdateStrategy if the InstanceSet is roleful, and the number of pods in a step
+// is limited by the maxUnavailable of the rolling update.
+func BuildUpdateSteps(its *workloads.InstanceSet, pods []*corev1.Pod) ([][]*corev1.Pod, error) {
+	_, maxUnavailable, err := parsePartitionNMaxUnavailable(its.Spec.UpdateStrategy.RollingUpdate, len(pods))
+	if err != nil {
+		return nil, err
+	}
+	maxUnavailable = max(maxUnavailable, 1)
+
+	var batches [][]*corev1.Pod
+	if len(its.Spec.Roles) > 0 {
+		if batches, err = simulateUpdatePlan(getInstanceSetForUpdatePlan(its), pods); err != nil {
+			return nil, err
+		}
+	} else {
+		podList := slices.Clone(pods)
+		sortObjects(podList, ComposeRolePriorityMap(its.Spec.Roles), false)
+		batches = append(batches, podList)
+	}
+
+	var steps [][]*corev1.Pod
+	for _, batch := range batches {
+		for len(batch) > 0 {
+			size := min(len(batch), maxUnavailable)
+			steps = append(steps, batch[:size])
+			batch = batch[size:]
+		}
+	}
+	return steps, nil
+}
+
+// simulateUpdatePlan executes the update plan repeatedly, and regards the pods to be updated by the previous execution
+// as updated and ready, until all pods are updated.
+func simulateUpdatePlan(its *workloads.InstanceSet, pods []*corev1.Pod) ([][]*corev1.Pod, error) {
+	podMap := make(map[string]*corev1.Pod, len(pods))
+	simulatedPods := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		podMap[pod.Name] = pod
+		simulatedPod := pod.DeepCopy()
+		simulatedPod.DeletionTimestamp = nil
+		simulatedPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		// an empty role has the same priority as no role, but passes the role label check of the updated pods.
+		if _, ok := simulatedPod.Labels[RoleLabelKey]; !ok {
+			if simulatedPod.Labels == nil {
+				simulatedPod.Labels = map[string]string{}
+			}
+			simulatedPod.Labels[RoleLabelKey] = ""
+		}
+		simulatedPods = append(simulatedPods, simulatedPod)
+	}
+
+	updated := sets.New[string]()
+	isPodUpdated := func(_ *workloads.InstanceSet, pod *corev1.Pod) (bool, error) {
+		return updated.Has(pod.Name), nil
+	}
+	var batches [][]*corev1.Pod
+	for updated.Len() < len(pods) {
+		podsToBeUpdated, err := NewUpdatePlan(*its, simulatedPods, isPodUpdated).Execute()
+		if err != nil {
+			return nil, err
+		}
+		if len(podsToBeUpdated) == 0 {
+			break
+		}
+		var batch []*corev1.Pod
+		for _, pod := range podsToBeUpdated {
+			updated.Insert(pod.Name)
+			batch = append(batch, podMap[pod.Name])
+		}
+		batches = append(batches, batch)
+	}
+
+	// the pods out of the plan are updated at last.
+	var rest []*corev1.Pod
+	for _, pod := range pods {
+		if !updated.Has(pod.Name) {
+			rest = append(rest, pod)
+		}
+	}
+	if len(rest) > 0 {
+		batches = append(batches, rest)
+	}
+	return batches, nil
+}
@@ -25,6 +25,7 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
@@ -198,5 +199,59 @@ var _ = Describe("update plan test.", func() {
 			}
 			checkPlan(expectedPlan, false)
 		})
+
+		checkSteps := func(expectedSteps [][]*corev1.Pod) {
+			var pods []*corev1.Pod
+			for i := range buildPodList() {
+				pods = append(pods, &buildPodList()[i])
+			}
+			steps, err := BuildUpdateSteps(its, pods)
+			Expect(err).Should(BeNil())
+			Expect(steps).Should(HaveLen(len(expectedSteps)))
+			for i := range expectedSteps {
+				Expect(equalPodList(toPodList(steps[i]), toPodList(expectedSteps[i]))).Should(BeTrue())
+			}
+		}
+
+		It("should build update steps by the update plan", func() {
+			By("build steps of a best effort parallel plan")
+			strategy := workloads.BestEffortParallelUpdateStrategy
+			its.Spec.MemberUpdateStrategy = &strategy
+			maxUnavailable := intstr.FromString("100%")
+			its.Spec.UpdateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{MaxUnavailable: &maxUnavailable}
+			checkSteps([][]*corev1.Pod{
+				{pod2, pod3, pod4, pod6},
+				{pod1},
+				{pod0},
+				{pod5},
+			})
+
+			By("limit the steps by the default maxUnavailable")
+			its.Spec.UpdateStrategy.RollingUpdate = nil
+			checkSteps([][]*corev1.Pod{
+				{pod4},
+				{pod2},
+				{pod6},
+				{pod3},
+				{pod1},
+				{pod0},
+				{pod5},
+			})
+		})
+
+		It("should build update steps by maxUnavailable for role-less pods", func() {
+			its.Spec.Roles = nil
+			for _, pod := range []*corev1.Pod{pod0, pod1, pod2, pod3, pod4, pod5, pod6} {
+				delete(pod.Labels, RoleLabelKey)
+			}
+			maxUnavailable := intstr.FromInt32(2)
+			its.Spec.UpdateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{MaxUnavailable: &maxUnavailable}
+			checkSteps([][]*corev1.Pod{
+				{pod6, pod5},
+				{pod4, pod3},
+				{pod2, pod1},
+				{pod0},
+			})
+		})
 	})
 })
@@ -0,0 +1,498 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// BackupManifestKind is the kind of the backup manifest.
+	BackupManifestKind = "BackupManifest"
+
+	manifestJobNamePrefix = "manifest-"
+
+	// backupManifestEnv is the name of the env that passes the manifest to the jobs.
+	backupManifestEnv = "DP_BACKUP_MANIFEST"
+
+	lastAppliedConfigAnnotationKey = "kubectl.kubernetes.io/last-applied-configuration"
+
+	// catalogManifestLinePrefix is the prefix of the lines that output the manifests
+	// by the catalog scanning script.
+	catalogManifestLinePrefix = "BACKUP_MANIFEST "
+	// catalogManifestErrorLinePrefix is the prefix of the lines that output the manifests
+	// failed to read by the catalog scanning script.
+	catalogManifestErrorLinePrefix = "BACKUP_MANIFEST_ERROR "
+	// catalogBeginLinePrefix and catalogEndLine enclose the output of the catalog scanning script,
+	// the begin line records the number of the manifests found, to detect the truncated output.
+	catalogBeginLinePrefix = "BACKUP_CATALOG_BEGIN "
+	catalogEndLine         = "BACKUP_CATALOG_END"
+
+	// maxConcurrentManifestJobs is the max number of the running jobs that write the manifests,
+	// to not flood the cluster when the manifests of the existing backups are backfilled.
+	maxConcurrentManifestJobs = 5
+
+	// maxCatalogManifestSize is the max size of a manifest output by the catalog scanning script.
+	maxCatalogManifestSize = 1024 * 1024
+)
+
+// BackupManifest is a self-describing record of a completed backup. It is written next
+// to the backup data in the backup repository, so that the Backup object can be recreated
+// from the backup repository when it is lost. The spec and status of the backup record
+// the backup method and ActionSet, the time range, the target, the encryption parameters
+// and the parent backup of the backup chain.
+type BackupManifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	UID         k8stypes.UID      `json:"uid,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// BackupRepoPathPrefix is the path prefix of the backup repository where the backup
+	// is taken, it is used to locate the Kopia repository of the copies of the backup
+	// in other backup repositories.
+	BackupRepoPathPrefix string `json:"backupRepoPathPrefix,omitempty"`
+
+	Spec   dpv1alpha1.BackupSpec   `json:"spec"`
+	Status dpv1alpha1.BackupStatus `json:"status"`
+}
+
+// BuildBackupManifest builds the manifest of the backup stored in the backup repository.
+func BuildBackupManifest(backup *dpv1alpha1.Backup, backupRepo *dpv1alpha1.BackupRepo) *BackupManifest {
+	m := &BackupManifest{
+		APIVersion:           dpv1alpha1.GroupVersion.String(),
+		Kind:                 BackupManifestKind,
+		Name:                 backup.Name,
+		Namespace:            backup.Namespace,
+		UID:                  backup.UID,
+		Labels:               backup.Labels,
+		BackupRepoPathPrefix: backupRepo.Spec.PathPrefix,
+		Spec:                 *backup.Spec.DeepCopy(),
+		Status:               *backup.Status.DeepCopy(),
+	}
+	for k, v := range backup.Annotations {
+		if k == lastAppliedConfigAnnotationKey {
+			continue
+		}
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[k] = v
+	}
+	// remove the fields that only make sense in the current cluster
+	m.Status.Expiration = nil
+	m.Status.PersistentVolumeClaimName = ""
+	m.Status.ManifestPath = ""
+	m.Status.Replicas = nil
+	m.Status.Verification = nil
+	m.Status.Retention = nil
+	return m
+}
+
+// ParseBackupManifest parses the backup manifest.
+func ParseBackupManifest(data []byte) (*BackupManifest, error) {
+	m := &BackupManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %s", err.Error())
+	}
+	if m.APIVersion != dpv1alpha1.GroupVersion.String() || m.Kind != BackupManifestKind {
+		return nil, fmt.Errorf("unsupported backup manifest %s/%s", m.APIVersion, m.Kind)
+	}
+	if m.Name == "" || m.Namespace == "" {
+		return nil, fmt.Errorf("the name or namespace of the backup manifest is empty")
+	}
+	if m.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return nil, fmt.Errorf("the backup %s/%s of the manifest is not completed", m.Namespace, m.Name)
+	}
+	return m, nil
+}
+
+// ToBackup builds the Backup object described by the manifest, which is found at the
+// manifestPath of the backupRepo. The Backup is stored at the directory of the manifest,
+// and the backup data is retained when the Backup is deleted. Only the labels and annotations
+// of KubeBlocks are kept, and the labels of the backup schedule are removed, so that the
+// Backup is neither governed by the backup schedule nor by its retention policy.
+func (m *BackupManifest) ToBackup(backupRepo *dpv1alpha1.BackupRepo, manifestPath string) *dpv1alpha1.Backup {
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        m.Name,
+			Namespace:   m.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec:   *m.Spec.DeepCopy(),
+		Status: *m.Status.DeepCopy(),
+	}
+	for k, v := range m.Labels {
+		if isCatalogMetaKey(k) && k != types.BackupScheduleLabelKey && k != types.AutoBackupLabelKey {
+			backup.Labels[k] = v
+		}
+	}
+	for k, v := range m.Annotations {
+		if isCatalogMetaKey(k) {
+			backup.Annotations[k] = v
+		}
+	}
+	backup.Annotations[types.CatalogBackupRepoAnnotationKey] = backupRepo.Name
+	backup.Spec.DeletionPolicy = dpv1alpha1.BackupDeletionPolicyRetain
+
+	backup.Status.BackupRepoName = backupRepo.Name
+	backup.Status.Path = filepath.Dir(manifestPath)
+	backup.Status.ManifestPath = manifestPath
+	if backup.Status.KopiaRepoPath != "" {
+		backup.Status.KopiaRepoPath = rebaseRepoPath(backup.Status.KopiaRepoPath,
+			m.BackupRepoPathPrefix, backupRepo.Spec.PathPrefix)
+	}
+	return backup
+}
+
+// isCatalogMetaKey checks if the label or annotation key belongs to KubeBlocks or the well-known
+// application labels, which can be recreated from the catalog.
+func isCatalogMetaKey(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return prefix == "kubeblocks.io" || strings.HasSuffix(prefix, ".kubeblocks.io") || prefix == "app.kubernetes.io"
+}
+
+// BuildBackupManifestPath builds the path of the manifest of the backup.
+func BuildBackupManifestPath(backup *dpv1alpha1.Backup) string {
+	return filepath.Join(backup.Status.Path, BackupManifestFileName)
+}
+
+// NeedsManifest checks if the manifest of the backup is to be written. The manifests
+// are only written for the completed backups stored in backup repositories, except
+// the continuous backups whose time range keeps growing.
+func NeedsManifest(backup *dpv1alpha1.Backup) bool {
+	return backup.Status.Phase == dpv1alpha1.BackupPhaseCompleted &&
+		backup.Status.ManifestPath == "" &&
+		backup.Status.BackupRepoName != "" &&
+		backup.Status.Path != "" &&
+		backup.Labels[types.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous)
+}
+
+// BuildManifestJobKey builds the key of the job that writes the manifest of the backup.
+func BuildManifestJobKey(backup *dpv1alpha1.Backup) client.ObjectKey {
+	jobName := fmt.Sprintf("%s-%s%s", backup.UID[:8], manifestJobNamePrefix, backup.Name)
+	if len(jobName) > 63 {
+		jobName = strings.TrimSuffix(jobName[:63], "-")
+	}
+	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
+}
+
+// BuildManifestLabels builds the labels of the job that writes the manifest of the backup.
+func BuildManifestLabels(backup *dpv1alpha1.Backup) map[string]string {
+	return map[string]string{
+		constant.AppManagedByLabelKey: types.AppName,
+		types.BackupManifestLabelKey:  backup.Name,
+	}
+}
+
+// ManifestWriter writes the manifest of a completed backup to the backup repository
+// where the backup is stored, and records the path of the manifest in the backup status.
+// The manifest is neither encrypted nor stored in the Kopia repository of the backup,
+// so that it can be read without any knowledge of the backup.
+type ManifestWriter struct {
+	intctrlutil.RequestCtx
+	Client               client.Client
+	Scheme               *k8sruntime.Scheme
+	Backup               *dpv1alpha1.Backup
+	WorkerServiceAccount string
+}
+
+// Write writes the manifest of the backup by a job if the backup repository enables the manifests,
+// and returns true if the writing is finished. A fatal error is returned if the job failed, the
+// failed job is kept and can be deleted to write the manifest again. The job is postponed if there
+// are too many running jobs writing the manifests.
+func (w *ManifestWriter) Write() (bool, error) {
+	if !NeedsManifest(w.Backup) {
+		return true, nil
+	}
+	jobKey := BuildManifestJobKey(w.Backup)
+	job := &batchv1.Job{}
+	exists, err := intctrlutil.CheckResourceExists(w.Ctx, w.Client, jobKey, job)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		_, finishedType, msg := dputils.IsJobFinished(job)
+		switch finishedType {
+		case batchv1.JobComplete:
+			w.Backup.Status.ManifestPath = BuildBackupManifestPath(w.Backup)
+			return true, intctrlutil.BackgroundDeleteObject(w.Client, w.Ctx, job)
+		case batchv1.JobFailed:
+			return true, intctrlutil.NewFatalError(fmt.Sprintf(
+				`write manifest job "%s" failed, you can delete it to re-write the manifest, %s`, job.Name, msg))
+		}
+		return false, nil
+	}
+
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	exists, err = intctrlutil.CheckResourceExists(w.Ctx, w.Client,
+		client.ObjectKey{Name: w.Backup.Status.BackupRepoName}, backupRepo)
+	if err != nil || !exists {
+		// the backup repo has been deleted, nothing to write.
+		return !exists, err
+	}
+	if !backupRepo.WritesManifests() {
+		return true, nil
+	}
+	if err = dputils.CheckBackupRepoInNamespace(w.Ctx, w.Client, backupRepo, w.Backup.Namespace); err != nil {
+		return intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal), err
+	}
+	running, err := w.countRunningJobs()
+	if err != nil {
+		return false, err
+	}
+	if running >= maxConcurrentManifestJobs {
+		w.Log.V(1).Info("too many running jobs writing the backup manifests, postpone it", "running", running)
+		return false, nil
+	}
+	if job, err = w.buildManifestJob(jobKey, backupRepo); err != nil {
+		return false, err
+	}
+	w.Log.V(1).Info("create a job to write the backup manifest", "job", client.ObjectKeyFromObject(job))
+	return false, client.IgnoreAlreadyExists(w.Client.Create(w.Ctx, job))
+}
+
+// countRunningJobs counts the running jobs that write the manifests of all the backups.
+func (w *ManifestWriter) countRunningJobs() (int, error) {
+	jobs := &batchv1.JobList{}
+	if err := w.Client.List(w.Ctx, jobs, client.HasLabels{types.BackupManifestLabelKey}); err != nil {
+		return 0, err
+	}
+	running := 0
+	for i := range jobs.Items {
+		if finished, _, _ := dputils.IsJobFinished(&jobs.Items[i]); !finished {
+			running++
+		}
+	}
+	return running, nil
+}
+
+// Cleanup deletes the jobs that write the manifest of the backup.
+func (w *ManifestWriter) Cleanup() error {
+	jobs := &batchv1.JobList{}
+	if err := w.Client.List(w.Ctx, jobs, client.InNamespace(w.Backup.Namespace),
+		client.MatchingLabels(BuildManifestLabels(w.Backup))); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		if err := intctrlutil.BackgroundDeleteObject(w.Client, w.Ctx, &jobs.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ManifestWriter) buildManifestJob(jobKey client.ObjectKey,
+	backupRepo *dpv1alpha1.BackupRepo) (*batchv1.Job, error) {
+	manifestEnv, err := buildBackupManifestEnv(w.Backup, backupRepo)
+	if err != nil {
+		return nil, err
+	}
+	runAsUser := int64(0)
+	container := corev1.Container{
+		Name:            "manifest",
+		Command:         []string{"sh", "-c"},
+		Args:            []string{buildPushManifestScript(w.Backup.Status.Path)},
+		Env:             []corev1.EnvVar{manifestEnv},
+		Image:           viper.GetString(constant.KBToolsImage),
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolptr.False(),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+
+	podSpec := corev1.PodSpec{
+		Containers:         []corev1.Container{container},
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: w.WorkerServiceAccount,
+	}
+	if err = dputils.AddTolerations(&podSpec); err != nil {
+		return nil, err
+	}
+	// write the manifest without encryption and outside the Kopia repository.
+	dputils.InjectDatasafed(&podSpec, backupRepo, RepoVolumeMountPath, nil, "")
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobKey.Namespace,
+			Name:      jobKey.Name,
+			Labels:    BuildManifestLabels(w.Backup),
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: jobKey.Namespace,
+					Name:      jobKey.Name,
+				},
+				Spec: podSpec,
+			},
+			BackoffLimit: &types.DefaultBackOffLimit,
+		},
+	}
+	if err = dputils.SetControllerReference(w.Backup, job, w.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func buildBackupManifestEnv(backup *dpv1alpha1.Backup, backupRepo *dpv1alpha1.BackupRepo) (corev1.EnvVar, error) {
+	data, err := json.Marshal(BuildBackupManifest(backup, backupRepo))
+	if err != nil {
+		return corev1.EnvVar{}, err
+	}
+	return corev1.EnvVar{Name: backupManifestEnv, Value: string(data)}, nil
+}
+
+// buildPushManifestScript builds the script that pushes the manifest passed by the env
+// to the backup path. The Kopia repository and the encryption are disabled for the manifest.
+func buildPushManifestScript(backupPath string) string {
+	return fmt.Sprintf(`
+set -eo pipefail
+export PATH="$PATH:$%s"
+unset %s %s %s
+manifestPath="%s"
+
+echo "writing backup manifest to ${manifestPath}"
+printf '%%s\n' "${%s}" | datasafed push - "${manifestPath}"
+`, types.DPDatasafedBinPath, types.DPDatasafedKopiaRepoRoot, types.DPDatasafedEncryptionAlgorithm,
+		types.DPDatasafedEncryptionPassPhrase, filepath.Join(backupPath, BackupManifestFileName), backupManifestEnv)
+}
+
+// CatalogEntry is a backup manifest found in the backup repository.
+type CatalogEntry struct {
+	// Path is the path of the manifest in the backup repository.
+	Path     string
+	Manifest *BackupManifest
+}
+
+// BuildScanManifestsScript builds the script that scans the backup repository under the
+// root path for the backup manifests, and outputs each of them in a line, which is parsed
+// by ParseCatalogOutput. The output is enclosed by the begin and end lines to detect the
+// truncation, e.g., by the rotation of the pod logs.
+func BuildScanManifestsScript(rootPath string) string {
+	return fmt.Sprintf(`
+set -eo pipefail
+export PATH="$PATH:$%s"
+unset %s %s %s
+rootPath="%s"
+fileList=$(mktemp)
+manifestList=$(mktemp)
+
+echo "scanning backup manifests in ${rootPath}"
+datasafed list -r -f "${rootPath}" > "${fileList}"
+grep -E "/%s$" "${fileList}" > "${manifestList}" || true
+printf '%%s%%s\n' "%s" "$(wc -l < "${manifestList}" | tr -d ' ')"
+while read -r file; do
+	if content=$(datasafed pull "${file}" - | tr -d '\n'); then
+		printf '%%s%%s %%s\n' "%s" "${file}" "${content}"
+	else
+		printf '%%s%%s\n' "%s" "${file}"
+	fi
+done < "${manifestList}"
+echo "%s"
+`, types.DPDatasafedBinPath, types.DPDatasafedKopiaRepoRoot, types.DPDatasafedEncryptionAlgorithm,
+		types.DPDatasafedEncryptionPassPhrase, rootPath, strings.ReplaceAll(BackupManifestFileName, ".", "\\."),
+		catalogBeginLinePrefix, catalogManifestLinePrefix, catalogManifestErrorLinePrefix, catalogEndLine)
+}
+
+// ErrCatalogTruncated is returned if the output of the catalog scanning script is incomplete,
+// e.g. the logs of the pod are rotated.
+var ErrCatalogTruncated = errors.New("the catalog output is truncated")
+
+// ParseCatalogOutput parses the backup manifests from the output of the catalog scanning
+// script. The invalid manifests are skipped and returned as the errors of the entries, and
+// an error is returned if the output is truncated.
+func ParseCatalogOutput(reader io.Reader) ([]CatalogEntry, []error, error) {
+	var (
+		entries  []CatalogEntry
+		errs     []error
+		expected = -1
+		ended    bool
+	)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCatalogManifestSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, catalogBeginLinePrefix):
+			count, err := strconv.Atoi(strings.TrimPrefix(line, catalogBeginLinePrefix))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid catalog output: %s", line)
+			}
+			expected = count
+		case line == catalogEndLine:
+			ended = true
+		case strings.HasPrefix(line, catalogManifestErrorLinePrefix):
+			errs = append(errs, fmt.Errorf("%s: failed to read the backup manifest",
+				strings.TrimPrefix(line, catalogManifestErrorLinePrefix)))
+		case strings.HasPrefix(line, catalogManifestLinePrefix):
+			path, content, found := strings.Cut(strings.TrimPrefix(line, catalogManifestLinePrefix), " ")
+			if !found {
+				errs = append(errs, fmt.Errorf("invalid catalog output: %s", line))
+				continue
+			}
+			m, err := ParseBackupManifest([]byte(content))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s", path, err.Error()))
+				continue
+			}
+			entries = append(entries, CatalogEntry{Path: path, Manifest: m})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if expected < 0 || !ended || len(entries)+len(errs) != expected {
+		return nil, nil, fmt.Errorf("%w, %d of %d manifests are read",
+			ErrCatalogTruncated, len(entries)+len(errs), expected)
+	}
+	return entries, errs, nil
+}
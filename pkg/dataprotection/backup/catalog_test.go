@@ -0,0 +1,209 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func newManifestTestBackup() *dpv1alpha1.Backup {
+	backup := newReplicateTestBackup("backup-20240102", "backup-20240101")
+	backup.Labels = map[string]string{
+		types.BackupTypeLabelKey:     string(dpv1alpha1.BackupTypeIncremental),
+		types.BackupScheduleLabelKey: "schedule",
+		types.AutoBackupLabelKey:     "true",
+		"team":                       "dba",
+	}
+	backup.Annotations = map[string]string{
+		lastAppliedConfigAnnotationKey:        "{}",
+		types.ConnectionPasswordAnnotationKey: "secret",
+	}
+	backup.Spec.DeletionPolicy = dpv1alpha1.BackupDeletionPolicyDelete
+	backup.Status.BackupMethod = &dpv1alpha1.BackupMethod{Name: "xtrabackup", ActionSetName: "xtrabackup-inc"}
+	backup.Status.EncryptionConfig = &dpv1alpha1.EncryptionConfig{Algorithm: "AES-256-CFB"}
+	backup.Status.Expiration = &metav1.Time{}
+	backup.Status.Replicas = []dpv1alpha1.BackupReplicaStatus{{BackupRepoName: "dr"}}
+	return backup
+}
+
+func TestBackupManifest(t *testing.T) {
+	backup := newManifestTestBackup()
+	data, err := json.Marshal(BuildBackupManifest(backup, newReplicateTestRepo("primary", "")))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "\n")
+
+	m, err := ParseBackupManifest(data)
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.GroupVersion.String(), m.APIVersion)
+	assert.Equal(t, BackupManifestKind, m.Kind)
+	assert.NotContains(t, m.Annotations, lastAppliedConfigAnnotationKey)
+	assert.Equal(t, "xtrabackup-inc", m.Status.BackupMethod.ActionSetName)
+	assert.Equal(t, "backup-20240101", m.Spec.ParentBackupName)
+	assert.Nil(t, m.Status.Expiration)
+	assert.Nil(t, m.Status.Replicas)
+
+	// recreate the backup from the copy in another backup repo
+	dr := newReplicateTestRepo("dr", "dr-site")
+	manifestPath := "/dr-site/default/policy/backup-20240102/" + BackupManifestFileName
+	recreated := m.ToBackup(dr, manifestPath)
+	assert.Equal(t, backup.Name, recreated.Name)
+	assert.Equal(t, backup.Namespace, recreated.Namespace)
+	assert.Equal(t, "dr", recreated.Annotations[types.CatalogBackupRepoAnnotationKey])
+	assert.Equal(t, "secret", recreated.Annotations[types.ConnectionPasswordAnnotationKey])
+	assert.Equal(t, string(dpv1alpha1.BackupTypeIncremental), recreated.Labels[types.BackupTypeLabelKey])
+	// the recreated backup is neither owned by a schedule nor carries the labels of other owners.
+	assert.NotContains(t, recreated.Labels, types.BackupScheduleLabelKey)
+	assert.NotContains(t, recreated.Labels, types.AutoBackupLabelKey)
+	assert.NotContains(t, recreated.Labels, "team")
+	assert.Equal(t, dpv1alpha1.BackupDeletionPolicyRetain, recreated.Spec.DeletionPolicy)
+	assert.Equal(t, dpv1alpha1.BackupPhaseCompleted, recreated.Status.Phase)
+	assert.Equal(t, "dr", recreated.Status.BackupRepoName)
+	assert.Equal(t, "/dr-site/default/policy/backup-20240102", recreated.Status.Path)
+	assert.Equal(t, "/dr-site/default/policy/kopia", recreated.Status.KopiaRepoPath)
+	assert.Equal(t, manifestPath, recreated.Status.ManifestPath)
+	assert.Equal(t, backup.Status.EncryptionConfig, recreated.Status.EncryptionConfig)
+
+	_, err = ParseBackupManifest([]byte(`{"apiVersion":"v1","kind":"Pod"}`))
+	assert.Error(t, err)
+	backup.Status.Phase = dpv1alpha1.BackupPhaseRunning
+	data, _ = json.Marshal(BuildBackupManifest(backup, newReplicateTestRepo("primary", "")))
+	_, err = ParseBackupManifest(data)
+	assert.Error(t, err)
+}
+
+func TestParseCatalogOutput(t *testing.T) {
+	backup := newManifestTestBackup()
+	data, err := json.Marshal(BuildBackupManifest(backup, newReplicateTestRepo("primary", "")))
+	assert.NoError(t, err)
+	lines := []string{
+		"scanning backup manifests in /",
+		catalogBeginLinePrefix + "3",
+		fmt.Sprintf("%s/default/policy/backup-20240102/%s %s", catalogManifestLinePrefix, BackupManifestFileName, data),
+		fmt.Sprintf("%s/default/policy/broken/%s {", catalogManifestLinePrefix, BackupManifestFileName),
+		catalogManifestErrorLinePrefix + "/default/policy/unreadable/" + BackupManifestFileName,
+		catalogEndLine,
+	}
+	entries, errs, err := ParseCatalogOutput(strings.NewReader(strings.Join(lines, "\n")))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "/default/policy/backup-20240102/"+BackupManifestFileName, entries[0].Path)
+	assert.Equal(t, backup.Name, entries[0].Manifest.Name)
+	assert.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "/default/policy/broken/")
+	assert.Contains(t, errs[1].Error(), "/default/policy/unreadable/")
+
+	// the output is truncated, e.g. the logs are rotated.
+	for _, truncated := range [][]string{
+		lines[:len(lines)-1],
+		lines[2:],
+		append(append([]string{}, lines[:3]...), lines[5:]...),
+	} {
+		_, _, err = ParseCatalogOutput(strings.NewReader(strings.Join(truncated, "\n")))
+		assert.ErrorIs(t, err, ErrCatalogTruncated)
+	}
+}
+
+func TestWriteBackupManifest(t *testing.T) {
+	ctx := context.Background()
+	backup := newManifestTestBackup()
+	primary := newReplicateTestRepo("primary", "")
+	primary.Spec.CatalogSync = &dpv1alpha1.BackupRepoCatalogSync{WriteManifests: true}
+	cli, scheme := newTestClient(t, backup, primary, newReplicateTestSecret(primary))
+	writer := &ManifestWriter{
+		RequestCtx:           intctrlutil.RequestCtx{Ctx: ctx, Log: logr.Discard()},
+		Client:               cli,
+		Scheme:               scheme,
+		Backup:               backup,
+		WorkerServiceAccount: "kubeblocks-dataprotection-worker",
+	}
+
+	assert.True(t, NeedsManifest(backup))
+	// the manifests are not written unless the backup repo opts in.
+	primary.Spec.CatalogSync.WriteManifests = false
+	assert.NoError(t, cli.Update(ctx, primary))
+	done, err := writer.Write()
+	assert.NoError(t, err)
+	assert.True(t, done)
+	exists, err := intctrlutil.CheckResourceExists(ctx, cli, BuildManifestJobKey(backup), &batchv1.Job{})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	primary.Spec.CatalogSync.WriteManifests = true
+	assert.NoError(t, cli.Update(ctx, primary))
+	done, err = writer.Write()
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	// the manifest is written without encryption and outside the kopia repository.
+	job := &batchv1.Job{}
+	jobKey := BuildManifestJobKey(backup)
+	assert.NoError(t, cli.Get(ctx, jobKey, job))
+	assert.Equal(t, backup.Name, job.Labels[types.BackupManifestLabelKey])
+	container := job.Spec.Template.Spec.Containers[0]
+	for _, env := range container.Env {
+		assert.NotEqual(t, types.DPDatasafedKopiaRepoRoot, env.Name)
+		assert.NotEqual(t, types.DPDatasafedEncryptionAlgorithm, env.Name)
+		if env.Name == backupManifestEnv {
+			m, err := ParseBackupManifest([]byte(env.Value))
+			assert.NoError(t, err)
+			assert.Equal(t, backup.Name, m.Name)
+		}
+	}
+	assert.Contains(t, container.Args[0], "/default/policy/backup-20240102/"+BackupManifestFileName)
+
+	// the job is failed
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	done, err = writer.Write()
+	assert.True(t, done)
+	assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	assert.Empty(t, backup.Status.ManifestPath)
+
+	// the job is completed
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	done, err = writer.Write()
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "/default/policy/backup-20240102/"+BackupManifestFileName, backup.Status.ManifestPath)
+	assert.False(t, NeedsManifest(backup))
+	exists, err = intctrlutil.CheckResourceExists(ctx, cli, jobKey, &batchv1.Job{})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// the manifests are not written for the continuous backups
+	continuous := newManifestTestBackup()
+	continuous.Labels[types.BackupTypeLabelKey] = string(dpv1alpha1.BackupTypeContinuous)
+	assert.False(t, NeedsManifest(continuous))
+	assert.NoError(t, writer.Cleanup())
+}
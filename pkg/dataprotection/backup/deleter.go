@@ -75,29 +75,14 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 		return DeletionStatusSucceeded, nil
 	}
 	jobKey := BuildDeleteBackupFilesJobKey(backup, false)
-	job := &batchv1.Job{}
-	exists, err := ctrlutil.CheckResourceExists(d.Ctx, d.Client, jobKey, job)
-	if err != nil {
-		return DeletionStatusUnknown, err
-	}
-
-	// if deletion job exists, check its status
-	if exists {
-		_, finishedType, msg := utils.IsJobFinished(job)
-		switch finishedType {
-		case batchv1.JobComplete:
-			return DeletionStatusSucceeded, nil
-		case batchv1.JobFailed:
-			return DeletionStatusFailed,
-				fmt.Errorf("deletion backup files job \"%s\" failed, you can delete it to re-delete the backup files, %s", job.Name, msg)
-		}
-		return DeletionStatusDeleting, nil
+	if status, exists, err := d.checkDeleteJob(jobKey); err != nil || exists {
+		return status, err
 	}
 
 	var backupRepo *dpv1alpha1.BackupRepo
 	if backup.Status.BackupRepoName != "" {
 		backupRepo = &dpv1alpha1.BackupRepo{}
-		if err = d.Client.Get(d.Ctx, client.ObjectKey{Name: backup.Status.BackupRepoName}, backupRepo); err != nil {
+		if err := d.Client.Get(d.Ctx, client.ObjectKey{Name: backup.Status.BackupRepoName}, backupRepo); err != nil {
 			if apierrors.IsNotFound(err) {
 				return DeletionStatusSucceeded, nil
 			}
@@ -117,7 +102,7 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 
 		// check if the backup PVC exists, if not, skip to delete backup files
 		pvcKey := client.ObjectKey{Namespace: backup.Namespace, Name: legacyPVCName}
-		if err = d.Client.Get(d.Ctx, pvcKey, &corev1.PersistentVolumeClaim{}); err != nil {
+		if err := d.Client.Get(d.Ctx, pvcKey, &corev1.PersistentVolumeClaim{}); err != nil {
 			if apierrors.IsNotFound(err) {
 				return DeletionStatusSucceeded, nil
 			}
@@ -153,7 +138,7 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 		_, finishedType, msg := utils.IsJobFinished(preJob)
 		if finishedType == batchv1.JobFailed {
 			return DeletionStatusFailed,
-				fmt.Errorf("pre-delete backup files job \"%s\" failed, you can delete it to re-delete the backup files, %s", job.Name, msg)
+				fmt.Errorf("pre-delete backup files job \"%s\" failed, you can delete it to re-delete the backup files, %s", preJob.Name, msg)
 		} else if finishedType != batchv1.JobComplete {
 			return DeletionStatusDeleting, nil
 		}
@@ -162,6 +147,54 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 	return DeletionStatusDeleting, d.createDeleteBackupFilesJob(jobKey, backup, backupRepo, legacyPVCName)
 }
 
+// DeleteReplicaFiles builds a job to delete the files of the backup replica, and returns
+// the deletion status.
+func (d *Deleter) DeleteReplicaFiles(backup *dpv1alpha1.Backup, replica *dpv1alpha1.BackupReplicaStatus) (DeletionStatus, error) {
+	jobKey := BuildDeleteReplicaFilesJobKey(backup, replica.BackupRepoName)
+	if status, exists, err := d.checkDeleteJob(jobKey); err != nil || exists {
+		return status, err
+	}
+	// the same as the backup files, do not delete the path without the backup name.
+	if replica.Path == "" || !strings.Contains(replica.Path, backup.Name) {
+		return DeletionStatusSucceeded, nil
+	}
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	if err := d.Client.Get(d.Ctx, client.ObjectKey{Name: replica.BackupRepoName}, backupRepo); err != nil {
+		if apierrors.IsNotFound(err) {
+			return DeletionStatusSucceeded, nil
+		}
+		return DeletionStatusUnknown, err
+	}
+
+	// delete the files at the location of the replica.
+	replicaBackup := backup.DeepCopy()
+	replicaBackup.Status.BackupRepoName = replica.BackupRepoName
+	replicaBackup.Status.Path = replica.Path
+	replicaBackup.Status.KopiaRepoPath = replica.KopiaRepoPath
+	return DeletionStatusDeleting, d.createDeleteBackupFilesJob(jobKey, replicaBackup, backupRepo, "")
+}
+
+// checkDeleteJob checks if the deletion job exists, and returns the deletion status by the job status.
+func (d *Deleter) checkDeleteJob(jobKey types.NamespacedName) (DeletionStatus, bool, error) {
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(d.Ctx, d.Client, jobKey, job)
+	if err != nil {
+		return DeletionStatusUnknown, false, err
+	}
+	if !exists {
+		return DeletionStatusUnknown, false, nil
+	}
+	_, finishedType, msg := utils.IsJobFinished(job)
+	switch finishedType {
+	case batchv1.JobComplete:
+		return DeletionStatusSucceeded, true, nil
+	case batchv1.JobFailed:
+		return DeletionStatusFailed, true,
+			fmt.Errorf("deletion backup files job \"%s\" failed, you can delete it to re-delete the backup files, %s", job.Name, msg)
+	}
+	return DeletionStatusDeleting, true, nil
+}
+
 func (d *Deleter) buildDeleteBackupFilesScript(backupPath string) string {
 
 	// this script first deletes the directory where the backup is located (including files
@@ -201,6 +234,13 @@ else
 	# remove empty dirs from the kopia repository
 	rmdirs "${targetPath}"
 
+	# remove the backup manifest, which is stored outside the kopia repository
+	(
+		unset DATASAFED_KOPIA_REPO_ROOT
+		datasafed rm "${targetPath}/%s"
+		rmdirs "${targetPath}"
+	)
+
 	# remove the kopia repository itself from the storage if it's empty
 	result=$(datasafed list "/")
 	if [ -z "$result" ]; then
@@ -214,7 +254,7 @@ else
 		rmdirs "${kopiaRepoPath}"
 	fi
 fi
-	`, dptypes.DPDatasafedBinPath, backupPath)
+	`, dptypes.DPDatasafedBinPath, backupPath, BackupManifestFileName)
 
 	return deleteScript
 }
@@ -376,6 +416,15 @@ func (d *Deleter) DeleteVolumeSnapshots(backup *dpv1alpha1.Backup) error {
 	return nil
 }
 
+// BuildDeleteReplicaFilesJobKey builds the key of the job that deletes the files of the backup replica.
+func BuildDeleteReplicaFilesJobKey(backup *dpv1alpha1.Backup, backupRepoName string) client.ObjectKey {
+	jobName := fmt.Sprintf("%s-%s%s-%s", backup.UID[:8], deleteBackupFilesJobNamePrefix, backupRepoName, backup.Name)
+	if len(jobName) > 63 {
+		jobName = strings.TrimSuffix(jobName[:63], "-")
+	}
+	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
+}
+
 func BuildDeleteBackupFilesJobKey(backup *dpv1alpha1.Backup, isPreDelete bool) client.ObjectKey {
 	var preDeletePrefix string
 	if isPreDelete {
@@ -0,0 +1,484 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	replicateJobNamePrefix        = "replicate-"
+	replicationStagingVolumeName  = "dp-replication-staging"
+	replicationStagingMountPath   = "/dp-replication-staging"
+	replicationTargetVolumePrefix = "dp-target-"
+)
+
+// Replicator copies a completed backup from the backup repository where it is stored
+// to the secondary backup repositories of the replication policy. Each copy is made by
+// a job that pulls the backup files from the source repository into a staging volume,
+// and then pushes them to the target repository. The staging volume is limited to the
+// total size of the backup with some headroom, and the job requests the same ephemeral
+// storage to be scheduled to a node with enough space. Since the files are read and written
+// by datasafed, the backup stored in a Kopia repository is copied into the Kopia
+// repository of the target. The replica of an incremental backup is made only after
+// the replica of its parent backup is completed in the same backup repository.
+type Replicator struct {
+	intctrlutil.RequestCtx
+	Client               client.Client
+	Scheme               *k8sruntime.Scheme
+	Backup               *dpv1alpha1.Backup
+	Policy               *dpv1alpha1.BackupReplicationPolicy
+	WorkerServiceAccount string
+
+	sourceRepo *dpv1alpha1.BackupRepo
+}
+
+// NeedsReplication checks if any replica of the backup is to be made or in progress.
+func NeedsReplication(backup *dpv1alpha1.Backup, policy *dpv1alpha1.BackupReplicationPolicy) bool {
+	if IsReplicationInProgress(backup) {
+		return true
+	}
+	if !IsReplicationRequired(backup, policy) {
+		return false
+	}
+	for _, repoName := range policy.BackupRepoNames {
+		if repoName != backup.Status.BackupRepoName && backup.GetReplica(repoName) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReplicationRequired checks if the backup should be replicated by the replication policy.
+func IsReplicationRequired(backup *dpv1alpha1.Backup, policy *dpv1alpha1.BackupReplicationPolicy) bool {
+	if policy == nil || backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return false
+	}
+	// the backup only takes volume snapshots or is stored in a legacy backup PVC.
+	if backup.Status.BackupRepoName == "" || backup.Status.Path == "" {
+		return false
+	}
+	return len(policy.BackupMethods) == 0 || slices.Contains(policy.BackupMethods, backup.Spec.BackupMethod)
+}
+
+// IsReplicationInProgress checks if any replica of the backup is pending or running.
+func IsReplicationInProgress(backup *dpv1alpha1.Backup) bool {
+	for i := range backup.Status.Replicas {
+		if isReplicaInProgress(&backup.Status.Replicas[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isReplicaInProgress(replica *dpv1alpha1.BackupReplicaStatus) bool {
+	return replica.Phase == "" || replica.Phase == dpv1alpha1.BackupReplicaPending ||
+		replica.Phase == dpv1alpha1.BackupReplicaRunning
+}
+
+// BuildReplicateJobKey builds the key of the job that copies the backup to the backup repository.
+func BuildReplicateJobKey(backup *dpv1alpha1.Backup, backupRepoName string) client.ObjectKey {
+	jobName := fmt.Sprintf("%s-%s%s-%s", backup.UID[:8], replicateJobNamePrefix, backupRepoName, backup.Name)
+	if len(jobName) > 63 {
+		jobName = strings.TrimSuffix(jobName[:63], "-")
+	}
+	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
+}
+
+// BuildReplicationLabels builds the labels of the jobs that copy the backup.
+func BuildReplicationLabels(backup *dpv1alpha1.Backup) map[string]string {
+	return map[string]string{
+		constant.AppManagedByLabelKey: types.AppName,
+		types.ReplicateBackupLabelKey: backup.Name,
+	}
+}
+
+// Replicate makes the replicas of the backup in the backup repositories of the replication
+// policy, and records them in status.replicas of the backup. It returns the name of the
+// backup repository that should be prepared in the namespace of the backup by the
+// BackupRepoController before the backup can be copied to it.
+func (r *Replicator) Replicate() (string, error) {
+	if IsReplicationRequired(r.Backup, r.Policy) {
+		for _, repoName := range r.Policy.BackupRepoNames {
+			if repoName == r.Backup.Status.BackupRepoName || r.Backup.GetReplica(repoName) != nil {
+				continue
+			}
+			r.Backup.Status.Replicas = append(r.Backup.Status.Replicas, dpv1alpha1.BackupReplicaStatus{
+				BackupRepoName: repoName,
+				Phase:          dpv1alpha1.BackupReplicaPending,
+			})
+		}
+	}
+	var waitRepoName string
+	for i := range r.Backup.Status.Replicas {
+		replica := &r.Backup.Status.Replicas[i]
+		if !isReplicaInProgress(replica) {
+			continue
+		}
+		err := r.replicate(replica)
+		switch {
+		case intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal):
+			replica.Phase = dpv1alpha1.BackupReplicaFailed
+			replica.FailureReason = err.Error()
+			replica.CompletionTimestamp = &metav1.Time{Time: metav1.Now().UTC()}
+			if err = r.deleteReplicateJob(replica.BackupRepoName); err != nil {
+				return waitRepoName, err
+			}
+		case intctrlutil.IsTargetError(err, dperrors.ErrorTypeWaitForBackupRepoPreparation):
+			if waitRepoName == "" {
+				waitRepoName = replica.BackupRepoName
+			}
+		case err != nil:
+			return waitRepoName, err
+		}
+	}
+	return waitRepoName, nil
+}
+
+// Cleanup deletes the jobs that copy the backup.
+func (r *Replicator) Cleanup() error {
+	jobs := &batchv1.JobList{}
+	if err := r.Client.List(r.Ctx, jobs, client.InNamespace(r.Backup.Namespace),
+		client.MatchingLabels(BuildReplicationLabels(r.Backup))); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		if err := intctrlutil.BackgroundDeleteObject(r.Client, r.Ctx, &jobs.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Replicator) replicate(replica *dpv1alpha1.BackupReplicaStatus) error {
+	targetRepo, ready, err := r.getBackupRepo(replica.BackupRepoName)
+	if err != nil || !ready {
+		return err
+	}
+	if r.sourceRepo == nil {
+		if r.sourceRepo, ready, err = r.getBackupRepo(r.Backup.Status.BackupRepoName); err != nil || !ready {
+			r.sourceRepo = nil
+			return err
+		}
+	}
+	if ready, err = r.checkParentReplica(replica.BackupRepoName); err != nil || !ready {
+		return err
+	}
+	if replica.Phase != dpv1alpha1.BackupReplicaRunning {
+		replica.Phase = dpv1alpha1.BackupReplicaRunning
+		replica.StartTimestamp = &metav1.Time{Time: metav1.Now().UTC()}
+		replica.Path = rebaseRepoPath(r.Backup.Status.Path, r.sourceRepo.Spec.PathPrefix, targetRepo.Spec.PathPrefix)
+		if r.Backup.Status.KopiaRepoPath != "" {
+			replica.KopiaRepoPath = rebaseRepoPath(r.Backup.Status.KopiaRepoPath,
+				r.sourceRepo.Spec.PathPrefix, targetRepo.Spec.PathPrefix)
+		}
+	}
+
+	jobKey := BuildReplicateJobKey(r.Backup, replica.BackupRepoName)
+	job := &batchv1.Job{}
+	exists, err := intctrlutil.CheckResourceExists(r.Ctx, r.Client, jobKey, job)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if job, err = r.buildReplicateJob(jobKey, replica, targetRepo); err != nil {
+			return err
+		}
+		r.Log.V(1).Info("create a job to replicate backup", "job", jobKey)
+		return client.IgnoreAlreadyExists(r.Client.Create(r.Ctx, job))
+	}
+	_, finishedType, msg := dputils.IsJobFinished(job)
+	switch finishedType {
+	case batchv1.JobComplete:
+		replica.Phase = dpv1alpha1.BackupReplicaCompleted
+		replica.CompletionTimestamp = &metav1.Time{Time: metav1.Now().UTC()}
+		return r.deleteReplicateJob(replica.BackupRepoName)
+	case batchv1.JobFailed:
+		return intctrlutil.NewFatalError(fmt.Sprintf(`replicate job "%s" failed: %s`, job.Name, msg))
+	}
+	return nil
+}
+
+// getBackupRepo gets the backup repository, and checks if it's ready to be used in the namespace
+// of the backup.
+func (r *Replicator) getBackupRepo(name string) (*dpv1alpha1.BackupRepo, bool, error) {
+	repo := &dpv1alpha1.BackupRepo{}
+	if err := r.Client.Get(r.Ctx, client.ObjectKey{Name: name}, repo); err != nil {
+		if apierrors.IsNotFound(err) {
+			err = intctrlutil.NewFatalError(fmt.Sprintf("backup repo %s not found", name))
+		}
+		return nil, false, err
+	}
+	err := dputils.CheckBackupRepoInNamespace(r.Ctx, r.Client, repo, r.Backup.Namespace)
+	if intctrlutil.IsTargetError(err, dperrors.ErrorTypeBackupRepoIsNotReady) {
+		// wait for the backup repo to be ready
+		return repo, false, nil
+	}
+	return repo, err == nil, err
+}
+
+// checkParentReplica checks if the replica of the parent backup is completed in the backup
+// repository, so that the incremental backup chain is kept in the backup repository.
+func (r *Replicator) checkParentReplica(repoName string) (bool, error) {
+	parentName := r.Backup.Spec.ParentBackupName
+	if parentName == "" {
+		return true, nil
+	}
+	parent := &dpv1alpha1.Backup{}
+	if err := r.Client.Get(r.Ctx, client.ObjectKey{Namespace: r.Backup.Namespace, Name: parentName}, parent); err != nil {
+		if apierrors.IsNotFound(err) {
+			err = intctrlutil.NewFatalError(fmt.Sprintf(`parent backup "%s" not found`, parentName))
+		}
+		return false, err
+	}
+	if parent.Status.BackupRepoName == repoName {
+		return true, nil
+	}
+	replica := parent.GetReplica(repoName)
+	switch {
+	case replica == nil:
+		// request the replica of the parent backup, which is made by the reconciliation of the parent.
+		patch := client.MergeFrom(parent.DeepCopy())
+		parent.Status.Replicas = append(parent.Status.Replicas, dpv1alpha1.BackupReplicaStatus{
+			BackupRepoName: repoName,
+			Phase:          dpv1alpha1.BackupReplicaPending,
+		})
+		return false, r.Client.Status().Patch(r.Ctx, parent, patch)
+	case replica.Phase == dpv1alpha1.BackupReplicaCompleted:
+		return true, nil
+	case replica.Phase == dpv1alpha1.BackupReplicaFailed:
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`the replica of parent backup "%s" failed: %s`,
+			parentName, replica.FailureReason))
+	}
+	return false, nil
+}
+
+func (r *Replicator) deleteReplicateJob(repoName string) error {
+	job := &batchv1.Job{}
+	exists, err := intctrlutil.CheckResourceExists(r.Ctx, r.Client, BuildReplicateJobKey(r.Backup, repoName), job)
+	if err != nil || !exists {
+		return err
+	}
+	return intctrlutil.BackgroundDeleteObject(r.Client, r.Ctx, job)
+}
+
+func (r *Replicator) buildReplicateJob(jobKey client.ObjectKey,
+	replica *dpv1alpha1.BackupReplicaStatus,
+	targetRepo *dpv1alpha1.BackupRepo) (*batchv1.Job, error) {
+	runAsUser := int64(0)
+	stagingMount := corev1.VolumeMount{Name: replicationStagingVolumeName, MountPath: replicationStagingMountPath}
+	buildContainer := func(name, script string) corev1.Container {
+		container := corev1.Container{
+			Name:            name,
+			Command:         []string{"sh", "-c"},
+			Args:            []string{script},
+			Image:           viper.GetString(constant.KBToolsImage),
+			ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+			VolumeMounts:    []corev1.VolumeMount{stagingMount},
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolptr.False(),
+				RunAsUser:                &runAsUser,
+			},
+		}
+		intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+		return container
+	}
+	encryptionConfig := r.Backup.Status.EncryptionConfig
+	stagingSize, err := replicationStagingSize(r.Backup)
+	if err != nil {
+		return nil, err
+	}
+
+	// pull the backup files from the source backup repository into the staging volume.
+	pullContainer := buildContainer("pull", buildPullBackupFilesScript(r.Backup.Status.Path))
+	pullContainer.Resources.Requests = corev1.ResourceList{corev1.ResourceEphemeralStorage: stagingSize}
+	pullSpec := corev1.PodSpec{
+		Containers: []corev1.Container{pullContainer},
+	}
+	dputils.InjectDatasafed(&pullSpec, r.sourceRepo, RepoVolumeMountPath, encryptionConfig, r.Backup.Status.KopiaRepoPath)
+
+	// push the staged files to the target backup repository, along with the manifest if the target enables it.
+	pushContainer := buildContainer("push", buildPushBackupFilesScript(replica.Path))
+	if targetRepo.WritesManifests() {
+		manifestEnv, err := buildBackupManifestEnv(r.Backup, r.sourceRepo)
+		if err != nil {
+			return nil, err
+		}
+		pushContainer.Env = append(pushContainer.Env, manifestEnv)
+	}
+	pushSpec := corev1.PodSpec{
+		Containers: []corev1.Container{pushContainer},
+	}
+	dputils.InjectDatasafed(&pushSpec, targetRepo, RepoVolumeMountPath, encryptionConfig, replica.KopiaRepoPath)
+	renamePodSpecVolumes(&pushSpec, replicationTargetVolumePrefix)
+
+	podSpec := corev1.PodSpec{
+		InitContainers: append(append(pullSpec.InitContainers, pullSpec.Containers...), pushSpec.InitContainers...),
+		Containers:     pushSpec.Containers,
+		Volumes: append(append([]corev1.Volume{{
+			Name:         replicationStagingVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &stagingSize}},
+		}}, pullSpec.Volumes...), pushSpec.Volumes...),
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: r.WorkerServiceAccount,
+	}
+	if err := dputils.AddTolerations(&podSpec); err != nil {
+		return nil, err
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobKey.Namespace,
+			Name:      jobKey.Name,
+			Labels:    BuildReplicationLabels(r.Backup),
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: jobKey.Namespace,
+					Name:      jobKey.Name,
+				},
+				Spec: podSpec,
+			},
+			BackoffLimit: &types.DefaultBackOffLimit,
+		},
+	}
+	if err := dputils.SetControllerReference(r.Backup, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// replicationStagingSize returns the size limit of the staging volume, it is the total size of the backup
+// with 10% headroom, or the configured size if the total size is unknown.
+func replicationStagingSize(backup *dpv1alpha1.Backup) (resource.Quantity, error) {
+	if totalSize, err := resource.ParseQuantity(backup.Status.TotalSize); err == nil && totalSize.Value() > 0 {
+		return *resource.NewQuantity(totalSize.Value()+totalSize.Value()/10, resource.BinarySI), nil
+	}
+	size, err := resource.ParseQuantity(viper.GetString(types.CfgKeyReplicationStagingSize))
+	if err != nil {
+		return size, fmt.Errorf("invalid %s: %s", types.CfgKeyReplicationStagingSize, err.Error())
+	}
+	return size, nil
+}
+
+// renamePodSpecVolumes adds the prefix to the names of the volumes and init containers of
+// the pod spec, to avoid conflicts when it's merged with another pod spec. The mounts of
+// the volumes not defined in the pod spec are kept, they are shared with the other pod spec.
+func renamePodSpecVolumes(podSpec *corev1.PodSpec, prefix string) {
+	volumeNames := map[string]bool{}
+	for i := range podSpec.Volumes {
+		volumeNames[podSpec.Volumes[i].Name] = true
+		podSpec.Volumes[i].Name = prefix + podSpec.Volumes[i].Name
+	}
+	renameMounts := func(containers []corev1.Container) {
+		for i := range containers {
+			for j := range containers[i].VolumeMounts {
+				if volumeNames[containers[i].VolumeMounts[j].Name] {
+					containers[i].VolumeMounts[j].Name = prefix + containers[i].VolumeMounts[j].Name
+				}
+			}
+		}
+	}
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].Name = prefix + podSpec.InitContainers[i].Name
+	}
+	renameMounts(podSpec.InitContainers)
+	renameMounts(podSpec.Containers)
+}
+
+// rebaseRepoPath replaces the path prefix of the source backup repository in the path
+// with the path prefix of the target backup repository.
+func rebaseRepoPath(path, sourcePathPrefix, targetPathPrefix string) string {
+	relPath := strings.TrimPrefix(path, filepath.Join("/", strings.Trim(sourcePathPrefix, "/")))
+	return filepath.Join("/", strings.Trim(targetPathPrefix, "/"), relPath)
+}
+
+func buildPullBackupFilesScript(sourcePath string) string {
+	return fmt.Sprintf(`
+set -eo pipefail
+export PATH="$PATH:$%s"
+sourcePath="%s"
+stagingPath="%s"
+
+echo "pulling backup files from ${sourcePath}"
+datasafed list -r -f "${sourcePath}" | while read -r file; do
+	relPath="${file#"${sourcePath}"}"
+	relPath="${relPath#/}"
+	# the manifest is not encrypted, it is written to the target separately.
+	if [ -z "${relPath}" ] || [ "${relPath}" = "%s" ]; then
+		continue
+	fi
+	mkdir -p "$(dirname "${stagingPath}/${relPath}")"
+	datasafed pull "${sourcePath}/${relPath}" "${stagingPath}/${relPath}"
+done
+
+if [ -z "$(find "${stagingPath}" -type f)" ]; then
+	echo "no backup files found in ${sourcePath}"
+	exit 1
+fi
+`, types.DPDatasafedBinPath, sourcePath, replicationStagingMountPath, BackupManifestFileName)
+}
+
+func buildPushBackupFilesScript(targetPath string) string {
+	return fmt.Sprintf(`
+set -eo pipefail
+export PATH="$PATH:$%s"
+targetPath="%s"
+stagingPath="%s"
+
+echo "pushing backup files to ${targetPath}"
+cd "${stagingPath}"
+find . -type f | while read -r file; do
+	relPath="${file#./}"
+	datasafed push "${stagingPath}/${relPath}" "${targetPath}/${relPath}"
+done
+
+if [ -n "${%s}" ]; then
+	echo "writing backup manifest to ${targetPath}"
+	(
+		unset %s %s %s
+		printf '%%s\n' "${%s}" | datasafed push - "${targetPath}/%s"
+	)
+fi
+`, types.DPDatasafedBinPath, targetPath, replicationStagingMountPath, backupManifestEnv,
+		types.DPDatasafedKopiaRepoRoot, types.DPDatasafedEncryptionAlgorithm, types.DPDatasafedEncryptionPassPhrase,
+		backupManifestEnv, BackupManifestFileName)
+}
@@ -0,0 +1,323 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const testReplicateNamespace = "default"
+
+func newReplicateTestRepo(name, pathPrefix string) *dpv1alpha1.BackupRepo {
+	return &dpv1alpha1.BackupRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: dpv1alpha1.BackupRepoSpec{
+			AccessMethod: dpv1alpha1.AccessMethodTool,
+			PathPrefix:   pathPrefix,
+		},
+		Status: dpv1alpha1.BackupRepoStatus{
+			Phase:                dpv1alpha1.BackupRepoReady,
+			ToolConfigSecretName: "tool-config-" + name,
+		},
+	}
+}
+
+func newReplicateTestSecret(repo *dpv1alpha1.BackupRepo) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: repo.Status.ToolConfigSecretName, Namespace: testReplicateNamespace},
+	}
+}
+
+func newReplicateTestBackup(name, parentName string) *dpv1alpha1.Backup {
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testReplicateNamespace,
+			UID:       "fedcba9876543210",
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: "policy",
+			BackupMethod:     "xtrabackup",
+			ParentBackupName: parentName,
+		},
+		Status: dpv1alpha1.BackupStatus{
+			Phase:          dpv1alpha1.BackupPhaseCompleted,
+			BackupRepoName: "primary",
+			Path:           "/default/policy/" + name,
+			KopiaRepoPath:  "/default/policy/kopia",
+			TotalSize:      "1Gi",
+		},
+	}
+}
+
+func newTestReplicator(t *testing.T, backup *dpv1alpha1.Backup, objs ...client.Object) (*Replicator, client.Client) {
+	cli, scheme := newTestClient(t, append(objs, backup)...)
+	return &Replicator{
+		RequestCtx: intctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()},
+		Client:     cli,
+		Scheme:     scheme,
+		Backup:     backup,
+		Policy: &dpv1alpha1.BackupReplicationPolicy{
+			BackupRepoNames: []string{"primary", "dr"},
+		},
+		WorkerServiceAccount: "kubeblocks-dataprotection-worker",
+	}, cli
+}
+
+func TestReplicateBackup(t *testing.T) {
+	primary := newReplicateTestRepo("primary", "")
+	dr := newReplicateTestRepo("dr", "/dr-site/")
+	dr.Spec.CatalogSync = &dpv1alpha1.BackupRepoCatalogSync{WriteManifests: true}
+	backup := newReplicateTestBackup("backup-20240101", "")
+	backup.Status.TotalSize = "10Gi"
+	replicator, cli := newTestReplicator(t, backup, primary, dr,
+		newReplicateTestSecret(primary), newReplicateTestSecret(dr))
+	ctx := context.Background()
+
+	// the replica in the primary backup repo is ignored.
+	assert.True(t, NeedsReplication(backup, replicator.Policy))
+	waitRepoName, err := replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Empty(t, waitRepoName)
+	assert.Len(t, backup.Status.Replicas, 1)
+	replica := backup.GetReplica("dr")
+	assert.Equal(t, dpv1alpha1.BackupReplicaRunning, replica.Phase)
+	assert.Equal(t, "/dr-site/default/policy/backup-20240101", replica.Path)
+	assert.Equal(t, "/dr-site/default/policy/kopia", replica.KopiaRepoPath)
+	assert.NotNil(t, replica.StartTimestamp)
+	assert.True(t, IsReplicationInProgress(backup))
+
+	// the job pulls the files from the primary repo and pushes them to the dr repo.
+	job := &batchv1.Job{}
+	jobKey := BuildReplicateJobKey(backup, "dr")
+	assert.NoError(t, cli.Get(ctx, jobKey, job))
+	assert.Equal(t, backup.Name, job.Labels[types.ReplicateBackupLabelKey])
+	podSpec := job.Spec.Template.Spec
+	var initContainerNames []string
+	for _, c := range podSpec.InitContainers {
+		initContainerNames = append(initContainerNames, c.Name)
+	}
+	assert.Equal(t, []string{"dp-copy-datasafed", "pull", "dp-target-dp-copy-datasafed"}, initContainerNames)
+	assert.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "push", podSpec.Containers[0].Name)
+	volumes := map[string]corev1.Volume{}
+	for _, v := range podSpec.Volumes {
+		assert.NotContains(t, volumes, v.Name)
+		volumes[v.Name] = v
+	}
+	assert.Equal(t, "tool-config-primary", volumes["dp-datasafed-config"].Secret.SecretName)
+	assert.Equal(t, "tool-config-dr", volumes["dp-target-dp-datasafed-config"].Secret.SecretName)
+	stagingSize := resource.MustParse("11Gi")
+	assert.Zero(t, stagingSize.Cmp(*volumes[replicationStagingVolumeName].EmptyDir.SizeLimit))
+	assert.Zero(t, stagingSize.Cmp(podSpec.InitContainers[1].Resources.Requests[corev1.ResourceEphemeralStorage]))
+	for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+		for _, m := range c.VolumeMounts {
+			assert.Contains(t, volumes, m.Name)
+		}
+	}
+	getEnv := func(c corev1.Container, name string) string {
+		for _, e := range c.Env {
+			if e.Name == name {
+				return e.Value
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, "/default/policy/kopia", getEnv(podSpec.InitContainers[1], types.DPDatasafedKopiaRepoRoot))
+	assert.Equal(t, "/dr-site/default/policy/kopia", getEnv(podSpec.Containers[0], types.DPDatasafedKopiaRepoRoot))
+	assert.Contains(t, podSpec.InitContainers[1].Args[0], `sourcePath="/default/policy/backup-20240101"`)
+	assert.Contains(t, podSpec.Containers[0].Args[0], `targetPath="/dr-site/default/policy/backup-20240101"`)
+	assert.NotEmpty(t, getEnv(podSpec.Containers[0], backupManifestEnv))
+
+	// the replica is completed when the job is completed.
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	_, err = replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.BackupReplicaCompleted, replica.Phase)
+	assert.NotNil(t, replica.CompletionTimestamp)
+	assert.False(t, NeedsReplication(backup, replicator.Policy))
+	exists, err := intctrlutil.CheckResourceExists(ctx, cli, jobKey, &batchv1.Job{})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestReplicateBackupWaiting(t *testing.T) {
+	t.Run("backup repo is not prepared in the namespace", func(t *testing.T) {
+		primary := newReplicateTestRepo("primary", "")
+		dr := newReplicateTestRepo("dr", "")
+		backup := newReplicateTestBackup("backup-20240101", "")
+		replicator, _ := newTestReplicator(t, backup, primary, dr, newReplicateTestSecret(primary))
+		waitRepoName, err := replicator.Replicate()
+		assert.NoError(t, err)
+		assert.Equal(t, "dr", waitRepoName)
+		assert.Equal(t, dpv1alpha1.BackupReplicaPending, backup.GetReplica("dr").Phase)
+	})
+
+	t.Run("backup repo is not ready", func(t *testing.T) {
+		primary := newReplicateTestRepo("primary", "")
+		dr := newReplicateTestRepo("dr", "")
+		dr.Status.Phase = dpv1alpha1.BackupRepoFailed
+		backup := newReplicateTestBackup("backup-20240101", "")
+		replicator, _ := newTestReplicator(t, backup, primary, dr,
+			newReplicateTestSecret(primary), newReplicateTestSecret(dr))
+		waitRepoName, err := replicator.Replicate()
+		assert.NoError(t, err)
+		assert.Empty(t, waitRepoName)
+		assert.Equal(t, dpv1alpha1.BackupReplicaPending, backup.GetReplica("dr").Phase)
+	})
+
+	t.Run("backup repo not found", func(t *testing.T) {
+		primary := newReplicateTestRepo("primary", "")
+		backup := newReplicateTestBackup("backup-20240101", "")
+		replicator, _ := newTestReplicator(t, backup, primary, newReplicateTestSecret(primary))
+		_, err := replicator.Replicate()
+		assert.NoError(t, err)
+		replica := backup.GetReplica("dr")
+		assert.Equal(t, dpv1alpha1.BackupReplicaFailed, replica.Phase)
+		assert.Contains(t, replica.FailureReason, "backup repo dr not found")
+		assert.False(t, IsReplicationInProgress(backup))
+	})
+}
+
+func TestReplicateIncrementalBackup(t *testing.T) {
+	primary := newReplicateTestRepo("primary", "")
+	dr := newReplicateTestRepo("dr", "")
+	parent := newReplicateTestBackup("backup-full", "")
+	parent.Spec.BackupMethod = "xtrabackup-full"
+	backup := newReplicateTestBackup("backup-inc", "backup-full")
+	replicator, cli := newTestReplicator(t, backup, primary, dr, parent,
+		newReplicateTestSecret(primary), newReplicateTestSecret(dr))
+	ctx := context.Background()
+	// only the incremental backups are replicated by the policy.
+	replicator.Policy.BackupMethods = []string{"xtrabackup"}
+	assert.False(t, NeedsReplication(parent, replicator.Policy))
+
+	// the replica of the parent is requested.
+	_, err := replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.BackupReplicaPending, backup.GetReplica("dr").Phase)
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(parent), parent))
+	assert.Equal(t, dpv1alpha1.BackupReplicaPending, parent.GetReplica("dr").Phase)
+	assert.True(t, NeedsReplication(parent, replicator.Policy))
+
+	// wait for the replica of the parent to be completed.
+	parent.Status.Replicas[0].Phase = dpv1alpha1.BackupReplicaRunning
+	assert.NoError(t, cli.Status().Update(ctx, parent))
+	_, err = replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.BackupReplicaPending, backup.GetReplica("dr").Phase)
+
+	parent.Status.Replicas[0].Phase = dpv1alpha1.BackupReplicaCompleted
+	assert.NoError(t, cli.Status().Update(ctx, parent))
+	_, err = replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.BackupReplicaRunning, backup.GetReplica("dr").Phase)
+
+	// the replica fails if the replica of the parent failed.
+	backup.Status.Replicas = nil
+	parent.Status.Replicas[0].Phase = dpv1alpha1.BackupReplicaFailed
+	parent.Status.Replicas[0].FailureReason = "job failed"
+	assert.NoError(t, cli.Status().Update(ctx, parent))
+	assert.NoError(t, replicator.Cleanup())
+	_, err = replicator.Replicate()
+	assert.NoError(t, err)
+	assert.Equal(t, dpv1alpha1.BackupReplicaFailed, backup.GetReplica("dr").Phase)
+	assert.Contains(t, backup.GetReplica("dr").FailureReason, "job failed")
+}
+
+func TestDeleteReplicaFiles(t *testing.T) {
+	dr := newReplicateTestRepo("dr", "dr-site")
+	backup := newReplicateTestBackup("backup-20240101", "")
+	replica := dpv1alpha1.BackupReplicaStatus{
+		BackupRepoName: "dr",
+		Phase:          dpv1alpha1.BackupReplicaCompleted,
+		Path:           "/dr-site/default/policy/backup-20240101",
+		KopiaRepoPath:  "/dr-site/default/policy/kopia",
+	}
+	backup.Status.Replicas = []dpv1alpha1.BackupReplicaStatus{replica}
+	replicator, cli := newTestReplicator(t, backup, dr)
+	deleter := &Deleter{RequestCtx: replicator.RequestCtx, Client: cli, Scheme: replicator.Scheme}
+
+	status, err := deleter.DeleteReplicaFiles(backup, &replica)
+	assert.NoError(t, err)
+	assert.Equal(t, DeletionStatusDeleting, status)
+	job := &batchv1.Job{}
+	assert.NoError(t, cli.Get(context.Background(), BuildDeleteReplicaFilesJobKey(backup, "dr"), job))
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Args[0], `targetPath="/dr-site/default/policy/backup-20240101"`)
+	assert.Equal(t, "tool-config-dr", job.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(context.Background(), job))
+	status, err = deleter.DeleteReplicaFiles(backup, &replica)
+	assert.NoError(t, err)
+	assert.Equal(t, DeletionStatusSucceeded, status)
+
+	// the replica in a deleted backup repo is ignored.
+	replica.BackupRepoName = "deleted"
+	status, err = deleter.DeleteReplicaFiles(backup, &replica)
+	assert.NoError(t, err)
+	assert.Equal(t, DeletionStatusSucceeded, status)
+}
+
+func TestReplicationStagingSize(t *testing.T) {
+	viper.Set(types.CfgKeyReplicationStagingSize, "20Gi")
+	defer viper.Set(types.CfgKeyReplicationStagingSize, "")
+	backup := newReplicateTestBackup("backup-20240101", "")
+	for totalSize, expected := range map[string]string{
+		"":         "20Gi",
+		"0":        "20Gi",
+		"1000":     "1100",
+		"1Gi":      "1181116006",
+		"not-size": "20Gi",
+	} {
+		backup.Status.TotalSize = totalSize
+		size, err := replicationStagingSize(backup)
+		assert.NoError(t, err)
+		expectedSize := resource.MustParse(expected)
+		assert.Zero(t, expectedSize.Cmp(size), totalSize)
+	}
+
+	viper.Set(types.CfgKeyReplicationStagingSize, "")
+	backup.Status.TotalSize = ""
+	_, err := replicationStagingSize(backup)
+	assert.Error(t, err)
+}
+
+func TestRebaseRepoPath(t *testing.T) {
+	assert.Equal(t, "/dr/ns/backup", rebaseRepoPath("/ns/backup", "", "dr"))
+	assert.Equal(t, "/ns/backup", rebaseRepoPath("/primary/ns/backup", "/primary/", ""))
+	assert.Equal(t, "/dr/site/ns/prefix/backup", rebaseRepoPath("/primary/ns/prefix/backup", "primary", "/dr/site/"))
+}
@@ -0,0 +1,397 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+// RetentionEvaluator evaluates a tiered retention policy on the scheduled backups
+// of a backup method.
+type RetentionEvaluator struct {
+	Policy *dpv1alpha1.BackupRetentionPolicy
+
+	// Backups are the completed scheduled backups governed by the policy.
+	Backups []*dpv1alpha1.Backup
+
+	// Dependents are the other backups of the same backup policy. The retained ones
+	// keep the governed backups they depend on.
+	Dependents []*dpv1alpha1.Backup
+
+	Now time.Time
+}
+
+// RetentionEvaluations caches the evaluations of the retention policies, so that a retention policy is
+// evaluated once in a GC cycle instead of once for every backup it governs.
+type RetentionEvaluations struct {
+	// TTL is how long an evaluation is reused, it should be shorter than the GC cycle.
+	TTL time.Duration
+
+	mu          sync.Mutex
+	evaluations map[string]*retentionEvaluation
+}
+
+type retentionEvaluation struct {
+	statuses    map[string]*dpv1alpha1.BackupRetentionStatus
+	err         error
+	evaluatedAt time.Time
+}
+
+// Get returns the retention status of the backups evaluated by the key, the evaluate function is
+// called only if there is no evaluation of the key within the TTL. The evaluation failed by a
+// non-fatal error is not reused.
+func (c *RetentionEvaluations) Get(key string, now time.Time,
+	evaluate func() (map[string]*dpv1alpha1.BackupRetentionStatus, error)) (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.evaluations == nil {
+		c.evaluations = map[string]*retentionEvaluation{}
+	}
+	for k, e := range c.evaluations {
+		if now.Sub(e.evaluatedAt) >= c.TTL {
+			delete(c.evaluations, k)
+		}
+	}
+	if e, ok := c.evaluations[key]; ok {
+		return e.statuses, e.err
+	}
+	statuses, err := evaluate()
+	if err == nil || intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+		c.evaluations[key] = &retentionEvaluation{statuses: statuses, err: err, evaluatedAt: now}
+	}
+	return statuses, err
+}
+
+// Invalidate drops the evaluation of the key, so that it is evaluated again at the next Get.
+func (c *RetentionEvaluations) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.evaluations, key)
+}
+
+type retentionTier struct {
+	name   dpv1alpha1.BackupRetentionTier
+	tier   *dpv1alpha1.RetentionTier
+	period func(t time.Time) string
+}
+
+type retentionResult struct {
+	tiers      []dpv1alpha1.BackupRetentionTier
+	requiredBy []string
+}
+
+func (r *retentionResult) retained() bool {
+	return len(r.tiers) > 0 || len(r.requiredBy) > 0
+}
+
+func (r *retentionResult) addRequiredBy(name string) bool {
+	for _, n := range r.requiredBy {
+		if n == name {
+			return false
+		}
+	}
+	r.requiredBy = append(r.requiredBy, name)
+	return true
+}
+
+// Evaluate evaluates the retention policy and returns the retention status of each
+// governed backup, keyed by the backup name.
+func (e *RetentionEvaluator) Evaluate() (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+	loc := time.UTC
+	if e.Policy.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(e.Policy.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %s of the retention policy: %s", e.Policy.TimeZone, err.Error())
+		}
+	}
+
+	backups := make([]*dpv1alpha1.Backup, len(e.Backups))
+	copy(backups, e.Backups)
+	// sort the backups from the latest to the earliest
+	sort.SliceStable(backups, func(i, j int) bool {
+		return getRetentionTime(backups[i]).After(getRetentionTime(backups[j]))
+	})
+	results := make(map[string]*retentionResult, len(backups))
+	for _, b := range backups {
+		results[b.Name] = &retentionResult{}
+	}
+
+	// keep the latest backups
+	if e.Policy.KeepLatest != nil {
+		for i := 0; i < len(backups) && i < int(*e.Policy.KeepLatest); i++ {
+			r := results[backups[i].Name]
+			r.tiers = append(r.tiers, dpv1alpha1.RetentionTierLatest)
+		}
+	}
+
+	// keep the latest backup of each period
+	for _, t := range e.buildTiers() {
+		if t.tier == nil {
+			continue
+		}
+		var maxAge time.Duration
+		if t.tier.MaxAge != "" {
+			var err error
+			if maxAge, err = t.tier.MaxAge.ToDuration(); err != nil {
+				return nil, fmt.Errorf("invalid maxAge of the %s retention tier: %s", t.name, err.Error())
+			}
+		}
+		periods := 0
+		lastPeriod := ""
+		for _, b := range backups {
+			ts := getRetentionTime(b)
+			if maxAge > 0 && e.Now.Sub(ts) > maxAge {
+				break
+			}
+			period := t.period(ts.In(loc))
+			if period == lastPeriod {
+				continue
+			}
+			lastPeriod = period
+			periods++
+			if t.tier.Count != nil && periods > int(*t.tier.Count) {
+				break
+			}
+			r := results[b.Name]
+			r.tiers = append(r.tiers, t.name)
+		}
+	}
+
+	e.retainDependencies(results)
+
+	statuses := make(map[string]*dpv1alpha1.BackupRetentionStatus, len(results))
+	for _, b := range backups {
+		r := results[b.Name]
+		status := &dpv1alpha1.BackupRetentionStatus{
+			Tiers:      r.tiers,
+			RequiredBy: r.requiredBy,
+		}
+		switch {
+		case len(r.tiers) > 0:
+			status.Decision = dpv1alpha1.BackupRetained
+			status.Message = fmt.Sprintf("kept by the retention tiers: %s", joinTiers(r.tiers))
+		case len(r.requiredBy) > 0:
+			status.Decision = dpv1alpha1.BackupRetained
+			status.Message = fmt.Sprintf("expired by the retention tiers, but required by the retained backups: %s",
+				strings.Join(r.requiredBy, ", "))
+		default:
+			status.Decision = dpv1alpha1.BackupExpired
+			status.Message = "not kept by any retention tier"
+		}
+		statuses[b.Name] = status
+	}
+	return statuses, nil
+}
+
+func (e *RetentionEvaluator) buildTiers() []retentionTier {
+	return []retentionTier{
+		{
+			name: dpv1alpha1.RetentionTierHourly,
+			tier: e.Policy.Hourly,
+			period: func(t time.Time) string {
+				return t.Format("2006-01-02T15")
+			},
+		},
+		{
+			name: dpv1alpha1.RetentionTierDaily,
+			tier: e.Policy.Daily,
+			period: func(t time.Time) string {
+				return t.Format("2006-01-02")
+			},
+		},
+		{
+			name: dpv1alpha1.RetentionTierWeekly,
+			tier: e.Policy.Weekly,
+			period: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", year, week)
+			},
+		},
+		{
+			name: dpv1alpha1.RetentionTierMonthly,
+			tier: e.Policy.Monthly,
+			period: func(t time.Time) string {
+				return t.Format("2006-01")
+			},
+		},
+		{
+			name: dpv1alpha1.RetentionTierYearly,
+			tier: e.Policy.Yearly,
+			period: func(t time.Time) string {
+				return t.Format("2006")
+			},
+		},
+	}
+}
+
+// retainDependencies keeps the governed backups that the retained backups depend on,
+// that is, the parent backups of the retained incremental backups, and the base backups
+// of the retained continuous backups.
+func (e *RetentionEvaluator) retainDependencies(results map[string]*retentionResult) {
+	all := make(map[string]*dpv1alpha1.Backup, len(e.Backups)+len(e.Dependents))
+	for _, b := range e.Backups {
+		all[b.Name] = b
+	}
+	for _, b := range e.Dependents {
+		all[b.Name] = b
+	}
+
+	var keepers []*dpv1alpha1.Backup
+	for _, b := range e.Backups {
+		if results[b.Name].retained() {
+			keepers = append(keepers, b)
+		}
+	}
+	for _, b := range e.Dependents {
+		if isDependentRetained(b, e.Now) {
+			keepers = append(keepers, b)
+		}
+	}
+
+	// retain the base backups of the continuous backups
+	for _, b := range keepers {
+		if b.Labels[dptypes.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous) {
+			continue
+		}
+		base := e.getBaseBackup(b)
+		if base == nil {
+			continue
+		}
+		if r, ok := results[base.Name]; ok {
+			r.addRequiredBy(b.Name)
+		}
+	}
+
+	// retain the parent backups of the incremental backups, including the ancestors
+	// of the parent backups that are retained above.
+	for _, b := range keepers {
+		visited := map[string]bool{b.Name: true}
+		for child := b; child.Spec.ParentBackupName != ""; {
+			parent, ok := all[child.Spec.ParentBackupName]
+			if !ok || visited[parent.Name] {
+				break
+			}
+			visited[parent.Name] = true
+			if r, ok := results[parent.Name]; ok && !r.addRequiredBy(child.Name) {
+				// the ancestors have been retained already
+				break
+			}
+			child = parent
+		}
+	}
+	for _, r := range results {
+		sort.Strings(r.requiredBy)
+	}
+}
+
+// getBaseBackup gets the earliest full backup completed after the continuous backup
+// started, which is used as the base backup for point-in-time recovery.
+func (e *RetentionEvaluator) getBaseBackup(continuous *dpv1alpha1.Backup) *dpv1alpha1.Backup {
+	start := continuous.GetStartTime()
+	if start == nil {
+		return nil
+	}
+	var base *dpv1alpha1.Backup
+	check := func(b *dpv1alpha1.Backup) {
+		if b.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+			return
+		}
+		backupType := b.Labels[dptypes.BackupTypeLabelKey]
+		if backupType != "" && backupType != string(dpv1alpha1.BackupTypeFull) {
+			return
+		}
+		end := getRetentionTime(b)
+		if end.Before(start.Time) {
+			return
+		}
+		if base == nil || end.Before(getRetentionTime(base)) {
+			base = b
+		}
+	}
+	for _, b := range e.Backups {
+		check(b)
+	}
+	for _, b := range e.Dependents {
+		if isDependentRetained(b, e.Now) {
+			check(b)
+		}
+	}
+	return base
+}
+
+// isDependentRetained checks if a backup that is not governed by the retention policy
+// is retained.
+func isDependentRetained(backup *dpv1alpha1.Backup, now time.Time) bool {
+	if !backup.DeletionTimestamp.IsZero() {
+		return false
+	}
+	switch backup.Status.Phase {
+	case dpv1alpha1.BackupPhaseFailed, dpv1alpha1.BackupPhaseDeleting:
+		return false
+	}
+	if backup.Status.Retention != nil {
+		return backup.Status.Retention.Decision != dpv1alpha1.BackupExpired
+	}
+	return backup.Status.Expiration == nil || backup.Status.Expiration.After(now)
+}
+
+// getRetentionTime gets the time used to determine the retention period of the backup.
+func getRetentionTime(backup *dpv1alpha1.Backup) time.Time {
+	if t := backup.GetEndTime(); t != nil {
+		return t.Time
+	}
+	return backup.CreationTimestamp.Time
+}
+
+func joinTiers(tiers []dpv1alpha1.BackupRetentionTier) string {
+	names := make([]string, len(tiers))
+	for i := range tiers {
+		names[i] = string(tiers[i])
+	}
+	return strings.Join(names, ", ")
+}
+
+// UpdateRetentionStatus updates the retention status of the backup, and returns true
+// if the status is changed. The transition time is only updated when the decision changes.
+func UpdateRetentionStatus(backup *dpv1alpha1.Backup, status *dpv1alpha1.BackupRetentionStatus, now time.Time) bool {
+	old := backup.Status.Retention
+	if old != nil && old.Decision == status.Decision {
+		status.LastTransitionTime = old.LastTransitionTime
+	} else {
+		status.LastTransitionTime = &metav1.Time{Time: now}
+	}
+	if old != nil && old.Decision == status.Decision && old.Message == status.Message &&
+		slices.Equal(old.Tiers, status.Tiers) && slices.Equal(old.RequiredBy, status.RequiredBy) {
+		return false
+	}
+	backup.Status.Retention = status
+	return true
+}
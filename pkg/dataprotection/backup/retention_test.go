@@ -0,0 +1,248 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func newRetentionTestBackup(name string, completed time.Time, backupType dpv1alpha1.BackupType) *dpv1alpha1.Backup {
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				types.AutoBackupLabelKey: "true",
+				types.BackupTypeLabelKey: string(backupType),
+			},
+		},
+		Status: dpv1alpha1.BackupStatus{
+			Phase:               dpv1alpha1.BackupPhaseCompleted,
+			CompletionTimestamp: &metav1.Time{Time: completed},
+		},
+	}
+}
+
+func getRetentionDecisions(statuses map[string]*dpv1alpha1.BackupRetentionStatus) map[string]dpv1alpha1.BackupRetentionDecision {
+	decisions := map[string]dpv1alpha1.BackupRetentionDecision{}
+	for name, s := range statuses {
+		decisions[name] = s.Decision
+	}
+	return decisions
+}
+
+func TestEvaluateRetentionTiers(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	var backups []*dpv1alpha1.Backup
+	names := map[string]*dpv1alpha1.Backup{}
+	// two backups a day in the last 60 days, at 01:00 and 13:00 (the latter only in the past)
+	for d := 0; d < 60; d++ {
+		day := now.AddDate(0, 0, -d)
+		for _, h := range []int{1, 13} {
+			ts := time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, time.UTC)
+			if ts.After(now) {
+				continue
+			}
+			b := newRetentionTestBackup(ts.Format("0102-15"), ts, dpv1alpha1.BackupTypeFull)
+			backups = append(backups, b)
+			names[b.Name] = b
+		}
+	}
+
+	e := &RetentionEvaluator{
+		Policy: &dpv1alpha1.BackupRetentionPolicy{
+			KeepLatest: pointer.Int32(1),
+			Daily:      &dpv1alpha1.RetentionTier{Count: pointer.Int32(3)},
+			Weekly:     &dpv1alpha1.RetentionTier{MaxAge: "18d"},
+			Monthly:    &dpv1alpha1.RetentionTier{Count: pointer.Int32(2), MaxAge: "1y"},
+		},
+		Backups: backups,
+		Now:     now,
+	}
+	statuses, err := e.Evaluate()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, len(backups))
+
+	var retained []string
+	for name, s := range statuses {
+		if s.Decision == dpv1alpha1.BackupRetained {
+			retained = append(retained, name)
+		}
+	}
+	// latest: 0315-01; daily: 0315-01, 0314-13, 0313-13;
+	// weekly (2024-03-11 is a Monday): 0315-01, 0310-13, 0303-13, 0225-13 is older than 18 days;
+	// monthly: 0315-01, 0229-13
+	assert.ElementsMatch(t, []string{"0315-01", "0314-13", "0313-13", "0310-13", "0303-13", "0229-13"}, retained)
+	assert.Equal(t, []dpv1alpha1.BackupRetentionTier{dpv1alpha1.RetentionTierLatest, dpv1alpha1.RetentionTierDaily,
+		dpv1alpha1.RetentionTierWeekly, dpv1alpha1.RetentionTierMonthly}, statuses["0315-01"].Tiers)
+	assert.Equal(t, dpv1alpha1.BackupExpired, statuses["0312-13"].Decision)
+	assert.Equal(t, "not kept by any retention tier", statuses["0312-13"].Message)
+
+	// the days are determined in the time zone of the policy
+	e.Policy = &dpv1alpha1.BackupRetentionPolicy{
+		Daily:    &dpv1alpha1.RetentionTier{Count: pointer.Int32(2)},
+		TimeZone: "America/New_York",
+	}
+	statuses, err = e.Evaluate()
+	assert.NoError(t, err)
+	// 03-15 01:00 UTC and 03-14 13:00 UTC are both on 03-14 in UTC-4,
+	// and 03-14 01:00 UTC is the latest backup on 03-13.
+	assert.Equal(t, dpv1alpha1.BackupRetained, statuses["0315-01"].Decision)
+	assert.Equal(t, dpv1alpha1.BackupExpired, statuses["0314-13"].Decision)
+	assert.Equal(t, dpv1alpha1.BackupRetained, statuses["0314-01"].Decision)
+
+	e.Policy.TimeZone = "invalid/zone"
+	_, err = e.Evaluate()
+	assert.Error(t, err)
+}
+
+func TestEvaluateRetentionChain(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	full1 := newRetentionTestBackup("full-1", now.AddDate(0, 0, -3), dpv1alpha1.BackupTypeFull)
+	full2 := newRetentionTestBackup("full-2", now.AddDate(0, 0, -2), dpv1alpha1.BackupTypeFull)
+	full3 := newRetentionTestBackup("full-3", now.AddDate(0, 0, -1), dpv1alpha1.BackupTypeFull)
+
+	// incremental backups of another backup method, inc-2 is based on inc-1 based on full-1
+	inc1 := newRetentionTestBackup("inc-1", now.AddDate(0, 0, -3).Add(time.Hour), dpv1alpha1.BackupTypeIncremental)
+	inc1.Spec.ParentBackupName = full1.Name
+	inc1.Status.Retention = &dpv1alpha1.BackupRetentionStatus{Decision: dpv1alpha1.BackupExpired}
+	inc2 := newRetentionTestBackup("inc-2", now.AddDate(0, 0, -3).Add(2*time.Hour), dpv1alpha1.BackupTypeIncremental)
+	inc2.Spec.ParentBackupName = inc1.Name
+	inc2.Status.Retention = &dpv1alpha1.BackupRetentionStatus{Decision: dpv1alpha1.BackupRetained}
+
+	// a continuous backup started after full-1, whose base backup is full-2
+	continuous := newRetentionTestBackup("continuous", now, dpv1alpha1.BackupTypeContinuous)
+	continuous.Status.Phase = dpv1alpha1.BackupPhaseRunning
+	continuous.Status.TimeRange = &dpv1alpha1.BackupTimeRange{
+		Start: &metav1.Time{Time: now.AddDate(0, 0, -2).Add(-time.Hour)},
+	}
+
+	e := &RetentionEvaluator{
+		Policy:     &dpv1alpha1.BackupRetentionPolicy{KeepLatest: pointer.Int32(1)},
+		Backups:    []*dpv1alpha1.Backup{full1, full2, full3},
+		Dependents: []*dpv1alpha1.Backup{inc1, inc2, continuous},
+		Now:        now,
+	}
+	statuses, err := e.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]dpv1alpha1.BackupRetentionDecision{
+		"full-1": dpv1alpha1.BackupRetained,
+		"full-2": dpv1alpha1.BackupRetained,
+		"full-3": dpv1alpha1.BackupRetained,
+	}, getRetentionDecisions(statuses))
+	assert.Equal(t, []string{"inc-1"}, statuses["full-1"].RequiredBy)
+	assert.Empty(t, statuses["full-1"].Tiers)
+	assert.Equal(t, []string{"continuous"}, statuses["full-2"].RequiredBy)
+	assert.Equal(t, "expired by the retention tiers, but required by the retained backups: continuous",
+		statuses["full-2"].Message)
+	assert.Equal(t, []dpv1alpha1.BackupRetentionTier{dpv1alpha1.RetentionTierLatest}, statuses["full-3"].Tiers)
+
+	// the dependents are no longer retained
+	inc2.Status.Retention.Decision = dpv1alpha1.BackupExpired
+	continuous.Status.Phase = dpv1alpha1.BackupPhaseFailed
+	statuses, err = e.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]dpv1alpha1.BackupRetentionDecision{
+		"full-1": dpv1alpha1.BackupExpired,
+		"full-2": dpv1alpha1.BackupExpired,
+		"full-3": dpv1alpha1.BackupRetained,
+	}, getRetentionDecisions(statuses))
+}
+
+func TestUpdateRetentionStatus(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	backup := &dpv1alpha1.Backup{}
+	retained := func() *dpv1alpha1.BackupRetentionStatus {
+		return &dpv1alpha1.BackupRetentionStatus{
+			Decision: dpv1alpha1.BackupRetained,
+			Tiers:    []dpv1alpha1.BackupRetentionTier{dpv1alpha1.RetentionTierDaily},
+			Message:  "kept by the retention tiers: Daily",
+		}
+	}
+	assert.True(t, UpdateRetentionStatus(backup, retained(), now))
+	assert.Equal(t, now, backup.Status.Retention.LastTransitionTime.Time)
+
+	// the same decision does not change the status
+	assert.False(t, UpdateRetentionStatus(backup, retained(), now.Add(time.Hour)))
+
+	// the transition time is kept if only the tiers are changed
+	status := retained()
+	status.Tiers = append(status.Tiers, dpv1alpha1.RetentionTierWeekly)
+	assert.True(t, UpdateRetentionStatus(backup, status, now.Add(time.Hour)))
+	assert.Equal(t, now, backup.Status.Retention.LastTransitionTime.Time)
+
+	assert.True(t, UpdateRetentionStatus(backup, &dpv1alpha1.BackupRetentionStatus{
+		Decision: dpv1alpha1.BackupExpired,
+	}, now.Add(2*time.Hour)))
+	assert.Equal(t, now.Add(2*time.Hour), backup.Status.Retention.LastTransitionTime.Time)
+}
+
+func TestRetentionEvaluations(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	evaluations := &RetentionEvaluations{TTL: 30 * time.Minute}
+	count := 0
+	evaluate := func(err error) func() (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+		return func() (map[string]*dpv1alpha1.BackupRetentionStatus, error) {
+			count++
+			if err != nil {
+				return nil, err
+			}
+			return map[string]*dpv1alpha1.BackupRetentionStatus{"full-1": {Decision: dpv1alpha1.BackupRetained}}, nil
+		}
+	}
+
+	// the policy is evaluated once for all the backups in the TTL
+	for i := 0; i < 3; i++ {
+		statuses, err := evaluations.Get("default/policy/xtrabackup", now.Add(time.Duration(i)*time.Minute), evaluate(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, dpv1alpha1.BackupRetained, statuses["full-1"].Decision)
+	}
+	assert.Equal(t, 1, count)
+	_, _ = evaluations.Get("default/policy/mysqldump", now, evaluate(nil))
+	assert.Equal(t, 2, count)
+
+	// the policy is evaluated again in the next GC cycle
+	_, _ = evaluations.Get("default/policy/xtrabackup", now.Add(time.Hour), evaluate(nil))
+	assert.Equal(t, 3, count)
+	assert.Len(t, evaluations.evaluations, 1)
+
+	// the evaluation failed by a transient error is not reused, but the fatal one is
+	_, err := evaluations.Get("default/other/xtrabackup", now, evaluate(errors.New("list failed")))
+	assert.Error(t, err)
+	_, _ = evaluations.Get("default/other/xtrabackup", now, evaluate(intctrlutil.NewFatalError("invalid time zone")))
+	_, err = evaluations.Get("default/other/xtrabackup", now, evaluate(nil))
+	assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	assert.Equal(t, 5, count)
+
+	// the invalidated evaluation is evaluated again within the TTL
+	evaluations.Invalidate("default/other/xtrabackup")
+	_, err = evaluations.Get("default/other/xtrabackup", now, evaluate(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, count)
+}
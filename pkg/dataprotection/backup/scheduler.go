@@ -24,7 +24,9 @@ import (
 	"reflect"
 	"slices"
 	"sort"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,19 +54,47 @@ type Scheduler struct {
 	BackupSchedule       *dpv1alpha1.BackupSchedule
 	BackupPolicy         *dpv1alpha1.BackupPolicy
 	WorkerServiceAccount string
+	// Limiter limits the concurrency of the backups created by the built-in scheduler.
+	Limiter *BackupConcurrencyLimiter
 }
 
-func (s *Scheduler) Schedule() error {
+// Schedule reconciles the workloads of the schedule policies, and returns the duration until
+// the next backup if the backups are scheduled by the built-in scheduler.
+func (s *Scheduler) Schedule() (time.Duration, error) {
 	if err := s.validate(); err != nil {
-		return err
+		return 0, err
 	}
 
+	builtIn := IsBuiltInSchedulerEnabled()
+	original := s.BackupSchedule.DeepCopy()
+	var requeueAfter time.Duration
 	for i := range s.BackupSchedule.Spec.Schedules {
-		if err := s.handleSchedulePolicy(i); err != nil {
-			return err
+		next, err := s.handleSchedulePolicy(i, builtIn)
+		if err != nil {
+			return 0, err
+		}
+		if next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
 		}
 	}
-	return nil
+
+	if builtIn {
+		if err := s.syncBuiltInScheduleStatus(); err != nil {
+			return 0, err
+		}
+	} else {
+		// the next scheduled times are only reported by the built-in scheduler.
+		for method, status := range s.BackupSchedule.Status.Schedules {
+			status.NextScheduleTime = nil
+			s.BackupSchedule.Status.Schedules[method] = status
+		}
+	}
+	if !reflect.DeepEqual(original.Status, s.BackupSchedule.Status) {
+		if err := s.Client.Status().Patch(s.Ctx, s.BackupSchedule, client.MergeFrom(original)); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
 }
 
 // validate validates the backup schedule.
@@ -86,21 +116,96 @@ func (s *Scheduler) validate() error {
 		return fmt.Errorf("backup method %s is not in backup policy %s/%s",
 			sp.BackupMethod, s.BackupPolicy.Namespace, s.BackupPolicy.Name)
 	}
+	if v := s.BackupSchedule.Spec.Verification; v != nil && !methodInBackupPolicy(v.BackupMethod) {
+		return fmt.Errorf("backup method %s of the verification is not in backup policy %s/%s",
+			v.BackupMethod, s.BackupPolicy.Namespace, s.BackupPolicy.Name)
+	}
 	return nil
 }
 
-func (s *Scheduler) handleSchedulePolicy(index int) error {
+// ScheduleVerification starts the verification of the latest completed backup when the
+// verification is due, and returns the duration until the next verification.
+func (s *Scheduler) ScheduleVerification() (time.Duration, error) {
+	policy := s.BackupSchedule.Spec.Verification
+	if policy == nil || boolptr.IsSetToFalse(policy.Enabled) {
+		return 0, nil
+	}
+	schedule, err := cron.ParseStandard(policy.CronExpression)
+	if err != nil {
+		return 0, intctrlutil.NewFatalError(fmt.Sprintf("invalid cron expression %s of the verification: %s",
+			policy.CronExpression, err.Error()))
+	}
+	now := time.Now()
+	last := s.BackupSchedule.CreationTimestamp.Time
+	if status := s.BackupSchedule.Status.Verification; status != nil && status.LastScheduleTime != nil {
+		last = status.LastScheduleTime.Time
+	}
+	if next := schedule.Next(last); now.Before(next) {
+		return next.Sub(now), nil
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err = s.Client.List(s.Ctx, backupList, client.InNamespace(s.BackupSchedule.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: s.BackupPolicy.Name}); err != nil {
+		return 0, err
+	}
+	backup := selectBackupToVerify(backupList.Items, policy.BackupMethod)
+	if backup != nil {
+		patch := client.MergeFrom(backup.DeepCopy())
+		backup.Status.Verification = &dpv1alpha1.BackupVerificationStatus{
+			Phase:              dpv1alpha1.BackupVerificationPending,
+			BackupScheduleName: s.BackupSchedule.Name,
+		}
+		if err = s.Client.Status().Patch(s.Ctx, backup, patch); err != nil {
+			return 0, err
+		}
+	}
+	patch := client.MergeFrom(s.BackupSchedule.DeepCopy())
+	s.BackupSchedule.Status.Verification = &dpv1alpha1.VerificationScheduleStatus{
+		LastScheduleTime: &metav1.Time{Time: now},
+	}
+	if backup != nil {
+		s.BackupSchedule.Status.Verification.LastBackupName = backup.Name
+	}
+	if err = s.Client.Status().Patch(s.Ctx, s.BackupSchedule, patch); err != nil {
+		return 0, err
+	}
+	return schedule.Next(now).Sub(now), nil
+}
+
+// selectBackupToVerify selects the latest completed backup of the backup method.
+// It returns nil if the latest backup has been verified.
+func selectBackupToVerify(backups []dpv1alpha1.Backup, method string) *dpv1alpha1.Backup {
+	var latest *dpv1alpha1.Backup
+	for i := range backups {
+		backup := &backups[i]
+		if backup.Spec.BackupMethod != method ||
+			backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted ||
+			backup.Status.CompletionTimestamp == nil {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTimestamp.Before(backup.Status.CompletionTimestamp) {
+			latest = backup
+		}
+	}
+	if latest == nil || latest.Status.Verification != nil {
+		return nil
+	}
+	return latest
+}
+
+func (s *Scheduler) handleSchedulePolicy(index int, builtIn bool) (time.Duration, error) {
 	schedulePolicy := &s.BackupSchedule.Spec.Schedules[index]
 
 	for _, method := range s.BackupPolicy.Spec.BackupMethods {
 		if method.Name == schedulePolicy.BackupMethod && !boolptr.IsSetToTrue(method.SnapshotVolumes) {
 			actionSet, err := dputils.GetActionSetByName(s.RequestCtx, s.Client, method.ActionSetName)
 			if err != nil {
-				return err
+				return 0, err
 			}
 			if actionSet.Spec.BackupType == dpv1alpha1.BackupTypeContinuous {
 				if err = s.reconfigure(schedulePolicy); err != nil {
-					return err
+					return 0, err
 				}
 				var targetSelectorLabels map[string]string
 				if method.Target != nil {
@@ -108,13 +213,26 @@ func (s *Scheduler) handleSchedulePolicy(index int) error {
 				} else if s.BackupPolicy.Spec.Target != nil {
 					targetSelectorLabels = s.BackupPolicy.Spec.Target.PodSelector.MatchLabels
 				}
-				return s.reconcileForContinuous(schedulePolicy, targetSelectorLabels)
+				return 0, s.reconcileForContinuous(schedulePolicy, targetSelectorLabels)
 			}
 		}
 	}
 
+	if builtIn {
+		// the backups are created by the built-in scheduler, remove the cronjob created before.
+		cronJob, err := s.getCronJob(schedulePolicy)
+		if err != nil {
+			return 0, err
+		}
+		if cronJob != nil {
+			if err = s.deleteCronJob(cronJob); err != nil {
+				return 0, err
+			}
+		}
+		return s.scheduleBuiltIn(schedulePolicy)
+	}
 	// create/delete/patch cronjob workload
-	return s.reconcileCronJob(schedulePolicy)
+	return 0, s.reconcileCronJob(schedulePolicy)
 }
 
 // buildCronJob builds cronjob from backup schedule.
@@ -154,7 +272,7 @@ func (s *Scheduler) buildCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy, cron
 		},
 	}
 
-	timeZone, cronExpression := BuildCronJobSchedule(schedulePolicy.CronExpression)
+	timeZone, cronExpression := BuildCronJobScheduleInTimeZone(schedulePolicy.CronExpression, schedulePolicy.TimeZone)
 	if timeZone != nil {
 		cronjob.Spec.Schedule = schedulePolicy.CronExpression
 		cronjob.Spec.TimeZone = timeZone
@@ -214,10 +332,9 @@ EOF
 	return podSpec, nil
 }
 
-// reconcileCronJob will create/delete/patch cronjob according to cronExpression and policy changes.
-func (s *Scheduler) reconcileCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy) error {
+// getCronJob returns the cronjob of the schedule policy, it returns nil if not found.
+func (s *Scheduler) getCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy) (*batchv1.CronJob, error) {
 	// get cronjob from labels
-	cronJob := &batchv1.CronJob{}
 	cronJobList := &batchv1.CronJobList{}
 	if err := s.Client.List(s.Ctx, cronJobList,
 		client.InNamespace(s.BackupSchedule.Namespace),
@@ -226,19 +343,36 @@ func (s *Scheduler) reconcileCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy)
 			dptypes.BackupMethodLabelKey:   schedulePolicy.BackupMethod,
 		},
 	); err != nil {
+		return nil, err
+	}
+	if len(cronJobList.Items) == 0 {
+		return nil, nil
+	}
+	return &cronJobList.Items[0], nil
+}
+
+func (s *Scheduler) deleteCronJob(cronJob *batchv1.CronJob) error {
+	if err := dputils.RemoveDataProtectionFinalizer(s.Ctx, s.Client, cronJob); err != nil {
+		return err
+	}
+	return client.IgnoreNotFound(s.Client.Delete(s.Ctx, cronJob))
+}
+
+// reconcileCronJob will create/delete/patch cronjob according to cronExpression and policy changes.
+func (s *Scheduler) reconcileCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy) error {
+	cronJob, err := s.getCronJob(schedulePolicy)
+	if err != nil {
 		return err
-	} else if len(cronJobList.Items) > 0 {
-		cronJob = &cronJobList.Items[0]
+	}
+	if cronJob == nil {
+		cronJob = &batchv1.CronJob{}
 	}
 
 	// schedule is disabled, delete cronjob if exists
 	if !boolptr.IsSetToTrue(schedulePolicy.Enabled) {
 		if len(cronJob.Name) != 0 {
 			// delete the old cronjob.
-			if err := dputils.RemoveDataProtectionFinalizer(s.Ctx, s.Client, cronJob); err != nil {
-				return err
-			}
-			return s.Client.Delete(s.Ctx, cronJob)
+			return s.deleteCronJob(cronJob)
 		}
 		// if cronjob does not exist, return
 		return nil
@@ -274,6 +408,10 @@ func (s *Scheduler) reconcileCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy)
 }
 
 func (s *Scheduler) generateBackupName(schedulePolicy *dpv1alpha1.SchedulePolicy) string {
+	return s.generateBackupNamePrefix(schedulePolicy) + "-$(date -u +'%Y%m%d%H%M%S')"
+}
+
+func (s *Scheduler) generateBackupNamePrefix(schedulePolicy *dpv1alpha1.SchedulePolicy) string {
 	var backupNamePrefix string
 	targets := dputils.GetBackupTargets(s.BackupPolicy, dputils.GetBackupMethodByName(schedulePolicy.BackupMethod, s.BackupPolicy))
 	if len(targets) > 0 {
@@ -285,7 +423,7 @@ func (s *Scheduler) generateBackupName(schedulePolicy *dpv1alpha1.SchedulePolicy
 	if backupNamePrefix == "" {
 		backupNamePrefix = s.BackupSchedule.Name
 	}
-	return backupNamePrefix + "-$(date -u +'%Y%m%d%H%M%S')"
+	return backupNamePrefix
 }
 
 func (s *Scheduler) getGenerateContinuousBackup(schedulePolicy *dpv1alpha1.SchedulePolicy) (*dpv1alpha1.Backup, error) {
@@ -0,0 +1,500 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// defaultSkipDeadline is the starting deadline of the runs whose catch-up policy is Skip,
+	// if the startingDeadlineMinutes of the backup schedule is not set.
+	defaultSkipDeadline = 5 * time.Minute
+
+	// throttledRequeueInterval is the interval to retry a run postponed by the concurrency limits.
+	throttledRequeueInterval = 30 * time.Second
+
+	// maxScheduleLookups is the maximum number of the scheduled times enumerated one by one
+	// to find the latest missed run.
+	maxScheduleLookups = 1000
+
+	// pendingBackupTTL is how long a backup created by the built-in scheduler is counted as
+	// running before it shows up in the informer cache.
+	pendingBackupTTL = time.Minute
+
+	scheduledBackupTimeLayout = "20060102150405"
+)
+
+// IsBuiltInSchedulerEnabled returns true if the backup schedules are run by the built-in
+// scheduler instead of the CronJobs.
+func IsBuiltInSchedulerEnabled() bool {
+	return viper.GetBool(dptypes.CfgKeyBuiltInBackupScheduler)
+}
+
+// cronSchedule evaluates the cron expression of a schedule policy in its time zone, and delays
+// every run by a stable jitter.
+type cronSchedule struct {
+	schedule cron.Schedule
+	jitter   time.Duration
+	// seed makes the jitters of the different schedules with the same cron expression differ.
+	seed string
+}
+
+func newCronSchedule(schedulePolicy *dpv1alpha1.SchedulePolicy, seed string) (*cronSchedule, error) {
+	location := time.UTC
+	if schedulePolicy.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedulePolicy.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %s: %s", schedulePolicy.TimeZone, err.Error())
+		}
+	}
+	schedule, err := cron.ParseStandard(schedulePolicy.CronExpression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %s: %s", schedulePolicy.CronExpression, err.Error())
+	}
+	// the time zone in the cron expression (CRON_TZ=) takes precedence.
+	if spec, ok := schedule.(*cron.SpecSchedule); ok && spec.Location == time.Local {
+		spec.Location = location
+	}
+	s := &cronSchedule{schedule: schedule, seed: seed}
+	if schedulePolicy.Jitter != nil && schedulePolicy.Jitter.Duration > 0 {
+		s.jitter = schedulePolicy.Jitter.Duration
+	}
+	return s, nil
+}
+
+// next returns the first scheduled time after t, it returns the zero time if there is none.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	return s.schedule.Next(t)
+}
+
+// isScheduledTime returns true if t is a scheduled time of the cron expression.
+func (s *cronSchedule) isScheduledTime(t time.Time) bool {
+	return s.schedule.Next(t.Add(-time.Second)).Equal(t)
+}
+
+// startTime returns the time to start the run scheduled at t, which is t delayed by the jitter.
+func (s *cronSchedule) startTime(t time.Time) time.Time {
+	if s.jitter <= 0 {
+		return t
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s.seed))
+	_, _ = h.Write([]byte(t.UTC().Format(time.RFC3339)))
+	return t.Add(time.Duration(h.Sum64() % uint64(s.jitter)))
+}
+
+// scheduledRun is the decision of the built-in scheduler for a schedule policy.
+type scheduledRun struct {
+	// scheduledTime is the scheduled time of the run to start, it is zero if no run is started.
+	scheduledTime time.Time
+	// skippedTime is the scheduled time of the run dropped because its starting deadline is exceeded.
+	skippedTime time.Time
+	// missedRuns is the number of the runs that are not started, including the skipped one.
+	// It may be less than the actual number if too many runs are missed.
+	missedRuns int
+	// nextTime is the scheduled time of the run after the decided one.
+	nextTime time.Time
+}
+
+// plan decides the run of the scheduled times from next to now. All the missed runs are
+// collapsed into the latest one, which is started unless its starting deadline is exceeded.
+// The deadline is unlimited if it is zero. It returns false if no run is due yet.
+func (s *cronSchedule) plan(next, now time.Time, deadline time.Duration) (scheduledRun, bool) {
+	if now.Before(s.startTime(next)) {
+		return scheduledRun{nextTime: next}, false
+	}
+	run := scheduledRun{}
+	latest, t := next, next
+	for i := 0; ; i++ {
+		if i == maxScheduleLookups {
+			// too many runs are missed, look for the latest runs in the widening windows before now.
+			for _, window := range []time.Duration{time.Minute, time.Hour, 24 * time.Hour, 31 * 24 * time.Hour, 366 * 24 * time.Hour} {
+				if from := now.Add(-s.jitter - window); from.After(t) && !s.next(from).After(now) {
+					t = from
+					break
+				}
+			}
+		}
+		n := s.next(t)
+		if n.IsZero() || now.Before(s.startTime(n)) {
+			run.nextTime = n
+			break
+		}
+		latest, t = n, n
+		run.missedRuns++
+	}
+	if deadline > 0 && now.Sub(s.startTime(latest)) > deadline {
+		run.skippedTime = latest
+		run.missedRuns++
+	} else {
+		run.scheduledTime = latest
+	}
+	return run, true
+}
+
+// missedRunDeadline returns the starting deadline of the runs of the schedule policy.
+func (s *Scheduler) missedRunDeadline(schedulePolicy *dpv1alpha1.SchedulePolicy) time.Duration {
+	if minutes := s.BackupSchedule.Spec.StartingDeadlineMinutes; minutes != nil && *minutes > 0 {
+		return time.Duration(*minutes) * time.Minute
+	}
+	if schedulePolicy.CatchUpPolicy == dpv1alpha1.ScheduleCatchUpSkip {
+		return defaultSkipDeadline
+	}
+	return 0
+}
+
+// scheduleBuiltIn creates the backups of the schedule policy at the scheduled times instead of
+// a CronJob, and returns the duration until the next run. The next scheduled time is recorded
+// in the status, so the runs missed while the manager is down can be found after restarting.
+func (s *Scheduler) scheduleBuiltIn(schedulePolicy *dpv1alpha1.SchedulePolicy) (time.Duration, error) {
+	method := schedulePolicy.BackupMethod
+	status, exists := s.BackupSchedule.Status.Schedules[method]
+	if !boolptr.IsSetToTrue(schedulePolicy.Enabled) {
+		if exists {
+			status.NextScheduleTime = nil
+			s.setScheduleStatus(method, status)
+		}
+		return 0, nil
+	}
+	schedule, err := newCronSchedule(schedulePolicy, string(s.BackupSchedule.UID)+"/"+method)
+	if err != nil {
+		return 0, intctrlutil.NewFatalError(err.Error())
+	}
+	status.Phase = dpv1alpha1.ScheduleRunning
+	status.FailureReason = ""
+
+	now := time.Now()
+	// start from the first scheduled time after now, if the schedule is newly enabled or the
+	// cron expression is changed.
+	if status.NextScheduleTime == nil || !schedule.isScheduledTime(status.NextScheduleTime.Time) {
+		return s.setNextScheduleTime(method, status, schedule, schedule.next(now), now), nil
+	}
+	run, due := schedule.plan(status.NextScheduleTime.Time, now, s.missedRunDeadline(schedulePolicy))
+	if !due {
+		return s.setNextScheduleTime(method, status, schedule, run.nextTime, now), nil
+	}
+	if !run.scheduledTime.IsZero() {
+		backup, err := s.createScheduledBackup(schedulePolicy, run.scheduledTime)
+		if err != nil {
+			return 0, err
+		}
+		if backup == nil {
+			// keep the next scheduled time to retry the run.
+			s.Recorder.Eventf(s.BackupSchedule, corev1.EventTypeNormal, "BackupPostponed",
+				"the backup of method %s scheduled at %s is postponed by the concurrency limits",
+				method, run.scheduledTime.UTC().Format(time.RFC3339))
+			s.setScheduleStatus(method, status)
+			return throttledRequeueInterval, nil
+		}
+		status.LastScheduleTime = &metav1.Time{Time: run.scheduledTime}
+		status.LastBackupName = backup.Name
+	}
+	if run.missedRuns > 0 {
+		action := fmt.Sprintf("a backup is started for the run at %s", run.scheduledTime.UTC().Format(time.RFC3339))
+		if run.scheduledTime.IsZero() {
+			action = "the starting deadline is exceeded"
+		}
+		s.Recorder.Eventf(s.BackupSchedule, corev1.EventTypeWarning, "MissedSchedule",
+			"%d run(s) of the backup method %s are missed, %s", run.missedRuns, method, action)
+	}
+	return s.setNextScheduleTime(method, status, schedule, run.nextTime, now), nil
+}
+
+// setNextScheduleTime records the next scheduled time, and returns the duration until its run is started.
+func (s *Scheduler) setNextScheduleTime(method string,
+	status dpv1alpha1.ScheduleStatus,
+	schedule *cronSchedule,
+	next, now time.Time) time.Duration {
+	if next.IsZero() {
+		status.NextScheduleTime = nil
+		s.setScheduleStatus(method, status)
+		return 0
+	}
+	status.NextScheduleTime = &metav1.Time{Time: next}
+	s.setScheduleStatus(method, status)
+	if d := schedule.startTime(next).Sub(now); d > 0 {
+		return d
+	}
+	return time.Second
+}
+
+func (s *Scheduler) setScheduleStatus(method string, status dpv1alpha1.ScheduleStatus) {
+	if s.BackupSchedule.Status.Schedules == nil {
+		s.BackupSchedule.Status.Schedules = map[string]dpv1alpha1.ScheduleStatus{}
+	}
+	s.BackupSchedule.Status.Schedules[method] = status
+}
+
+// syncBuiltInScheduleStatus removes the status of the removed schedule policies, and records
+// the last time the backups of each schedule policy were completed.
+func (s *Scheduler) syncBuiltInScheduleStatus() error {
+	methods := map[string]struct{}{}
+	for _, sp := range s.BackupSchedule.Spec.Schedules {
+		methods[sp.BackupMethod] = struct{}{}
+	}
+	for method := range s.BackupSchedule.Status.Schedules {
+		if _, ok := methods[method]; !ok {
+			delete(s.BackupSchedule.Status.Schedules, method)
+		}
+	}
+	if len(s.BackupSchedule.Status.Schedules) == 0 {
+		return nil
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err := s.Client.List(s.Ctx, backupList, client.InNamespace(s.BackupSchedule.Namespace),
+		client.MatchingLabels{dptypes.BackupScheduleLabelKey: s.BackupSchedule.Name}); err != nil {
+		return err
+	}
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		status, ok := s.BackupSchedule.Status.Schedules[backup.Spec.BackupMethod]
+		if !ok || backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted || backup.Status.CompletionTimestamp == nil {
+			continue
+		}
+		if status.LastSuccessfulTime == nil || status.LastSuccessfulTime.Before(backup.Status.CompletionTimestamp) {
+			status.LastSuccessfulTime = backup.Status.CompletionTimestamp.DeepCopy()
+			s.BackupSchedule.Status.Schedules[backup.Spec.BackupMethod] = status
+		}
+	}
+	return nil
+}
+
+// createScheduledBackup creates the backup of the run scheduled at scheduledTime. The name of the
+// backup is derived from the scheduled time, so a run never creates more than one backup.
+// It returns nil if the backup is postponed by the concurrency limits.
+func (s *Scheduler) createScheduledBackup(schedulePolicy *dpv1alpha1.SchedulePolicy,
+	scheduledTime time.Time) (*dpv1alpha1.Backup, error) {
+	backup := s.buildScheduledBackup(schedulePolicy, scheduledTime, false)
+	existing := &dpv1alpha1.Backup{}
+	exists, err := intctrlutil.CheckResourceExists(s.Ctx, s.Client, client.ObjectKeyFromObject(backup), existing)
+	if err != nil {
+		return nil, err
+	}
+	if exists && existing.Spec.BackupMethod != schedulePolicy.BackupMethod {
+		// another schedule policy of the same backup schedule runs at the same time.
+		backup = s.buildScheduledBackup(schedulePolicy, scheduledTime, true)
+		if exists, err = intctrlutil.CheckResourceExists(s.Ctx, s.Client, client.ObjectKeyFromObject(backup), existing); err != nil {
+			return nil, err
+		}
+	}
+	if exists {
+		return existing, nil
+	}
+
+	repo, err := s.getBackupRepo()
+	if err != nil {
+		return nil, err
+	}
+	limiter := s.Limiter
+	if limiter == nil {
+		limiter = NewBackupConcurrencyLimiter()
+	}
+	admitted, err := limiter.Admit(s.RequestCtx, s.Client, repo, func() error {
+		return intctrlutil.IgnoreIsAlreadyExists(s.Client.Create(s.Ctx, backup))
+	}, backup)
+	if err != nil || !admitted {
+		return nil, err
+	}
+	return backup, nil
+}
+
+func (s *Scheduler) buildScheduledBackup(schedulePolicy *dpv1alpha1.SchedulePolicy,
+	scheduledTime time.Time, withMethod bool) *dpv1alpha1.Backup {
+	name := s.generateBackupNamePrefix(schedulePolicy)
+	if withMethod {
+		name += "-" + schedulePolicy.BackupMethod
+	}
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-" + scheduledTime.UTC().Format(scheduledBackupTimeLayout),
+			Namespace: s.BackupSchedule.Namespace,
+			Labels: map[string]string{
+				dptypes.AutoBackupLabelKey:     "true",
+				dptypes.BackupScheduleLabelKey: s.BackupSchedule.Name,
+				constant.AppManagedByLabelKey:  dptypes.AppName,
+			},
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: s.BackupPolicy.Name,
+			BackupMethod:     schedulePolicy.BackupMethod,
+			RetentionPeriod:  schedulePolicy.RetentionPeriod,
+		},
+	}
+}
+
+// getBackupRepo returns the backup repo used by the backups of the backup policy, it returns
+// nil if the repo can not be determined, and the backup controller will report the error.
+func (s *Scheduler) getBackupRepo() (*dpv1alpha1.BackupRepo, error) {
+	if name := s.BackupPolicy.Spec.BackupRepoName; name != nil && *name != "" {
+		repo := &dpv1alpha1.BackupRepo{}
+		if err := s.Client.Get(s.Ctx, client.ObjectKey{Name: *name}, repo); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		return repo, nil
+	}
+	repoList := &dpv1alpha1.BackupRepoList{}
+	if err := s.Client.List(s.Ctx, repoList); err != nil {
+		return nil, err
+	}
+	var defaultRepo *dpv1alpha1.BackupRepo
+	for i := range repoList.Items {
+		repo := &repoList.Items[i]
+		if repo.Annotations[dptypes.DefaultBackupRepoAnnotationKey] != "true" ||
+			repo.Status.Phase != dpv1alpha1.BackupRepoReady {
+			continue
+		}
+		if defaultRepo != nil {
+			return nil, nil
+		}
+		defaultRepo = repo
+	}
+	return defaultRepo, nil
+}
+
+// BackupConcurrencyLimiter limits the number of the running backups when the built-in scheduler
+// starts the scheduled backups. The admissions are serialized, and the backups created recently
+// are counted before they show up in the informer cache.
+type BackupConcurrencyLimiter struct {
+	mu      sync.Mutex
+	pending map[types.NamespacedName]pendingBackup
+}
+
+type pendingBackup struct {
+	repo      string
+	createdAt time.Time
+}
+
+func NewBackupConcurrencyLimiter() *BackupConcurrencyLimiter {
+	return &BackupConcurrencyLimiter{pending: map[types.NamespacedName]pendingBackup{}}
+}
+
+// Admit calls create to create the backup stored in the repo if the number of the running backups
+// is below both the global limit and the limit of the repo. It returns false if the backup is postponed.
+func (l *BackupConcurrencyLimiter) Admit(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	repo *dpv1alpha1.BackupRepo,
+	create func() error,
+	backup *dpv1alpha1.Backup) (bool, error) {
+	globalLimit := viper.GetInt(dptypes.CfgKeyMaxConcurrentBackups)
+	repoLimit := 0
+	repoName := ""
+	if repo != nil {
+		repoName = repo.Name
+		if repo.Spec.MaxConcurrentBackups != nil {
+			repoLimit = int(*repo.Spec.MaxConcurrentBackups)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if globalLimit > 0 || repoLimit > 0 {
+		running, err := l.runningBackups(reqCtx, cli)
+		if err != nil {
+			return false, err
+		}
+		repoRunning := 0
+		for _, r := range running {
+			if repoName != "" && r == repoName {
+				repoRunning++
+			}
+		}
+		if globalLimit > 0 && len(running) >= globalLimit {
+			reqCtx.Log.V(1).Info("the global concurrency limit of backups is reached",
+				"limit", globalLimit, "backup", backup.Name)
+			return false, nil
+		}
+		if repoLimit > 0 && repoRunning >= repoLimit {
+			reqCtx.Log.V(1).Info("the concurrency limit of backups of the backup repo is reached",
+				"backupRepo", repoName, "limit", repoLimit, "backup", backup.Name)
+			return false, nil
+		}
+	}
+	if err := create(); err != nil {
+		return false, err
+	}
+	l.pending[client.ObjectKeyFromObject(backup)] = pendingBackup{repo: repoName, createdAt: time.Now()}
+	return true, nil
+}
+
+// runningBackups returns the backup repos of the running backups, keyed by the backups.
+func (l *BackupConcurrencyLimiter) runningBackups(reqCtx intctrlutil.RequestCtx,
+	cli client.Client) (map[types.NamespacedName]string, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(reqCtx.Ctx, backupList); err != nil {
+		return nil, err
+	}
+	running := map[types.NamespacedName]string{}
+	listed := map[types.NamespacedName]struct{}{}
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		key := client.ObjectKeyFromObject(backup)
+		listed[key] = struct{}{}
+		if !isRunningBackup(backup) {
+			continue
+		}
+		repo := backup.Status.BackupRepoName
+		if repo == "" {
+			repo = backup.Labels[dptypes.BackupRepoLabelKey]
+		}
+		running[key] = repo
+	}
+	for key, p := range l.pending {
+		if _, ok := listed[key]; ok || time.Since(p.createdAt) > pendingBackupTTL {
+			delete(l.pending, key)
+			continue
+		}
+		running[key] = p.repo
+	}
+	return running, nil
+}
+
+// isRunningBackup returns true if the backup is started or to be started. The continuous backups
+// and the backups recreated from the catalog of a backup repo never run a backup workload.
+func isRunningBackup(backup *dpv1alpha1.Backup) bool {
+	if !backup.DeletionTimestamp.IsZero() ||
+		backup.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) ||
+		backup.Annotations[dptypes.CatalogBackupRepoAnnotationKey] != "" {
+		return false
+	}
+	switch backup.Status.Phase {
+	case "", dpv1alpha1.BackupPhaseNew, dpv1alpha1.BackupPhaseRunning:
+		return true
+	default:
+		return false
+	}
+}
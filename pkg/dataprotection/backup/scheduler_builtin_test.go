@@ -0,0 +1,311 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	ts, err := time.Parse(time.RFC3339, value)
+	assert.NoError(t, err)
+	return ts
+}
+
+func TestCronSchedule(t *testing.T) {
+	policy := &dpv1alpha1.SchedulePolicy{CronExpression: "0 2 * * *", TimeZone: "Asia/Shanghai"}
+	schedule, err := newCronSchedule(policy, "uid/xtrabackup")
+	assert.NoError(t, err)
+	next := schedule.next(mustParseTime(t, "2024-01-01T00:00:00Z"))
+	assert.True(t, next.Equal(mustParseTime(t, "2024-01-01T18:00:00Z")))
+	assert.True(t, schedule.isScheduledTime(next))
+	assert.False(t, schedule.isScheduledTime(next.Add(time.Hour)))
+	assert.Equal(t, next, schedule.startTime(next))
+
+	// the time zone in the cron expression takes precedence.
+	schedule, err = newCronSchedule(&dpv1alpha1.SchedulePolicy{CronExpression: "CRON_TZ=UTC 0 2 * * *", TimeZone: "Asia/Shanghai"}, "")
+	assert.NoError(t, err)
+	assert.True(t, schedule.next(mustParseTime(t, "2024-01-01T00:00:00Z")).Equal(mustParseTime(t, "2024-01-01T02:00:00Z")))
+
+	_, err = newCronSchedule(&dpv1alpha1.SchedulePolicy{CronExpression: "0 2 * * *", TimeZone: "Mars/Olympus"}, "")
+	assert.Error(t, err)
+	_, err = newCronSchedule(&dpv1alpha1.SchedulePolicy{CronExpression: "0 25 * * *"}, "")
+	assert.Error(t, err)
+
+	// the jitters are stable, and differ between the schedules.
+	policy.Jitter = &metav1.Duration{Duration: 30 * time.Minute}
+	schedule, _ = newCronSchedule(policy, "uid-1/xtrabackup")
+	other, _ := newCronSchedule(policy, "uid-2/xtrabackup")
+	start := schedule.startTime(next)
+	assert.Equal(t, start, schedule.startTime(next))
+	assert.False(t, start.Before(next))
+	assert.True(t, start.Before(next.Add(30*time.Minute)))
+	assert.NotEqual(t, start, other.startTime(next))
+}
+
+func TestCronSchedulePlan(t *testing.T) {
+	schedule, err := newCronSchedule(&dpv1alpha1.SchedulePolicy{CronExpression: "0 * * * *"}, "")
+	assert.NoError(t, err)
+	next := mustParseTime(t, "2024-01-01T10:00:00Z")
+
+	// not due
+	run, due := schedule.plan(next, next.Add(-time.Minute), 0)
+	assert.False(t, due)
+	assert.Equal(t, next, run.nextTime)
+
+	// on time
+	run, due = schedule.plan(next, next.Add(10*time.Second), 0)
+	assert.True(t, due)
+	assert.Equal(t, next, run.scheduledTime)
+	assert.Zero(t, run.missedRuns)
+	assert.True(t, run.nextTime.Equal(mustParseTime(t, "2024-01-01T11:00:00Z")))
+
+	// the missed runs are collapsed into the latest one
+	now := mustParseTime(t, "2024-01-01T13:20:00Z")
+	run, due = schedule.plan(next, now, 0)
+	assert.True(t, due)
+	assert.True(t, run.scheduledTime.Equal(mustParseTime(t, "2024-01-01T13:00:00Z")))
+	assert.Equal(t, 3, run.missedRuns)
+	assert.True(t, run.nextTime.Equal(mustParseTime(t, "2024-01-01T14:00:00Z")))
+
+	// the latest run exceeds the starting deadline
+	run, due = schedule.plan(next, now, defaultSkipDeadline)
+	assert.True(t, due)
+	assert.True(t, run.scheduledTime.IsZero())
+	assert.True(t, run.skippedTime.Equal(mustParseTime(t, "2024-01-01T13:00:00Z")))
+	assert.Equal(t, 4, run.missedRuns)
+
+	// a long downtime
+	run, due = schedule.plan(next, next.AddDate(1, 0, 0).Add(time.Minute), 0)
+	assert.True(t, due)
+	assert.True(t, run.scheduledTime.Equal(next.AddDate(1, 0, 0)))
+	assert.True(t, run.nextTime.Equal(next.AddDate(1, 0, 0).Add(time.Hour)))
+}
+
+func TestScheduleBuiltIn(t *testing.T) {
+	viper.Set(types.CfgKeyBuiltInBackupScheduler, true)
+	defer viper.Set(types.CfgKeyBuiltInBackupScheduler, false)
+
+	ctx := context.Background()
+	actionSet := &dpv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "xtrabackup"},
+		Spec:       dpv1alpha1.ActionSetSpec{BackupType: dpv1alpha1.BackupTypeFull},
+	}
+	backupPolicy := &dpv1alpha1.BackupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: testReplicateNamespace},
+		Spec: dpv1alpha1.BackupPolicySpec{
+			BackupRepoName: pointer.String("primary"),
+			BackupMethods:  []dpv1alpha1.BackupMethod{{Name: "xtrabackup", ActionSetName: "xtrabackup"}},
+		},
+	}
+	backupSchedule := &dpv1alpha1.BackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule", Namespace: testReplicateNamespace, UID: "0123456789abcdef"},
+		Spec: dpv1alpha1.BackupScheduleSpec{
+			BackupPolicyName: backupPolicy.Name,
+			Schedules: []dpv1alpha1.SchedulePolicy{{
+				Enabled:         pointer.Bool(true),
+				BackupMethod:    "xtrabackup",
+				CronExpression:  "0 * * * *",
+				RetentionPeriod: "7d",
+			}},
+		},
+	}
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "schedule-xtrabackup",
+			Namespace:  testReplicateNamespace,
+			Finalizers: []string{types.DataProtectionFinalizerName},
+			Labels: map[string]string{
+				types.BackupScheduleLabelKey: backupSchedule.Name,
+				types.BackupMethodLabelKey:   "xtrabackup",
+			},
+		},
+	}
+	repo := newReplicateTestRepo("primary", "")
+	cli, scheme := newTestClient(t, actionSet, backupPolicy, backupSchedule, cronJob, repo)
+	recorder := record.NewFakeRecorder(10)
+	scheduler := &Scheduler{
+		RequestCtx:     intctrlutil.RequestCtx{Ctx: ctx, Log: logr.Discard(), Recorder: recorder},
+		Client:         cli,
+		Scheme:         scheme,
+		BackupSchedule: backupSchedule,
+		BackupPolicy:   backupPolicy,
+		Limiter:        NewBackupConcurrencyLimiter(),
+	}
+	getSchedule := func() *dpv1alpha1.BackupSchedule {
+		schedule := &dpv1alpha1.BackupSchedule{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(backupSchedule), schedule))
+		return schedule
+	}
+	setNextScheduleTime := func(next time.Time) {
+		schedule := getSchedule()
+		status := schedule.Status.Schedules["xtrabackup"]
+		status.NextScheduleTime = &metav1.Time{Time: next}
+		schedule.Status.Schedules["xtrabackup"] = status
+		assert.NoError(t, cli.Status().Update(ctx, schedule))
+		scheduler.BackupSchedule = schedule
+	}
+
+	// the cronjob is replaced by the built-in scheduler, and the next run is recorded
+	requeueAfter, err := scheduler.Schedule()
+	assert.NoError(t, err)
+	assert.True(t, requeueAfter > 0 && requeueAfter <= time.Hour)
+	exists, err := intctrlutil.CheckResourceExists(ctx, cli, client.ObjectKeyFromObject(cronJob), &batchv1.CronJob{})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	status := getSchedule().Status.Schedules["xtrabackup"]
+	assert.Equal(t, dpv1alpha1.ScheduleRunning, status.Phase)
+	assert.NotNil(t, status.NextScheduleTime)
+	assert.Equal(t, 0, status.NextScheduleTime.Minute())
+	assert.Nil(t, status.LastScheduleTime)
+
+	// the missed runs are caught up by a single backup
+	latest := time.Now().UTC().Truncate(time.Hour)
+	setNextScheduleTime(latest.Add(-2 * time.Hour))
+	_, err = scheduler.Schedule()
+	assert.NoError(t, err)
+	status = getSchedule().Status.Schedules["xtrabackup"]
+	assert.True(t, status.LastScheduleTime.Time.Equal(latest))
+	assert.True(t, status.NextScheduleTime.Time.Equal(latest.Add(time.Hour)))
+	backupName := "schedule-" + latest.Format(scheduledBackupTimeLayout)
+	assert.Equal(t, backupName, status.LastBackupName)
+	backup := &dpv1alpha1.Backup{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: backupName, Namespace: testReplicateNamespace}, backup))
+	assert.Equal(t, "xtrabackup", backup.Spec.BackupMethod)
+	assert.Equal(t, backupPolicy.Name, backup.Spec.BackupPolicyName)
+	assert.Equal(t, "true", backup.Labels[types.AutoBackupLabelKey])
+	assert.Equal(t, backupSchedule.Name, backup.Labels[types.BackupScheduleLabelKey])
+	assert.Contains(t, <-recorder.Events, "MissedSchedule")
+
+	// the run is postponed by the concurrency limit of the backup repo
+	repo.Spec.MaxConcurrentBackups = pointer.Int32(1)
+	assert.NoError(t, cli.Update(ctx, repo))
+	assert.NoError(t, cli.Delete(ctx, backup))
+	// forget the deleted backup which is counted until it shows up in the cache
+	scheduler.Limiter = NewBackupConcurrencyLimiter()
+	running := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: testReplicateNamespace},
+	}
+	assert.NoError(t, cli.Create(ctx, running))
+	running.Status.Phase = dpv1alpha1.BackupPhaseRunning
+	running.Status.BackupRepoName = repo.Name
+	assert.NoError(t, cli.Status().Update(ctx, running))
+	setNextScheduleTime(latest)
+	requeueAfter, err = scheduler.Schedule()
+	assert.NoError(t, err)
+	assert.Equal(t, throttledRequeueInterval, requeueAfter)
+	status = getSchedule().Status.Schedules["xtrabackup"]
+	assert.True(t, status.NextScheduleTime.Time.Equal(latest))
+	assert.Contains(t, <-recorder.Events, "BackupPostponed")
+
+	// the running backup is completed
+	running.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	assert.NoError(t, cli.Status().Update(ctx, running))
+	_, err = scheduler.Schedule()
+	assert.NoError(t, err)
+	status = getSchedule().Status.Schedules["xtrabackup"]
+	assert.True(t, status.LastScheduleTime.Time.Equal(latest))
+	assert.True(t, status.NextScheduleTime.Time.Equal(latest.Add(time.Hour)))
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: backupName, Namespace: testReplicateNamespace}, backup))
+
+	// the completion time of the scheduled backups is recorded
+	backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	backup.Status.CompletionTimestamp = &metav1.Time{Time: latest.Add(time.Minute)}
+	assert.NoError(t, cli.Status().Update(ctx, backup))
+	scheduler.BackupSchedule = getSchedule()
+	_, err = scheduler.Schedule()
+	assert.NoError(t, err)
+	status = getSchedule().Status.Schedules["xtrabackup"]
+	assert.True(t, status.LastSuccessfulTime.Time.Equal(latest.Add(time.Minute)))
+
+	// the schedule is disabled
+	scheduler.BackupSchedule.Spec.Schedules[0].Enabled = pointer.Bool(false)
+	requeueAfter, err = scheduler.Schedule()
+	assert.NoError(t, err)
+	assert.Zero(t, requeueAfter)
+	assert.Nil(t, getSchedule().Status.Schedules["xtrabackup"].NextScheduleTime)
+}
+
+func TestBackupConcurrencyLimiter(t *testing.T) {
+	viper.Set(types.CfgKeyMaxConcurrentBackups, 2)
+	defer viper.Set(types.CfgKeyMaxConcurrentBackups, 0)
+
+	ctx := context.Background()
+	newBackup := func(name, repo string, phase dpv1alpha1.BackupPhase) *dpv1alpha1.Backup {
+		backup := &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testReplicateNamespace, Labels: map[string]string{}},
+		}
+		backup.Status.Phase = phase
+		backup.Status.BackupRepoName = repo
+		return backup
+	}
+	continuous := newBackup("continuous", "primary", dpv1alpha1.BackupPhaseRunning)
+	continuous.Labels[types.BackupTypeLabelKey] = string(dpv1alpha1.BackupTypeContinuous)
+	cli, _ := newTestClient(t, continuous, newBackup("completed", "primary", dpv1alpha1.BackupPhaseCompleted))
+	reqCtx := intctrlutil.RequestCtx{Ctx: ctx, Log: logr.Discard()}
+
+	primary := newReplicateTestRepo("primary", "")
+	primary.Spec.MaxConcurrentBackups = pointer.Int32(1)
+	secondary := newReplicateTestRepo("secondary", "")
+	created := 0
+	create := func() error {
+		created++
+		return nil
+	}
+
+	limiter := NewBackupConcurrencyLimiter()
+	admitted, err := limiter.Admit(reqCtx, cli, primary, create, newBackup("b1", "", ""))
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	// the pending backup is counted before it shows up in the cache
+	admitted, err = limiter.Admit(reqCtx, cli, primary, create, newBackup("b2", "", ""))
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+	admitted, err = limiter.Admit(reqCtx, cli, secondary, create, newBackup("b3", "", ""))
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	// the global limit is reached
+	admitted, err = limiter.Admit(reqCtx, cli, nil, create, newBackup("b4", "", ""))
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, 2, created)
+
+	// the backup shows up in the cache and is completed
+	assert.NoError(t, cli.Create(ctx, newBackup("b1", "primary", dpv1alpha1.BackupPhaseCompleted)))
+	admitted, err = limiter.Admit(reqCtx, cli, primary, create, newBackup("b2", "", ""))
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	assert.Equal(t, 3, created)
+}
@@ -119,7 +119,8 @@ var _ = Describe("Scheduler Test", func() {
 			It("should schedule", func() {
 				scheduler.BackupSchedule = backupSchedule
 				scheduler.BackupPolicy = backupPolicy
-				Expect(scheduler.Schedule()).Should(Succeed())
+				_, err := scheduler.Schedule()
+				Expect(err).Should(Succeed())
 			})
 
 			It("schedule should fail if invalid backup policy", func() {
@@ -128,7 +129,8 @@ var _ = Describe("Scheduler Test", func() {
 				for i := range scheduler.BackupPolicy.Spec.BackupMethods {
 					scheduler.BackupPolicy.Spec.BackupMethods[i].Name = "not-exist"
 				}
-				Expect(scheduler.Schedule()).ShouldNot(Succeed())
+				_, err := scheduler.Schedule()
+				Expect(err).ShouldNot(Succeed())
 			})
 
 			It("test schedule for continuous backup", func() {
@@ -149,7 +151,8 @@ var _ = Describe("Scheduler Test", func() {
 
 				scheduler.BackupPolicy = backupPolicy
 				scheduler.BackupSchedule = backupSchedule
-				Expect(scheduler.Schedule()).Should(Succeed())
+				_, err := scheduler.Schedule()
+				Expect(err).Should(Succeed())
 
 				By("check the continuous backup created")
 				backupName := GenerateCRNameByBackupSchedule(backupSchedule, testdp.BackupMethodName)
@@ -176,7 +179,8 @@ var _ = Describe("Scheduler Test", func() {
 				})
 				By("Expect only one continuous backup to exist")
 				scheduler.BackupSchedule = backupSchedule
-				Expect(scheduler.Schedule()).Should(Succeed())
+				_, err = scheduler.Schedule()
+				Expect(err).Should(Succeed())
 				Eventually(testapps.List(&testCtx, generics.BackupSignature, client.MatchingLabels{
 					dptypes.BackupTypeLabelKey:     string(dpv1alpha1.BackupTypeContinuous),
 					dptypes.BackupScheduleLabelKey: backupSchedule.Name,
@@ -28,4 +28,7 @@ const (
 
 	// BackupInfoFileName is the backup info file name in the backup path.
 	BackupInfoFileName = "backup.info"
+
+	// BackupManifestFileName is the backup manifest file name in the backup path.
+	BackupManifestFileName = "kubeblocks-backup-manifest.json"
 )
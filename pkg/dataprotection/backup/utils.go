@@ -275,7 +275,15 @@ func SetExpirationByCreationTime(backup *dpv1alpha1.Backup) error {
 // For kubernetes version < 1.22, the CRON_TZ environment variable is not supported.
 // The kube-controller-manager interprets schedules relative to its local time zone.
 func BuildCronJobSchedule(cronExpression string) (*string, string) {
-	timeZone := "UTC"
+	return BuildCronJobScheduleInTimeZone(cronExpression, "")
+}
+
+// BuildCronJobScheduleInTimeZone is like BuildCronJobSchedule, but the cron expression is
+// interpreted in the specified time zone, which defaults to UTC.
+func BuildCronJobScheduleInTimeZone(cronExpression, timeZone string) (*string, string) {
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
 	ver, err := dputils.GetKubeVersion()
 	if err != nil {
 		return nil, cronExpression
@@ -23,13 +23,31 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
+// newTestClient builds a fake client with the objects for the tests that do not need the envtest,
+// the status of the Backups and BackupSchedules can only be updated by the status client.
+func newTestClient(t *testing.T, objs ...client.Object) (client.Client, *k8sruntime.Scheme) {
+	scheme := k8sruntime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&dpv1alpha1.Backup{}, &dpv1alpha1.BackupSchedule{}).
+		Build()
+	return cli, scheme
+}
+
 func TestBuildCronJobSchedule(t *testing.T) {
 	const (
 		cronExpression       = "0 0 * * *"
@@ -86,4 +104,13 @@ func TestBuildCronJobSchedule(t *testing.T) {
 			assert.Equal(t, tt.timeZone, tz)
 		})
 	}
+
+	viper.Set(constant.CfgKeyServerInfo, version.Info{GitVersion: "v1.25.0"})
+	tz, cronExp := BuildCronJobScheduleInTimeZone(cronExpression, "Asia/Shanghai")
+	assert.Equal(t, cronExpression, cronExp)
+	assert.Equal(t, pointer.String("Asia/Shanghai"), tz)
+	viper.Set(constant.CfgKeyServerInfo, version.Info{GitVersion: "v1.22.0"})
+	tz, cronExp = BuildCronJobScheduleInTimeZone(cronExpression, "Asia/Shanghai")
+	assert.Equal(t, "CRON_TZ=Asia/Shanghai "+cronExpression, cronExp)
+	assert.Nil(t, tz)
 }
@@ -0,0 +1,724 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+)
+
+const (
+	defaultVerificationTimeoutMinutes = 120
+	validationContainerName           = "validate"
+)
+
+var verificationStages = []dpv1alpha1.BackupVerificationStage{
+	dpv1alpha1.BackupVerificationStagePrepareData,
+	dpv1alpha1.BackupVerificationStageStartInstance,
+	dpv1alpha1.BackupVerificationStagePostReady,
+	dpv1alpha1.BackupVerificationStageValidate,
+}
+
+// Verifier verifies a completed backup by a test restore. The backup is restored into
+// throwaway persistent volume claims, a scratch instance is started on them from the
+// pod spec of the backup target, and then the postReady actions of the ActionSet and
+// the validation job of the verification policy are performed against the instance.
+// The scratch instance is isolated from the source workload by a network policy and
+// the membership of the workload is rewritten in its env, to not join the workload.
+type Verifier struct {
+	intctrlutil.RequestCtx
+	Client               client.Client
+	Scheme               *k8sruntime.Scheme
+	Recorder             record.EventRecorder
+	Backup               *dpv1alpha1.Backup
+	Policy               *dpv1alpha1.BackupVerificationPolicy
+	WorkerServiceAccount string
+
+	target    *dpv1alpha1.BackupStatusTarget
+	source    *corev1.Pod
+	actionSet *dpv1alpha1.ActionSet
+}
+
+// IsVerificationInProgress checks if the verification of the backup is pending or running.
+func IsVerificationInProgress(backup *dpv1alpha1.Backup) bool {
+	status := backup.Status.Verification
+	if status == nil {
+		return false
+	}
+	return status.Phase == dpv1alpha1.BackupVerificationPending ||
+		status.Phase == dpv1alpha1.BackupVerificationRunning
+}
+
+// GenerateVerificationName generates the name shared by the objects created for verifying the backup.
+func GenerateVerificationName(backup *dpv1alpha1.Backup) string {
+	name := fmt.Sprintf("verify-%s-%s", backup.UID[:8], backup.Name)
+	// leave room for the suffixes of the restores and the job, their names are used as label values.
+	if len(name) > 48 {
+		return strings.TrimSuffix(name[:48], "-")
+	}
+	return name
+}
+
+// BuildVerificationLabels builds the labels of the objects created for verifying the backup.
+func BuildVerificationLabels(backup *dpv1alpha1.Backup) map[string]string {
+	return map[string]string{
+		constant.AppManagedByLabelKey: types.AppName,
+		types.VerifyBackupLabelKey:    backup.Name,
+	}
+}
+
+// Verify moves the verification of the backup one step forward. The progress is recorded
+// in the backup status, and the objects created for the verification are removed once
+// the verification is finished.
+func (v *Verifier) Verify() error {
+	status := v.Backup.Status.Verification
+	if status.Phase == dpv1alpha1.BackupVerificationPending {
+		status.Phase = dpv1alpha1.BackupVerificationRunning
+		status.Stage = verificationStages[0]
+		status.StartTimestamp = &metav1.Time{Time: time.Now()}
+		status.CompletionTimestamp = nil
+		status.Message = ""
+	}
+	if v.Policy == nil {
+		return v.finish(false, fmt.Sprintf(`the verification policy is not found in backupSchedule "%s"`, status.BackupScheduleName))
+	}
+	if status.StartTimestamp != nil && time.Now().After(status.StartTimestamp.Add(v.timeout())) {
+		return v.finish(false, fmt.Sprintf("the verification is timed out at stage %s", status.Stage))
+	}
+	if err := v.init(); err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return v.finish(false, err.Error())
+		}
+		return err
+	}
+	for {
+		var (
+			done bool
+			err  error
+		)
+		switch status.Stage {
+		case dpv1alpha1.BackupVerificationStagePrepareData:
+			done, err = v.prepareData()
+		case dpv1alpha1.BackupVerificationStageStartInstance:
+			done, err = v.startInstance()
+		case dpv1alpha1.BackupVerificationStagePostReady:
+			done, err = v.postReady()
+		case dpv1alpha1.BackupVerificationStageValidate:
+			done, err = v.validate()
+		default:
+			err = intctrlutil.NewFatalError(fmt.Sprintf("unknown verification stage %s", status.Stage))
+		}
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return v.finish(false, err.Error())
+		}
+		if err != nil || !done {
+			return err
+		}
+		next := nextVerificationStage(status.Stage)
+		if next == "" {
+			return v.finish(true, "the backup is restored and validated successfully")
+		}
+		status.Stage = next
+	}
+}
+
+// Cleanup removes the objects created for verifying the backup.
+func (v *Verifier) Cleanup() error {
+	opts := []client.ListOption{
+		client.InNamespace(v.Backup.Namespace),
+		client.MatchingLabels(BuildVerificationLabels(v.Backup)),
+	}
+	// the restore controller cleans up the restore jobs by itself.
+	restores := &dpv1alpha1.RestoreList{}
+	if err := v.Client.List(v.Ctx, restores, opts...); err != nil {
+		return err
+	}
+	for i := range restores.Items {
+		if err := intctrlutil.BackgroundDeleteObject(v.Client, v.Ctx, &restores.Items[i]); err != nil {
+			return err
+		}
+	}
+	var objs []client.Object
+	pods := &corev1.PodList{}
+	if err := v.Client.List(v.Ctx, pods, opts...); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		objs = append(objs, &pods.Items[i])
+	}
+	jobs := &batchv1.JobList{}
+	if err := v.Client.List(v.Ctx, jobs, opts...); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		objs = append(objs, &jobs.Items[i])
+	}
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := v.Client.List(v.Ctx, pvcs, opts...); err != nil {
+		return err
+	}
+	for i := range pvcs.Items {
+		objs = append(objs, &pvcs.Items[i])
+	}
+	policies := &networkingv1.NetworkPolicyList{}
+	if err := v.Client.List(v.Ctx, policies, opts...); err != nil {
+		return err
+	}
+	for i := range policies.Items {
+		objs = append(objs, &policies.Items[i])
+	}
+	for _, obj := range objs {
+		if err := dputils.RemoveDataProtectionFinalizer(v.Ctx, v.Client, obj); err != nil {
+			return err
+		}
+		if err := intctrlutil.BackgroundDeleteObject(v.Client, v.Ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) timeout() time.Duration {
+	minutes := int32(defaultVerificationTimeoutMinutes)
+	if v.Policy.TimeoutMinutes != nil {
+		minutes = *v.Policy.TimeoutMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// init resolves the backup target, the source instance and the ActionSet of the backup.
+func (v *Verifier) init() error {
+	if v.Backup.Status.BackupMethod == nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`status.backupMethod of backup "%s" is empty`, v.Backup.Name))
+	}
+	v.target = v.Backup.Status.Target
+	if v.target == nil && len(v.Backup.Status.Targets) > 0 {
+		v.target = &v.Backup.Status.Targets[0]
+	}
+	if v.target == nil || len(v.target.SelectedTargetPods) == 0 {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the target pods of backup "%s" are not found`, v.Backup.Name))
+	}
+	v.source = &corev1.Pod{}
+	key := client.ObjectKey{Namespace: v.Backup.Namespace, Name: v.target.SelectedTargetPods[0]}
+	if err := v.Client.Get(v.Ctx, key, v.source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`the source instance "%s" of the backup is not found`, key.Name))
+		}
+		return err
+	}
+	actionSet, err := dputils.GetActionSetByName(v.RequestCtx, v.Client, v.Backup.Status.BackupMethod.ActionSetName)
+	if err != nil {
+		return err
+	}
+	v.actionSet = actionSet
+	if !v.useVolumeSnapshot() && !v.actionSet.HasPrepareDataStage() && !v.actionSet.HasPostReadyStage() {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`backup "%s" can not be restored, neither prepareData nor postReady is defined`, v.Backup.Name))
+	}
+	return nil
+}
+
+func (v *Verifier) useVolumeSnapshot() bool {
+	return boolptr.IsSetToTrue(v.Backup.Status.BackupMethod.SnapshotVolumes)
+}
+
+// finish cleans up the verification and records the result.
+func (v *Verifier) finish(passed bool, message string) error {
+	if err := v.Cleanup(); err != nil {
+		return err
+	}
+	status := v.Backup.Status.Verification
+	status.Phase = dpv1alpha1.BackupVerificationFailed
+	if passed {
+		status.Phase = dpv1alpha1.BackupVerificationPassed
+	}
+	status.CompletionTimestamp = &metav1.Time{Time: time.Now()}
+	status.Message = message
+	return nil
+}
+
+func nextVerificationStage(stage dpv1alpha1.BackupVerificationStage) dpv1alpha1.BackupVerificationStage {
+	for i := range verificationStages {
+		if verificationStages[i] == stage && i+1 < len(verificationStages) {
+			return verificationStages[i+1]
+		}
+	}
+	return ""
+}
+
+// restoredVolumes returns the volumes of the source instance whose data are restored in the prepareData stage.
+func (v *Verifier) restoredVolumes() []corev1.Volume {
+	targetVolumes := v.Backup.Status.BackupMethod.TargetVolumes
+	if targetVolumes == nil || (!v.useVolumeSnapshot() && !v.actionSet.HasPrepareDataStage()) {
+		return nil
+	}
+	var volumes []corev1.Volume
+	for _, volume := range v.source.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && dputils.ExistTargetVolume(targetVolumes, volume.Name) {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+func (v *Verifier) claimName(volumeName string) string {
+	return fmt.Sprintf("%s-%s", volumeName, GenerateVerificationName(v.Backup))
+}
+
+func (v *Verifier) prepareData() (bool, error) {
+	volumes := v.restoredVolumes()
+	if len(volumes) == 0 {
+		return true, nil
+	}
+	var claims []dpv1alpha1.RestoreVolumeClaim
+	for _, volume := range volumes {
+		pvc := &corev1.PersistentVolumeClaim{}
+		key := client.ObjectKey{Namespace: v.source.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}
+		if err := v.Client.Get(v.Ctx, key, pvc); err != nil {
+			return false, err
+		}
+		claims = append(claims, v.buildRestoreVolumeClaim(volume.Name, pvc))
+	}
+	return v.checkRestore(v.buildPrepareDataRestore(claims))
+}
+
+func (v *Verifier) buildRestoreVolumeClaim(volumeName string, source *corev1.PersistentVolumeClaim) dpv1alpha1.RestoreVolumeClaim {
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes:      source.Spec.AccessModes,
+		Resources:        source.Spec.Resources,
+		StorageClassName: source.Spec.StorageClassName,
+		VolumeMode:       source.Spec.VolumeMode,
+	}
+	if v.Policy.StorageClassName != nil {
+		spec.StorageClassName = v.Policy.StorageClassName
+	}
+	return dpv1alpha1.RestoreVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   v.claimName(volumeName),
+			Labels: BuildVerificationLabels(v.Backup),
+		},
+		VolumeClaimSpec: spec,
+		VolumeConfig: dpv1alpha1.VolumeConfig{
+			VolumeSource: volumeName,
+		},
+	}
+}
+
+func (v *Verifier) buildPrepareDataRestore(claims []dpv1alpha1.RestoreVolumeClaim) *dpv1alpha1.Restore {
+	return &dpv1alpha1.Restore{
+		ObjectMeta: v.buildRestoreObjectMeta(dpv1alpha1.PrepareData),
+		Spec: dpv1alpha1.RestoreSpec{
+			Backup: v.buildBackupRef(),
+			PrepareDataConfig: &dpv1alpha1.PrepareDataConfig{
+				RequiredPolicyForAllPodSelection: v.buildRequiredPolicy(),
+				RestoreVolumeClaims:              claims,
+				VolumeClaimRestorePolicy:         dpv1alpha1.VolumeClaimRestorePolicyParallel,
+				SchedulingSpec: dpv1alpha1.SchedulingSpec{
+					Tolerations:  v.source.Spec.Tolerations,
+					NodeSelector: v.source.Spec.NodeSelector,
+				},
+			},
+		},
+	}
+}
+
+func (v *Verifier) buildPostReadyRestore() *dpv1alpha1.Restore {
+	selector := metav1.LabelSelector{MatchLabels: v.buildScratchInstanceLabels()}
+	restore := &dpv1alpha1.Restore{
+		ObjectMeta: v.buildRestoreObjectMeta(dpv1alpha1.PostReady),
+		Spec: dpv1alpha1.RestoreSpec{
+			Backup: v.buildBackupRef(),
+			ReadyConfig: &dpv1alpha1.ReadyConfig{
+				ExecAction: &dpv1alpha1.ExecAction{
+					Target: dpv1alpha1.ExecActionTarget{
+						PodSelector: selector,
+					},
+				},
+				JobAction: &dpv1alpha1.JobAction{
+					RequiredPolicyForAllPodSelection: v.buildRequiredPolicy(),
+					Target: dpv1alpha1.JobActionTarget{
+						PodSelector: dpv1alpha1.PodSelector{
+							LabelSelector: &selector,
+						},
+					},
+				},
+			},
+		},
+	}
+	if v.target.PodSelector != nil {
+		restore.Spec.ReadyConfig.JobAction.Target.PodSelector.Strategy = v.target.PodSelector.Strategy
+	}
+	if targetVolumes := v.Backup.Status.BackupMethod.TargetVolumes; targetVolumes != nil {
+		restore.Spec.ReadyConfig.JobAction.Target.VolumeMounts = targetVolumes.VolumeMounts
+	}
+	return restore
+}
+
+func (v *Verifier) buildRestoreObjectMeta(stage dpv1alpha1.RestoreStage) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      fmt.Sprintf("%s-%s", GenerateVerificationName(v.Backup), strings.ToLower(string(stage))),
+		Namespace: v.Backup.Namespace,
+		Labels:    BuildVerificationLabels(v.Backup),
+	}
+}
+
+func (v *Verifier) buildBackupRef() dpv1alpha1.BackupRef {
+	return dpv1alpha1.BackupRef{
+		Name:             v.Backup.Name,
+		Namespace:        v.Backup.Namespace,
+		SourceTargetName: v.target.Name,
+	}
+}
+
+// buildRequiredPolicy restores the data of the source instance only, as there is only one scratch instance.
+func (v *Verifier) buildRequiredPolicy() *dpv1alpha1.RequiredPolicyForAllPodSelection {
+	if v.target.PodSelector == nil || v.target.PodSelector.Strategy != dpv1alpha1.PodSelectionStrategyAll {
+		return nil
+	}
+	return &dpv1alpha1.RequiredPolicyForAllPodSelection{
+		DataRestorePolicy: dpv1alpha1.OneToManyRestorePolicy,
+		SourceOfOneToMany: &dpv1alpha1.SourceOfOneToMany{
+			TargetPodName: v.source.Name,
+		},
+	}
+}
+
+// checkRestore creates the restore if it does not exist, and checks if it is completed.
+func (v *Verifier) checkRestore(restore *dpv1alpha1.Restore) (bool, error) {
+	existing := &dpv1alpha1.Restore{}
+	exists, err := intctrlutil.CheckResourceExists(v.Ctx, v.Client, client.ObjectKeyFromObject(restore), existing)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		if err = dputils.SetControllerReference(v.Backup, restore, v.Scheme); err != nil {
+			return false, err
+		}
+		return false, client.IgnoreAlreadyExists(v.Client.Create(v.Ctx, restore))
+	}
+	switch existing.Status.Phase {
+	case dpv1alpha1.RestorePhaseCompleted:
+		return true, nil
+	case dpv1alpha1.RestorePhaseFailed:
+		msg := fmt.Sprintf(`restore "%s" failed`, existing.Name)
+		for _, cond := range existing.Status.Conditions {
+			if cond.Status == metav1.ConditionFalse && cond.Message != "" {
+				msg = fmt.Sprintf("%s: %s", msg, cond.Message)
+				break
+			}
+		}
+		return false, intctrlutil.NewFatalError(msg)
+	}
+	return false, nil
+}
+
+func (v *Verifier) buildScratchInstanceLabels() map[string]string {
+	labels := BuildVerificationLabels(v.Backup)
+	labels[types.BackupTargetPodLabelKey] = v.source.Name
+	return labels
+}
+
+// buildScratchInstance builds the scratch instance from the pod spec of the source instance,
+// the restored volumes are replaced by the throwaway claims and the other claims are replaced
+// by empty dirs. The other members of the source workload are removed from the env, and the
+// scratch instance takes the place of the source instance in it.
+func (v *Verifier) buildScratchInstance(peers []string) *corev1.Pod {
+	restored := map[string]bool{}
+	for _, volume := range v.restoredVolumes() {
+		restored[volume.Name] = true
+	}
+	name := GenerateVerificationName(v.Backup)
+	spec := v.source.Spec.DeepCopy()
+	// the scratch instance is not a member of the source workload, and it can be scheduled freely.
+	spec.NodeName = ""
+	spec.Hostname = ""
+	spec.Subdomain = ""
+	spec.Affinity = nil
+	spec.TopologySpreadConstraints = nil
+	spec.ReadinessGates = nil
+	// the network policy takes no effect on the pods in the host network.
+	spec.HostNetwork = false
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Ports {
+				containers[i].Ports[j].HostPort = 0
+			}
+			for j := range containers[i].Env {
+				containers[i].Env[j] = v.rewriteMembershipEnv(containers[i].Env[j], name, peers)
+			}
+		}
+	}
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		if restored[volume.Name] {
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: v.claimName(volume.Name)}
+		} else {
+			volume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: v.Backup.Namespace,
+			Labels:    v.buildScratchInstanceLabels(),
+		},
+		Spec: *spec,
+	}
+}
+
+// rewriteMembershipEnv rewrites the env which lists the members of the source workload, e.g. the pod names
+// or FQDNs of the component, to list the scratch instance only.
+func (v *Verifier) rewriteMembershipEnv(env corev1.EnvVar, name string, peers []string) corev1.EnvVar {
+	if env.Name == constant.KBEnvCompReplicas {
+		env.Value = "1"
+		return env
+	}
+	if env.ValueFrom != nil || len(peers) == 0 {
+		return env
+	}
+	member := func(item string) string {
+		fields := strings.FieldsFunc(strings.TrimSpace(item), func(r rune) bool { return r == '.' || r == ':' })
+		if len(fields) == 0 {
+			return ""
+		}
+		return fields[0]
+	}
+	items := strings.Split(env.Value, ",")
+	var (
+		members []string
+		isList  bool
+	)
+	for _, item := range items {
+		switch m := member(item); {
+		case m == "":
+			members = append(members, item)
+		case m == v.source.Name:
+			members = append(members, strings.Replace(item, v.source.Name, name, 1))
+		case slices.Contains(peers, m):
+			isList = true
+		default:
+			members = append(members, item)
+		}
+	}
+	if isList {
+		env.Value = strings.Join(members, ",")
+	}
+	return env
+}
+
+// listPeers lists the names of the other members of the component of the source instance.
+func (v *Verifier) listPeers() ([]string, error) {
+	clusterName := v.source.Labels[constant.AppInstanceLabelKey]
+	compName := v.source.Labels[constant.KBAppComponentLabelKey]
+	if clusterName == "" || compName == "" {
+		return nil, nil
+	}
+	pods := &corev1.PodList{}
+	if err := v.Client.List(v.Ctx, pods, client.InNamespace(v.source.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: clusterName, constant.KBAppComponentLabelKey: compName}); err != nil {
+		return nil, err
+	}
+	var peers []string
+	for _, pod := range pods.Items {
+		if pod.Name != v.source.Name {
+			peers = append(peers, pod.Name)
+		}
+	}
+	return peers, nil
+}
+
+// buildNetworkPolicy builds the network policy to isolate the scratch instance, only the postReady and validation
+// jobs of the verification can connect to it, and it can connect to the DNS only.
+func (v *Verifier) buildNetworkPolicy() *networkingv1.NetworkPolicy {
+	dnsPort := intstr.FromInt32(53)
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	postReadyRestoreName := v.buildRestoreObjectMeta(dpv1alpha1.PostReady).Name
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateVerificationName(v.Backup),
+			Namespace: v.Backup.Namespace,
+			Labels:    BuildVerificationLabels(v.Backup),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: v.buildScratchInstanceLabels()},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: &metav1.LabelSelector{MatchLabels: BuildVerificationLabels(v.Backup)}},
+						{PodSelector: &metav1.LabelSelector{MatchLabels: restore.BuildRestoreLabels(postReadyRestoreName)}},
+					},
+				},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (v *Verifier) getScratchInstance() (*corev1.Pod, bool, error) {
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: v.Backup.Namespace, Name: GenerateVerificationName(v.Backup)}
+	exists, err := intctrlutil.CheckResourceExists(v.Ctx, v.Client, key, pod)
+	return pod, exists, err
+}
+
+func (v *Verifier) startInstance() (bool, error) {
+	pod, exists, err := v.getScratchInstance()
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		// isolate the scratch instance before it is started.
+		policy := v.buildNetworkPolicy()
+		if err = dputils.SetControllerReference(v.Backup, policy, v.Scheme); err != nil {
+			return false, err
+		}
+		if err = v.Client.Create(v.Ctx, policy); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		var peers []string
+		if peers, err = v.listPeers(); err != nil {
+			return false, err
+		}
+		pod = v.buildScratchInstance(peers)
+		if err = dputils.SetControllerReference(v.Backup, pod, v.Scheme); err != nil {
+			return false, err
+		}
+		return false, client.IgnoreAlreadyExists(v.Client.Create(v.Ctx, pod))
+	}
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`the scratch instance "%s" is terminated: %s`, pod.Name, pod.Status.Message))
+	}
+	return pod.Status.Phase == corev1.PodRunning && podutils.IsPodReady(pod), nil
+}
+
+func (v *Verifier) postReady() (bool, error) {
+	if !v.actionSet.HasPostReadyStage() {
+		return true, nil
+	}
+	return v.checkRestore(v.buildPostReadyRestore())
+}
+
+func (v *Verifier) validate() (bool, error) {
+	if v.Policy.ValidationJob == nil {
+		return true, nil
+	}
+	pod, exists, err := v.getScratchInstance()
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`the scratch instance "%s" is not found`, pod.Name))
+	}
+	podSpec, err := v.buildValidationPodSpec(pod)
+	if err != nil {
+		return false, err
+	}
+	job := &action.JobAction{
+		Name:  validationContainerName,
+		Owner: v.Backup,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", GenerateVerificationName(v.Backup), validationContainerName),
+			Namespace: v.Backup.Namespace,
+			Labels:    BuildVerificationLabels(v.Backup),
+		},
+		PodSpec: podSpec,
+	}
+	status, err := job.Execute(action.ActionContext{
+		Ctx:      v.Ctx,
+		Client:   v.Client,
+		Recorder: v.Recorder,
+		Scheme:   v.Scheme,
+	})
+	if err != nil {
+		return false, err
+	}
+	switch status.Phase {
+	case dpv1alpha1.ActionPhaseCompleted:
+		return true, nil
+	case dpv1alpha1.ActionPhaseFailed:
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`the validation job "%s" failed: %s`, job.ObjectMeta.Name, status.FailureReason))
+	}
+	return false, nil
+}
+
+func (v *Verifier) buildValidationPodSpec(pod *corev1.Pod) (*corev1.PodSpec, error) {
+	// the host and port in the connection credential refer to the source instance.
+	credential := v.target.ConnectionCredential.DeepCopy()
+	if credential != nil {
+		credential.HostKey = ""
+		credential.PortKey = ""
+	}
+	env, err := dputils.BuildEnvByTarget(pod, credential, v.target.ContainerPort)
+	if err != nil {
+		return nil, intctrlutil.NewFatalError(err.Error())
+	}
+	// the scratch instance has no DNS record, connect to it by the pod IP.
+	env = dputils.MergeEnv(env, []corev1.EnvVar{{Name: types.DPDBHost, Value: pod.Status.PodIP}})
+	env = dputils.MergeEnv(env, v.Policy.ValidationJob.Env)
+	podSpec := &corev1.PodSpec{
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: v.WorkerServiceAccount,
+		Containers: []corev1.Container{
+			{
+				Name:      validationContainerName,
+				Image:     v.Policy.ValidationJob.Image,
+				Command:   v.Policy.ValidationJob.Command,
+				Env:       env,
+				Resources: v.Policy.ValidationJob.Resources,
+			},
+		},
+	}
+	if err = dputils.AddTolerations(podSpec); err != nil {
+		return nil, err
+	}
+	return podSpec, nil
+}
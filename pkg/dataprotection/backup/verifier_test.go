@@ -0,0 +1,375 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+const (
+	testVerifyNamespace = "default"
+	testVerifySource    = "mysql-0"
+)
+
+func newVerifyTestObjects() (*dpv1alpha1.Backup, *corev1.Pod, *corev1.PersistentVolumeClaim, *dpv1alpha1.ActionSet) {
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-20240101",
+			Namespace: testVerifyNamespace,
+			UID:       "0123456789abcdef",
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: "policy",
+			BackupMethod:     "xtrabackup",
+		},
+		Status: dpv1alpha1.BackupStatus{
+			Phase: dpv1alpha1.BackupPhaseCompleted,
+			Target: &dpv1alpha1.BackupStatusTarget{
+				BackupTarget: dpv1alpha1.BackupTarget{
+					PodSelector: &dpv1alpha1.PodSelector{Strategy: dpv1alpha1.PodSelectionStrategyAny},
+					ConnectionCredential: &dpv1alpha1.ConnectionCredential{
+						SecretName:  "mysql-account",
+						HostKey:     "host",
+						UsernameKey: "username",
+						PasswordKey: "password",
+					},
+				},
+				SelectedTargetPods: []string{testVerifySource},
+			},
+			BackupMethod: &dpv1alpha1.BackupMethod{
+				Name:          "xtrabackup",
+				ActionSetName: "xtrabackup",
+				TargetVolumes: &dpv1alpha1.TargetVolumeInfo{
+					Volumes:      []string{"data"},
+					VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/mysql"}},
+				},
+			},
+			Verification: &dpv1alpha1.BackupVerificationStatus{
+				Phase:              dpv1alpha1.BackupVerificationPending,
+				BackupScheduleName: "schedule",
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testVerifySource,
+			Namespace: testVerifyNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/instance": "mysql", "apps.kubeblocks.io/component-name": "mysql"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:    "node-1",
+			Hostname:    testVerifySource,
+			Subdomain:   "mysql-headless",
+			Affinity:    &corev1.Affinity{},
+			HostNetwork: true,
+			Containers: []corev1.Container{{
+				Name:  "mysql",
+				Image: "mysql:8.0",
+				Ports: []corev1.ContainerPort{{Name: "mysql", ContainerPort: 3306, HostPort: 3306}},
+				Env: []corev1.EnvVar{
+					{Name: "KB_COMP_REPLICAS", Value: "2"},
+					{Name: "MYSQL_POD_FQDN_LIST", Value: "mysql-0.mysql-headless.default.svc,mysql-1.mysql-headless.default.svc"},
+					{Name: "MYSQL_LEADER", Value: "mysql-1"},
+					{Name: "MYSQL_ROOT_HOST", Value: "%"},
+				},
+			}},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-mysql-0"},
+					},
+				},
+				{
+					Name: "log",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "log-mysql-0"},
+					},
+				},
+				{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "mysql-config"}},
+					},
+				},
+			},
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-mysql-0",
+			Namespace: testVerifyNamespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: pointer.String("standard"),
+			VolumeName:       "pv-data-mysql-0",
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+		},
+	}
+	actionSet := &dpv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "xtrabackup"},
+		Spec: dpv1alpha1.ActionSetSpec{
+			BackupType: dpv1alpha1.BackupTypeFull,
+			Restore: &dpv1alpha1.RestoreActionSpec{
+				PrepareData: &dpv1alpha1.JobActionSpec{},
+				PostReady:   []dpv1alpha1.ActionSpec{{}},
+			},
+		},
+	}
+	return backup, pod, pvc, actionSet
+}
+
+func newVerifyTestPeer() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mysql-1",
+			Namespace: testVerifyNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/instance": "mysql", "apps.kubeblocks.io/component-name": "mysql"},
+		},
+	}
+}
+
+func newTestVerifier(t *testing.T, objs ...client.Object) (*Verifier, client.Client) {
+	cli, scheme := newTestClient(t, objs...)
+	backup := objs[0].(*dpv1alpha1.Backup)
+	return &Verifier{
+		RequestCtx: intctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()},
+		Client:     cli,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(100),
+		Backup:     backup,
+		Policy: &dpv1alpha1.BackupVerificationPolicy{
+			BackupMethod:     "xtrabackup",
+			CronExpression:   "0 3 * * *",
+			StorageClassName: pointer.String("scratch"),
+			ValidationJob: &dpv1alpha1.BackupValidationJob{
+				Image:   "mysql:8.0",
+				Command: []string{"mysqlcheck", "--all-databases"},
+			},
+		},
+		WorkerServiceAccount: "kubeblocks-dataprotection-worker",
+	}, cli
+}
+
+func completeRestore(t *testing.T, cli client.Client, name string) {
+	restore := &dpv1alpha1.Restore{}
+	assert.NoError(t, cli.Get(context.Background(), client.ObjectKey{Namespace: testVerifyNamespace, Name: name}, restore))
+	restore.Status.Phase = dpv1alpha1.RestorePhaseCompleted
+	assert.NoError(t, cli.Update(context.Background(), restore))
+}
+
+func TestVerifyBackup(t *testing.T) {
+	backup, source, pvc, actionSet := newVerifyTestObjects()
+	verifier, cli := newTestVerifier(t, backup, source, pvc, actionSet, newVerifyTestPeer())
+	ctx := context.Background()
+	name := GenerateVerificationName(backup)
+	status := backup.Status.Verification
+
+	// restore the backup into the throwaway claims.
+	assert.NoError(t, verifier.Verify())
+	assert.Equal(t, dpv1alpha1.BackupVerificationRunning, status.Phase)
+	assert.Equal(t, dpv1alpha1.BackupVerificationStagePrepareData, status.Stage)
+	assert.NotNil(t, status.StartTimestamp)
+	restore := &dpv1alpha1.Restore{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: testVerifyNamespace, Name: name + "-preparedata"}, restore))
+	assert.Equal(t, backup.Name, restore.Spec.Backup.Name)
+	assert.Len(t, restore.Spec.PrepareDataConfig.RestoreVolumeClaims, 1)
+	claim := restore.Spec.PrepareDataConfig.RestoreVolumeClaims[0]
+	assert.Equal(t, "data-"+name, claim.Name)
+	assert.Equal(t, "data", claim.VolumeSource)
+	assert.Equal(t, "scratch", *claim.VolumeClaimSpec.StorageClassName)
+	assert.Empty(t, claim.VolumeClaimSpec.VolumeName)
+	assert.Equal(t, backup.Name, claim.Labels[types.VerifyBackupLabelKey])
+
+	// start the scratch instance on the restored claims.
+	completeRestore(t, cli, restore.Name)
+	assert.NoError(t, verifier.Verify())
+	assert.Equal(t, dpv1alpha1.BackupVerificationStageStartInstance, status.Stage)
+	pod := &corev1.Pod{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: testVerifyNamespace, Name: name}, pod))
+	assert.Empty(t, pod.Spec.NodeName)
+	assert.Empty(t, pod.Spec.Subdomain)
+	assert.Nil(t, pod.Spec.Affinity)
+	assert.Equal(t, testVerifySource, pod.Labels[types.BackupTargetPodLabelKey])
+	assert.NotContains(t, pod.Labels, "app.kubernetes.io/instance")
+	assert.Equal(t, "data-"+name, pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.NotNil(t, pod.Spec.Volumes[1].EmptyDir)
+	assert.NotNil(t, pod.Spec.Volumes[2].ConfigMap)
+	assert.False(t, pod.Spec.HostNetwork)
+	assert.Zero(t, pod.Spec.Containers[0].Ports[0].HostPort)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "KB_COMP_REPLICAS", Value: "1"},
+		{Name: "MYSQL_POD_FQDN_LIST", Value: name + ".mysql-headless.default.svc"},
+		{Name: "MYSQL_LEADER", Value: ""},
+		{Name: "MYSQL_ROOT_HOST", Value: "%"},
+	}, pod.Spec.Containers[0].Env)
+
+	// the scratch instance is isolated.
+	policy := &networkingv1.NetworkPolicy{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: testVerifyNamespace, Name: name}, policy))
+	assert.Equal(t, pod.Labels, policy.Spec.PodSelector.MatchLabels)
+	assert.Len(t, policy.Spec.PolicyTypes, 2)
+	assert.Len(t, policy.Spec.Ingress[0].From, 2)
+	assert.Empty(t, policy.Spec.Egress[0].To)
+
+	// perform the postReady actions against the scratch instance.
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.PodIP = "10.0.0.10"
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, pod))
+	assert.NoError(t, verifier.Verify())
+	assert.Equal(t, dpv1alpha1.BackupVerificationStagePostReady, status.Stage)
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: testVerifyNamespace, Name: name + "-postready"}, restore))
+	assert.Equal(t, pod.Labels, restore.Spec.ReadyConfig.JobAction.Target.PodSelector.MatchLabels)
+	assert.Equal(t, backup.Status.BackupMethod.TargetVolumes.VolumeMounts, restore.Spec.ReadyConfig.JobAction.Target.VolumeMounts)
+
+	// run the validation job against the scratch instance.
+	completeRestore(t, cli, restore.Name)
+	assert.NoError(t, verifier.Verify())
+	assert.Equal(t, dpv1alpha1.BackupVerificationStageValidate, status.Stage)
+	job := &batchv1.Job{}
+	assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: testVerifyNamespace, Name: name + "-validate"}, job))
+	env := map[string]corev1.EnvVar{}
+	for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	assert.Equal(t, "10.0.0.10", env[types.DPDBHost].Value)
+	assert.Equal(t, "3306", env[types.DPDBPort].Value)
+	assert.Equal(t, "username", env[types.DPDBUser].ValueFrom.SecretKeyRef.Key)
+
+	// the verification is passed and the objects are removed.
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	assert.NoError(t, verifier.Verify())
+	assert.Equal(t, dpv1alpha1.BackupVerificationPassed, status.Phase)
+	assert.NotNil(t, status.CompletionTimestamp)
+	assert.False(t, IsVerificationInProgress(backup))
+	restores := &dpv1alpha1.RestoreList{}
+	assert.NoError(t, cli.List(ctx, restores, client.InNamespace(testVerifyNamespace)))
+	assert.Empty(t, restores.Items)
+	pods := &corev1.PodList{}
+	assert.NoError(t, cli.List(ctx, pods, client.MatchingLabels(BuildVerificationLabels(backup))))
+	assert.Empty(t, pods.Items)
+	jobs := &batchv1.JobList{}
+	assert.NoError(t, cli.List(ctx, jobs, client.InNamespace(testVerifyNamespace)))
+	assert.Empty(t, jobs.Items)
+	policies := &networkingv1.NetworkPolicyList{}
+	assert.NoError(t, cli.List(ctx, policies, client.InNamespace(testVerifyNamespace)))
+	assert.Empty(t, policies.Items)
+}
+
+func TestVerifyBackupFailed(t *testing.T) {
+	t.Run("restore failed", func(t *testing.T) {
+		backup, source, pvc, actionSet := newVerifyTestObjects()
+		verifier, cli := newTestVerifier(t, backup, source, pvc, actionSet)
+		assert.NoError(t, verifier.Verify())
+		restore := &dpv1alpha1.Restore{}
+		key := client.ObjectKey{Namespace: testVerifyNamespace, Name: GenerateVerificationName(backup) + "-preparedata"}
+		assert.NoError(t, cli.Get(context.Background(), key, restore))
+		restore.Status.Phase = dpv1alpha1.RestorePhaseFailed
+		restore.Status.Conditions = []metav1.Condition{{Type: "PrepareData", Status: metav1.ConditionFalse, Message: "job failed"}}
+		assert.NoError(t, cli.Update(context.Background(), restore))
+
+		assert.NoError(t, verifier.Verify())
+		assert.Equal(t, dpv1alpha1.BackupVerificationFailed, backup.Status.Verification.Phase)
+		assert.Equal(t, dpv1alpha1.BackupVerificationStagePrepareData, backup.Status.Verification.Stage)
+		assert.Contains(t, backup.Status.Verification.Message, "job failed")
+		assert.True(t, apierrors.IsNotFound(cli.Get(context.Background(), key, restore)))
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		backup, source, pvc, actionSet := newVerifyTestObjects()
+		backup.Status.Verification.Phase = dpv1alpha1.BackupVerificationRunning
+		backup.Status.Verification.Stage = dpv1alpha1.BackupVerificationStageStartInstance
+		backup.Status.Verification.StartTimestamp = &metav1.Time{Time: time.Now().Add(-3 * time.Hour)}
+		verifier, _ := newTestVerifier(t, backup, source, pvc, actionSet)
+		assert.NoError(t, verifier.Verify())
+		assert.Equal(t, dpv1alpha1.BackupVerificationFailed, backup.Status.Verification.Phase)
+		assert.Contains(t, backup.Status.Verification.Message, "timed out at stage StartInstance")
+	})
+
+	t.Run("source instance not found", func(t *testing.T) {
+		backup, _, pvc, actionSet := newVerifyTestObjects()
+		verifier, _ := newTestVerifier(t, backup, pvc, actionSet)
+		assert.NoError(t, verifier.Verify())
+		assert.Equal(t, dpv1alpha1.BackupVerificationFailed, backup.Status.Verification.Phase)
+		assert.Contains(t, backup.Status.Verification.Message, testVerifySource)
+	})
+}
+
+func TestSelectBackupToVerify(t *testing.T) {
+	newBackup := func(name, method string, phase dpv1alpha1.BackupPhase, completion time.Time) dpv1alpha1.Backup {
+		return dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       dpv1alpha1.BackupSpec{BackupMethod: method},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:               phase,
+				CompletionTimestamp: &metav1.Time{Time: completion},
+			},
+		}
+	}
+	now := time.Now()
+	backups := []dpv1alpha1.Backup{
+		newBackup("b1", "xtrabackup", dpv1alpha1.BackupPhaseCompleted, now.Add(-2*time.Hour)),
+		newBackup("b2", "xtrabackup", dpv1alpha1.BackupPhaseCompleted, now.Add(-time.Hour)),
+		newBackup("b3", "xtrabackup", dpv1alpha1.BackupPhaseFailed, now),
+		newBackup("b4", "volume-snapshot", dpv1alpha1.BackupPhaseCompleted, now),
+	}
+	assert.Equal(t, "b2", selectBackupToVerify(backups, "xtrabackup").Name)
+	assert.Equal(t, "b4", selectBackupToVerify(backups, "volume-snapshot").Name)
+	assert.Nil(t, selectBackupToVerify(backups, "mysqldump"))
+
+	// the latest backup has been verified.
+	backups[1].Status.Verification = &dpv1alpha1.BackupVerificationStatus{Phase: dpv1alpha1.BackupVerificationPassed}
+	assert.Nil(t, selectBackupToVerify(backups, "xtrabackup"))
+}
+
+func TestGenerateVerificationName(t *testing.T) {
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mysql-cluster-backup-policy-xtrabackup-20240101000000",
+			UID:  "0123456789abcdef",
+		},
+	}
+	name := GenerateVerificationName(backup)
+	assert.LessOrEqual(t, len(name), 48)
+	assert.Equal(t, "verify-01234567-mysql-cluster-backup-policy-xtra", name)
+}
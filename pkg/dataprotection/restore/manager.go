@@ -85,6 +85,13 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 		}
 		return nil, err
 	}
+	// read the backup data from the replica if the restore uses the backup repo of the replica.
+	if repoName := r.Restore.Status.BackupRepoName; repoName != "" && backup.GetReplica(repoName) != nil {
+		if !utils.SwitchBackupToReplica(backup, repoName) {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the replica of backup "%s" in backup repo %s is not completed`,
+				backupName, repoName))
+		}
+	}
 	backupMethod := backup.Status.BackupMethod
 	if backupMethod == nil {
 		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`status.backupMethod of backup "%s" is empty`, backupName))
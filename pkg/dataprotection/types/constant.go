@@ -33,6 +33,15 @@ const (
 	CfgKeyWorkerServiceAccountAnnotations = "WORKER_SERVICE_ACCOUNT_ANNOTATIONS"
 	// CfgKeyWorkerClusterRoleName is the key of cluster role name for binding the service account of the worker
 	CfgKeyWorkerClusterRoleName = "WORKER_CLUSTER_ROLE_NAME"
+	// CfgKeyBuiltInBackupScheduler is the key of whether the backup schedules are run by the built-in
+	// scheduler of the dataprotection manager instead of the CronJobs
+	CfgKeyBuiltInBackupScheduler = "BUILTIN_BACKUP_SCHEDULER"
+	// CfgKeyMaxConcurrentBackups is the key of the maximum number of the running backups, the built-in
+	// scheduler postpones the scheduled backups when the limit is reached, zero means no limit
+	CfgKeyMaxConcurrentBackups = "MAX_CONCURRENT_BACKUPS"
+	// CfgKeyReplicationStagingSize is the key of the size limit of the staging volume of the replicate job,
+	// it is used when the total size of the backup is unknown
+	CfgKeyReplicationStagingSize = "REPLICATION_STAGING_SIZE"
 	// CfgDataProtectionReconcileWorkers the max reconcile workers for MaxConcurrentReconciles
 	CfgDataProtectionReconcileWorkers = "DATAPROTECTION_RECONCILE_WORKERS"
 )
@@ -62,6 +71,8 @@ const (
 	ConnectionPasswordAnnotationKey = "dataprotection.kubeblocks.io/connection-password"
 	// GeminiAcknowledgedAnnotationKey indicates whether Gemini has acknowledged the backup.
 	GeminiAcknowledgedAnnotationKey = "dataprotection.kubeblocks.io/gemini-acknowledged"
+	// CatalogBackupRepoAnnotationKey specifies the backup repo from whose catalog the backup is recreated.
+	CatalogBackupRepoAnnotationKey = "dataprotection.kubeblocks.io/catalog-backup-repo"
 )
 
 // label keys
@@ -84,6 +95,14 @@ const (
 	AutoBackupLabelKey = "dataprotection.kubeblocks.io/autobackup"
 	// BackupTargetPodLabelKey specifies the backup target pod label key.
 	BackupTargetPodLabelKey = "dataprotection.kubeblocks.io/target-pod-name"
+	// VerifyBackupLabelKey specifies the name of the backup verified by the labeled object.
+	VerifyBackupLabelKey = "dataprotection.kubeblocks.io/verify-backup"
+	// ReplicateBackupLabelKey specifies the name of the backup replicated by the labeled object.
+	ReplicateBackupLabelKey = "dataprotection.kubeblocks.io/replicate-backup"
+	// BackupRepoLabelKey specifies the name of the backup repo used by the labeled object.
+	BackupRepoLabelKey = "dataprotection.kubeblocks.io/backup-repo-name"
+	// BackupManifestLabelKey specifies the name of the backup whose manifest is written by the labeled object.
+	BackupManifestLabelKey = "dataprotection.kubeblocks.io/backup-manifest"
 )
 
 // env names
@@ -53,3 +53,21 @@ func GetBackupMethodsFromBackupPolicy(backupPolicyList *dpv1alpha1.BackupPolicyL
 	}
 	return defaultBackupMethod, backupMethodsMap
 }
+
+// SwitchBackupToReplica switches the location of the backup data to the replica in the
+// specified backup repository, so that the backup data can be accessed from the replica.
+// It returns false if the backup has no completed replica in the backup repository.
+func SwitchBackupToReplica(backup *dpv1alpha1.Backup, backupRepoName string) bool {
+	if backup.Status.BackupRepoName == backupRepoName {
+		return true
+	}
+	replica := backup.GetReplica(backupRepoName)
+	if replica == nil || replica.Phase != dpv1alpha1.BackupReplicaCompleted {
+		return false
+	}
+	backup.Status.BackupRepoName = replica.BackupRepoName
+	backup.Status.Path = replica.Path
+	backup.Status.KopiaRepoPath = replica.KopiaRepoPath
+	backup.Status.PersistentVolumeClaimName = ""
+	return true
+}
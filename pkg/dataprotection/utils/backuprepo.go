@@ -20,13 +20,17 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package utils
 
 import (
+	"context"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
@@ -38,6 +42,43 @@ const (
 	datasafedConfigMountPath = "/etc/datasafed"
 )
 
+// CheckBackupRepoInNamespace checks if the backup repo is ready to be used in the namespace.
+// It returns an error with ErrorTypeWaitForBackupRepoPreparation if the PVC or the tool config
+// secret of the backup repo has not been created in the namespace by the BackupRepoController.
+func CheckBackupRepoInNamespace(ctx context.Context, cli client.Client, repo *dpv1alpha1.BackupRepo, namespace string) error {
+	if repo.Status.Phase != dpv1alpha1.BackupRepoReady {
+		return dperrors.NewBackupRepoIsNotReady(repo.Name)
+	}
+	var (
+		objKey client.ObjectKey
+		obj    client.Object
+	)
+	switch {
+	case repo.AccessByMount():
+		if repo.Status.BackupPVCName == "" {
+			return intctrlutil.NewFatalError(fmt.Sprintf("BackupPVCName is empty in BackupRepo %s", repo.Name))
+		}
+		objKey = client.ObjectKey{Namespace: namespace, Name: repo.Status.BackupPVCName}
+		obj = &corev1.PersistentVolumeClaim{}
+	case repo.AccessByTool():
+		if repo.Status.ToolConfigSecretName == "" {
+			return intctrlutil.NewFatalError(fmt.Sprintf("ToolConfigSecretName is empty in BackupRepo %s", repo.Name))
+		}
+		objKey = client.ObjectKey{Namespace: namespace, Name: repo.Status.ToolConfigSecretName}
+		obj = &corev1.Secret{}
+	default:
+		return nil
+	}
+	if err := cli.Get(ctx, objKey, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return intctrlutil.NewErrorf(dperrors.ErrorTypeWaitForBackupRepoPreparation,
+				"backup repo %s is not ready in the namespace %s", repo.Name, namespace)
+		}
+		return err
+	}
+	return nil
+}
+
 func InjectDatasafed(podSpec *corev1.PodSpec, repo *dpv1alpha1.BackupRepo, repoVolumeMountPath string,
 	encryptionConfig *dpv1alpha1.EncryptionConfig, kopiaRepoPath string) {
 	if repo.AccessByMount() {
@@ -110,3 +110,43 @@ func TestGetBackupStatusTarget(t *testing.T) {
 		assert.Error(t, errors.New("backup status target should be empty"))
 	}
 }
+
+func TestSwitchBackupToReplica(t *testing.T) {
+	newBackup := func() *dpv1alpha1.Backup {
+		return &dpv1alpha1.Backup{
+			Status: dpv1alpha1.BackupStatus{
+				BackupRepoName: "primary",
+				Path:           "/default/backup",
+				KopiaRepoPath:  "/default/kopia",
+				Replicas: []dpv1alpha1.BackupReplicaStatus{
+					{
+						BackupRepoName: "dr",
+						Phase:          dpv1alpha1.BackupReplicaCompleted,
+						Path:           "/dr/default/backup",
+						KopiaRepoPath:  "/dr/default/kopia",
+					},
+					{
+						BackupRepoName: "running",
+						Phase:          dpv1alpha1.BackupReplicaRunning,
+						Path:           "/running/default/backup",
+					},
+				},
+			},
+		}
+	}
+
+	backup := newBackup()
+	assert.True(t, SwitchBackupToReplica(backup, "primary"))
+	assert.Equal(t, "/default/backup", backup.Status.Path)
+
+	assert.True(t, SwitchBackupToReplica(backup, "dr"))
+	assert.Equal(t, "dr", backup.Status.BackupRepoName)
+	assert.Equal(t, "/dr/default/backup", backup.Status.Path)
+	assert.Equal(t, "/dr/default/kopia", backup.Status.KopiaRepoPath)
+
+	backup = newBackup()
+	assert.False(t, SwitchBackupToReplica(backup, "running"))
+	assert.False(t, SwitchBackupToReplica(backup, "unknown"))
+	assert.Equal(t, "primary", backup.Status.BackupRepoName)
+	assert.Equal(t, "/default/backup", backup.Status.Path)
+}
@@ -21,6 +21,9 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"time"
@@ -34,6 +37,58 @@ const (
 
 type Client interface {
 	Action(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error)
+
+	// ActionExecution returns the execution of an action with the given id, which is recorded in the journal of kb-agent.
+	ActionExecution(ctx context.Context, id string) (proto.ActionExecution, error)
+
+	// ListActionExecutions returns all the action executions recorded in the journal of kb-agent.
+	ListActionExecutions(ctx context.Context) ([]proto.ActionExecution, error)
+
+	// ProbeEvents returns the latest events of the probe, or of all probes if the probe is empty.
+	ProbeEvents(ctx context.Context, probe string) ([]proto.ProbeEvent, error)
+
+	// WatchProbeEvents streams the probe events as they happen, starting with the latest events of all probes.
+	// The channel is closed when the ctx is done or the stream is broken.
+	WatchProbeEvents(ctx context.Context) (<-chan proto.ProbeEvent, error)
+
+	// VolumeUsage returns the usage of the volumes reported by the kb-agent.
+	VolumeUsage(ctx context.Context) ([]proto.VolumeUsage, error)
+}
+
+// Credentials are used to authenticate to the kb-agent.
+type Credentials struct {
+	// CA is the PEM encoded CA certificate to verify the server certificate, the TLS is enabled if it is set.
+	CA []byte
+	// Cert and Key are the PEM encoded client certificate and key, which are presented to the server if the TLS is enabled.
+	Cert []byte
+	Key  []byte
+	// ServerName is used to verify the hostname of the server certificate.
+	ServerName string
+	// Token is the bearer token sent to the server.
+	Token string
+}
+
+func (c *Credentials) tlsConfig() (*tls.Config, error) {
+	if c == nil || len(c.CA) == 0 {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(c.CA) {
+		return nil, fmt.Errorf("no valid CA certificate found")
+	}
+	config := &tls.Config{
+		RootCAs:    pool,
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(c.Cert) > 0 || len(c.Key) > 0 {
+		cert, err := tls.X509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
 }
 
 // HACK: for unit test only.
@@ -54,7 +109,7 @@ func GetMockClient() Client {
 	return mockClient
 }
 
-func NewClient(host string, port int32) (Client, error) {
+func NewClient(host string, port int32, creds *Credentials) (Client, error) {
 	if mockClient != nil || mockClientError != nil {
 		return mockClient, mockClientError
 	}
@@ -67,18 +122,33 @@ func NewClient(host string, port int32) (Client, error) {
 	dialer := &net.Dialer{
 		Timeout: defaultConnectTimeout,
 	}
+	tlsConfig, err := creds.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 	transport := &http.Transport{
 		Dial:                dialer.Dial,
 		TLSHandshakeTimeout: defaultConnectTimeout,
+		TLSClientConfig:     tlsConfig,
 	}
 	cli := &http.Client{
 		// don't set timeout at client level
 		// Timeout:   time.Second * 30,
 		Transport: transport,
 	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	token := ""
+	if creds != nil {
+		token = creds.Token
+	}
 	return &httpClient{
+		scheme: scheme,
 		host:   host,
 		port:   port,
+		token:  token,
 		client: cli,
 	}, nil
 }
@@ -70,3 +70,78 @@ func (mr *MockClientMockRecorder) Action(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Action", reflect.TypeOf((*MockClient)(nil).Action), arg0, arg1)
 }
+
+// ActionExecution mocks base method.
+func (m *MockClient) ActionExecution(arg0 context.Context, arg1 string) (proto.ActionExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActionExecution", arg0, arg1)
+	ret0, _ := ret[0].(proto.ActionExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActionExecution indicates an expected call of ActionExecution.
+func (mr *MockClientMockRecorder) ActionExecution(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActionExecution", reflect.TypeOf((*MockClient)(nil).ActionExecution), arg0, arg1)
+}
+
+// ListActionExecutions mocks base method.
+func (m *MockClient) ListActionExecutions(arg0 context.Context) ([]proto.ActionExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActionExecutions", arg0)
+	ret0, _ := ret[0].([]proto.ActionExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActionExecutions indicates an expected call of ListActionExecutions.
+func (mr *MockClientMockRecorder) ListActionExecutions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActionExecutions", reflect.TypeOf((*MockClient)(nil).ListActionExecutions), arg0)
+}
+
+// ProbeEvents mocks base method.
+func (m *MockClient) ProbeEvents(arg0 context.Context, arg1 string) ([]proto.ProbeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProbeEvents", arg0, arg1)
+	ret0, _ := ret[0].([]proto.ProbeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProbeEvents indicates an expected call of ProbeEvents.
+func (mr *MockClientMockRecorder) ProbeEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeEvents", reflect.TypeOf((*MockClient)(nil).ProbeEvents), arg0, arg1)
+}
+
+// VolumeUsage mocks base method.
+func (m *MockClient) VolumeUsage(arg0 context.Context) ([]proto.VolumeUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeUsage", arg0)
+	ret0, _ := ret[0].([]proto.VolumeUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeUsage indicates an expected call of VolumeUsage.
+func (mr *MockClientMockRecorder) VolumeUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeUsage", reflect.TypeOf((*MockClient)(nil).VolumeUsage), arg0)
+}
+
+// WatchProbeEvents mocks base method.
+func (m *MockClient) WatchProbeEvents(arg0 context.Context) (<-chan proto.ProbeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchProbeEvents", arg0)
+	ret0, _ := ret[0].(<-chan proto.ProbeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchProbeEvents indicates an expected call of WatchProbeEvents.
+func (mr *MockClientMockRecorder) WatchProbeEvents(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchProbeEvents", reflect.TypeOf((*MockClient)(nil).WatchProbeEvents), arg0)
+}
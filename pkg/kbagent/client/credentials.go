@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// NewCredentials4Pod loads the credentials to access the kb-agent in the pod, it follows the volumes of pod rather than
+// the spec of component, since the pod may not be updated yet.
+func NewCredentials4Pod(ctx context.Context, cli client.Reader, pod *corev1.Pod) (*Credentials, error) {
+	var creds *Credentials
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret == nil {
+			continue
+		}
+		switch {
+		case volume.Name == kbagent.TokenVolumeName:
+			data, err := secretVolumeData(ctx, cli, pod.Namespace, volume.Secret)
+			if err != nil {
+				return nil, err
+			}
+			if creds == nil {
+				creds = &Credentials{}
+			}
+			creds.Token = string(data[kbagent.TokenSecretKey])
+		case volume.Name == constant.VolumeName && kbagent.IsTLSEnabled(pod):
+			data, err := secretVolumeData(ctx, cli, pod.Namespace, volume.Secret)
+			if err != nil {
+				return nil, err
+			}
+			if creds == nil {
+				creds = &Credentials{}
+			}
+			creds.CA = data[constant.CAName]
+			creds.Cert = data[constant.CertName]
+			creds.Key = data[constant.KeyName]
+			creds.ServerName = podFQDN(pod)
+		}
+	}
+	return creds, nil
+}
+
+// podFQDN returns the FQDN of pod, which is covered by the certificates of component.
+func podFQDN(pod *corev1.Pod) string {
+	if len(pod.Spec.Hostname) == 0 || len(pod.Spec.Subdomain) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s.%s.svc.%s", pod.Spec.Hostname, pod.Spec.Subdomain,
+		pod.Namespace, viper.GetString(constant.KubernetesClusterDomainEnv))
+}
+
+// secretVolumeData returns the data of secret volume, keyed by the paths of items.
+func secretVolumeData(ctx context.Context, cli client.Reader, namespace string, source *corev1.SecretVolumeSource) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.SecretName}, secret); err != nil {
+		return nil, err
+	}
+	if len(source.Items) == 0 {
+		return secret.Data, nil
+	}
+	data := make(map[string][]byte)
+	for _, item := range source.Items {
+		data[item.Path] = secret.Data[item.Key]
+	}
+	return data, nil
+}
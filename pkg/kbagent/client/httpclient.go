@@ -20,23 +20,32 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
 
 const (
-	urlTemplate = "http://%s:%d%s"
+	urlTemplate = "%s://%s:%d%s"
+
+	watchURISuffix  = "/watch"
+	eventDataPrefix = "data:"
 )
 
 type httpClient struct {
+	scheme string
 	host   string
 	port   int32
+	token  string
 	client *http.Client
 }
 
@@ -50,7 +59,7 @@ func (c *httpClient) Action(ctx context.Context, req proto.ActionRequest) (proto
 		return rsp, err
 	}
 
-	url := fmt.Sprintf(urlTemplate, c.host, c.port, proto.ServiceAction.URI)
+	url := c.url(proto.ServiceAction.URI)
 	payload, err := c.request(ctx, http.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
 		return rsp, err
@@ -60,11 +69,127 @@ func (c *httpClient) Action(ctx context.Context, req proto.ActionRequest) (proto
 	return decode(payload, &rsp)
 }
 
+func (c *httpClient) ActionExecution(ctx context.Context, id string) (proto.ActionExecution, error) {
+	executions, err := c.queryActionExecutions(ctx, fmt.Sprintf("%s/%s", proto.ServiceAction.URI, id))
+	if err != nil {
+		return proto.ActionExecution{}, err
+	}
+	if len(executions) == 0 {
+		return proto.ActionExecution{}, errors.Wrapf(proto.ErrNotDefined, "action execution %s is not found", id)
+	}
+	return executions[0], nil
+}
+
+func (c *httpClient) ListActionExecutions(ctx context.Context) ([]proto.ActionExecution, error) {
+	return c.queryActionExecutions(ctx, proto.ServiceAction.URI)
+}
+
+func (c *httpClient) queryActionExecutions(ctx context.Context, uri string) ([]proto.ActionExecution, error) {
+	rsp := proto.ActionExecutionResponse{}
+
+	payload, err := c.request(ctx, http.MethodGet, c.url(uri), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer payload.Close()
+	rsp, err = decode(payload, &rsp)
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Error) > 0 {
+		return nil, errors.Wrap(proto.Type2Error(rsp.Error), rsp.Message)
+	}
+	return rsp.Executions, nil
+}
+
+func (c *httpClient) ProbeEvents(ctx context.Context, probe string) ([]proto.ProbeEvent, error) {
+	rsp := proto.ProbeResponse{}
+
+	data, err := json.Marshal(proto.ProbeRequest{Probe: probe})
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := c.request(ctx, http.MethodPost, c.url(proto.ServiceProbe.URI), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer payload.Close()
+	rsp, err = decode(payload, &rsp)
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Error) > 0 {
+		return nil, errors.Wrap(proto.Type2Error(rsp.Error), rsp.Message)
+	}
+	return rsp.Events, nil
+}
+
+func (c *httpClient) VolumeUsage(ctx context.Context) ([]proto.VolumeUsage, error) {
+	rsp := proto.VolumeResponse{}
+
+	payload, err := c.request(ctx, http.MethodPost, c.url(proto.ServiceVolume.URI), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer payload.Close()
+	rsp, err = decode(payload, &rsp)
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Error) > 0 {
+		return nil, errors.Wrap(proto.Type2Error(rsp.Error), rsp.Message)
+	}
+	return rsp.Volumes, nil
+}
+
+func (c *httpClient) WatchProbeEvents(ctx context.Context) (<-chan proto.ProbeEvent, error) {
+	payload, err := c.request(ctx, http.MethodGet, c.url(proto.ServiceProbe.URI+watchURISuffix), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan proto.ProbeEvent)
+	go func() {
+		defer close(events)
+		defer payload.Close()
+
+		scanner := bufio.NewScanner(payload)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, eventDataPrefix) {
+				continue // keepalive comments and blank lines
+			}
+			event := proto.ProbeEvent{}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, eventDataPrefix))), &event); err != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (c *httpClient) url(uri string) string {
+	return fmt.Sprintf(urlTemplate, c.scheme, c.host, c.port, uri)
+}
+
 func (c *httpClient) request(ctx context.Context, method, url string, body io.Reader) (io.ReadCloser, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
+	if len(c.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 
 	rsp, err := c.client.Do(req)
 	if err != nil {
@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+const (
+	namespace = "kbagent"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	serviceRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "service",
+		Name:      "requests_total",
+		Help:      "The number of requests handled by the services, partitioned by service, method and result.",
+	}, []string{"service", "method", "result"})
+
+	serviceRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "service",
+		Name:      "request_duration_seconds",
+		Help:      "The latency of requests handled by the services, partitioned by service and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	actionRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "action",
+		Name:      "requests_total",
+		Help:      "The number of action requests, partitioned by action and result.",
+	}, []string{"action", "result"})
+
+	actionExecutionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "action",
+		Name:      "execution_duration_seconds",
+		Help:      "The latency of action executions, partitioned by action and result.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"action", "result"})
+
+	probeResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "probe",
+		Name:      "results_total",
+		Help:      "The number of probe runs, partitioned by probe and result (success or failure).",
+	}, []string{"probe", "result"})
+
+	probeConsecutiveFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "probe",
+		Name:      "consecutive_failures",
+		Help:      "The current number of consecutive failures of probe.",
+	}, []string{"probe"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		serviceRequests,
+		serviceRequestDuration,
+		actionRequests,
+		actionExecutionDuration,
+		probeResults,
+		probeConsecutiveFailures,
+	)
+}
+
+// Handler returns the HTTP handler to expose the metrics of kb-agent.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveServiceRequest records a request handled by the service.
+func ObserveServiceRequest(service, method string, start time.Time, err error) {
+	serviceRequests.WithLabelValues(service, method, result(err)).Inc()
+	serviceRequestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveActionRequest records a request of the action, the request of a non-blocking action
+// may result in inProgress.
+func ObserveActionRequest(action string, err error) {
+	actionRequests.WithLabelValues(action, result(err)).Inc()
+}
+
+// ObserveActionExecution records a finished execution of the action.
+func ObserveActionExecution(action string, start time.Time, err error) {
+	actionExecutionDuration.WithLabelValues(action, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveProbe records a run of the probe, and the consecutive failures of it.
+func ObserveProbe(probe string, err error, consecutiveFailures int64) {
+	if err == nil {
+		probeResults.WithLabelValues(probe, resultSuccess).Inc()
+	} else {
+		probeResults.WithLabelValues(probe, resultFailure).Inc()
+	}
+	probeConsecutiveFailures.WithLabelValues(probe).Set(float64(consecutiveFailures))
+}
+
+// result returns the result type of error, which is the same as the error type in responses.
+func result(err error) string {
+	if err == nil {
+		return resultSuccess
+	}
+	return proto.Error2Type(err)
+}
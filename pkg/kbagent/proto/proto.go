@@ -24,6 +24,8 @@ import "time"
 type Action struct {
 	Name           string       `json:"name"`
 	Exec           *ExecAction  `json:"exec,omitempty"`
+	HTTP           *HTTPAction  `json:"http,omitempty"`
+	GRPC           *GRPCAction  `json:"grpc,omitempty"`
 	TimeoutSeconds int32        `json:"timeoutSeconds,omitempty"`
 	RetryPolicy    *RetryPolicy `json:"retryPolicy,omitempty"`
 }
@@ -33,6 +35,30 @@ type ExecAction struct {
 	Args     []string `json:"args,omitempty"`
 }
 
+type HTTPAction struct {
+	Scheme             string       `json:"scheme,omitempty"`
+	Host               string       `json:"host,omitempty"`
+	Port               int32        `json:"port"`
+	Method             string       `json:"method,omitempty"`
+	Path               string       `json:"path,omitempty"`
+	Headers            []HTTPHeader `json:"headers,omitempty"`
+	Body               string       `json:"body,omitempty"`
+	InsecureSkipVerify bool         `json:"insecureSkipVerify,omitempty"`
+}
+
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type GRPCAction struct {
+	Host    string `json:"host,omitempty"`
+	Port    int32  `json:"port"`
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	Request string `json:"request,omitempty"`
+}
+
 type RetryPolicy struct {
 	MaxRetries    int           `json:"maxRetries,omitempty"`
 	RetryInterval time.Duration `json:"retryInterval,omitempty"`
@@ -44,14 +70,52 @@ type ActionRequest struct {
 	NonBlocking    *bool             `json:"nonBlocking,omitempty"`
 	TimeoutSeconds *int32            `json:"timeoutSeconds,omitempty"`
 	RetryPolicy    *RetryPolicy      `json:"retryPolicy,omitempty"`
+	// InputFrom streams the output of an action run by the kb-agent of another replica into the standard input of
+	// the action, only the exec actions are supported.
+	InputFrom *ActionInputSource `json:"inputFrom,omitempty"`
+}
+
+// ActionInputSource is an action run by the kb-agent of another replica, whose output is used as the input of action.
+// The kb-agents of a component share the token and certificates, which are used to access the source too.
+type ActionInputSource struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+	// ServerName is used to verify the hostname of the server certificate if the TLS is enabled.
+	ServerName string            `json:"serverName,omitempty"`
+	Action     string            `json:"action"`
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 type ActionResponse struct {
+	ID      string `json:"id,omitempty"`
 	Error   string `json:"error,omitempty"`
 	Message string `json:"message,omitempty"`
 	Output  []byte `json:"output,omitempty"`
 }
 
+// ActionExecution is the record of an action execution kept in the journal of kb-agent.
+type ActionExecution struct {
+	ID          string            `json:"id"`
+	Action      string            `json:"action"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	NonBlocking bool              `json:"nonBlocking,omitempty"`
+	StartTime   time.Time         `json:"startTime"`
+	EndTime     *time.Time        `json:"endTime,omitempty"`
+	ExitCode    *int32            `json:"exitCode,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Stdout      string            `json:"stdout,omitempty"`
+	Stderr      string            `json:"stderr,omitempty"`
+	// InputBytes is the number of bytes read from the input source so far, if the action has one.
+	InputBytes int64 `json:"inputBytes,omitempty"`
+}
+
+type ActionExecutionResponse struct {
+	Error      string            `json:"error,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Executions []ActionExecution `json:"executions,omitempty"`
+}
+
 // TODO: define the event spec for probe or async action
 
 type Probe struct {
@@ -64,8 +128,38 @@ type Probe struct {
 }
 
 type ProbeEvent struct {
-	Probe   string `json:"probe,omitempty"`
-	Code    int32  `json:"code,omitempty"`
-	Output  []byte `json:"output,omitempty"`
-	Message string `json:"message,omitempty"`
+	Probe   string     `json:"probe,omitempty"`
+	Code    int32      `json:"code,omitempty"`
+	Output  []byte     `json:"output,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Time    *time.Time `json:"time,omitempty"`
+}
+
+type ProbeRequest struct {
+	// Probe is the name of probe to query, all probes are returned if it is empty.
+	Probe string `json:"probe,omitempty"`
+}
+
+type ProbeResponse struct {
+	Error   string       `json:"error,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Events  []ProbeEvent `json:"events,omitempty"`
+}
+
+// Volume is a volume of the replica mounted into the kb-agent, whose usage is reported to the controller.
+type Volume struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+}
+
+type VolumeUsage struct {
+	Name       string `json:"name"`
+	UsedBytes  int64  `json:"usedBytes"`
+	TotalBytes int64  `json:"totalBytes"`
+}
+
+type VolumeResponse struct {
+	Error   string        `json:"error,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Volumes []VolumeUsage `json:"volumes,omitempty"`
 }
@@ -36,4 +36,9 @@ var (
 		Version: "v1.0",
 		URI:     "/v1.0/probe",
 	}
+	ServiceVolume = &Service{
+		Kind:    "Volume",
+		Version: "v1.0",
+		URI:     "/v1.0/volume",
+	}
 )
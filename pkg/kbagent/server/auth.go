@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+func (s *server) tlsConfig() (*tls.Config, error) {
+	if s.config.TLSCertFile == "" && s.config.TLSKeyFile == "" {
+		if s.config.TLSClientCAFile != "" {
+			return nil, errors.New("the client CA is specified but the TLS is not enabled")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(s.config.TLSCertFile, s.config.TLSKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "load TLS certificate")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if s.config.TLSClientCAFile != "" {
+		ca, err := os.ReadFile(s.config.TLSClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load TLS client CA")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificate found in the TLS client CA file %s", s.config.TLSClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+func (s *server) loadToken() ([]byte, error) {
+	if s.config.TokenFile == "" {
+		return nil, nil
+	}
+	token, err := os.ReadFile(s.config.TokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "load token")
+	}
+	token = bytes.TrimSpace(token)
+	if len(token) == 0 {
+		return nil, fmt.Errorf("the token file %s is empty", s.config.TokenFile)
+	}
+	return token, nil
+}
+
+func (s *server) tokenAuthenticator(token []byte, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	expected := append([]byte(bearerPrefix), token...)
+	return func(ctx *fasthttp.RequestCtx) {
+		// the metrics are scraped without the token
+		if string(ctx.Path()) == metricsURI {
+			next(ctx)
+			return
+		}
+		actual := ctx.Request.Header.Peek(authorizationHeader)
+		if subtle.ConstantTimeCompare(actual, expected) != 1 {
+			s.logger.Info("unauthorized request", "remote", ctx.RemoteAddr().String(), "path", string(ctx.Path()))
+			ctx.Response.SetStatusCode(fasthttp.StatusUnauthorized)
+			return
+		}
+		next(ctx)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(cn string, ca *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).Should(BeNil())
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	parent, signer := tpl, key
+	if ca == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+	} else {
+		parent, signer = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, signer)
+	Expect(err).Should(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).Should(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).Should(BeNil())
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func freePort() int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).Should(BeNil())
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+var _ = Describe("authentication", func() {
+	var (
+		dir    string
+		ca     *testCert
+		config Config
+		srv    Server
+	)
+
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, data, 0600)).Should(Succeed())
+		return path
+	}
+
+	newClient := func(creds *kbacli.Credentials) kbacli.Client {
+		cli, err := kbacli.NewClient("127.0.0.1", int32(config.Port), creds)
+		Expect(err).Should(BeNil())
+		return cli
+	}
+
+	callAction := func(cli kbacli.Client) error {
+		_, err := cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+		return err
+	}
+
+	start := func() {
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "echo",
+				Exec: &proto.ExecAction{Commands: []string{"echo", "hello"}},
+			},
+		}, nil, nil)
+		Expect(err).Should(BeNil())
+		srv = NewHTTPServer(logr.Discard(), config, services)
+		Expect(srv.StartNonBlocking()).Should(Succeed())
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", fmt.Sprint(config.Port)))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		ca = newTestCert("ca", nil)
+		config = Config{
+			Address: "127.0.0.1",
+			Port:    freePort(),
+		}
+	})
+
+	AfterEach(func() {
+		if srv != nil {
+			Expect(srv.Close()).Should(Succeed())
+			srv = nil
+		}
+	})
+
+	Context("token", func() {
+		BeforeEach(func() {
+			config.TokenFile = writeFile("token", []byte("t0ken\n"))
+			start()
+		})
+
+		It("ok", func() {
+			Expect(callAction(newClient(&kbacli.Credentials{Token: "t0ken"}))).Should(Succeed())
+		})
+
+		It("no token", func() {
+			err := callAction(newClient(nil))
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("401"))
+		})
+
+		It("metrics without token", func() {
+			rsp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", config.Port, metricsURI))
+			Expect(err).Should(BeNil())
+			defer rsp.Body.Close()
+			Expect(rsp.StatusCode).Should(Equal(http.StatusOK))
+		})
+
+		It("wrong token", func() {
+			err := callAction(newClient(&kbacli.Credentials{Token: "token"}))
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("401"))
+		})
+	})
+
+	Context("mutual TLS", func() {
+		BeforeEach(func() {
+			server := newTestCert("server", ca)
+			config.TLSCertFile = writeFile("tls.crt", server.certPEM)
+			config.TLSKeyFile = writeFile("tls.key", server.keyPEM)
+			config.TLSClientCAFile = writeFile("ca.crt", ca.certPEM)
+			config.MetricsPort = freePort()
+			start()
+		})
+
+		It("ok", func() {
+			client := newTestCert("client", ca)
+			Expect(callAction(newClient(&kbacli.Credentials{
+				CA:   ca.certPEM,
+				Cert: client.certPEM,
+				Key:  client.keyPEM,
+			}))).Should(Succeed())
+		})
+
+		It("no client certificate", func() {
+			Expect(callAction(newClient(&kbacli.Credentials{CA: ca.certPEM}))).ShouldNot(Succeed())
+		})
+
+		It("untrusted client certificate", func() {
+			client := newTestCert("client", newTestCert("another-ca", nil))
+			Expect(callAction(newClient(&kbacli.Credentials{
+				CA:   ca.certPEM,
+				Cert: client.certPEM,
+				Key:  client.keyPEM,
+			}))).ShouldNot(Succeed())
+		})
+
+		It("plain http", func() {
+			Expect(callAction(newClient(nil))).ShouldNot(Succeed())
+		})
+
+		It("metrics on the dedicated port", func() {
+			rsp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", config.MetricsPort, metricsURI))
+			Expect(err).Should(BeNil())
+			defer rsp.Body.Close()
+			Expect(rsp.StatusCode).Should(Equal(http.StatusOK))
+
+			rsp, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", config.MetricsPort, "/v1.0/action"))
+			Expect(err).Should(BeNil())
+			defer rsp.Body.Close()
+			Expect(rsp.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("misconfiguration", func() {
+		It("empty token", func() {
+			config.TokenFile = writeFile("token", []byte(" \n"))
+			services, err := service.New(logr.Discard(), nil, nil, nil)
+			Expect(err).Should(BeNil())
+			Expect(NewHTTPServer(logr.Discard(), config, services).StartNonBlocking()).ShouldNot(Succeed())
+		})
+
+		It("client CA without TLS", func() {
+			config.TLSClientCAFile = writeFile("ca.crt", ca.certPEM)
+			services, err := service.New(logr.Discard(), nil, nil, nil)
+			Expect(err).Should(BeNil())
+			Expect(NewHTTPServer(logr.Discard(), config, services).StartNonBlocking()).ShouldNot(Succeed())
+		})
+	})
+})
@@ -20,7 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package server
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -29,13 +32,30 @@ import (
 	fasthttprouter "github.com/fasthttp/router"
 	"github.com/go-logr/logr"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
 
+	"github.com/apecloud/kubeblocks/pkg/kbagent/metrics"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
 )
 
 const (
 	defaultMaxConcurrency = 8
 	jsonContentTypeHeader = "application/json"
+	queryIDParam          = "id"
+	watchURISuffix        = "/watch"
+	metricsURI            = "/metrics"
+
+	requestMethod = "request"
+	queryMethod   = "query"
+	streamMethod  = "stream"
+
+	streamContentTypeHeader = "application/octet-stream"
+
+	eventStreamContentTypeHeader = "text/event-stream"
+	// watchKeepaliveInterval is the interval to send comments to keep the watch stream alive,
+	// and to detect the subscribers gone.
+	watchKeepaliveInterval = 15 * time.Second
 )
 
 type server struct {
@@ -43,6 +63,9 @@ type server struct {
 	config   Config
 	services []service.Service
 	servers  []*fasthttp.Server
+	// ctx is canceled when the server is closed, to terminate the long-lived watch streams.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 var _ Server = &server{}
@@ -51,6 +74,8 @@ var _ Server = &server{}
 func (s *server) StartNonBlocking() error {
 	s.logger.Info("starting HTTP server")
 
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
 	// start all services first
 	for i := range s.services {
 		if err := s.services[i].Start(); err != nil {
@@ -61,10 +86,22 @@ func (s *server) StartNonBlocking() error {
 	}
 
 	handler := s.router()
+	token, err := s.loadToken()
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		handler = s.tokenAuthenticator(token, handler)
+	}
 	if s.config.Logging {
 		handler = s.apiLogger(handler)
 	}
 
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+
 	var listeners []net.Listener
 	if s.config.UnixDomainSocket != "" {
 		socket := fmt.Sprintf("%s/kbagent.socket", s.config.UnixDomainSocket)
@@ -86,31 +123,54 @@ func (s *server) StartNonBlocking() error {
 		return errors.New("no endpoint to listen on")
 	}
 
-	for _, listener := range listeners {
-		// customServer is created in a loop because each instance
-		// has a handle on the underlying listener.
-		customServer := &fasthttp.Server{
-			Handler: handler,
+	if tlsConfig != nil {
+		for i := range listeners {
+			listeners[i] = tls.NewListener(listeners[i], tlsConfig)
 		}
+		s.logger.Info("TLS enabled", "client certificate verification", tlsConfig.ClientCAs != nil)
+	}
 
-		if s.config.Concurrency > 0 {
-			customServer.Concurrency = s.config.Concurrency
-		} else {
-			customServer.Concurrency = defaultMaxConcurrency
+	if s.config.MetricsPort > 0 {
+		l, err := net.Listen("tcp", fmt.Sprintf("%s:%v", s.config.Address, s.config.MetricsPort))
+		if err != nil {
+			return err
 		}
+		s.serve(l, s.metricsRouter())
+	}
 
-		s.servers = append(s.servers, customServer)
-		go func(l net.Listener) {
-			if err := customServer.Serve(l); err != nil {
-				panic(err)
-			}
-		}(listener)
+	for _, listener := range listeners {
+		s.serve(listener, handler)
 	}
 
 	return nil
 }
 
+func (s *server) serve(listener net.Listener, handler fasthttp.RequestHandler) {
+	// customServer is created for each listener because each instance
+	// has a handle on the underlying listener.
+	customServer := &fasthttp.Server{
+		Handler: handler,
+	}
+
+	if s.config.Concurrency > 0 {
+		customServer.Concurrency = s.config.Concurrency
+	} else {
+		customServer.Concurrency = defaultMaxConcurrency
+	}
+
+	s.servers = append(s.servers, customServer)
+	go func(l net.Listener) {
+		if err := customServer.Serve(l); err != nil {
+			panic(err)
+		}
+	}(listener)
+}
+
 func (s *server) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
 	errs := make([]error, len(s.servers))
 
 	for i, ln := range s.servers {
@@ -144,12 +204,40 @@ func (s *server) router() fasthttp.RequestHandler {
 	for i := range s.services {
 		s.registerService(router, s.services[i])
 	}
+	if s.config.MetricsPort <= 0 {
+		router.Handle(fasthttp.MethodGet, metricsURI, fasthttpadaptor.NewFastHTTPHandler(metrics.Handler()))
+	}
+	return router.Handler
+}
+
+func (s *server) metricsRouter() fasthttp.RequestHandler {
+	router := fasthttprouter.New()
+	router.Handle(fasthttp.MethodGet, metricsURI, fasthttpadaptor.NewFastHTTPHandler(metrics.Handler()))
 	return router.Handler
 }
 
 func (s *server) registerService(router *fasthttprouter.Router, svc service.Service) {
 	router.Handle(fasthttp.MethodPost, svc.URI(), s.dispatcher(svc))
 	s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodPost, "uri", svc.URI())
+
+	if qs, ok := svc.(service.QueryableService); ok {
+		queryURI := fmt.Sprintf("%s/{%s}", svc.URI(), queryIDParam)
+		router.Handle(fasthttp.MethodGet, svc.URI(), s.queryDispatcher(qs))
+		router.Handle(fasthttp.MethodGet, queryURI, s.queryDispatcher(qs))
+		s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodGet, "uri", queryURI)
+	}
+
+	if ws, ok := svc.(service.WatchableService); ok {
+		watchURI := svc.URI() + watchURISuffix
+		router.Handle(fasthttp.MethodGet, watchURI, s.watchDispatcher(ws))
+		s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodGet, "uri", watchURI)
+	}
+
+	if ss, ok := svc.(service.StreamableService); ok {
+		streamURI := svc.URI() + service.StreamURISuffix
+		router.Handle(fasthttp.MethodPost, streamURI, s.streamDispatcher(ss))
+		s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodPost, "uri", streamURI)
+	}
 }
 
 func (s *server) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
@@ -157,7 +245,9 @@ func (s *server) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
 		ctx := context.Background()
 		body := reqCtx.PostBody()
 
+		start := time.Now()
 		output, err := svc.HandleRequest(ctx, body)
+		metrics.ObserveServiceRequest(svc.Kind(), requestMethod, start, responseError(output, err))
 		statusCode := fasthttp.StatusOK
 		if err != nil {
 			statusCode = fasthttp.StatusInternalServerError
@@ -166,6 +256,92 @@ func (s *server) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
 	}
 }
 
+func (s *server) queryDispatcher(svc service.QueryableService) func(*fasthttp.RequestCtx) {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		ctx := context.Background()
+		id, _ := reqCtx.UserValue(queryIDParam).(string)
+
+		start := time.Now()
+		output, err := svc.HandleQuery(ctx, id)
+		metrics.ObserveServiceRequest(svc.Kind(), queryMethod, start, responseError(output, err))
+		statusCode := fasthttp.StatusOK
+		if err != nil {
+			statusCode = fasthttp.StatusInternalServerError
+		}
+		respond(reqCtx, statusCode, output, err)
+	}
+}
+
+// watchDispatcher streams the events of service as server-sent events, each event is a JSON object in a data field.
+func (s *server) watchDispatcher(svc service.WatchableService) func(*fasthttp.RequestCtx) {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		ctx, cancel := context.WithCancel(s.ctx)
+		events, err := svc.Watch(ctx)
+		if err != nil {
+			cancel()
+			respond(reqCtx, fasthttp.StatusInternalServerError, nil, err)
+			return
+		}
+
+		reqCtx.Response.Header.SetContentType(eventStreamContentTypeHeader)
+		reqCtx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-cache")
+		reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+
+			ticker := time.NewTicker(watchKeepaliveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					fmt.Fprintf(w, "data: %s\n\n", event)
+				case <-ticker.C:
+					fmt.Fprint(w, ": keepalive\n\n")
+				}
+				if err := w.Flush(); err != nil {
+					return // the subscriber is gone
+				}
+			}
+		})
+	}
+}
+
+// streamDispatcher streams the output of request as it is produced, the request is canceled if the receiver is gone.
+func (s *server) streamDispatcher(svc service.StreamableService) func(*fasthttp.RequestCtx) {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		// the request is released after the handler returns, but the stream is written after that
+		body := append([]byte(nil), reqCtx.PostBody()...)
+
+		reqCtx.Response.Header.SetContentType(streamContentTypeHeader)
+		reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			ctx, cancel := context.WithCancel(s.ctx)
+			defer cancel()
+
+			start := time.Now()
+			err := svc.HandleStream(ctx, body, w)
+			metrics.ObserveServiceRequest(svc.Kind(), streamMethod, start, err)
+		})
+	}
+}
+
+// responseError returns the error carried in the response of service, if any.
+func responseError(output []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	rsp := struct {
+		Error string `json:"error,omitempty"`
+	}{}
+	if json.Unmarshal(output, &rsp) != nil {
+		return nil
+	}
+	return proto.Type2Error(rsp.Error)
+}
+
 func respond(ctx *fasthttp.RequestCtx, code int, body []byte, err error) {
 	ctx.Response.Header.SetContentType(jsonContentTypeHeader)
 	ctx.Response.SetStatusCode(code)
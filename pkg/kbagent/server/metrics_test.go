@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+var _ = Describe("metrics", func() {
+	var (
+		port int
+		srv  Server
+		cli  kbacli.Client
+	)
+
+	scrape := func() string {
+		rsp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, metricsURI))
+		Expect(err).Should(BeNil())
+		defer rsp.Body.Close()
+		Expect(rsp.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(rsp.Body)
+		Expect(err).Should(BeNil())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		port = freePort()
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "metricsSucceed",
+				Exec: &proto.ExecAction{Commands: []string{"echo", "hello"}},
+			},
+			{
+				Name: "metricsFail",
+				Exec: &proto.ExecAction{Commands: []string{"/bin/sh", "-c", "echo oops >&2; exit 1"}},
+			},
+			{
+				Name: "metricsProbe",
+				Exec: &proto.ExecAction{Commands: []string{"/bin/sh", "-c", "exit 1"}},
+			},
+		}, []proto.Probe{
+			{
+				Action:        "metricsProbe",
+				PeriodSeconds: 1,
+			},
+		}, nil)
+		Expect(err).Should(BeNil())
+		srv = NewHTTPServer(logr.Discard(), Config{Address: "127.0.0.1", Port: port}, services)
+		Expect(srv.StartNonBlocking()).Should(Succeed())
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", fmt.Sprint(port)))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+
+		cli, err = kbacli.NewClient("127.0.0.1", int32(port), nil)
+		Expect(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(srv.Close()).Should(Succeed())
+	})
+
+	It("actions", func() {
+		rsp, err := cli.Action(ctx, proto.ActionRequest{Action: "metricsSucceed"})
+		Expect(err).Should(BeNil())
+		Expect(rsp.Error).Should(BeEmpty())
+		rsp, err = cli.Action(ctx, proto.ActionRequest{Action: "metricsFail"})
+		Expect(err).Should(BeNil())
+		Expect(rsp.Error).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+
+		body := scrape()
+		Expect(body).Should(ContainSubstring(`kbagent_action_requests_total{action="metricsSucceed",result="success"} 1`))
+		Expect(body).Should(ContainSubstring(`kbagent_action_requests_total{action="metricsFail",result="failed"} 1`))
+		Expect(body).Should(ContainSubstring(`kbagent_action_execution_duration_seconds_count{action="metricsFail",result="failed"} 1`))
+		Expect(body).Should(ContainSubstring(`kbagent_service_requests_total{method="request",result="failed",service="Action"}`))
+	})
+
+	It("probes", func() {
+		Eventually(scrape).WithTimeout(5 * time.Second).Should(MatchRegexp(`kbagent_probe_consecutive_failures{probe="metricsProbe"} [1-9]`))
+		Expect(scrape()).Should(MatchRegexp(`kbagent_probe_results_total{probe="metricsProbe",result="failure"} [1-9]`))
+	})
+})
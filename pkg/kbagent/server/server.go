@@ -37,8 +37,21 @@ type Config struct {
 	Address          string
 	UnixDomainSocket string
 	Port             int
-	Concurrency      int
-	Logging          bool
+	// MetricsPort serves the metrics on a dedicated port if it is set, which is exempted from the TLS and
+	// authentication, otherwise the metrics are served on the port of service.
+	MetricsPort int
+	Concurrency int
+	Logging     bool
+
+	// TLSCertFile and TLSKeyFile enable the TLS for the server.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile enables the verification of client certificates, the clients must present
+	// a certificate signed by the CA.
+	TLSClientCAFile string
+	// TokenFile enables the bearer token authentication, the clients must present the token in the file.
+	// The metrics endpoint is exempted from it, but not from the mutual TLS, see MetricsPort.
+	TokenFile string
 }
 
 // NewHTTPServer returns a new HTTP server.
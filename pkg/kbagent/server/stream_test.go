@@ -0,0 +1,136 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+var _ = Describe("stream", func() {
+	var (
+		sourcePort int
+		servers    []Server
+		cli        kbacli.Client
+	)
+
+	startServer := func(actions []proto.Action) int {
+		port := freePort()
+		services, err := service.New(logr.Discard(), actions, nil, nil)
+		Expect(err).Should(BeNil())
+		srv := NewHTTPServer(logr.Discard(), Config{Address: "127.0.0.1", Port: port}, services)
+		Expect(srv.StartNonBlocking()).Should(Succeed())
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", fmt.Sprint(port)))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+		servers = append(servers, srv)
+		return port
+	}
+
+	BeforeEach(func() {
+		servers = nil
+		sourcePort = startServer([]proto.Action{
+			{
+				Name: "dataDump",
+				Exec: &proto.ExecAction{Commands: []string{"sh", "-c", "head -c 1048576 /dev/zero"}},
+			},
+			{
+				Name: "brokenDump",
+				Exec: &proto.ExecAction{Commands: []string{"sh", "-c", "echo -n partial; exit 1"}},
+			},
+		})
+		targetPort := startServer([]proto.Action{
+			{
+				Name: "dataLoad",
+				Exec: &proto.ExecAction{Commands: []string{"sh", "-c", "wc -c | tr -d ' '"}},
+			},
+		})
+
+		var err error
+		cli, err = kbacli.NewClient("127.0.0.1", int32(targetPort), nil)
+		Expect(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		for _, srv := range servers {
+			Expect(srv.Close()).Should(Succeed())
+		}
+	})
+
+	It("load the data dumped by peer", func() {
+		rsp, err := cli.Action(ctx, proto.ActionRequest{
+			Action: "dataLoad",
+			InputFrom: &proto.ActionInputSource{
+				Host:   "127.0.0.1",
+				Port:   int32(sourcePort),
+				Action: "dataDump",
+			},
+		})
+		Expect(err).Should(BeNil())
+		Expect(rsp.Error).Should(BeEmpty())
+		Expect(strings.TrimSpace(string(rsp.Output))).Should(Equal("1048576"))
+
+		execution, err := cli.ActionExecution(ctx, rsp.ID)
+		Expect(err).Should(BeNil())
+		Expect(execution.InputBytes).Should(Equal(int64(1048576)))
+	})
+
+	It("fail if the peer failed", func() {
+		rsp, err := cli.Action(ctx, proto.ActionRequest{
+			Action: "dataLoad",
+			InputFrom: &proto.ActionInputSource{
+				Host:   "127.0.0.1",
+				Port:   int32(sourcePort),
+				Action: "brokenDump",
+			},
+		})
+		Expect(err).Should(BeNil())
+		Expect(proto.Type2Error(rsp.Error)).Should(Equal(proto.ErrFailed))
+		Expect(rsp.Message).Should(ContainSubstring("brokenDump"))
+	})
+
+	It("fail if the peer action is not defined", func() {
+		rsp, err := cli.Action(ctx, proto.ActionRequest{
+			Action: "dataLoad",
+			InputFrom: &proto.ActionInputSource{
+				Host:   "127.0.0.1",
+				Port:   int32(sourcePort),
+				Action: "unknown",
+			},
+		})
+		Expect(err).Should(BeNil())
+		Expect(proto.Type2Error(rsp.Error)).Should(Equal(proto.ErrFailed))
+		Expect(rsp.Message).Should(ContainSubstring("not defined"))
+	})
+})
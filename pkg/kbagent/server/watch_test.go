@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+var _ = Describe("watch", func() {
+	var (
+		port int
+		srv  Server
+		cli  kbacli.Client
+	)
+
+	BeforeEach(func() {
+		port = freePort()
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "roleProbe",
+				Exec: &proto.ExecAction{Commands: []string{"echo", "-n", "leader"}},
+			},
+		}, []proto.Probe{
+			{
+				Action:        "roleProbe",
+				PeriodSeconds: 1,
+			},
+		}, nil)
+		Expect(err).Should(BeNil())
+		srv = NewHTTPServer(logr.Discard(), Config{Address: "127.0.0.1", Port: port}, services)
+		Expect(srv.StartNonBlocking()).Should(Succeed())
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", fmt.Sprint(port)))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+
+		cli, err = kbacli.NewClient("127.0.0.1", int32(port), nil)
+		Expect(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(srv.Close()).Should(Succeed())
+	})
+
+	It("stream probe events", func() {
+		events, err := cli.WatchProbeEvents(ctx)
+		Expect(err).Should(BeNil())
+
+		event := proto.ProbeEvent{}
+		Eventually(events).WithTimeout(5 * time.Second).Should(Receive(&event))
+		Expect(event.Probe).Should(Equal("roleProbe"))
+		Expect(event.Output).Should(Equal([]byte("leader")))
+
+		latest, err := cli.ProbeEvents(ctx, "roleProbe")
+		Expect(err).Should(BeNil())
+		Expect(latest).Should(HaveLen(1))
+		Expect(latest[0].Output).Should(Equal([]byte("leader")))
+	})
+
+	It("stream closed with server", func() {
+		events, err := cli.WatchProbeEvents(ctx)
+		Expect(err).Should(BeNil())
+		Eventually(events).WithTimeout(5 * time.Second).Should(Receive())
+
+		Expect(srv.Close()).Should(Succeed())
+		Eventually(events).Should(BeClosed())
+	})
+})
@@ -20,23 +20,35 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/util/rand"
 
+	"github.com/apecloud/kubeblocks/pkg/kbagent/metrics"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
 
 func newActionService(logger logr.Logger, actions []proto.Action) (*actionService, error) {
+	j, err := newJournal(logger.WithName("journal"))
+	if err != nil {
+		return nil, err
+	}
 	sa := &actionService{
 		logger:         logger,
 		actions:        make(map[string]*proto.Action),
+		journal:        j,
 		mutex:          sync.Mutex{},
 		runningActions: map[string]*runningAction{},
 	}
@@ -50,16 +62,20 @@ func newActionService(logger logr.Logger, actions []proto.Action) (*actionServic
 type actionService struct {
 	logger  logr.Logger
 	actions map[string]*proto.Action
+	journal *journal
 
 	mutex          sync.Mutex
 	runningActions map[string]*runningAction
 }
 
 type runningAction struct {
-	resultChan chan *commandResult
+	id     string
+	done   chan struct{}
+	result *commandResult
 }
 
-var _ Service = &actionService{}
+var _ QueryableService = &actionService{}
+var _ StreamableService = &actionService{}
 
 func (s *actionService) Kind() string {
 	return proto.ServiceAction.Kind
@@ -76,9 +92,25 @@ func (s *actionService) Start() error {
 func (s *actionService) HandleRequest(ctx context.Context, payload []byte) ([]byte, error) {
 	req, err := s.decode(payload)
 	if err != nil {
-		return s.encode(nil, err), nil
+		return s.encode("", nil, err), nil
 	}
-	return s.encode(s.handleRequest(ctx, req)), nil
+	id, output, err := s.handleJournaledRequest(ctx, req)
+	metrics.ObserveActionRequest(req.Action, err)
+	return s.encode(id, output, err), nil
+}
+
+func (s *actionService) HandleQuery(ctx context.Context, id string) ([]byte, error) {
+	rsp := &proto.ActionExecutionResponse{}
+	if len(id) == 0 {
+		rsp.Executions = s.journal.list()
+	} else if execution := s.journal.get(id); execution != nil {
+		rsp.Executions = []proto.ActionExecution{*execution}
+	} else {
+		err := errors.Wrapf(proto.ErrNotDefined, "action execution %s is not found", id)
+		rsp.Error = proto.Error2Type(err)
+		rsp.Message = err.Error()
+	}
+	return json.Marshal(rsp)
 }
 
 func (s *actionService) decode(payload []byte) (*proto.ActionRequest, error) {
@@ -89,8 +121,8 @@ func (s *actionService) decode(payload []byte) (*proto.ActionRequest, error) {
 	return req, nil
 }
 
-func (s *actionService) encode(out []byte, err error) []byte {
-	rsp := &proto.ActionResponse{}
+func (s *actionService) encode(id string, out []byte, err error) []byte {
+	rsp := &proto.ActionResponse{ID: id}
 	if err == nil {
 		rsp.Output = out
 	} else {
@@ -101,46 +133,175 @@ func (s *actionService) encode(out []byte, err error) []byte {
 	return data
 }
 
+// handleRequest handles the request without recording it in the journal, it is used by the probes.
 func (s *actionService) handleRequest(ctx context.Context, req *proto.ActionRequest) ([]byte, error) {
-	if _, ok := s.actions[req.Action]; !ok {
-		return nil, errors.Wrapf(proto.ErrNotDefined, "%s is not defined", req.Action)
-	}
-	action := s.actions[req.Action]
-	if action.Exec == nil {
-		return nil, errors.Wrap(proto.ErrNotImplemented, "only exec action is supported")
+	action, err := s.checkAction(req)
+	if err != nil {
+		return nil, err
 	}
-	return s.handleExecAction(ctx, req, action)
+	return runAction(ctx, action, req.Parameters, req.TimeoutSeconds)
 }
 
-func (s *actionService) handleExecAction(ctx context.Context, req *proto.ActionRequest, action *proto.Action) ([]byte, error) {
+func (s *actionService) handleJournaledRequest(ctx context.Context, req *proto.ActionRequest) (string, []byte, error) {
+	action, err := s.checkAction(req)
+	if err != nil {
+		return "", nil, err
+	}
 	if req.NonBlocking == nil || !*req.NonBlocking {
-		return runCommand(ctx, action.Exec, req.Parameters, req.TimeoutSeconds)
+		id := rand.String(16)
+		s.journal.start(id, req)
+		result, err := s.runJournaledAction(ctx, id, action, req)
+		if err != nil {
+			s.journal.finish(id, &commandResult{}, err)
+			return id, nil, err
+		}
+		output, err := result.output()
+		s.journal.finish(id, result, err)
+		return id, output, err
+	}
+	return s.handleRequestNonBlocking(ctx, req, action)
+}
+
+func (s *actionService) checkAction(req *proto.ActionRequest) (*proto.Action, error) {
+	action, ok := s.actions[req.Action]
+	if !ok {
+		return nil, errors.Wrapf(proto.ErrNotDefined, "%s is not defined", req.Action)
+	}
+	if action.Exec == nil && action.HTTP == nil && action.GRPC == nil {
+		return nil, errors.Wrap(proto.ErrNotImplemented, "only exec, http and grpc actions are supported")
 	}
-	return s.handleExecActionNonBlocking(ctx, req, action)
+	return action, nil
 }
 
-func (s *actionService) handleExecActionNonBlocking(ctx context.Context, req *proto.ActionRequest, action *proto.Action) ([]byte, error) {
+// handleRequestNonBlocking runs the action asynchronously.
+// The requests of the same action with the same parameters are coalesced into one run, which is identified by an id.
+// The result of the run is returned to the first request after it has finished,
+// and it is also kept in the journal, which can be queried by the id.
+func (s *actionService) handleRequestNonBlocking(ctx context.Context, req *proto.ActionRequest, action *proto.Action) (string, []byte, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	running, ok := s.runningActions[req.Action]
+	key := runningActionKey(req)
+	running, ok := s.runningActions[key]
 	if !ok {
-		resultChan, err := runCommandNonBlocking(ctx, action.Exec, req.Parameters, req.TimeoutSeconds)
+		id := rand.String(16)
+		resultChan, err := s.runJournaledActionNonBlocking(ctx, id, action, req)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		running = &runningAction{
-			resultChan: resultChan,
+			id:   id,
+			done: make(chan struct{}),
 		}
-		s.runningActions[req.Action] = running
+		s.journal.start(running.id, req)
+		go func() {
+			result := <-resultChan
+			_, err := result.output()
+			s.journal.finish(running.id, result, err)
+			running.result = result
+			close(running.done)
+		}()
+		s.runningActions[key] = running
+	}
+	select {
+	case <-running.done:
+	default:
+		return running.id, nil, proto.ErrInProgress
 	}
-	result := gather(running.resultChan)
-	if result == nil {
-		return nil, proto.ErrInProgress
+	delete(s.runningActions, key)
+	output, err := running.result.output()
+	return running.id, output, err
+}
+
+func runningActionKey(req *proto.ActionRequest) string {
+	keys := maps.Keys(req.Parameters)
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(req.Parameters[k]))
+		h.Write([]byte{0})
 	}
-	delete(s.runningActions, req.Action)
-	if (*result).err != nil {
-		return nil, (*result).err
+	if req.InputFrom != nil {
+		// the runs with different input sources are not coalesced
+		h.Write([]byte(fmt.Sprintf("%s:%d/%s", req.InputFrom.Host, req.InputFrom.Port, req.InputFrom.Action)))
 	}
-	return (*result).stdout.Bytes(), nil
+	return fmt.Sprintf("%s-%s", req.Action, hex.EncodeToString(h.Sum(nil)))
+}
+
+func (s *actionService) runJournaledAction(ctx context.Context, id string, action *proto.Action, req *proto.ActionRequest) (*commandResult, error) {
+	resultChan, err := s.runJournaledActionNonBlocking(ctx, id, action, req)
+	if err != nil {
+		return nil, err
+	}
+	return <-resultChan, nil
+}
+
+func (s *actionService) runJournaledActionNonBlocking(ctx context.Context, id string, action *proto.Action, req *proto.ActionRequest) (chan *commandResult, error) {
+	if req.InputFrom != nil {
+		return s.runActionWithInput(ctx, id, action, req)
+	}
+	return runActionNonBlocking(ctx, action, req.Parameters, req.TimeoutSeconds)
+}
+
+func runAction(ctx context.Context, action *proto.Action, parameters map[string]string, timeout *int32) ([]byte, error) {
+	result, err := runActionX(ctx, action, parameters, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return result.output()
+}
+
+func runActionX(ctx context.Context, action *proto.Action, parameters map[string]string, timeout *int32) (*commandResult, error) {
+	resultChan, err := runActionNonBlocking(ctx, action, parameters, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return <-resultChan, nil
+}
+
+func runActionNonBlocking(ctx context.Context, action *proto.Action, parameters map[string]string, timeout *int32) (chan *commandResult, error) {
+	start := time.Now()
+	resultChan, err := dispatchAction(ctx, action, parameters, timeout)
+	if err != nil {
+		metrics.ObserveActionExecution(action.Name, start, err)
+		return nil, err
+	}
+	observedChan := make(chan *commandResult, 1)
+	go func() {
+		result := <-resultChan
+		_, err := result.output()
+		metrics.ObserveActionExecution(action.Name, start, err)
+		observedChan <- result
+	}()
+	return observedChan, nil
+}
+
+func dispatchAction(ctx context.Context, action *proto.Action, parameters map[string]string, timeout *int32) (chan *commandResult, error) {
+	var run func(context.Context) ([]byte, error)
+	switch {
+	case action.Exec != nil:
+		return runCommandNonBlocking(ctx, action.Exec, parameters, timeout)
+	case action.HTTP != nil:
+		run = func(ctx context.Context) ([]byte, error) {
+			return runHTTP(ctx, action.HTTP, parameters, timeout)
+		}
+	case action.GRPC != nil:
+		run = func(ctx context.Context) ([]byte, error) {
+			return runGRPC(ctx, action.GRPC, parameters, timeout)
+		}
+	default:
+		return nil, errors.Wrapf(proto.ErrNotImplemented, "action %s has no handler defined", action.Name)
+	}
+	resultChan := make(chan *commandResult, 1)
+	go func() {
+		output, err := run(ctx)
+		resultChan <- &commandResult{
+			err:    err,
+			stdout: bytes.NewBuffer(output),
+			stderr: bytes.NewBuffer(nil),
+		}
+	}()
+	return resultChan, nil
 }
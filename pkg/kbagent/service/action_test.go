@@ -20,10 +20,139 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"encoding/json"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
 
 var _ = Describe("action", func() {
+	newService := func() *actionService {
+		actions := []proto.Action{
+			{
+				Name: "echo",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "sleep 0.2; echo -n $PARAM"},
+				},
+			},
+			{
+				Name: "fail",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "echo -n failed >&2; exit 2"},
+				},
+			},
+		}
+		s, err := newActionService(logr.New(nil), actions)
+		Expect(err).Should(BeNil())
+		return s
+	}
+
+	handle := func(s *actionService, req *proto.ActionRequest) proto.ActionResponse {
+		payload, err := json.Marshal(req)
+		Expect(err).Should(BeNil())
+		data, err := s.HandleRequest(ctx, payload)
+		Expect(err).Should(BeNil())
+		rsp := proto.ActionResponse{}
+		Expect(json.Unmarshal(data, &rsp)).Should(Succeed())
+		return rsp
+	}
+
+	query := func(s *actionService, id string) proto.ActionExecutionResponse {
+		data, err := s.HandleQuery(ctx, id)
+		Expect(err).Should(BeNil())
+		rsp := proto.ActionExecutionResponse{}
+		Expect(json.Unmarshal(data, &rsp)).Should(Succeed())
+		return rsp
+	}
+
 	Context("action", func() {
+		It("not defined", func() {
+			s := newService()
+			rsp := handle(s, &proto.ActionRequest{Action: "not-defined"})
+			Expect(rsp.Error).Should(Equal(proto.Error2Type(proto.ErrNotDefined)))
+		})
+
+		It("blocking", func() {
+			s := newService()
+			rsp := handle(s, &proto.ActionRequest{Action: "echo", Parameters: map[string]string{"PARAM": "blocking"}})
+			Expect(rsp.Error).Should(BeEmpty())
+			Expect(rsp.ID).ShouldNot(BeEmpty())
+			Expect(string(rsp.Output)).Should(Equal("blocking"))
+
+			result := query(s, rsp.ID)
+			Expect(result.Executions).Should(HaveLen(1))
+			Expect(result.Executions[0].EndTime).ShouldNot(BeNil())
+			Expect(*result.Executions[0].ExitCode).Should(Equal(int32(0)))
+			Expect(result.Executions[0].Stdout).Should(Equal("blocking"))
+		})
+
+		It("failed", func() {
+			s := newService()
+			rsp := handle(s, &proto.ActionRequest{Action: "fail"})
+			Expect(rsp.Error).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+
+			result := query(s, rsp.ID)
+			Expect(result.Executions).Should(HaveLen(1))
+			Expect(*result.Executions[0].ExitCode).Should(Equal(int32(2)))
+			Expect(result.Executions[0].Stderr).Should(Equal("failed"))
+			Expect(result.Executions[0].Error).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+		})
+
+		It("non-blocking", func() {
+			s := newService()
+			nonBlocking := true
+			req1 := &proto.ActionRequest{Action: "echo", Parameters: map[string]string{"PARAM": "first"}, NonBlocking: &nonBlocking}
+			req2 := &proto.ActionRequest{Action: "echo", Parameters: map[string]string{"PARAM": "second"}, NonBlocking: &nonBlocking}
+
+			rsp1 := handle(s, req1)
+			Expect(rsp1.Error).Should(Equal(proto.Error2Type(proto.ErrInProgress)))
+			rsp2 := handle(s, req2)
+			Expect(rsp2.Error).Should(Equal(proto.Error2Type(proto.ErrInProgress)))
+			Expect(rsp1.ID).ShouldNot(Equal(rsp2.ID))
+
+			// the same request is coalesced into the running one
+			Expect(handle(s, req1).ID).Should(Equal(rsp1.ID))
+
+			Eventually(func() string {
+				return string(handle(s, req1).Output)
+			}).WithTimeout(5 * time.Second).Should(Equal("first"))
+			Eventually(func() string {
+				return string(handle(s, req2).Output)
+			}).WithTimeout(5 * time.Second).Should(Equal("second"))
+
+			result := query(s, rsp1.ID)
+			Expect(result.Executions).Should(HaveLen(1))
+			Expect(result.Executions[0].NonBlocking).Should(BeTrue())
+			Expect(result.Executions[0].Parameters).Should(HaveKeyWithValue("PARAM", "first"))
+		})
+
+		It("query not found", func() {
+			s := newService()
+			rsp := query(s, "not-found")
+			Expect(proto.Type2Error(rsp.Error)).Should(Equal(proto.ErrNotDefined))
+		})
+
+		It("list", func() {
+			s := newService()
+			handle(s, &proto.ActionRequest{Action: "echo"})
+			handle(s, &proto.ActionRequest{Action: "fail"})
+			rsp := query(s, "")
+			Expect(rsp.Executions).Should(HaveLen(2))
+			Expect(rsp.Executions[0].Action).Should(Equal("echo"))
+			Expect(rsp.Executions[1].Action).Should(Equal("fail"))
+		})
+
+		It("probe is not journaled", func() {
+			s := newService()
+			_, err := s.handleRequest(ctx, &proto.ActionRequest{Action: "fail"})
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+			Expect(query(s, "").Executions).Should(BeEmpty())
+		})
 	})
 })
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
@@ -39,9 +40,10 @@ const (
 )
 
 type commandResult struct {
-	err    error
-	stdout *bytes.Buffer
-	stderr *bytes.Buffer
+	err      error
+	exitCode *int32
+	stdout   *bytes.Buffer
+	stderr   *bytes.Buffer
 }
 
 func gather[T interface{}](ch chan T) *T {
@@ -61,12 +63,15 @@ func runCommand(ctx context.Context, action *proto.ExecAction, parameters map[st
 	if err != nil {
 		return nil, err
 	}
-	result := <-resultChan
-	err = result.err
+	return (<-resultChan).output()
+}
+
+func (r *commandResult) output() ([]byte, error) {
+	err := r.err
 	if err != nil {
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
-			stderrMsg := result.stderr.String()
+			stderrMsg := r.stderr.String()
 			if len(stderrMsg) > 0 {
 				err = errors.Wrapf(proto.ErrFailed, "exec exit %d and stderr: %s", exitErr.ExitCode(), stderrMsg)
 			} else {
@@ -75,7 +80,7 @@ func runCommand(ctx context.Context, action *proto.ExecAction, parameters map[st
 		}
 		return nil, err
 	}
-	return result.stdout.Bytes(), nil
+	return r.stdout.Bytes(), nil
 }
 
 func runCommandNonBlocking(ctx context.Context, action *proto.ExecAction, parameters map[string]string, timeout *int32) (chan *commandResult, error) {
@@ -92,10 +97,18 @@ func runCommandNonBlocking(ctx context.Context, action *proto.ExecAction, parame
 		if !ok {
 			execErr = errors.New("runtime error: error chan closed unexpectedly")
 		}
+		var exitCode *int32
+		var exitErr *exec.ExitError
+		if execErr == nil {
+			exitCode = ptr.To(int32(0))
+		} else if errors.As(execErr, &exitErr) {
+			exitCode = ptr.To(int32(exitErr.ExitCode()))
+		}
 		resultChan <- &commandResult{
-			err:    execErr,
-			stdout: stdoutBuf,
-			stderr: stderrBuf,
+			err:      execErr,
+			exitCode: exitCode,
+			stdout:   stdoutBuf,
+			stderr:   stderrBuf,
 		}
 	}()
 	return resultChan, nil
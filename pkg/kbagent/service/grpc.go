@@ -0,0 +1,210 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func runGRPC(ctx context.Context, action *proto.GRPCAction, parameters map[string]string, timeout *int32) ([]byte, error) {
+	request, err := renderTemplate(action.Request, templateVars(parameters))
+	if err != nil {
+		return nil, err
+	}
+
+	var timeoutCancel context.CancelFunc
+	if timeout != nil && *timeout > 0 {
+		ctx, timeoutCancel = context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
+		defer timeoutCancel()
+	}
+
+	host := action.Host
+	if host == "" {
+		host = defaultActionHost
+	}
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(host, fmt.Sprintf("%d", action.Port)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrFailed, "dial grpc server error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	method, err := resolveGRPCMethod(ctx, conn, action.Service, action.Method)
+	if err != nil {
+		return nil, grpcError(ctx, err, "resolve grpc method")
+	}
+
+	req := dynamicpb.NewMessage(method.Input())
+	if len(request) > 0 {
+		if err = protojson.Unmarshal([]byte(request), req); err != nil {
+			return nil, errors.Wrapf(proto.ErrBadRequest, "decode grpc request error: %s", err.Error())
+		}
+	}
+	rsp := dynamicpb.NewMessage(method.Output())
+	fullMethod := fmt.Sprintf("/%s/%s", action.Service, action.Method)
+	if err = conn.Invoke(ctx, fullMethod, req, rsp); err != nil {
+		return nil, grpcError(ctx, err, "call grpc method")
+	}
+	output, err := protojson.Marshal(rsp)
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrInternalError, "encode grpc response error: %s", err.Error())
+	}
+	return output, nil
+}
+
+// resolveGRPCMethod resolves the method descriptor through the server reflection service.
+func resolveGRPCMethod(ctx context.Context, conn *grpc.ClientConn, service, method string) (protoreflect.MethodDescriptor, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	request := func(req *reflectionpb.ServerReflectionRequest) ([][]byte, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+		rsp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if errRsp := rsp.GetErrorResponse(); errRsp != nil {
+			return nil, status.Error(codes.Code(errRsp.ErrorCode), errRsp.ErrorMessage)
+		}
+		return rsp.GetFileDescriptorResponse().GetFileDescriptorProto(), nil
+	}
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	collect := func(data [][]byte) error {
+		for _, d := range data {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := gproto.Unmarshal(d, fd); err != nil {
+				return err
+			}
+			files[fd.GetName()] = fd
+		}
+		return nil
+	}
+
+	data, err := request(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = collect(data); err != nil {
+		return nil, err
+	}
+
+	// fetch the dependencies which are not sent along by the server
+	for pending := true; pending; {
+		pending = false
+		for _, fd := range files {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := files[dep]; ok {
+					continue
+				}
+				if gfd, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					files[dep] = protodesc.ToFileDescriptorProto(gfd)
+					pending = true
+					continue
+				}
+				data, err = request(&reflectionpb.ServerReflectionRequest{
+					MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+				})
+				if err != nil {
+					return nil, err
+				}
+				if err = collect(data); err != nil {
+					return nil, err
+				}
+				pending = true
+			}
+		}
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range files {
+		fds.File = append(fds.File, fd)
+	}
+	registry, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := registry.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "service %s is not found", service)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, status.Errorf(codes.Unimplemented, "method %s is not found in service %s", method, service)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, status.Errorf(codes.InvalidArgument, "streaming method %s is not supported", method)
+	}
+	return md, nil
+}
+
+func grpcError(ctx context.Context, err error, msg string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return proto.ErrTimedOut
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return errors.Wrapf(proto.ErrFailed, "%s error: %s", msg, err.Error())
+	}
+	var e error
+	switch s.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		e = proto.ErrBadRequest
+	case codes.Unimplemented:
+		e = proto.ErrNotImplemented
+	case codes.DeadlineExceeded:
+		e = proto.ErrTimedOut
+	case codes.ResourceExhausted, codes.Aborted:
+		e = proto.ErrBusy
+	default:
+		e = proto.ErrFailed
+	}
+	return errors.Wrapf(e, "%s error: %s", msg, s.Message())
+}
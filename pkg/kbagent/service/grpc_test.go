@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("grpc", func() {
+	var (
+		server *grpc.Server
+		port   int32
+	)
+
+	BeforeEach(func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).Should(BeNil())
+		port = int32(lis.Addr().(*net.TCPAddr).Port)
+
+		server = grpc.NewServer()
+		hs := health.NewServer()
+		hs.SetServingStatus("mysql", healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(server, hs)
+		reflection.Register(server)
+		go func() {
+			_ = server.Serve(lis)
+		}()
+	})
+
+	AfterEach(func() {
+		server.Stop()
+	})
+
+	Context("runGRPC", func() {
+		It("simple", func() {
+			action := &proto.GRPCAction{
+				Port:    port,
+				Service: "grpc.health.v1.Health",
+				Method:  "Check",
+				Request: `{"service": "{{ .SERVICE }}"}`,
+			}
+			output, err := runGRPC(ctx, action, map[string]string{"SERVICE": "mysql"}, nil)
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(MatchJSON(`{"status": "NOT_SERVING"}`))
+		})
+
+		It("error status", func() {
+			action := &proto.GRPCAction{
+				Port:    port,
+				Service: "grpc.health.v1.Health",
+				Method:  "Check",
+				Request: `{"service": "unknown"}`,
+			}
+			_, err := runGRPC(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+		})
+
+		It("method not found", func() {
+			action := &proto.GRPCAction{
+				Port:    port,
+				Service: "grpc.health.v1.Health",
+				Method:  "NotExist",
+			}
+			_, err := runGRPC(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrNotImplemented)).Should(BeTrue())
+		})
+
+		It("streaming method", func() {
+			action := &proto.GRPCAction{
+				Port:    port,
+				Service: "grpc.health.v1.Health",
+				Method:  "Watch",
+			}
+			_, err := runGRPC(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrBadRequest)).Should(BeTrue())
+		})
+
+		It("bad request", func() {
+			action := &proto.GRPCAction{
+				Port:    port,
+				Service: "grpc.health.v1.Health",
+				Method:  "Check",
+				Request: `{"unknown": "field"}`,
+			}
+			_, err := runGRPC(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrBadRequest)).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+const (
+	defaultActionHost   = "127.0.0.1"
+	defaultHTTPScheme   = "http"
+	maxHTTPResponseSize = 4 * 1024 * 1024
+)
+
+var (
+	// the clients are shared by all the HTTP actions, so the idle connections are reused instead of leaked.
+	httpActionClient = &http.Client{
+		Transport: newHTTPActionTransport(false),
+	}
+	insecureHTTPActionClient = &http.Client{
+		Transport: newHTTPActionTransport(true),
+	}
+)
+
+func newHTTPActionTransport(insecureSkipVerify bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 4
+	transport.IdleConnTimeout = 30 * time.Second
+	if insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return transport
+}
+
+func runHTTP(ctx context.Context, action *proto.HTTPAction, parameters map[string]string, timeout *int32) ([]byte, error) {
+	req, err := buildHTTPRequest(ctx, action, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeoutCancel context.CancelFunc
+	if timeout != nil && *timeout > 0 {
+		ctx, timeoutCancel = context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
+		defer timeoutCancel()
+	}
+
+	cli := httpActionClient
+	if action.InsecureSkipVerify {
+		cli = insecureHTTPActionClient
+	}
+	rsp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, proto.ErrTimedOut
+		}
+		return nil, errors.Wrapf(proto.ErrFailed, "http request error: %s", err.Error())
+	}
+	defer rsp.Body.Close()
+
+	// read one more byte than the limit to tell the too large response from the one of the limit size,
+	// a truncated response should not be taken as the output of the action.
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, maxHTTPResponseSize+1))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, proto.ErrTimedOut
+		}
+		return nil, errors.Wrapf(proto.ErrFailed, "read http response error: %s", err.Error())
+	}
+	if len(body) > maxHTTPResponseSize {
+		return nil, errors.Wrapf(proto.ErrFailed, "http response too large, exceeds %d bytes", maxHTTPResponseSize)
+	}
+	if err = httpStatus2Error(rsp.StatusCode); err != nil {
+		if len(body) > 0 {
+			return nil, errors.Wrapf(err, "http status %d and body: %s", rsp.StatusCode, string(body))
+		}
+		return nil, errors.Wrapf(err, "http status %d but body is blank", rsp.StatusCode)
+	}
+	return body, nil
+}
+
+func buildHTTPRequest(ctx context.Context, action *proto.HTTPAction, parameters map[string]string) (*http.Request, error) {
+	vars := templateVars(parameters)
+
+	path, err := renderTemplate(action.Path, vars)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderTemplate(action.Body, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := strings.ToLower(action.Scheme)
+	if scheme == "" {
+		scheme = defaultHTTPScheme
+	}
+	host := action.Host
+	if host == "" {
+		host = defaultActionHost
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	method := strings.ToUpper(action.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, fmt.Sprintf("%d", action.Port)), path)
+
+	var bodyReader io.Reader
+	if len(body) > 0 {
+		bodyReader = bytes.NewBufferString(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrBadRequest, "build http request error: %s", err.Error())
+	}
+	for _, h := range action.Headers {
+		value, err := renderTemplate(h.Value, vars)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(h.Name, "Host") {
+			req.Host = value
+		} else {
+			req.Header.Add(h.Name, value)
+		}
+	}
+	return req, nil
+}
+
+func httpStatus2Error(code int) error {
+	switch {
+	case code == http.StatusAccepted:
+		return proto.ErrInProgress
+	case code >= 200 && code < 300:
+		return nil
+	case code == http.StatusBadRequest:
+		return proto.ErrBadRequest
+	case code == http.StatusNotImplemented:
+		return proto.ErrNotImplemented
+	case code == http.StatusRequestTimeout, code == http.StatusGatewayTimeout:
+		return proto.ErrTimedOut
+	case code == http.StatusConflict, code == http.StatusTooManyRequests, code == http.StatusServiceUnavailable:
+		return proto.ErrBusy
+	default:
+		return proto.ErrFailed
+	}
+}
+
+// templateVars returns the variables available to templates of HTTP and gRPC actions,
+// which is the same as the environment variables of the exec actions.
+func templateVars(parameters map[string]string) map[string]string {
+	vars := util.EnvL2M(os.Environ())
+	for k, v := range parameters {
+		vars[k] = v
+	}
+	return vars
+}
+
+func renderTemplate(tpl string, vars map[string]string) (string, error) {
+	if !strings.Contains(tpl, "{{") {
+		return tpl, nil
+	}
+	t, err := template.New("action").Option("missingkey=zero").Parse(tpl)
+	if err != nil {
+		return "", errors.Wrapf(proto.ErrBadRequest, "parse template error: %s", err.Error())
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, vars); err != nil {
+		return "", errors.Wrapf(proto.ErrBadRequest, "render template error: %s", err.Error())
+	}
+	return buf.String(), nil
+}
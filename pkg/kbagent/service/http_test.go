@@ -0,0 +1,188 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("http", func() {
+	var (
+		server *httptest.Server
+		port   int32
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/echo":
+				body, _ := io.ReadAll(r.Body)
+				_, _ = w.Write([]byte(r.Method + " " + r.Header.Get("X-Role") + " " + string(body)))
+			case "/busy":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case "/accepted":
+				w.WriteHeader(http.StatusAccepted)
+			case "/large":
+				size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+				_, _ = w.Write([]byte(strings.Repeat("x", size)))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("internal error"))
+			}
+		}))
+		_, p, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+		Expect(err).Should(BeNil())
+		pp, err := strconv.Atoi(p)
+		Expect(err).Should(BeNil())
+		port = int32(pp)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("runHTTP", func() {
+		It("simple", func() {
+			action := &proto.HTTPAction{
+				Port:   port,
+				Method: http.MethodPost,
+				Path:   "/echo",
+				Headers: []proto.HTTPHeader{
+					{Name: "X-Role", Value: "{{ .ROLE }}"},
+				},
+				Body: `{"candidate": "{{ .CANDIDATE }}"}`,
+			}
+			parameters := map[string]string{
+				"ROLE":      "leader",
+				"CANDIDATE": "pod-1",
+			}
+			output, err := runHTTP(ctx, action, parameters, nil)
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(Equal(`POST leader {"candidate": "pod-1"}`))
+		})
+
+		It("default method", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "echo",
+			}
+			output, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(HavePrefix(http.MethodGet))
+		})
+
+		It("fail", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "/fail",
+			}
+			_, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring("internal error"))
+		})
+
+		It("busy", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "/busy",
+			}
+			_, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrBusy)).Should(BeTrue())
+		})
+
+		It("in progress", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "/accepted",
+			}
+			_, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrInProgress)).Should(BeTrue())
+		})
+
+		It("response too large", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "/large?size=" + strconv.Itoa(maxHTTPResponseSize),
+			}
+			output, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).Should(BeNil())
+			Expect(output).Should(HaveLen(maxHTTPResponseSize))
+
+			action.Path = "/large?size=" + strconv.Itoa(maxHTTPResponseSize+1)
+			_, err = runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring("response too large"))
+		})
+
+		It("https", func() {
+			tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer tlsServer.Close()
+			_, p, err := net.SplitHostPort(strings.TrimPrefix(tlsServer.URL, "https://"))
+			Expect(err).Should(BeNil())
+			pp, err := strconv.Atoi(p)
+			Expect(err).Should(BeNil())
+
+			By("verify the certificate of the server by default")
+			action := &proto.HTTPAction{
+				Scheme: "HTTPS",
+				Port:   int32(pp),
+				Path:   "/",
+			}
+			_, err = runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+
+			By("skip verifying the certificate")
+			action.InsecureSkipVerify = true
+			output, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(Equal("ok"))
+		})
+
+		It("bad template", func() {
+			action := &proto.HTTPAction{
+				Port: port,
+				Path: "/echo",
+				Body: "{{ .CANDIDATE ",
+			}
+			_, err := runHTTP(ctx, action, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrBadRequest)).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,261 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/maps"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// JournalDirKey is the config key of the directory to persist the journal of action executions.
+	// The journal is kept in memory only if it is not set.
+	JournalDirKey = "journal-dir"
+	// JournalMaxEntriesKey is the config key of the maximum number of action executions kept in the journal.
+	JournalMaxEntriesKey = "journal-max-entries"
+
+	DefaultJournalMaxEntries = 128
+
+	maxJournalOutputSize = 4096
+	journalFileSuffix    = ".json"
+	redactedValue        = "******"
+)
+
+// journal keeps a bounded history of action executions, the oldest entries are evicted first.
+type journal struct {
+	logger     logr.Logger
+	dir        string
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries []*proto.ActionExecution
+}
+
+func newJournal(logger logr.Logger) (*journal, error) {
+	j := &journal{
+		logger:     logger,
+		dir:        viper.GetString(JournalDirKey),
+		maxEntries: viper.GetInt(JournalMaxEntriesKey),
+		entries:    make([]*proto.ActionExecution, 0),
+	}
+	if j.maxEntries <= 0 {
+		j.maxEntries = DefaultJournalMaxEntries
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *journal) load() error {
+	if len(j.dir) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(j.dir, "*"+journalFileSuffix))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		entry := &proto.ActionExecution{}
+		if err = json.Unmarshal(data, entry); err != nil {
+			j.logger.Error(err, "ignore the corrupted journal entry", "file", file)
+			_ = os.Remove(file)
+			continue
+		}
+		if entry.EndTime == nil {
+			// the execution was interrupted by the restart of kb-agent
+			now := time.Now()
+			entry.EndTime = &now
+			entry.Error = proto.Error2Type(proto.ErrInternalError)
+			entry.Message = "the execution was interrupted by the restart of kb-agent"
+			j.persist(entry)
+		}
+		j.entries = append(j.entries, entry)
+	}
+	sort.SliceStable(j.entries, func(i, k int) bool {
+		return j.entries[i].StartTime.Before(j.entries[k].StartTime)
+	})
+	j.evict()
+	return nil
+}
+
+func (j *journal) start(id string, req *proto.ActionRequest) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry := &proto.ActionExecution{
+		ID:          id,
+		Action:      req.Action,
+		Parameters:  redactParameters(req.Parameters),
+		NonBlocking: req.NonBlocking != nil && *req.NonBlocking,
+		StartTime:   time.Now(),
+	}
+	j.entries = append(j.entries, entry)
+	j.persist(entry)
+	j.evict()
+}
+
+func (j *journal) finish(id string, result *commandResult, err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, entry := range j.entries {
+		if entry.ID != id {
+			continue
+		}
+		now := time.Now()
+		entry.EndTime = &now
+		entry.ExitCode = result.exitCode
+		if result.stdout != nil {
+			entry.Stdout = truncate(result.stdout.String())
+		}
+		if result.stderr != nil {
+			entry.Stderr = truncate(result.stderr.String())
+		}
+		if err != nil {
+			entry.Error = proto.Error2Type(err)
+			entry.Message = err.Error()
+		}
+		j.persist(entry)
+		return
+	}
+}
+
+// progress updates the number of bytes read from the input source, it is kept in memory until the execution finishes.
+func (j *journal) progress(id string, inputBytes int64) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, entry := range j.entries {
+		if entry.ID == id {
+			entry.InputBytes = inputBytes
+			return
+		}
+	}
+}
+
+func (j *journal) get(id string) *proto.ActionExecution {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, entry := range j.entries {
+		if entry.ID == id {
+			return copyExecution(entry)
+		}
+	}
+	return nil
+}
+
+func (j *journal) list() []proto.ActionExecution {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entries := make([]proto.ActionExecution, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, *copyExecution(entry))
+	}
+	return entries
+}
+
+func (j *journal) evict() {
+	for len(j.entries) > j.maxEntries {
+		if len(j.dir) > 0 {
+			if err := os.Remove(j.file(j.entries[0].ID)); err != nil && !os.IsNotExist(err) {
+				j.logger.Error(err, "failed to remove the journal entry", "id", j.entries[0].ID)
+			}
+		}
+		j.entries = j.entries[1:]
+	}
+}
+
+func (j *journal) persist(entry *proto.ActionExecution) {
+	if len(j.dir) == 0 {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		// write to a temporary file and then rename it, to avoid the partial write
+		tmp := j.file(entry.ID) + ".tmp"
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			err = os.Rename(tmp, j.file(entry.ID))
+		}
+	}
+	if err != nil {
+		j.logger.Error(err, "failed to persist the journal entry", "id", entry.ID)
+	}
+}
+
+func (j *journal) file(id string) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%s%s", id, journalFileSuffix))
+}
+
+func copyExecution(entry *proto.ActionExecution) *proto.ActionExecution {
+	c := *entry
+	c.Parameters = maps.Clone(entry.Parameters)
+	return &c
+}
+
+// redactParameters hides the values of sensitive parameters, such as passwords and the statements which may embed them.
+func redactParameters(parameters map[string]string) map[string]string {
+	if parameters == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(parameters))
+	for k, v := range parameters {
+		key := strings.ToUpper(k)
+		if strings.Contains(key, "PASSWORD") || strings.Contains(key, "SECRET") || strings.Contains(key, "STATEMENT") {
+			v = redactedValue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// truncate truncates s to at most maxJournalOutputSize bytes, without splitting a multi-byte rune.
+func truncate(s string) string {
+	if len(s) <= maxJournalOutputSize {
+		return s
+	}
+	end := maxJournalOutputSize
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+var _ = Describe("journal", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kbagent-journal-")
+		Expect(err).Should(BeNil())
+		viper.Set(JournalDirKey, dir)
+		viper.Set(JournalMaxEntriesKey, 2)
+	})
+
+	AfterEach(func() {
+		viper.Set(JournalDirKey, "")
+		viper.Set(JournalMaxEntriesKey, 0)
+		Expect(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	result := func(stdout string) *commandResult {
+		return &commandResult{
+			exitCode: ptr.To(int32(0)),
+			stdout:   bytes.NewBufferString(stdout),
+			stderr:   bytes.NewBuffer(nil),
+		}
+	}
+
+	Context("journal", func() {
+		It("persist and reload", func() {
+			j, err := newJournal(logr.New(nil))
+			Expect(err).Should(BeNil())
+			j.start("id-1", &proto.ActionRequest{Action: "action", Parameters: map[string]string{"KEY": "value"}})
+			j.finish("id-1", result("output"), nil)
+			j.start("id-2", &proto.ActionRequest{Action: "action"})
+
+			j2, err := newJournal(logr.New(nil))
+			Expect(err).Should(BeNil())
+			entries := j2.list()
+			Expect(entries).Should(HaveLen(2))
+			Expect(entries[0].ID).Should(Equal("id-1"))
+			Expect(entries[0].Stdout).Should(Equal("output"))
+			Expect(entries[0].Parameters).Should(HaveKeyWithValue("KEY", "value"))
+			// the running one is marked as interrupted
+			Expect(entries[1].ID).Should(Equal("id-2"))
+			Expect(entries[1].EndTime).ShouldNot(BeNil())
+			Expect(entries[1].Error).Should(Equal(proto.Error2Type(proto.ErrInternalError)))
+		})
+
+		It("evict", func() {
+			j, err := newJournal(logr.New(nil))
+			Expect(err).Should(BeNil())
+			for _, id := range []string{"id-1", "id-2", "id-3"} {
+				j.start(id, &proto.ActionRequest{Action: "action"})
+			}
+			Expect(j.list()).Should(HaveLen(2))
+			Expect(j.get("id-1")).Should(BeNil())
+			Expect(j.get("id-3")).ShouldNot(BeNil())
+			_, err = os.Stat(filepath.Join(dir, "id-1"+journalFileSuffix))
+			Expect(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("truncate and redact", func() {
+			j, err := newJournal(logr.New(nil))
+			Expect(err).Should(BeNil())
+			parameters := map[string]string{
+				"KB_ACCOUNT_NAME":      "user",
+				"KB_ACCOUNT_PASSWORD":  "password",
+				"KB_ACCOUNT_STATEMENT": "CREATE USER user IDENTIFIED BY 'password'",
+			}
+			j.start("id", &proto.ActionRequest{Action: "accountProvision", Parameters: parameters})
+			j.finish("id", result(string(bytes.Repeat([]byte{'x'}, maxJournalOutputSize*2))), nil)
+
+			entry := j.get("id")
+			Expect(entry).ShouldNot(BeNil())
+			Expect(entry.Stdout).Should(HaveLen(maxJournalOutputSize))
+			Expect(entry.Parameters).Should(HaveKeyWithValue("KB_ACCOUNT_NAME", "user"))
+			Expect(entry.Parameters).Should(HaveKeyWithValue("KB_ACCOUNT_PASSWORD", redactedValue))
+			Expect(entry.Parameters).Should(HaveKeyWithValue("KB_ACCOUNT_STATEMENT", redactedValue))
+		})
+
+		It("truncate on rune boundaries", func() {
+			s := strings.Repeat("x", maxJournalOutputSize-1) + "中文"
+			truncated := truncate(s)
+			Expect(truncated).Should(Equal(strings.Repeat("x", maxJournalOutputSize-1)))
+			Expect(utf8.ValidString(truncated)).Should(BeTrue())
+			Expect(truncate("中文")).Should(Equal("中文"))
+		})
+	})
+})
@@ -24,19 +24,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
 
+	"github.com/apecloud/kubeblocks/pkg/kbagent/metrics"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
 )
 
 const (
 	defaultProbePeriodSeconds = 60
+
+	// watchBufferSize is the number of events buffered for each subscriber, the subscriber will be dropped
+	// if it can't keep up with the events.
+	watchBufferSize = 16
 )
 
 func newProbeService(logger logr.Logger, actionService *actionService, probes []proto.Probe) (*probeService, error) {
@@ -45,6 +52,8 @@ func newProbeService(logger logr.Logger, actionService *actionService, probes []
 		actionService: actionService,
 		probes:        make(map[string]*proto.Probe),
 		runners:       make(map[string]*probeRunner),
+		latestEvents:  make(map[string]*proto.ProbeEvent),
+		subscribers:   make(map[chan []byte]struct{}),
 	}
 	for i, p := range probes {
 		if _, ok := actionService.actions[p.Action]; !ok {
@@ -61,9 +70,14 @@ type probeService struct {
 	actionService *actionService
 	probes        map[string]*proto.Probe
 	runners       map[string]*probeRunner
+
+	// latestEvents and subscribers are shared by all probe runners
+	lock         sync.Mutex
+	latestEvents map[string]*proto.ProbeEvent
+	subscribers  map[chan []byte]struct{}
 }
 
-var _ Service = &probeService{}
+var _ WatchableService = &probeService{}
 
 func (s *probeService) Kind() string {
 	return proto.ServiceProbe.Kind
@@ -78,6 +92,7 @@ func (s *probeService) Start() error {
 		runner := &probeRunner{
 			logger:        s.logger.WithValues("probe", name),
 			actionService: s.actionService,
+			service:       s,
 		}
 		go runner.run(s.probes[name])
 		s.runners[name] = runner
@@ -85,13 +100,120 @@ func (s *probeService) Start() error {
 	return nil
 }
 
+// HandleRequest returns the latest events of probes.
 func (s *probeService) HandleRequest(ctx context.Context, payload []byte) ([]byte, error) {
-	return nil, errors.Wrapf(proto.ErrNotImplemented, "service %s does not support request handling", s.Kind())
+	req := &proto.ProbeRequest{}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, req); err != nil {
+			return s.encode(nil, errors.Wrapf(proto.ErrBadRequest, "unmarshal probe request error: %s", err.Error()))
+		}
+	}
+	return s.encode(s.latest(req.Probe))
+}
+
+func (s *probeService) Watch(ctx context.Context) (<-chan []byte, error) {
+	ch := make(chan []byte, watchBufferSize)
+
+	s.lock.Lock()
+	// the subscriber always starts with the latest events
+	for _, name := range s.sortedProbes() {
+		if event, ok := s.latestEvents[name]; ok && len(ch) < cap(ch) {
+			data, err := json.Marshal(event)
+			if err != nil {
+				s.lock.Unlock()
+				return nil, err
+			}
+			ch <- data
+		}
+	}
+	s.subscribers[ch] = struct{}{}
+	s.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(ch)
+	}()
+	return ch, nil
+}
+
+func (s *probeService) latest(probe string) ([]proto.ProbeEvent, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(probe) > 0 {
+		if _, ok := s.probes[probe]; !ok {
+			return nil, errors.Wrapf(proto.ErrNotDefined, "probe %s is not defined", probe)
+		}
+		event, ok := s.latestEvents[probe]
+		if !ok {
+			return nil, errors.Wrapf(proto.ErrInProgress, "probe %s has no result yet", probe)
+		}
+		return []proto.ProbeEvent{*event}, nil
+	}
+
+	events := make([]proto.ProbeEvent, 0)
+	for _, name := range s.sortedProbes() {
+		if event, ok := s.latestEvents[name]; ok {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+func (s *probeService) sortedProbes() []string {
+	names := maps.Keys(s.probes)
+	slices.Sort(names)
+	return names
+}
+
+func (s *probeService) encode(events []proto.ProbeEvent, err error) ([]byte, error) {
+	rsp := &proto.ProbeResponse{Events: events}
+	if err != nil {
+		rsp.Error = proto.Error2Type(err)
+		rsp.Message = err.Error()
+	}
+	return json.Marshal(rsp)
+}
+
+// publish records the event as the latest one, and sends it to all subscribers.
+// It returns false if there is no subscriber to receive the event.
+func (s *probeService) publish(event *proto.ProbeEvent) bool {
+	data, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error(err, "failed to marshal probe event")
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.latestEvents[event.Probe] = event
+	for ch := range s.subscribers {
+		select {
+		case ch <- data:
+		default:
+			s.logger.Info("the subscriber can't keep up with the probe events, drop it")
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	return len(s.subscribers) > 0
+}
+
+func (s *probeService) unsubscribe(ch chan []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
 }
 
 type probeRunner struct {
 	logger        logr.Logger
 	actionService *actionService
+	service       *probeService
 	ticker        *time.Ticker
 	succeedCount  int64
 	failedCount   int64
@@ -124,6 +246,7 @@ func (r *probeRunner) runLoop(probe *proto.Probe) {
 			r.succeedCount = 0
 			r.failedCount++
 		}
+		metrics.ObserveProbe(probe.Action, err, r.failedCount)
 
 		r.report(probe, output, err)
 
@@ -174,11 +297,17 @@ func (r *probeRunner) sendEvent(probe string, code int32, output []byte, message
 	prefixLen := min(len(output), 32)
 	r.logger.Info("send probe event", "code", code, "output", string(output[:prefixLen]), "message", message)
 
+	now := time.Now()
 	eventMsg := &proto.ProbeEvent{
 		Probe:   probe,
 		Code:    code,
 		Message: message,
 		Output:  output,
+		Time:    &now,
+	}
+	// the Kubernetes event is the fallback if no one is watching the probe events
+	if r.service.publish(eventMsg) {
+		return
 	}
 	msg, err := json.Marshal(&eventMsg)
 	if err != nil {
@@ -20,13 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/go-logr/logr"
-	"github.com/pkg/errors"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
@@ -84,9 +85,95 @@ var _ = Describe("probe", func() {
 			Expect(err).Should(BeNil())
 			Expect(service).ShouldNot(BeNil())
 
-			_, err = service.HandleRequest(ctx, nil)
-			Expect(err).ShouldNot(BeNil())
-			Expect(errors.Is(err, proto.ErrNotImplemented)).Should(BeTrue())
+			decode := func(output []byte) proto.ProbeResponse {
+				rsp := proto.ProbeResponse{}
+				Expect(json.Unmarshal(output, &rsp)).Should(Succeed())
+				return rsp
+			}
+
+			output, err := service.HandleRequest(ctx, nil)
+			Expect(err).Should(BeNil())
+			Expect(decode(output).Events).Should(BeEmpty())
+
+			output, err = service.HandleRequest(ctx, []byte(`{"probe":"roleProbe"}`))
+			Expect(err).Should(BeNil())
+			Expect(proto.Type2Error(decode(output).Error)).Should(Equal(proto.ErrInProgress))
+
+			output, err = service.HandleRequest(ctx, []byte(`{"probe":"not-defined"}`))
+			Expect(err).Should(BeNil())
+			Expect(proto.Type2Error(decode(output).Error)).Should(Equal(proto.ErrNotDefined))
+
+			output, err = service.HandleRequest(ctx, []byte(`{`))
+			Expect(err).Should(BeNil())
+			Expect(proto.Type2Error(decode(output).Error)).Should(Equal(proto.ErrBadRequest))
+		})
+
+		It("handle request - latest event", func() {
+			service, err := newProbeService(logr.New(nil), actionSvc, probes)
+			Expect(err).Should(BeNil())
+			Expect(service.Start()).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				output, err := service.HandleRequest(ctx, []byte(`{"probe":"roleProbe"}`))
+				g.Expect(err).Should(BeNil())
+				rsp := proto.ProbeResponse{}
+				g.Expect(json.Unmarshal(output, &rsp)).Should(Succeed())
+				g.Expect(rsp.Error).Should(BeEmpty())
+				g.Expect(rsp.Events).Should(HaveLen(1))
+				g.Expect(rsp.Events[0].Code).Should(Equal(int32(0)))
+				g.Expect(rsp.Events[0].Output).Should(Equal([]byte("leader")))
+				g.Expect(rsp.Events[0].Time).ShouldNot(BeNil())
+			}).WithTimeout(5 * time.Second).Should(Succeed())
+		})
+
+		It("watch", func() {
+			service, err := newProbeService(logr.New(nil), actionSvc, probes)
+			Expect(err).Should(BeNil())
+
+			// the latest event is sent to new subscribers first
+			Expect(service.publish(&proto.ProbeEvent{Probe: "roleProbe", Output: []byte("leader")})).Should(BeFalse())
+
+			watchCtx, watchCancel := context.WithCancel(ctx)
+			events, err := service.Watch(watchCtx)
+			Expect(err).Should(BeNil())
+
+			receive := func() proto.ProbeEvent {
+				event := proto.ProbeEvent{}
+				var data []byte
+				Eventually(events).Should(Receive(&data))
+				Expect(json.Unmarshal(data, &event)).Should(Succeed())
+				return event
+			}
+			Expect(receive().Output).Should(Equal([]byte("leader")))
+
+			Expect(service.publish(&proto.ProbeEvent{Probe: "roleProbe", Output: []byte("follower")})).Should(BeTrue())
+			Expect(receive().Output).Should(Equal([]byte("follower")))
+
+			watchCancel()
+			Eventually(events).Should(BeClosed())
+			Eventually(func() bool {
+				return service.publish(&proto.ProbeEvent{Probe: "roleProbe"})
+			}).Should(BeFalse())
+		})
+
+		It("watch - slow subscriber", func() {
+			service, err := newProbeService(logr.New(nil), actionSvc, probes)
+			Expect(err).Should(BeNil())
+
+			events, err := service.Watch(ctx)
+			Expect(err).Should(BeNil())
+			for i := 0; i < watchBufferSize; i++ {
+				Expect(service.publish(&proto.ProbeEvent{Probe: "roleProbe"})).Should(BeTrue())
+			}
+			// the subscriber is dropped once its buffer is full
+			Expect(service.publish(&proto.ProbeEvent{Probe: "roleProbe"})).Should(BeFalse())
+			Eventually(func() int {
+				count := 0
+				for range events {
+					count++
+				}
+				return count
+			}).Should(Equal(watchBufferSize))
 		})
 
 		It("initial delay seconds", func() {
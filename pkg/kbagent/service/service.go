@@ -21,6 +21,7 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-logr/logr"
 
@@ -36,7 +37,33 @@ type Service interface {
 	HandleRequest(ctx context.Context, payload []byte) ([]byte, error)
 }
 
-func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe) ([]Service, error) {
+// QueryableService is a Service that keeps the results of requests, which can be queried by id.
+type QueryableService interface {
+	Service
+
+	// HandleQuery returns the result of the request with the given id, or all the results kept if the id is empty.
+	HandleQuery(ctx context.Context, id string) ([]byte, error)
+}
+
+// WatchableService is a Service that streams its events as they happen.
+type WatchableService interface {
+	Service
+
+	// Watch subscribes the events of the service, the channel is closed when the ctx is done,
+	// or the subscriber can't keep up with the events.
+	Watch(ctx context.Context) (<-chan []byte, error)
+}
+
+// StreamableService is a Service that streams the output of requests as they are produced.
+type StreamableService interface {
+	Service
+
+	// HandleStream handles the request and writes its output to @w as a stream, the result of request is carried
+	// in the stream too. It returns an error only if the stream can't be written.
+	HandleStream(ctx context.Context, payload []byte, w io.Writer) error
+}
+
+func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe, volumes []proto.Volume) ([]Service, error) {
 	sa, err := newActionService(logger, actions)
 	if err != nil {
 		return nil, err
@@ -45,5 +72,5 @@ func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe) ([]Se
 	if err != nil {
 		return nil, err
 	}
-	return []Service{sa, sp}, nil
+	return []Service{sa, sp, newVolumeService(logger, volumes)}, nil
 }
@@ -0,0 +1,407 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/metrics"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// StreamURISuffix is the suffix of the URI to stream the output of actions.
+	StreamURISuffix = "/stream"
+
+	// the kb-agents of a component share the token and certificates, the config of server is reused to access the peers.
+	peerTokenFileKey   = "token-file"
+	peerTLSCertFileKey = "tls-cert-file"
+	peerTLSKeyFileKey  = "tls-key-file"
+	peerTLSCAFileKey   = "tls-client-ca-file"
+
+	// the output is streamed in frames, each frame is a type byte, a 4-byte big-endian length, and the payload.
+	// The stream ends with an end frame if the action succeeded, or an error frame carrying the error otherwise.
+	frameTypeData  byte = 'D'
+	frameTypeEnd   byte = 'Z'
+	frameTypeError byte = 'E'
+	frameHeaderLen      = 5
+	maxFrameLen         = 1024 * 1024
+
+	peerConnectTimeout = 5 * time.Second
+)
+
+// peerServerNameKey is the context key of the server name to verify the certificate of the peer.
+type peerServerNameKey struct{}
+
+// peerClient is shared by the streams from the peers, to reuse the connections instead of leaking a transport per stream.
+// The TLS handshake is done with the server name of the peer carried by the context of the request.
+var peerClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext:    (&net.Dialer{Timeout: peerConnectTimeout}).DialContext,
+		DialTLSContext: dialPeerTLS,
+	},
+}
+
+func dialPeerTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	serverName, _ := ctx.Value(peerServerNameKey{}).(string)
+	tlsConfig, err := peerTLSConfig(serverName)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("no certificate to access the peer %s", addr)
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: peerConnectTimeout},
+		Config:    tlsConfig,
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// HandleStream runs the exec action and streams its output to @w, the result of action is sent in-band as the last frame.
+func (s *actionService) HandleStream(ctx context.Context, payload []byte, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fw := &frameWriter{writer: w, cancel: cancel}
+	req, err := s.decode(payload)
+	if err == nil {
+		err = s.handleStream(ctx, req, fw)
+		metrics.ObserveActionRequest(req.Action, err)
+	}
+	if fw.err != nil {
+		return fw.err // the receiver is gone
+	}
+	if err != nil {
+		return fw.writeFrame(frameTypeError, []byte(proto.Error2Type(err)+":"+err.Error()))
+	}
+	return fw.writeFrame(frameTypeEnd, nil)
+}
+
+func (s *actionService) handleStream(ctx context.Context, req *proto.ActionRequest, w io.Writer) error {
+	action, err := s.checkAction(req)
+	if err != nil {
+		return err
+	}
+	if action.Exec == nil {
+		return errors.Wrap(proto.ErrNotImplemented, "only exec actions can be streamed")
+	}
+	start := time.Now()
+	stderr := bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+	errChan, err := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, nil, w, stderr)
+	if err != nil {
+		return err
+	}
+	execErr, ok := <-errChan
+	if !ok {
+		execErr = errors.New("runtime error: error chan closed unexpectedly")
+	}
+	// the output has been streamed, only the error is concerned
+	_, err = (&commandResult{err: execErr, stdout: bytes.NewBuffer(nil), stderr: stderr}).output()
+	metrics.ObserveActionExecution(action.Name, start, err)
+	return err
+}
+
+// runActionWithInput runs the exec action with the output of the input source as its standard input,
+// the number of bytes read from the source is recorded in the journal entry @id as the progress.
+func (s *actionService) runActionWithInput(ctx context.Context, id string, action *proto.Action, req *proto.ActionRequest) (chan *commandResult, error) {
+	if action.Exec == nil {
+		return nil, errors.Wrap(proto.ErrNotImplemented, "only exec actions can take the input from peers")
+	}
+	input, err := openInputStream(ctx, req.InputFrom)
+	if err != nil {
+		return nil, err
+	}
+	reader := &countingReader{
+		reader: input,
+		observe: func(n int64) {
+			s.journal.progress(id, n)
+		},
+	}
+
+	start := time.Now()
+	stdout := bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+	stderr := bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+	errChan, err := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, reader, stdout, stderr)
+	if err != nil {
+		_ = input.Close()
+		return nil, err
+	}
+	resultChan := make(chan *commandResult, 1)
+	go func() {
+		execErr, ok := <-errChan
+		if !ok {
+			execErr = errors.New("runtime error: error chan closed unexpectedly")
+		}
+		_ = input.Close()
+		var exitCode *int32
+		var exitErr *exec.ExitError
+		if execErr == nil {
+			exitCode = ptr.To(int32(0))
+		} else if errors.As(execErr, &exitErr) {
+			exitCode = ptr.To(int32(exitErr.ExitCode()))
+		}
+		// the failure of source takes precedence, and the action may even succeed with a partial input
+		if readErr := reader.error(); readErr != nil {
+			execErr = errors.Wrapf(proto.ErrFailed, "read the input from %s error: %s", req.InputFrom.Action, readErr.Error())
+		}
+		result := &commandResult{
+			err:      execErr,
+			exitCode: exitCode,
+			stdout:   stdout,
+			stderr:   stderr,
+		}
+		_, err := result.output()
+		metrics.ObserveActionExecution(action.Name, start, err)
+		resultChan <- result
+	}()
+	return resultChan, nil
+}
+
+// openInputStream requests the kb-agent of source to stream the output of action.
+func openInputStream(ctx context.Context, source *proto.ActionInputSource) (io.ReadCloser, error) {
+	data, err := json.Marshal(proto.ActionRequest{
+		Action:     source.Action,
+		Parameters: source.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := peerTLSConfig(source.ServerName)
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrInternalError, "load the TLS config to access peers error: %s", err.Error())
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s%s%s", scheme, net.JoinHostPort(source.Host, fmt.Sprintf("%d", source.Port)),
+		proto.ServiceAction.URI, StreamURISuffix)
+	ctx = context.WithValue(ctx, peerServerNameKey{}, source.ServerName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrBadRequest, "build the stream request error: %s", err.Error())
+	}
+	if tokenFile := viper.GetString(peerTokenFileKey); len(tokenFile) > 0 {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, errors.Wrapf(proto.ErrInternalError, "load the token to access peers error: %s", err.Error())
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	rsp, err := peerClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(proto.ErrFailed, "request the input from %s error: %s", source.Host, err.Error())
+	}
+	if rsp.StatusCode != http.StatusOK {
+		_ = rsp.Body.Close()
+		return nil, errors.Wrapf(proto.ErrFailed, "request the input from %s error: unexpected http status %s", source.Host, rsp.Status)
+	}
+	return &frameReader{body: rsp.Body}, nil
+}
+
+func peerTLSConfig(serverName string) (*tls.Config, error) {
+	certFile, keyFile := viper.GetString(peerTLSCertFileKey), viper.GetString(peerTLSKeyFileKey)
+	if len(certFile) == 0 || len(keyFile) == 0 {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}
+	if caFile := viper.GetString(peerTLSCAFileKey); len(caFile) > 0 {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificate found in the CA file %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// frameWriter writes the data as frames, and flushes each frame to the receiver as soon as possible.
+type frameWriter struct {
+	writer io.Writer
+	// cancel stops the action if the receiver is gone
+	cancel context.CancelFunc
+	err    error
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, maxFrameLen)
+		if err := w.writeFrame(frameTypeData, p[written:written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+func (w *frameWriter) writeFrame(frameType byte, payload []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	header := make([]byte, frameHeaderLen)
+	header[0] = frameType
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	_, err := w.writer.Write(append(header, payload...))
+	if err == nil {
+		if f, ok := w.writer.(interface{ Flush() error }); ok {
+			err = f.Flush()
+		}
+	}
+	if err != nil {
+		w.err = err
+		w.cancel()
+	}
+	return err
+}
+
+// frameReader reads the data from frames, it returns io.EOF only if the end frame is received.
+type frameReader struct {
+	body      io.ReadCloser
+	remaining int
+	err       error
+}
+
+func (r *frameReader) Read(p []byte) (int, error) {
+	for r.remaining == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		header := make([]byte, frameHeaderLen)
+		if _, err := io.ReadFull(r.body, header); err != nil {
+			r.err = errors.Wrap(io.ErrUnexpectedEOF, "the stream is broken")
+			continue
+		}
+		length := int(binary.BigEndian.Uint32(header[1:]))
+		if length > maxFrameLen {
+			r.err = fmt.Errorf("the frame length %d exceeds the limit", length)
+			continue
+		}
+		switch header[0] {
+		case frameTypeData:
+			r.remaining = length
+		case frameTypeEnd:
+			r.err = io.EOF
+		case frameTypeError:
+			msg := make([]byte, length)
+			if _, err := io.ReadFull(r.body, msg); err != nil {
+				r.err = errors.Wrap(io.ErrUnexpectedEOF, "the stream is broken")
+			} else {
+				r.err = streamError(string(msg))
+			}
+		default:
+			r.err = fmt.Errorf("unknown frame type %q", header[0])
+		}
+	}
+	n, err := r.body.Read(p[:min(len(p), r.remaining)])
+	r.remaining -= n
+	if err == io.EOF && r.remaining > 0 {
+		err = errors.Wrap(io.ErrUnexpectedEOF, "the stream is broken")
+	} else if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		r.err = err
+		return n, err
+	}
+	return n, nil
+}
+
+func (r *frameReader) Close() error {
+	if r.err == io.EOF {
+		// the stream is complete, drain the rest of the body to return the connection to the shared transport.
+		_, _ = io.CopyN(io.Discard, r.body, maxFrameLen)
+	}
+	return r.body.Close()
+}
+
+// streamError restores the error sent in an error frame.
+func streamError(msg string) error {
+	errType, message, found := strings.Cut(msg, ":")
+	if !found {
+		return errors.New(msg)
+	}
+	if err := proto.Type2Error(errType); err != nil {
+		return errors.Wrap(err, message)
+	}
+	return errors.New(message)
+}
+
+// countingReader counts the bytes read, and reports the total to @observe.
+type countingReader struct {
+	reader  io.Reader
+	observe func(int64)
+	count   atomic.Int64
+
+	mutex sync.Mutex
+	err   error
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.observe(r.count.Add(int64(n)))
+	}
+	if err != nil && err != io.EOF {
+		r.mutex.Lock()
+		r.err = err
+		r.mutex.Unlock()
+	}
+	return n, err
+}
+
+func (r *countingReader) error() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
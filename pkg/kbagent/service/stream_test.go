@@ -0,0 +1,66 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("stream", func() {
+	It("reuses the connections to the peer", func() {
+		var conns atomic.Int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fw := &frameWriter{writer: w, cancel: func() {}}
+			_, _ = fw.Write([]byte("data"))
+			_ = fw.writeFrame(frameTypeEnd, nil)
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		Expect(err).Should(BeNil())
+		portNum, err := strconv.Atoi(port)
+		Expect(err).Should(BeNil())
+		for i := 0; i < 3; i++ {
+			stream, err := openInputStream(ctx, &proto.ActionInputSource{Host: host, Port: int32(portNum), Action: "dataDump"})
+			Expect(err).Should(BeNil())
+			data, err := io.ReadAll(stream)
+			Expect(err).Should(BeNil())
+			Expect(string(data)).Should(Equal("data"))
+			Expect(stream.Close()).Should(Succeed())
+		}
+		Expect(conns.Load()).Should(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+// statfs is replaced in tests.
+var statfs = unix.Statfs
+
+func newVolumeService(logger logr.Logger, volumes []proto.Volume) *volumeService {
+	sv := &volumeService{
+		logger:  logger,
+		volumes: volumes,
+	}
+	logger.Info(fmt.Sprintf("create service %s", sv.Kind()), "volumes", len(volumes))
+	return sv
+}
+
+// volumeService reports the usage of the volumes mounted into kb-agent, which is used by the controller
+// to protect the volumes from running out of space.
+type volumeService struct {
+	logger  logr.Logger
+	volumes []proto.Volume
+}
+
+var _ Service = &volumeService{}
+
+func (s *volumeService) Kind() string {
+	return proto.ServiceVolume.Kind
+}
+
+func (s *volumeService) URI() string {
+	return proto.ServiceVolume.URI
+}
+
+func (s *volumeService) Start() error {
+	return nil
+}
+
+// HandleRequest returns the usage of all the volumes.
+func (s *volumeService) HandleRequest(ctx context.Context, payload []byte) ([]byte, error) {
+	return s.encode(s.usage())
+}
+
+func (s *volumeService) usage() ([]proto.VolumeUsage, error) {
+	usages := make([]proto.VolumeUsage, 0, len(s.volumes))
+	for _, v := range s.volumes {
+		stat := unix.Statfs_t{}
+		if err := statfs(v.MountPath, &stat); err != nil {
+			return nil, errors.Wrapf(proto.ErrFailed, "stat volume %s error: %s", v.Name, err.Error())
+		}
+		// the space reserved for the root user is not counted as the capacity, which is the same as df.
+		used := int64(stat.Blocks-stat.Bfree) * int64(stat.Bsize)
+		usages = append(usages, proto.VolumeUsage{
+			Name:       v.Name,
+			UsedBytes:  used,
+			TotalBytes: used + int64(stat.Bavail)*int64(stat.Bsize),
+		})
+	}
+	return usages, nil
+}
+
+func (s *volumeService) encode(usages []proto.VolumeUsage, err error) ([]byte, error) {
+	rsp := &proto.VolumeResponse{Volumes: usages}
+	if err != nil {
+		rsp.Error = proto.Error2Type(err)
+		rsp.Message = err.Error()
+	}
+	return json.Marshal(rsp)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sys/unix"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("volume", func() {
+	Context("volume", func() {
+		var (
+			volumes = []proto.Volume{
+				{Name: "data", MountPath: "/kbagent-volumes/data"},
+				{Name: "log", MountPath: "/kbagent-volumes/log"},
+			}
+		)
+
+		AfterEach(func() {
+			statfs = unix.Statfs
+		})
+
+		It("new", func() {
+			service := newVolumeService(logr.New(nil), volumes)
+			Expect(service).ShouldNot(BeNil())
+			Expect(service.Kind()).Should(Equal(proto.ServiceVolume.Kind))
+			Expect(service.Start()).Should(Succeed())
+		})
+
+		It("usage", func() {
+			statfs = func(path string, stat *unix.Statfs_t) error {
+				stat.Bsize = 1024
+				stat.Blocks = 100
+				stat.Bfree = 20
+				stat.Bavail = 10
+				if path == "/kbagent-volumes/log" {
+					stat.Bfree = 100
+					stat.Bavail = 90
+				}
+				return nil
+			}
+			service := newVolumeService(logr.New(nil), volumes)
+			output, err := service.HandleRequest(ctx, nil)
+			Expect(err).Should(BeNil())
+
+			rsp := &proto.VolumeResponse{}
+			Expect(json.Unmarshal(output, rsp)).Should(Succeed())
+			Expect(rsp.Error).Should(BeEmpty())
+			Expect(rsp.Volumes).Should(Equal([]proto.VolumeUsage{
+				// the reserved blocks are not counted as the capacity
+				{Name: "data", UsedBytes: 80 * 1024, TotalBytes: 90 * 1024},
+				{Name: "log", UsedBytes: 0, TotalBytes: 90 * 1024},
+			}))
+		})
+
+		It("fail", func() {
+			statfs = func(path string, stat *unix.Statfs_t) error {
+				return fmt.Errorf("no such file or directory")
+			}
+			service := newVolumeService(logr.New(nil), volumes)
+			output, err := service.HandleRequest(ctx, nil)
+			Expect(err).Should(BeNil())
+
+			rsp := &proto.VolumeResponse{}
+			Expect(json.Unmarshal(output, rsp)).Should(Succeed())
+			Expect(rsp.Error).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+			Expect(rsp.Message).Should(ContainSubstring("data"))
+		})
+	})
+})
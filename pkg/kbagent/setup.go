@@ -21,6 +21,7 @@ package util
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -34,17 +35,39 @@ const (
 	ContainerName     = "kbagent"
 	InitContainerName = "init-kbagent"
 	DefaultPortName   = "http"
+	// MetricsPortName is the name of port to serve the metrics of kb-agent, which is exempted from the authentication.
+	MetricsPortName = "metrics"
+	// MetricsPortFlag is the flag of kb-agent to serve the metrics on a dedicated port.
+	MetricsPortFlag = "--metrics-port"
+
+	// TokenVolumeName is the name of the volume that holds the bearer token of kb-agent.
+	TokenVolumeName = "kbagent-token"
+	// TokenSecretKey is the key of the bearer token in the secret.
+	TokenSecretKey = "token"
+	// TLSCertFileFlag is the flag of kb-agent to enable the TLS.
+	TLSCertFileFlag = "--tls-cert-file"
 
 	actionEnvName = "KB_AGENT_ACTION"
 	probeEnvName  = "KB_AGENT_PROBE"
+	volumeEnvName = "KB_AGENT_VOLUME"
 )
 
-func BuildStartupEnv(actions []proto.Action, probes []proto.Probe) ([]corev1.EnvVar, error) {
+// IsTLSEnabled checks whether the TLS of the kb-agent container in the pod is enabled.
+func IsTLSEnabled(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == ContainerName {
+			return slices.Contains(c.Args, TLSCertFileFlag)
+		}
+	}
+	return false
+}
+
+func BuildStartupEnv(actions []proto.Action, probes []proto.Probe, volumes []proto.Volume) ([]corev1.EnvVar, error) {
 	da, dp, err := serializeActionNProbe(actions, probes)
 	if err != nil {
 		return nil, err
 	}
-	return append(util.DefaultEnvVars(), []corev1.EnvVar{
+	envs := append(util.DefaultEnvVars(), []corev1.EnvVar{
 		{
 			Name:  actionEnvName,
 			Value: da,
@@ -53,7 +76,19 @@ func BuildStartupEnv(actions []proto.Action, probes []proto.Probe) ([]corev1.Env
 			Name:  probeEnvName,
 			Value: dp,
 		},
-	}...), nil
+	}...)
+	// the env is only added if there are volumes to report, to keep the pods of the existing components unchanged.
+	if len(volumes) > 0 {
+		dv, err := json.Marshal(volumes)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name:  volumeEnvName,
+			Value: string(dv),
+		})
+	}
+	return envs, nil
 }
 
 func Initialize(logger logr.Logger, envs []string) ([]service.Service, error) {
@@ -67,7 +102,14 @@ func Initialize(logger logr.Logger, envs []string) ([]service.Service, error) {
 		return nil, err
 	}
 
-	return service.New(logger, actions, probes)
+	volumes := make([]proto.Volume, 0)
+	if dv, ok := util.EnvL2M(envs)[volumeEnvName]; ok {
+		if err = json.Unmarshal([]byte(dv), &volumes); err != nil {
+			return nil, err
+		}
+	}
+
+	return service.New(logger, actions, probes, volumes)
 }
 
 func getActionNProbeEnvValue(envs []string) (string, string) {
@@ -21,22 +21,18 @@ package operations
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
-	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/common"
-	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/operations/custom"
 )
 
 type CustomOpsHandler struct{}
@@ -80,6 +76,7 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 		completedActionCount int
 		compFailedCount      int
 		compCompleteCount    int
+		workflowRequeueAfter time.Duration
 	)
 	// TODO: support Parallelism
 	for _, v := range customSpec.CustomOpsComponents {
@@ -105,6 +102,9 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 			}
 		}
 		completedActionCount += workflowStatus.CompletedCount
+		if workflowStatus.RequeueAfter != 0 && (workflowRequeueAfter == 0 || workflowStatus.RequeueAfter < workflowRequeueAfter) {
+			workflowRequeueAfter = workflowStatus.RequeueAfter
+		}
 	}
 	// sync progress
 	if err := syncProgressToOpsRequest(reqCtx, cli, opsRes, oldOpsRequest, completedActionCount, compCount*len(opsRes.OpsDef.Spec.Actions)); err != nil {
@@ -112,7 +112,7 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 	}
 	// check if the ops has been finished.
 	if compCompleteCount != compCount {
-		return opsRequestPhase, 0, nil
+		return opsRequestPhase, workflowRequeueAfter, nil
 	}
 	if compFailedCount == 0 {
 		return opsv1alpha1.OpsSucceedPhase, 0, nil
@@ -125,21 +125,6 @@ func (c CustomOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, c
 	return nil
 }
 
-func (c CustomOpsHandler) listComponents(reqCtx intctrlutil.RequestCtx,
-	cli client.Client,
-	cluster *appsv1.Cluster,
-	componentName string) ([]appsv1.Component, error) {
-	if cluster.Spec.GetComponentByName(componentName) != nil {
-		comp, err := component.GetComponentByName(reqCtx.Ctx, cli, cluster.Namespace,
-			constant.GenerateClusterComponentName(cluster.Name, componentName))
-		if err != nil {
-			return nil, err
-		}
-		return []appsv1.Component{*comp}, nil
-	}
-	return intctrlutil.ListShardingComponents(reqCtx.Ctx, cli, cluster, componentName)
-}
-
 func (c CustomOpsHandler) checkExpression(reqCtx intctrlutil.RequestCtx,
 	cli client.Client,
 	opsRes *OpsResource,
@@ -149,7 +134,7 @@ func (c CustomOpsHandler) checkExpression(reqCtx intctrlutil.RequestCtx,
 	if opsSpec.Force {
 		return nil
 	}
-	comps, err := c.listComponents(reqCtx, cli, opsRes.Cluster, compCustomItem.ComponentName)
+	comps, err := custom.ListComponents(reqCtx.Ctx, cli, opsRes.Cluster, compCustomItem.ComponentName)
 	if err != nil {
 		return err
 	}
@@ -158,36 +143,15 @@ func (c CustomOpsHandler) checkExpression(reqCtx intctrlutil.RequestCtx,
 		if err != nil {
 			return err
 		}
-		// get the built-in objects and covert the json tag
-		getBuiltInObjs := func() (map[string]interface{}, error) {
-			b, err := json.Marshal(map[string]interface{}{
-				"cluster":    opsRes.Cluster,
-				"component":  &comp,
-				"parameters": params,
-			})
-			if err != nil {
-				return nil, err
-			}
-			data := map[string]interface{}{}
-			if err = json.Unmarshal(b, &data); err != nil {
-				return nil, err
-			}
-			return data, nil
-		}
-
-		data, err := getBuiltInObjs()
+		data, err := custom.BuildExpressionData(opsRes.Cluster, &comp, params, nil)
 		if err != nil {
 			return err
 		}
-		tmpl, err := template.New("opsDefTemplate").Parse(rule.Expression)
+		result, err := custom.RenderExpression(rule.Expression, data)
 		if err != nil {
 			return err
 		}
-		var buf strings.Builder
-		if err = tmpl.Execute(&buf, data); err != nil {
-			return err
-		}
-		if buf.String() == "false" {
+		if result == "false" {
 			if needWaitPreConditionDeadline(opsRes.OpsRequest) {
 				return intctrlutil.NewRequeueError(time.Second, rule.Message)
 			}
@@ -316,7 +280,7 @@ func initOpsDefAndValidate(reqCtx intctrlutil.RequestCtx,
 			return err
 		}
 		// covert to type map[string]interface{}
-		params, err := common.CoverStringToInterfaceBySchemaType(parametersSchema.OpenAPIV3Schema, paramsMap)
+		params, err := covertParametersBySchema(parametersSchema, paramsMap)
 		if err != nil {
 			return intctrlutil.NewFatalError(err.Error())
 		}
@@ -325,6 +289,10 @@ func initOpsDefAndValidate(reqCtx intctrlutil.RequestCtx,
 				return intctrlutil.NewFatalError(err.Error())
 			}
 		}
+		// validate the parameters with the CEL rules
+		if err = validateParametersWithRules(parametersSchema.Rules, params); err != nil {
+			return err
+		}
 
 		// 2. validate component and componentDef
 		if len(opsRes.OpsDef.Spec.ComponentInfos) > 0 {
@@ -350,3 +318,107 @@ func initOpsDefAndValidate(reqCtx intctrlutil.RequestCtx,
 	}
 	return nil
 }
+
+// covertParametersBySchema coverts the values of the parameters according to the types defined in the schema.
+func covertParametersBySchema(parametersSchema *opsv1alpha1.ParametersSchema, paramsMap map[string]string) (map[string]interface{}, error) {
+	if parametersSchema == nil || parametersSchema.OpenAPIV3Schema == nil {
+		params := map[string]interface{}{}
+		for k, v := range paramsMap {
+			params[k] = v
+		}
+		return params, nil
+	}
+	return common.CoverStringToInterfaceBySchemaType(parametersSchema.OpenAPIV3Schema, paramsMap)
+}
+
+// validateParametersWithRules validates the parameters with the CEL rules, the message of the rule is returned if it is not met.
+func validateParametersWithRules(rules []opsv1alpha1.CELRule, params map[string]interface{}) error {
+	for _, rule := range rules {
+		program, err := custom.CompileParametersCELExpression(rule.Expression)
+		if err != nil {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`invalid parameters rule "%s": %s`, rule.Expression, err.Error()))
+		}
+		passed, err := custom.EvaluateCELExpression(program, map[string]interface{}{"params": params})
+		if err != nil {
+			return intctrlutil.NewFatalError(fmt.Sprintf("%s: %s", rule.Message, err.Error()))
+		}
+		if !passed {
+			return intctrlutil.NewFatalError(rule.Message)
+		}
+	}
+	return nil
+}
+
+// checkCELPreConditions checks the CEL preConditions of the OpsDefinition for each component before the OpsRequest is started.
+// It waits until the preConditionDeadlineSeconds if any of the preConditions is not met.
+func checkCELPreConditions(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	if opsRes.OpsRequest.Spec.Force {
+		return nil
+	}
+	var rules []opsv1alpha1.CELRule
+	for _, v := range opsRes.OpsDef.Spec.PreConditions {
+		if v.CELRule != nil {
+			rules = append(rules, *v.CELRule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	checkRule := func(rule opsv1alpha1.CELRule, vars map[string]interface{}) error {
+		program, err := custom.CompilePreConditionCELExpression(rule.Expression)
+		if err != nil {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`invalid preCondition "%s": %s`, rule.Expression, err.Error()))
+		}
+		passed, err := custom.EvaluateCELExpression(program, vars)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%s: %s", rule.Message, err.Error())
+		case !passed:
+			return fmt.Errorf("%s", rule.Message)
+		default:
+			return nil
+		}
+	}
+	for _, compCustomItem := range opsRes.OpsRequest.Spec.CustomOps.CustomOpsComponents {
+		paramsMap, err := covertParametersToMap(reqCtx.Ctx, cli, compCustomItem.Parameters, opsRes.OpsRequest.Namespace)
+		if err != nil {
+			return err
+		}
+		params, err := covertParametersBySchema(opsRes.OpsDef.Spec.ParametersSchema, paramsMap)
+		if err != nil {
+			return intctrlutil.NewFatalError(err.Error())
+		}
+		comps, err := custom.ListComponents(reqCtx.Ctx, cli, opsRes.Cluster, compCustomItem.ComponentName)
+		if err != nil {
+			return err
+		}
+		for i := range comps {
+			compName, err := component.ShortName(opsRes.Cluster.Name, comps[i].Name)
+			if err != nil {
+				return err
+			}
+			pods, err := component.ListOwnedPods(reqCtx.Ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, compName)
+			if err != nil {
+				return err
+			}
+			vars, err := custom.BuildPreConditionCELVars(opsRes.Cluster, &comps[i], pods, params)
+			if err != nil {
+				return err
+			}
+			for _, rule := range rules {
+				if err = checkRule(rule, vars); err == nil {
+					continue
+				}
+				if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+					return err
+				}
+				opsRes.Recorder.Event(opsRes.OpsRequest, corev1.EventTypeWarning, "PreCheckFailed", err.Error())
+				if needWaitPreConditionDeadline(opsRes.OpsRequest) {
+					return intctrlutil.NewRequeueError(time.Second, err.Error())
+				}
+				return intctrlutil.NewFatalError(err.Error())
+			}
+		}
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ package custom
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -44,6 +45,8 @@ type ActionStatus struct {
 	ExistFailure bool
 	// return the action tasks(required).
 	ActionTasks []opsv1alpha1.ActionTask
+	// the duration to check the action status again, it is required if the action is not driven by the events of the tasks.
+	RequeueAfter time.Duration
 }
 
 func NewActiontatus() *ActionStatus {
@@ -142,6 +145,7 @@ func (actionCtx ActionContext) checkPodTaskStatus(
 		switch pod.Status.Phase {
 		case corev1.PodSucceeded:
 			completed = true
+			task.Output = getPodTerminationMessage(pod)
 		case corev1.PodFailed:
 			if task.Retries < backOffLimit {
 				task.Retries += 1
@@ -154,3 +158,16 @@ func (actionCtx ActionContext) checkPodTaskStatus(
 	}
 	return completed, existFailure, nil
 }
+
+// getPodTerminationMessage returns the termination message of the first container as the output of the task.
+func getPodTerminationMessage(pod *corev1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == pod.Spec.Containers[0].Name && status.State.Terminated != nil {
+			return truncateOutput(status.State.Terminated.Message)
+		}
+	}
+	return ""
+}
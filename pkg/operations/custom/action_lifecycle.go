@@ -0,0 +1,318 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package custom
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	// lifecycleActionSwitchoverCandidateParameter is the parameter which specifies the candidate of the switchover action.
+	lifecycleActionSwitchoverCandidateParameter = "candidate"
+
+	lifecycleActionRetryInterval = time.Second * 5
+)
+
+type LifecycleAction struct {
+	OpsRequest     *opsv1alpha1.OpsRequest
+	Cluster        *appsv1.Cluster
+	OpsDef         *opsv1alpha1.OpsDefinition
+	CustomCompOps  *opsv1alpha1.CustomOpsComponent
+	Params         map[string]string
+	progressDetail opsv1alpha1.ProgressStatusDetail
+}
+
+func NewLifecycleAction(opsRequest *opsv1alpha1.OpsRequest,
+	cluster *appsv1.Cluster,
+	opsDef *opsv1alpha1.OpsDefinition,
+	customCompOps *opsv1alpha1.CustomOpsComponent,
+	params map[string]string,
+	progressDetail opsv1alpha1.ProgressStatusDetail) *LifecycleAction {
+	return &LifecycleAction{
+		OpsRequest:     opsRequest,
+		Cluster:        cluster,
+		OpsDef:         opsDef,
+		CustomCompOps:  customCompOps,
+		Params:         params,
+		progressDetail: progressDetail,
+	}
+}
+
+func (l *LifecycleAction) Execute(actionCtx ActionContext) (*ActionStatus, error) {
+	if actionCtx.Action.LifecycleAction == nil {
+		return nil, nil
+	}
+	var (
+		podInfoExtractorName = actionCtx.Action.LifecycleAction.PodInfoExtractorName
+		actionStatus         = NewActiontatus()
+	)
+	if podInfoExtractorName != "" {
+		// invoke the lifecycle action on each of the target pods.
+		podInfoExtractor := getTargetPodInfoExtractor(l.OpsDef, podInfoExtractorName)
+		if podInfoExtractor == nil {
+			return nil, intctrlutil.NewFatalError("can not found the podInfoExtractor: " + podInfoExtractorName)
+		}
+		targetPods, err := getTargetPods(actionCtx.ReqCtx.Ctx, actionCtx.Client, l.Cluster, podInfoExtractor.PodSelector, l.CustomCompOps.ComponentName)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range targetPods {
+			actionStatus.ActionTasks = append(actionStatus.ActionTasks, opsv1alpha1.ActionTask{
+				Namespace:     pod.Namespace,
+				ObjectKey:     fmt.Sprintf("%s/%s", constant.PodKind, pod.Name),
+				TargetPodName: pod.Name,
+				Status:        opsv1alpha1.ProcessingActionTaskStatus,
+			})
+		}
+	} else {
+		// invoke the lifecycle action once for each of the components, the pod is determined by the lifecycle action.
+		comps, err := ListComponents(actionCtx.ReqCtx.Ctx, actionCtx.Client, l.Cluster, l.CustomCompOps.ComponentName)
+		if err != nil {
+			return nil, err
+		}
+		for _, comp := range comps {
+			actionStatus.ActionTasks = append(actionStatus.ActionTasks, opsv1alpha1.ActionTask{
+				Namespace: comp.Namespace,
+				ObjectKey: fmt.Sprintf("%s/%s", appsv1.ComponentKind, comp.Name),
+				Status:    opsv1alpha1.ProcessingActionTaskStatus,
+			})
+		}
+	}
+	for i := range actionStatus.ActionTasks {
+		if err := l.invokeLifecycleAction(actionCtx, &actionStatus.ActionTasks[i]); err != nil {
+			return nil, err
+		}
+	}
+	actionStatus.RequeueAfter = lifecycleActionRetryInterval
+	return actionStatus, nil
+}
+
+func (l *LifecycleAction) CheckStatus(actionCtx ActionContext) (*ActionStatus, error) {
+	actionStatus, err := actionCtx.checkActionStatus(l.progressDetail, l.checkTaskStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !actionStatus.IsCompleted {
+		actionStatus.RequeueAfter = lifecycleActionRetryInterval
+	}
+	return actionStatus, nil
+}
+
+func (l *LifecycleAction) checkTaskStatus(actionCtx ActionContext,
+	task *opsv1alpha1.ActionTask,
+	_ int) (bool, bool, error) {
+	switch task.Status {
+	case opsv1alpha1.FailedActionTaskStatus:
+		return true, true, nil
+	case opsv1alpha1.SucceedActionTaskStatus:
+		return true, false, nil
+	default:
+		// the task is in progress, or failed before and is waiting for a retry.
+		if err := l.invokeLifecycleAction(actionCtx, task); err != nil {
+			return false, false, err
+		}
+		if task.Status == opsv1alpha1.ProcessingActionTaskStatus {
+			return false, false, nil
+		}
+		return true, task.Status == opsv1alpha1.FailedActionTaskStatus, nil
+	}
+}
+
+// isNonBlockingLifecycleAction checks whether the lifecycle action may run for a long time, such actions are called
+// in the non-blocking way and polled on the same pod until they are done.
+func isNonBlockingLifecycleAction(name string) bool {
+	return slices.Contains([]string{"switchover", "dataDump", "dataLoad"}, name)
+}
+
+// invokeLifecycleAction invokes the lifecycle action and updates the status of the task.
+// The long-running actions are invoked in the non-blocking way, the task is kept in processing until the action is done.
+// If the lifecycle action fails, the task is kept in processing to retry until the backoffLimit is reached.
+func (l *LifecycleAction) invokeLifecycleAction(actionCtx ActionContext, task *opsv1alpha1.ActionTask) error {
+	var (
+		ctx             = actionCtx.ReqCtx.Ctx
+		cli             = actionCtx.Client
+		lifecycleAction = actionCtx.Action.LifecycleAction
+		fullCompName    = getNameFromObjectKey(task.ObjectKey)
+		targetPod       *corev1.Pod
+	)
+	if task.TargetPodName != "" {
+		targetPod = &corev1.Pod{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: task.TargetPodName, Namespace: task.Namespace}, targetPod); err != nil {
+			return err
+		}
+		fullCompName = constant.GenerateClusterComponentName(l.Cluster.Name, targetPod.Labels[constant.KBAppComponentLabelKey])
+	}
+	comp, compDef, err := component.GetCompNCompDefByName(ctx, cli, l.Cluster.Namespace, fullCompName)
+	if err != nil {
+		return err
+	}
+	synthesizedComp, err := component.BuildSynthesizedComponent(ctx, cli, compDef, comp, l.Cluster)
+	if err != nil {
+		return err
+	}
+	pods, err := component.ListOwnedPods(ctx, cli, l.Cluster.Namespace, l.Cluster.Name, synthesizedComp.Name)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`can not find any pod of the component "%s"`, fullCompName))
+	}
+	opts := &lifecycle.Options{TimeoutSeconds: lifecycleAction.TimeoutSeconds}
+	if isNonBlockingLifecycleAction(lifecycleAction.Name) {
+		if targetPod == nil {
+			// record the pod before the call, the non-blocking action is polled on it until it is done.
+			if targetPod, err = selectLifecycleActionPod(synthesizedComp, lifecycleAction.Name, pods); err != nil {
+				return err
+			}
+			task.TargetPodName = targetPod.Name
+		}
+		synthesizedComp = pinLifecycleAction(synthesizedComp, lifecycleAction.Name)
+		opts.NonBlocking = pointer.Bool(true)
+	}
+	lfa, err := lifecycle.New(synthesizedComp, targetPod, pods...)
+	if err != nil {
+		return err
+	}
+	var output []byte
+	switch lifecycleAction.Name {
+	case "switchover":
+		err = lfa.Switchover(ctx, cli, opts, l.Params[lifecycleActionSwitchoverCandidateParameter])
+	case "dataDump":
+		err = lfa.DataDump(ctx, cli, opts)
+	case "dataLoad":
+		err = lfa.DataLoad(ctx, cli, opts)
+	case "readonly":
+		err = lfa.Readonly(ctx, cli, opts)
+	case "readwrite":
+		err = lfa.Readwrite(ctx, cli, opts)
+	case "memberJoin":
+		err = lfa.MemberJoin(ctx, cli, opts)
+	case "memberLeave":
+		err = lfa.MemberLeave(ctx, cli, opts)
+	case "roleProbe":
+		output, err = lfa.RoleProbe(ctx, cli, opts)
+	default:
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the lifecycle action "%s" is not supported`, lifecycleAction.Name))
+	}
+	switch {
+	case errors.Is(err, lifecycle.ErrActionNotDefined):
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the lifecycle action "%s" is not defined in the ComponentDefinition "%s"`,
+			lifecycleAction.Name, compDef.Name))
+	case errors.Is(err, lifecycle.ErrActionInProgress):
+		task.Status = opsv1alpha1.ProcessingActionTaskStatus
+	case err != nil:
+		actionCtx.ReqCtx.Log.Info(fmt.Sprintf(`failed to invoke the lifecycle action "%s" on "%s": %s`,
+			lifecycleAction.Name, task.ObjectKey, err.Error()))
+		if task.Retries >= lifecycleAction.BackoffLimit {
+			task.Status = opsv1alpha1.FailedActionTaskStatus
+		} else {
+			task.Retries += 1
+			task.Status = opsv1alpha1.ProcessingActionTaskStatus
+		}
+	default:
+		task.Status = opsv1alpha1.SucceedActionTaskStatus
+		task.Output = truncateOutput(string(output))
+	}
+	return nil
+}
+
+// lifecycleActionSpec returns the spec of the lifecycle action defined in the component.
+func lifecycleActionSpec(actions *appsv1.ComponentLifecycleActions, name string) **appsv1.Action {
+	if actions == nil {
+		return nil
+	}
+	switch name {
+	case "switchover":
+		return &actions.Switchover
+	case "dataDump":
+		return &actions.DataDump
+	case "dataLoad":
+		return &actions.DataLoad
+	}
+	return nil
+}
+
+// selectLifecycleActionPod selects the pod to call the lifecycle action on in the way of the target pod selector of the action.
+func selectLifecycleActionPod(synthesizedComp *component.SynthesizedComponent, name string, pods []*corev1.Pod) (*corev1.Pod, error) {
+	spec := lifecycleActionSpec(synthesizedComp.LifecycleActions, name)
+	if spec == nil || *spec == nil {
+		return pods[0], nil // not defined, it is reported by the call
+	}
+	var (
+		selector    appsv1.TargetPodSelector
+		matchingKey string
+	)
+	switch action := *spec; {
+	case action.Exec != nil:
+		selector, matchingKey = action.Exec.TargetPodSelector, action.Exec.MatchingKey
+	case action.HTTP != nil:
+		selector, matchingKey = action.HTTP.TargetPodSelector, action.HTTP.MatchingKey
+	case action.GRPC != nil:
+		selector, matchingKey = action.GRPC.TargetPodSelector, action.GRPC.MatchingKey
+	}
+	switch selector {
+	case "", appsv1.AnyReplica:
+		return pods[0], nil
+	case appsv1.RoleSelector:
+		for _, pod := range pods {
+			if pod.Labels[constant.RoleLabelKey] == matchingKey {
+				return pod, nil
+			}
+		}
+		return nil, fmt.Errorf(`no pod with the role "%s" to invoke the lifecycle action "%s"`, matchingKey, name)
+	default:
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the target pod selector "%s" of the lifecycle action "%s" is not supported`, selector, name))
+	}
+}
+
+// pinLifecycleAction returns a copy of the component whose lifecycle action is called on the target pod only,
+// since the role of the pods may change while the action is running, such as the switchover.
+func pinLifecycleAction(synthesizedComp *component.SynthesizedComponent, name string) *component.SynthesizedComponent {
+	spec := lifecycleActionSpec(synthesizedComp.LifecycleActions, name)
+	if spec == nil || *spec == nil {
+		return synthesizedComp
+	}
+	comp := *synthesizedComp
+	comp.LifecycleActions = synthesizedComp.LifecycleActions.DeepCopy()
+	action := *lifecycleActionSpec(comp.LifecycleActions, name)
+	switch {
+	case action.Exec != nil:
+		action.Exec.TargetPodSelector = ""
+	case action.HTTP != nil:
+		action.HTTP.TargetPodSelector = ""
+	case action.GRPC != nil:
+		action.GRPC.TargetPodSelector = ""
+	}
+	return &comp
+}
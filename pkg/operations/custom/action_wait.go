@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package custom
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const waitForCheckInterval = time.Second * 5
+
+type WaitForAction struct {
+	Cluster        *appsv1.Cluster
+	CustomCompOps  *opsv1alpha1.CustomOpsComponent
+	Params         map[string]string
+	Outputs        map[string]string
+	progressDetail opsv1alpha1.ProgressStatusDetail
+}
+
+func NewWaitForAction(cluster *appsv1.Cluster,
+	customCompOps *opsv1alpha1.CustomOpsComponent,
+	params map[string]string,
+	outputs map[string]string,
+	progressDetail opsv1alpha1.ProgressStatusDetail) *WaitForAction {
+	return &WaitForAction{
+		Cluster:        cluster,
+		CustomCompOps:  customCompOps,
+		Params:         params,
+		Outputs:        outputs,
+		progressDetail: progressDetail,
+	}
+}
+
+func (w *WaitForAction) Execute(actionCtx ActionContext) (*ActionStatus, error) {
+	if actionCtx.Action.WaitFor == nil {
+		return nil, nil
+	}
+	// the condition will be checked in CheckStatus.
+	return &ActionStatus{RequeueAfter: waitForCheckInterval}, nil
+}
+
+func (w *WaitForAction) CheckStatus(actionCtx ActionContext) (*ActionStatus, error) {
+	waitFor := actionCtx.Action.WaitFor
+	comps, err := ListComponents(actionCtx.ReqCtx.Ctx, actionCtx.Client, w.Cluster, w.CustomCompOps.ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	satisfied := true
+	for i := range comps {
+		data, err := BuildExpressionData(w.Cluster, &comps[i], w.Params, w.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		result, err := RenderExpression(waitFor.Expression, data)
+		if err != nil {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`failed to render the expression of the action "%s": %s`,
+				actionCtx.Action.Name, err.Error()))
+		}
+		if result != "true" {
+			satisfied = false
+			break
+		}
+	}
+	switch {
+	case satisfied:
+		return &ActionStatus{IsCompleted: true}, nil
+	case waitFor.Timeout != nil && w.progressDetail.StartTime.Add(waitFor.Timeout.Duration).Before(time.Now()):
+		return &ActionStatus{IsCompleted: true, ExistFailure: true}, nil
+	default:
+		return &ActionStatus{RequeueAfter: waitForCheckInterval}, nil
+	}
+}
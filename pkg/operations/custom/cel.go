@@ -0,0 +1,303 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package custom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	celVarCluster   = "cluster"
+	celVarComponent = "component"
+	celVarPods      = "pods"
+	celVarParams    = "params"
+)
+
+var (
+	celObjectTypesOnce sync.Once
+	celObjectTypes     *celObjectTypeDecls
+)
+
+// celPod is the view of a pod in the CEL expressions.
+type celPod struct {
+	Name      string            `json:"name"`
+	Role      string            `json:"role"`
+	Ready     bool              `json:"ready"`
+	Available bool              `json:"available"`
+	Phase     string            `json:"phase"`
+	NodeName  string            `json:"nodeName"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// celObjectTypeDecls declares the Go types as CEL object types with their JSON field names, so the references to
+// the unknown fields are rejected when the expressions are compiled. The objects are evaluated as JSON maps.
+type celObjectTypeDecls struct {
+	fields map[string]map[string]*types.Type
+}
+
+// declare returns the CEL type of the Go type, and declares the struct types as object types.
+func (d *celObjectTypeDecls) declare(t reflect.Type) *types.Type {
+	switch t {
+	case reflect.TypeOf(metav1.Time{}), reflect.TypeOf(metav1.MicroTime{}), reflect.TypeOf(metav1.Duration{}), reflect.TypeOf(resource.Quantity{}):
+		return types.StringType
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return d.declare(t.Elem())
+	case reflect.Bool:
+		return types.BoolType
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return types.IntType
+	case reflect.String:
+		return types.StringType
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return types.StringType // base64 encoded
+		}
+		return types.NewListType(d.declare(t.Elem()))
+	case reflect.Map:
+		return types.NewMapType(types.StringType, d.declare(t.Elem()))
+	case reflect.Struct:
+		if t.Name() == "" || reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+			return types.DynType
+		}
+		name := celObjectTypeName(t)
+		if _, ok := d.fields[name]; !ok {
+			d.fields[name] = map[string]*types.Type{}
+			d.declareFields(t, d.fields[name])
+		}
+		return types.NewObjectType(name)
+	default:
+		return types.DynType
+	}
+}
+
+func (d *celObjectTypeDecls) declareFields(t reflect.Type, fields map[string]*types.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if name == "" && (field.Anonymous || strings.Contains(opts, "inline")) && fieldType.Kind() == reflect.Struct {
+			d.declareFields(fieldType, fields)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = d.declare(field.Type)
+	}
+}
+
+func celObjectTypeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+}
+
+func getCELObjectTypes() *celObjectTypeDecls {
+	celObjectTypesOnce.Do(func() {
+		celObjectTypes = &celObjectTypeDecls{fields: map[string]map[string]*types.Type{}}
+		for _, obj := range []interface{}{appsv1.Cluster{}, appsv1.Component{}, celPod{}} {
+			celObjectTypes.declare(reflect.TypeOf(obj))
+		}
+	})
+	return celObjectTypes
+}
+
+// celObjectTypeProvider provides the declared object types to the CEL type checker.
+type celObjectTypeProvider struct {
+	types.Provider
+	decls *celObjectTypeDecls
+}
+
+func (p *celObjectTypeProvider) FindStructType(structType string) (*types.Type, bool) {
+	if _, ok := p.decls.fields[structType]; ok {
+		return types.NewTypeTypeWithParam(types.NewObjectType(structType)), true
+	}
+	return p.Provider.FindStructType(structType)
+}
+
+func (p *celObjectTypeProvider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	fields, ok := p.decls.fields[structType]
+	if !ok {
+		return p.Provider.FindStructFieldType(structType, fieldName)
+	}
+	// the field values are selected from the JSON maps in the standard way.
+	fieldType, ok := fields[fieldName]
+	if !ok {
+		return nil, false
+	}
+	return &types.FieldType{Type: fieldType}, true
+}
+
+// celObjectTypesOption registers the declared object types in the CEL environment.
+func celObjectTypesOption() cel.EnvOption {
+	return func(env *cel.Env) (*cel.Env, error) {
+		return cel.CustomTypeProvider(&celObjectTypeProvider{
+			Provider: env.CELTypeProvider(),
+			decls:    getCELObjectTypes(),
+		})(env)
+	}
+}
+
+// CompilePreConditionCELExpression compiles the CEL expression of the preCondition.
+func CompilePreConditionCELExpression(expression string) (cel.Program, error) {
+	return compileCELExpression(expression,
+		celObjectTypesOption(),
+		cel.Variable(celVarCluster, cel.ObjectType(celObjectTypeName(reflect.TypeOf(appsv1.Cluster{})))),
+		cel.Variable(celVarComponent, cel.ObjectType(celObjectTypeName(reflect.TypeOf(appsv1.Component{})))),
+		cel.Variable(celVarPods, cel.ListType(cel.ObjectType(celObjectTypeName(reflect.TypeOf(celPod{}))))),
+		cel.Variable(celVarParams, cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+// CompileParametersCELExpression compiles the CEL expression of the parameters rule.
+func CompileParametersCELExpression(expression string) (cel.Program, error) {
+	return compileCELExpression(expression,
+		cel.Variable(celVarParams, cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+func compileCELExpression(expression string, opts ...cel.EnvOption) (cel.Program, error) {
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	// the type of the parameters are unknown at the compile time.
+	if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("the expression must return a bool, but got %s", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// EvaluateCELExpression evaluates the compiled CEL expression with the variables.
+func EvaluateCELExpression(program cel.Program, vars map[string]interface{}) (bool, error) {
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("the expression must return a bool, but got %v", out.Value())
+	}
+	return result, nil
+}
+
+// BuildPreConditionCELVars builds the variables of the preCondition CEL expressions.
+func BuildPreConditionCELVars(cluster *appsv1.Cluster,
+	comp *appsv1.Component,
+	pods []*corev1.Pod,
+	params map[string]interface{}) (map[string]interface{}, error) {
+	toMap := func(obj interface{}) (map[string]interface{}, error) {
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		// decode the integers as int64 to match the declared types.
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		data := map[string]interface{}{}
+		if err = decoder.Decode(&data); err != nil {
+			return nil, err
+		}
+		return convertJSONNumbers(data).(map[string]interface{}), nil
+	}
+	clusterData, err := toMap(cluster)
+	if err != nil {
+		return nil, err
+	}
+	compData, err := toMap(comp)
+	if err != nil {
+		return nil, err
+	}
+	podsData := make([]map[string]interface{}, 0, len(pods))
+	for _, pod := range pods {
+		podData, err := toMap(celPod{
+			Name:      pod.Name,
+			Role:      pod.Labels[constant.RoleLabelKey],
+			Ready:     intctrlutil.PodIsReady(pod),
+			Available: intctrlutil.IsAvailable(pod, 0),
+			Phase:     string(pod.Status.Phase),
+			NodeName:  pod.Spec.NodeName,
+			Labels:    pod.Labels,
+		})
+		if err != nil {
+			return nil, err
+		}
+		podsData = append(podsData, podData)
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		celVarCluster:   clusterData,
+		celVarComponent: compData,
+		celVarPods:      podsData,
+		celVarParams:    params,
+	}, nil
+}
+
+// convertJSONNumbers converts the json.Number values to int64, or float64 if they are not integers.
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
@@ -22,10 +22,12 @@ package custom
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/jsonpath"
@@ -50,6 +52,9 @@ const (
 	kbEnvCompServiceVersion  = "KB_COMP_SERVICE_VERSION"
 	kbEnvAccountUserName     = "KB_ACCOUNT_USERNAME"
 	kbEnvAccountPassword     = "KB_ACCOUNT_PASSWORD"
+
+	// the max length of the task output.
+	maxTaskOutputLength = 1024
 )
 
 // buildComponentDefEnvs builds the env vars by the opsDefinition.spec.componentDefinitionRef
@@ -399,3 +404,62 @@ func getNameFromObjectKey(objectKey string) string {
 	}
 	return objectKey
 }
+
+// truncateOutput truncates the output of the task to avoid the status of the OpsRequest being too large.
+func truncateOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxTaskOutputLength {
+		return output[:maxTaskOutputLength]
+	}
+	return output
+}
+
+// ListComponents lists the components of the component or the sharding.
+func ListComponents(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1.Cluster,
+	componentName string) ([]appsv1.Component, error) {
+	if cluster.Spec.GetComponentByName(componentName) != nil {
+		comp, err := component.GetComponentByName(ctx, cli, cluster.Namespace,
+			constant.GenerateClusterComponentName(cluster.Name, componentName))
+		if err != nil {
+			return nil, err
+		}
+		return []appsv1.Component{*comp}, nil
+	}
+	return intctrlutil.ListShardingComponents(ctx, cli, cluster, componentName)
+}
+
+// BuildExpressionData builds the built-in objects of the expressions in the OpsDefinition, the json tags are used as the keys of the fields.
+func BuildExpressionData(cluster *appsv1.Cluster,
+	comp *appsv1.Component,
+	params map[string]string,
+	outputs map[string]string) (map[string]interface{}, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"cluster":    cluster,
+		"component":  comp,
+		"parameters": params,
+		"outputs":    outputs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{}
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RenderExpression renders the Go template expression with the data.
+func RenderExpression(expression string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.New("opsDefTemplate").Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/operations/custom"
+)
+
+var _ = Describe("Custom CEL rules test", func() {
+
+	Context("parameters rules", func() {
+		It("validates the parameters across fields", func() {
+			schema := &opsv1alpha1.ParametersSchema{
+				OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"minReplicas": {Type: "integer"},
+						"maxReplicas": {Type: "integer"},
+					},
+				},
+				Rules: []opsv1alpha1.CELRule{
+					{
+						Expression: "!has(params.minReplicas) || !has(params.maxReplicas) || params.minReplicas <= params.maxReplicas",
+						Message:    "minReplicas must not be greater than maxReplicas",
+					},
+				},
+			}
+			params, err := covertParametersBySchema(schema, map[string]string{"minReplicas": "1", "maxReplicas": "3"})
+			Expect(err).Should(BeNil())
+			Expect(validateParametersWithRules(schema.Rules, params)).Should(Succeed())
+
+			params, err = covertParametersBySchema(schema, map[string]string{"minReplicas": "5"})
+			Expect(err).Should(BeNil())
+			Expect(validateParametersWithRules(schema.Rules, params)).Should(Succeed())
+
+			params, err = covertParametersBySchema(schema, map[string]string{"minReplicas": "5", "maxReplicas": "3"})
+			Expect(err).Should(BeNil())
+			err = validateParametersWithRules(schema.Rules, params)
+			Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+			Expect(err.Error()).Should(Equal("minReplicas must not be greater than maxReplicas"))
+
+			By("the values are strings without the openAPIV3Schema")
+			params, err = covertParametersBySchema(&opsv1alpha1.ParametersSchema{}, map[string]string{"mode": "fast"})
+			Expect(err).Should(BeNil())
+			Expect(validateParametersWithRules([]opsv1alpha1.CELRule{{Expression: "params.mode in ['fast', 'safe']", Message: "invalid mode"}}, params)).Should(Succeed())
+		})
+
+		It("compiles the expressions", func() {
+			_, err := custom.CompileParametersCELExpression("params.a ==")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompileParametersCELExpression("cluster.status.phase == 'Running'")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompileParametersCELExpression("size(params)")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompilePreConditionCELExpression("pods.exists(p, p.role == 'primary') && cluster.status.phase == 'Running'")
+			Expect(err).Should(BeNil())
+
+			By("the fields of the cluster, component and pods are checked")
+			_, err = custom.CompilePreConditionCELExpression("cluster.status.phse == 'Running'")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompilePreConditionCELExpression("component.spec.replica > 1")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompilePreConditionCELExpression("pods.exists(p, p.rol == 'primary')")
+			Expect(err).ShouldNot(BeNil())
+			_, err = custom.CompilePreConditionCELExpression("cluster.status.phase == 1")
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
+
+	Context("preConditions", func() {
+		It("evaluates the preConditions with the cluster, component, pods and parameters", func() {
+			cluster := &appsv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status:     appsv1.ClusterStatus{Phase: appsv1.RunningClusterPhase},
+			}
+			comp := &appsv1.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-mysql"},
+				Spec:       appsv1.ComponentSpec{Replicas: 2},
+			}
+			newPod := func(name, role string, ready bool) *corev1.Pod {
+				status := corev1.ConditionFalse
+				if ready {
+					status = corev1.ConditionTrue
+				}
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{constant.RoleLabelKey: role}},
+					Status: corev1.PodStatus{
+						Phase:      corev1.PodRunning,
+						Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+					},
+				}
+			}
+			pods := []*corev1.Pod{newPod("test-mysql-0", "primary", true), newPod("test-mysql-1", "secondary", false)}
+			vars, err := custom.BuildPreConditionCELVars(cluster, comp, pods, map[string]interface{}{"replicas": int64(2)})
+			Expect(err).Should(BeNil())
+
+			check := func(expression string) bool {
+				program, err := custom.CompilePreConditionCELExpression(expression)
+				Expect(err).Should(BeNil())
+				passed, err := custom.EvaluateCELExpression(program, vars)
+				Expect(err).Should(BeNil())
+				return passed
+			}
+			Expect(check("cluster.status.phase == 'Running'")).Should(BeTrue())
+			Expect(check("pods.exists(p, p.role == 'primary' && p.ready)")).Should(BeTrue())
+			Expect(check("pods.all(p, p.ready)")).Should(BeFalse())
+			Expect(check("component.spec.replicas == params.replicas")).Should(BeTrue())
+		})
+	})
+})
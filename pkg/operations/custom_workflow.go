@@ -21,6 +21,9 @@ package operations
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -34,6 +37,8 @@ type WorkflowStatus struct {
 	IsCompleted    bool
 	ExistFailure   bool
 	CompletedCount int
+	// the duration to run the workflow again, it is set if the running action is not driven by the events.
+	RequeueAfter time.Duration
 }
 
 type WorkflowContext struct {
@@ -85,11 +90,36 @@ steps:
 			err = intctrlutil.NewFatalError("can not find the action progress for action " + actions[i].Name)
 			return nil, err
 		}
+		var (
+			outputs        map[string]string
+			params         map[string]string
+			compCustomItem *opsv1alpha1.CustomOpsComponent
+		)
+		if actionProgress.Status == opsv1alpha1.PendingProgressStatus || actionProgress.Status == opsv1alpha1.ProcessingProgressStatus {
+			outputs = buildActionOutputs(compStatus.ProgressDetails)
+			compCustomItem = buildActionCustomOpsComponent(compCustomSpec, actions[i], outputs)
+			params, err = covertParametersToMap(w.reqCtx.Ctx, w.Cli, compCustomItem.Parameters, w.OpsRes.OpsRequest.Namespace)
+			if err != nil {
+				return nil, err
+			}
+		}
 		switch actionProgress.Status {
 		case opsv1alpha1.PendingProgressStatus:
-			// execute action and set status progress
 			progressDetail := *actionProgress
-			ac := w.getAction(actions[i], compCustomSpec, compSpec, progressDetail)
+			// skip the action if the condition is not met
+			var matched bool
+			if matched, err = w.checkActionCondition(actions[i], compCustomSpec.ComponentName, params, outputs); err != nil {
+				return nil, err
+			}
+			if !matched {
+				progressDetail.SetStatusAndMessage(opsv1alpha1.SucceedProgressStatus,
+					fmt.Sprintf(`the action "%s" of the component "%s" is skipped because the condition is not met`, actions[i].Name, compCustomSpec.ComponentName))
+				setComponentStatusProgressDetail(w.reqCtx.Recorder, w.OpsRes.OpsRequest, &compStatus.ProgressDetails, progressDetail)
+				setSucceedWorkflowStatus(i)
+				continue
+			}
+			// execute action and set status progress
+			ac := w.getAction(actions[i], compCustomItem, compSpec, params, outputs, progressDetail)
 			if ac == nil {
 				err = intctrlutil.NewFatalError("the action type is not implement for action " + actions[i].Name)
 				return nil, err
@@ -99,6 +129,7 @@ steps:
 				return nil, err
 			}
 			progressDetail.ActionTasks = actionStatus.ActionTasks
+			workflowStatus.RequeueAfter = actionStatus.RequeueAfter
 			progressDetail.SetStatusAndMessage(opsv1alpha1.ProcessingProgressStatus,
 				fmt.Sprintf(`Start to processing action "%s" of the component %s`, actions[i].Name, compCustomSpec.ComponentName))
 			setComponentStatusProgressDetail(w.reqCtx.Recorder, w.OpsRes.OpsRequest, &compStatus.ProgressDetails, progressDetail)
@@ -106,7 +137,7 @@ steps:
 		case opsv1alpha1.ProcessingProgressStatus:
 			// check action status and set status progress
 			progressDetail := *actionProgress
-			ac := w.getAction(actions[i], compCustomSpec, compSpec, progressDetail)
+			ac := w.getAction(actions[i], compCustomItem, compSpec, params, outputs, progressDetail)
 			if ac == nil {
 				err = intctrlutil.NewFatalError("the action type is not implement for action " + actions[i].Name)
 				return nil, err
@@ -116,6 +147,7 @@ steps:
 				return nil, err
 			}
 			progressDetail.ActionTasks = actionStatus.ActionTasks
+			workflowStatus.RequeueAfter = actionStatus.RequeueAfter
 			if actionStatus.IsCompleted {
 				if actionStatus.ExistFailure {
 					progressDetail.Status = opsv1alpha1.FailedProgressStatus
@@ -148,6 +180,8 @@ steps:
 func (w *WorkflowContext) getAction(action opsv1alpha1.OpsAction,
 	compCustomItem *opsv1alpha1.CustomOpsComponent,
 	compSpec *appsv1.ClusterComponentSpec,
+	params map[string]string,
+	outputs map[string]string,
 	progressDetail opsv1alpha1.ProgressStatusDetail) custom.OpsAction {
 	switch {
 	case action.Workload != nil:
@@ -156,6 +190,11 @@ func (w *WorkflowContext) getAction(action opsv1alpha1.OpsAction,
 	case action.Exec != nil:
 		return custom.NewExecAction(w.OpsRes.OpsRequest, w.OpsRes.Cluster,
 			w.OpsRes.OpsDef, compCustomItem, compSpec, progressDetail)
+	case action.LifecycleAction != nil:
+		return custom.NewLifecycleAction(w.OpsRes.OpsRequest, w.OpsRes.Cluster,
+			w.OpsRes.OpsDef, compCustomItem, params, progressDetail)
+	case action.WaitFor != nil:
+		return custom.NewWaitForAction(w.OpsRes.Cluster, compCustomItem, params, outputs, progressDetail)
 	case action.ResourceModifier != nil:
 		// TODO: implement it.
 		return nil
@@ -163,3 +202,72 @@ func (w *WorkflowContext) getAction(action opsv1alpha1.OpsAction,
 		return nil
 	}
 }
+
+// checkActionCondition checks if the action should be executed by the expression of the "when".
+// The action is skipped if the expression is rendered as "false" for any of the components.
+func (w *WorkflowContext) checkActionCondition(action opsv1alpha1.OpsAction,
+	compName string,
+	params map[string]string,
+	outputs map[string]string) (bool, error) {
+	if action.When == "" {
+		return true, nil
+	}
+	comps, err := custom.ListComponents(w.reqCtx.Ctx, w.Cli, w.OpsRes.Cluster, compName)
+	if err != nil {
+		return false, err
+	}
+	for i := range comps {
+		data, err := custom.BuildExpressionData(w.OpsRes.Cluster, &comps[i], params, outputs)
+		if err != nil {
+			return false, err
+		}
+		result, err := custom.RenderExpression(action.When, data)
+		if err != nil {
+			return false, intctrlutil.NewFatalError(fmt.Sprintf(`failed to render the "when" expression of the action "%s": %s`, action.Name, err.Error()))
+		}
+		if result == "false" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// buildActionOutputs builds the outputs of the completed actions, the output of an action is the outputs of its tasks joined by a newline.
+func buildActionOutputs(progressDetails []opsv1alpha1.ProgressStatusDetail) map[string]string {
+	outputs := map[string]string{}
+	for _, v := range progressDetails {
+		if v.Status != opsv1alpha1.SucceedProgressStatus && v.Status != opsv1alpha1.FailedProgressStatus {
+			continue
+		}
+		var taskOutputs []string
+		for _, task := range v.ActionTasks {
+			if task.Output != "" {
+				taskOutputs = append(taskOutputs, task.Output)
+			}
+		}
+		outputs[v.ActionName] = strings.Join(taskOutputs, "\n")
+	}
+	return outputs
+}
+
+// buildActionCustomOpsComponent builds the CustomOpsComponent for the action with the parameters from the outputs of the earlier actions.
+func buildActionCustomOpsComponent(compCustomSpec *opsv1alpha1.CustomOpsComponent,
+	action opsv1alpha1.OpsAction,
+	outputs map[string]string) *opsv1alpha1.CustomOpsComponent {
+	if len(action.ParametersFrom) == 0 {
+		return compCustomSpec
+	}
+	compCustomItem := compCustomSpec.DeepCopy()
+	for _, ref := range action.ParametersFrom {
+		param := opsv1alpha1.Parameter{Name: ref.Name, Value: outputs[ref.ActionName]}
+		index := slices.IndexFunc(compCustomItem.Parameters, func(p opsv1alpha1.Parameter) bool {
+			return p.Name == ref.Name
+		})
+		if index >= 0 {
+			compCustomItem.Parameters[index] = param
+		} else {
+			compCustomItem.Parameters = append(compCustomItem.Parameters, param)
+		}
+	}
+	return compCustomItem
+}
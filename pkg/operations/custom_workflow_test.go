@@ -0,0 +1,103 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/operations/custom"
+)
+
+var _ = Describe("Custom workflow util test", func() {
+
+	Context("action outputs and conditions", func() {
+		It("passes the outputs of the earlier actions to the later actions", func() {
+			progressDetails := []opsv1alpha1.ProgressStatusDetail{
+				{
+					ActionName: "probe",
+					Status:     opsv1alpha1.SucceedProgressStatus,
+					ActionTasks: []opsv1alpha1.ActionTask{
+						{ObjectKey: "Pod/pod-0", Output: "primary"},
+						{ObjectKey: "Pod/pod-1", Output: "secondary"},
+						{ObjectKey: "Pod/pod-2"},
+					},
+				},
+				{
+					ActionName: "skipped",
+					Status:     opsv1alpha1.SucceedProgressStatus,
+				},
+				{
+					ActionName:  "pending",
+					Status:      opsv1alpha1.PendingProgressStatus,
+					ActionTasks: []opsv1alpha1.ActionTask{{ObjectKey: "Pod/pod-0", Output: "ignored"}},
+				},
+			}
+			outputs := buildActionOutputs(progressDetails)
+			Expect(outputs).Should(Equal(map[string]string{"probe": "primary\nsecondary", "skipped": ""}))
+
+			compCustomSpec := &opsv1alpha1.CustomOpsComponent{
+				ComponentOps: opsv1alpha1.ComponentOps{ComponentName: "mysql"},
+				Parameters: []opsv1alpha1.Parameter{
+					{Name: "sql", Value: "select 1"},
+					{Name: "roles", Value: "overridden"},
+				},
+			}
+			action := opsv1alpha1.OpsAction{
+				Name: "switchover",
+				ParametersFrom: []opsv1alpha1.OpsActionOutputRef{
+					{Name: "roles", ActionName: "probe"},
+					{Name: "candidate", ActionName: "skipped"},
+				},
+			}
+			compCustomItem := buildActionCustomOpsComponent(compCustomSpec, action, outputs)
+			Expect(compCustomItem.Parameters).Should(Equal([]opsv1alpha1.Parameter{
+				{Name: "sql", Value: "select 1"},
+				{Name: "roles", Value: "primary\nsecondary"},
+				{Name: "candidate"},
+			}))
+			// the spec of the OpsRequest should not be changed.
+			Expect(compCustomSpec.Parameters).Should(HaveLen(2))
+			Expect(buildActionCustomOpsComponent(compCustomSpec, opsv1alpha1.OpsAction{Name: "sql"}, outputs)).Should(BeIdenticalTo(compCustomSpec))
+		})
+
+		It("renders the expressions with the parameters and outputs", func() {
+			comp := &appsv1.Component{Status: appsv1.ComponentStatus{Phase: appsv1.RunningClusterCompPhase}}
+			data, err := custom.BuildExpressionData(&appsv1.Cluster{}, comp,
+				map[string]string{"force": "false"}, map[string]string{"probe": "primary"})
+			Expect(err).Should(BeNil())
+
+			result, err := custom.RenderExpression(`{{ eq .outputs.probe "primary" }}`, data)
+			Expect(err).Should(BeNil())
+			Expect(result).Should(Equal("true"))
+
+			result, err = custom.RenderExpression(`
+{{- and (eq .component.status.phase "Running") (eq .parameters.force "true") }}
+`, data)
+			Expect(err).Should(BeNil())
+			Expect(result).Should(Equal("false"))
+
+			_, err = custom.RenderExpression(`{{ eq .outputs.probe }`, data)
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
+})
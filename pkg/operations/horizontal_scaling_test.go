@@ -162,6 +162,8 @@ var _ = Describe("HorizontalScaling OpsRequest", func() {
 			opsProgressDetails := opsRes.OpsRequest.Status.Components[defaultCompName].ProgressDetails
 			Expect(opsRes.OpsRequest.Status.Progress).Should(Equal("2/2"))
 			Expect(len(opsProgressDetails)).Should(Equal(2))
+			// the outcome of cancelled horizontal scaling is not reported as cancelled with rollback
+			Expect(opsRes.OpsRequest.Status.Components[defaultCompName].Reason).ShouldNot(Equal(opsv1alpha1.ReasonOpsCancelledWithRollback))
 		}
 
 		deletePods := func(pods ...*corev1.Pod) {
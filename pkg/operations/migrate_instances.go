@@ -0,0 +1,615 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	pendingToMigratePrefix          = "Pending to migrate pod"
+	switchingOverLeaderPrefix       = "Switching over the leader"
+	waitingForSwitchoverCandidate   = "Waiting for an available instance on other nodes to switch over the leader to"
+	waitingForInstanceMigratedMsg   = "Waiting for the instance to be recreated on other nodes"
+	reasonLeaderSwitchedOver        = "LeaderSwitchedOver"
+	reasonInstancesOfOtherClusters  = "InstancesOfOtherClusters"
+	migrateInstancesRequeueDuration = 5 * time.Second
+	// switchoverLeaderTimeout is the duration to wait for the role labels to be updated after the switchover.
+	switchoverLeaderTimeout  = 5 * time.Minute
+	switchoverSinceSeparator = " since "
+)
+
+type migrateInstancesOpsHandler struct{}
+
+var _ OpsHandler = migrateInstancesOpsHandler{}
+
+func init() {
+	migrateInstancesBehaviour := OpsBehaviour{
+		FromClusterPhases: []appsv1.ClusterPhase{appsv1.RunningClusterPhase, appsv1.AbnormalClusterPhase, appsv1.UpdatingClusterPhase},
+		ToClusterPhase:    appsv1.UpdatingClusterPhase,
+		QueueByCluster:    true,
+		OpsHandler:        migrateInstancesOpsHandler{},
+	}
+	opsMgr := GetOpsManager()
+	opsMgr.RegisterOps(opsv1alpha1.MigrateInstancesType, migrateInstancesBehaviour)
+}
+
+// ActionStartedCondition the started condition when handle the migrate-instances request.
+func (r migrateInstancesOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*metav1.Condition, error) {
+	return opsv1alpha1.NewInstancesMigratingCondition(opsRes.OpsRequest), nil
+}
+
+// Action checks the nodes to migrate the instances off, they must be cordoned to keep the migrated instances
+// from being scheduled back.
+func (r migrateInstancesOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	migrateInstances := opsRes.OpsRequest.Spec.MigrateInstances
+	nodes, err := r.listNodes(reqCtx, cli, migrateInstances)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return intctrlutil.NewFatalError("no node matches the nodeName or nodeSelector of spec.migrateInstances")
+	}
+	for _, node := range nodes {
+		if !node.Spec.Unschedulable {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`the node "%s" must be cordoned before migrating the instances off it`, node.Name))
+		}
+	}
+	return nil
+}
+
+func (r migrateInstancesOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	return nil
+}
+
+// ReconcileAction will be performed when action is done and loops till OpsRequest.status.phase is Succeed/Failed.
+// the Reconcile function for migrate-instances opsRequest.
+func (r migrateInstancesOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (opsv1alpha1.OpsPhase, time.Duration, error) {
+	var (
+		oldOpsRequest   = opsRes.OpsRequest.DeepCopy()
+		opsRequestPhase = opsRes.OpsRequest.Status.Phase
+		expectCount     int
+		completedCount  int
+		failedCount     int
+	)
+	nodes, err := r.listNodes(reqCtx, cli, opsRes.OpsRequest.Spec.MigrateInstances)
+	if err != nil {
+		return opsRequestPhase, 0, err
+	}
+	nodeNames := sets.New[string]()
+	for _, node := range nodes {
+		nodeNames.Insert(node.Name)
+	}
+	if opsRes.OpsRequest.Status.Components == nil {
+		// the instances to migrate are determined at the beginning.
+		if err = r.initProgressDetails(reqCtx, cli, opsRes, nodeNames); err != nil {
+			return opsRequestPhase, 0, err
+		}
+	}
+	for compName, compStatus := range opsRes.OpsRequest.Status.Components {
+		subCompletedCount, subFailedCount, err := r.migrateComponentInstances(reqCtx, cli, opsRes, compName, &compStatus, nodeNames)
+		if err != nil {
+			return opsRequestPhase, 0, err
+		}
+		expectCount += len(compStatus.ProgressDetails)
+		completedCount += subCompletedCount
+		failedCount += subFailedCount
+		opsRes.OpsRequest.Status.Components[compName] = compStatus
+	}
+	if err = syncProgressToOpsRequest(reqCtx, cli, opsRes, oldOpsRequest, completedCount, expectCount); err != nil {
+		return opsRequestPhase, 0, err
+	}
+	if completedCount != expectCount {
+		// the switchover and the data transfer run in kb-agent, requeue to refresh the progress.
+		return opsRequestPhase, migrateInstancesRequeueDuration, nil
+	}
+	if failedCount > 0 {
+		return opsv1alpha1.OpsFailedPhase, 0, nil
+	}
+	if err = (rebuildInstanceOpsHandler{}).cleanupTmpResources(reqCtx, cli, opsRes); err != nil {
+		return opsRequestPhase, 0, err
+	}
+	// the nodes are not evacuated if the instances of other clusters are still on them.
+	clusters, err := r.listOtherClustersOnNodes(reqCtx, cli, opsRes, nodeNames)
+	if err != nil {
+		return opsRequestPhase, 0, err
+	}
+	if len(clusters) > 0 {
+		return opsv1alpha1.OpsFailedPhase, 0, fmt.Errorf(`the instances of the cluster are migrated, but the instances of the clusters "%s" are still on the nodes`,
+			strings.Join(clusters, ", "))
+	}
+	return opsv1alpha1.OpsSucceedPhase, 0, nil
+}
+
+// listNodes lists the nodes specified by the nodeName or nodeSelector.
+func (r migrateInstancesOpsHandler) listNodes(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	migrateInstances *opsv1alpha1.MigrateInstances) ([]corev1.Node, error) {
+	nodeList := &corev1.NodeList{}
+	if err := cli.List(reqCtx.Ctx, nodeList); err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(migrateInstances.NodeSelector)
+	var nodes []corev1.Node
+	for _, node := range nodeList.Items {
+		if migrateInstances.NodeName != "" && node.Name != migrateInstances.NodeName {
+			continue
+		}
+		if migrateInstances.NodeName == "" && !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// initProgressDetails finds the instances of the cluster on the nodes and initializes the progress details for them.
+func (r migrateInstancesOpsHandler) initProgressDetails(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	nodeNames sets.Set[string]) error {
+	podList := &corev1.PodList{}
+	if err := cli.List(reqCtx.Ctx, podList, client.InNamespace(opsRes.Cluster.Namespace),
+		client.MatchingLabels(constant.GetClusterLabels(opsRes.Cluster.Name))); err != nil {
+		return err
+	}
+	compPods := map[string][]*corev1.Pod{}
+	for i, pod := range podList.Items {
+		compName := pod.Labels[constant.KBAppComponentLabelKey]
+		if compName == "" || !nodeNames.Has(pod.Spec.NodeName) || !isOwnedByInstanceSet(&pod) {
+			continue
+		}
+		compPods[compName] = append(compPods[compName], &podList.Items[i])
+	}
+	clusters, err := r.listOtherClustersOnNodes(reqCtx, cli, opsRes, nodeNames)
+	if err != nil {
+		return err
+	}
+	if len(clusters) > 0 {
+		opsRes.Recorder.Eventf(opsRes.OpsRequest, corev1.EventTypeWarning, reasonInstancesOfOtherClusters,
+			`the instances of the clusters "%s" are on the nodes too, create an OpsRequest for each of them to migrate their instances, `+
+				`otherwise this OpsRequest fails as the nodes are not evacuated`, strings.Join(clusters, ", "))
+	}
+	opsRes.OpsRequest.Status.Components = map[string]opsv1alpha1.OpsRequestComponentStatus{}
+	for compName, pods := range compPods {
+		synthesizedComp, err := rebuildInstanceOpsHandler{}.buildSynthesizedComponent(reqCtx.Ctx, cli, opsRes.Cluster, compName)
+		if err != nil {
+			return err
+		}
+		compStatus := opsv1alpha1.OpsRequestComponentStatus{}
+		for _, pod := range sortInstancesToMigrate(synthesizedComp, pods) {
+			setComponentStatusProgressDetail(opsRes.Recorder, opsRes.OpsRequest, &compStatus.ProgressDetails,
+				opsv1alpha1.ProgressStatusDetail{
+					ObjectKey: getProgressObjectKey(constant.PodKind, pod.Name),
+					Status:    opsv1alpha1.PendingProgressStatus,
+					Message:   fmt.Sprintf(`%s "%s" off node "%s"`, pendingToMigratePrefix, pod.Name, pod.Spec.NodeName),
+				})
+		}
+		opsRes.OpsRequest.Status.Components[compName] = compStatus
+	}
+	return nil
+}
+
+// listOtherClustersOnNodes returns the clusters other than the one of the OpsRequest that have instances on the nodes.
+// An OpsRequest migrates the instances of its own cluster only, each of the other clusters needs its own OpsRequest
+// to evacuate the nodes.
+func (r migrateInstancesOpsHandler) listOtherClustersOnNodes(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	nodeNames sets.Set[string]) ([]string, error) {
+	podList := &corev1.PodList{}
+	if err := cli.List(reqCtx.Ctx, podList, client.MatchingLabels{constant.AppManagedByLabelKey: constant.AppName}); err != nil {
+		return nil, err
+	}
+	clusters := sets.New[string]()
+	for _, pod := range podList.Items {
+		clusterName := pod.Labels[constant.AppInstanceLabelKey]
+		if clusterName == "" || !nodeNames.Has(pod.Spec.NodeName) ||
+			(pod.Namespace == opsRes.Cluster.Namespace && clusterName == opsRes.Cluster.Name) {
+			continue
+		}
+		clusters.Insert(pod.Namespace + "/" + clusterName)
+	}
+	return sets.List(clusters), nil
+}
+
+// migrateComponentInstances migrates the instances of the component one at a time.
+func (r migrateInstancesOpsHandler) migrateComponentInstances(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compName string,
+	compStatus *opsv1alpha1.OpsRequestComponentStatus,
+	nodeNames sets.Set[string]) (int, int, error) {
+	var (
+		completedCount int
+		failedCount    int
+	)
+	for _, progressDetail := range compStatus.ProgressDetails {
+		if isCompletedProgressStatus(progressDetail.Status) {
+			completedCount += 1
+			if progressDetail.Status == opsv1alpha1.FailedProgressStatus {
+				failedCount += 1
+			}
+		}
+	}
+	if completedCount == len(compStatus.ProgressDetails) {
+		return completedCount, failedCount, nil
+	}
+	synthesizedComp, err := rebuildInstanceOpsHandler{}.buildSynthesizedComponent(reqCtx.Ctx, cli, opsRes.Cluster, compName)
+	if err != nil {
+		return 0, 0, err
+	}
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, compName)
+	if err != nil {
+		return 0, 0, err
+	}
+	index := nextInstanceToMigrate(synthesizedComp, pods, compStatus.ProgressDetails, opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey])
+	if index < 0 {
+		// waiting for the other instances to be available.
+		return completedCount, failedCount, nil
+	}
+	progressDetail := compStatus.ProgressDetails[index]
+	progressDetail.Status = opsv1alpha1.ProcessingProgressStatus
+	completed, err := r.migrateInstance(reqCtx, cli, opsRes, synthesizedComp, pods, nodeNames, &progressDetail, index)
+	switch {
+	case intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal):
+		// If a fatal error occurs, this instance migrates failed.
+		progressDetail.SetStatusAndMessage(opsv1alpha1.FailedProgressStatus, err.Error())
+		completedCount += 1
+		failedCount += 1
+	case err != nil:
+		return 0, 0, err
+	case completed:
+		progressDetail.SetStatusAndMessage(opsv1alpha1.SucceedProgressStatus,
+			fmt.Sprintf("Migrate pod %s successfully", instanceNameFromObjectKey(progressDetail.ObjectKey)))
+		completedCount += 1
+	}
+	setComponentStatusProgressDetail(opsRes.Recorder, opsRes.OpsRequest, &compStatus.ProgressDetails, progressDetail)
+	return completedCount, failedCount, nil
+}
+
+// migrateInstance migrates the instance off the nodes:
+//  1. switches over the leader to an available instance on other nodes.
+//  2. rebuilds the instance in place if its volumes are bound to the node, otherwise, recreates it on other nodes.
+//  3. waits for the migrated instance to be available.
+func (r migrateInstancesOpsHandler) migrateInstance(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod,
+	nodeNames sets.Set[string],
+	progressDetail *opsv1alpha1.ProgressStatusDetail,
+	index int) (bool, error) {
+	ignoreRoleCheck := opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey]
+	podName := instanceNameFromObjectKey(progressDetail.ObjectKey)
+	idx := slices.IndexFunc(pods, func(pod *corev1.Pod) bool { return pod.Name == podName })
+	if idx < 0 {
+		if strings.HasPrefix(progressDetail.Message, pendingToMigratePrefix) {
+			// the instance has been deleted before the migration, e.g., scaled in.
+			return true, nil
+		}
+		// waiting for the instance to be recreated.
+		return false, nil
+	}
+	pod := pods[idx]
+	if progressDetail.Message == waitingForInstanceMigratedMsg {
+		if notRecreatedDuringOperation(opsRes.OpsRequest.Status.StartTimestamp, pod) ||
+			pod.Spec.NodeName == "" || nodeNames.Has(pod.Spec.NodeName) {
+			return false, nil
+		}
+		return instanceIsAvailable(synthesizedComp, pod, ignoreRoleCheck)
+	}
+
+	// 1. switch over the leader before migrating it.
+	if nodeNames.Has(pod.Spec.NodeName) {
+		if leader := leaderPod(synthesizedComp, pods); leader != nil && leader.Name == pod.Name {
+			switched, err := r.switchoverLeader(reqCtx, cli, opsRes, synthesizedComp, pods, leader, nodeNames, progressDetail)
+			if err != nil || !switched {
+				return false, err
+			}
+		}
+	}
+
+	// 2. migrate the instance.
+	nodeBound, err := hasNodeBoundVolumes(reqCtx, cli, pod)
+	if err != nil {
+		return false, err
+	}
+	if nodeBound {
+		return r.rebuildInstanceInPlace(reqCtx, cli, opsRes, synthesizedComp, progressDetail, podName, index)
+	}
+	if !nodeNames.Has(pod.Spec.NodeName) && pod.Spec.NodeName != "" {
+		// the instance has been moved to other nodes.
+		return instanceIsAvailable(synthesizedComp, pod, ignoreRoleCheck)
+	}
+	progressDetail.Message = waitingForInstanceMigratedMsg
+	var options []client.DeleteOption
+	if opsRes.OpsRequest.Spec.Force {
+		options = append(options, client.GracePeriodSeconds(0))
+	}
+	return false, intctrlutil.BackgroundDeleteObject(cli, reqCtx.Ctx, pod, options...)
+}
+
+// switchoverLeader switches over the leader to an available instance on other nodes, it returns true if the leader
+// can be migrated directly for the switchover action is not defined.
+func (r migrateInstancesOpsHandler) switchoverLeader(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod,
+	leader *corev1.Pod,
+	nodeNames sets.Set[string],
+	progressDetail *opsv1alpha1.ProgressStatusDetail) (bool, error) {
+	if strings.HasPrefix(progressDetail.Message, switchingOverLeaderPrefix) {
+		// waiting for the role labels to be updated, the migration of the instance fails if the switchover takes too long.
+		if isSwitchoverLeaderTimedOut(progressDetail.Message, time.Now()) {
+			return false, intctrlutil.NewFatalError(fmt.Sprintf(`the leader "%s" is not switched over within %s`,
+				leader.Name, switchoverLeaderTimeout))
+		}
+		return false, nil
+	}
+	candidate := selectSwitchoverCandidate(synthesizedComp, pods, leader, nodeNames, opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey])
+	if candidate == nil {
+		progressDetail.Message = waitingForSwitchoverCandidate
+		return false, nil
+	}
+	compDef, err := component.GetCompDefByName(reqCtx.Ctx, cli, synthesizedComp.CompDefName)
+	if err != nil {
+		return false, err
+	}
+	synthesizedComp.TemplateVars, _, err = component.ResolveTemplateNEnvVars(reqCtx.Ctx, cli, synthesizedComp, compDef.Spec.Vars)
+	if err != nil {
+		return false, err
+	}
+	lfa, err := lifecycle.New(synthesizedComp, nil, pods...)
+	if err != nil {
+		return false, err
+	}
+	leaderLfa, err := lifecycle.New(synthesizedComp, leader, pods...)
+	if err != nil {
+		return false, err
+	}
+	err = lifecycle.WithReadonly(reqCtx.Ctx, cli, leaderLfa, func() error {
+		return lfa.Switchover(reqCtx.Ctx, cli, nil, candidate.Name)
+	})
+	if errors.Is(err, lifecycle.ErrActionNotDefined) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	progressDetail.Message = fmt.Sprintf(`%s "%s" to "%s"%s%s`, switchingOverLeaderPrefix, leader.Name, candidate.Name,
+		switchoverSinceSeparator, time.Now().UTC().Format(time.RFC3339))
+	opsRes.Recorder.Eventf(opsRes.OpsRequest, corev1.EventTypeNormal, reasonLeaderSwitchedOver,
+		`the leader "%s" has been switched over to "%s" before the migration`, leader.Name, candidate.Name)
+	return false, nil
+}
+
+// isSwitchoverLeaderTimedOut checks whether the switchover recorded in the message has started for longer than switchoverLeaderTimeout.
+func isSwitchoverLeaderTimedOut(message string, now time.Time) bool {
+	idx := strings.LastIndex(message, switchoverSinceSeparator)
+	if idx < 0 {
+		return false
+	}
+	since, err := time.Parse(time.RFC3339, message[idx+len(switchoverSinceSeparator):])
+	if err != nil {
+		return false
+	}
+	return now.Sub(since) > switchoverLeaderTimeout
+}
+
+// rebuildInstanceInPlace rebuilds the instance with new volumes on other nodes according to the localVolumePolicy.
+func (r migrateInstancesOpsHandler) rebuildInstanceInPlace(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	progressDetail *opsv1alpha1.ProgressStatusDetail,
+	podName string,
+	index int) (bool, error) {
+	instance := opsv1alpha1.Instance{Name: podName}
+	inPlaceHelper, err := rebuildInstanceOpsHandler{}.prepareInplaceRebuildHelper(reqCtx, cli, opsRes, nil, instance, "", index)
+	if err != nil {
+		return false, err
+	}
+	if opsRes.OpsRequest.Spec.MigrateInstances.LocalVolumePolicy == opsv1alpha1.RebuildFromPeerLocalVolumePolicy {
+		rebuildFrom := opsv1alpha1.RebuildInstance{
+			ComponentOps:   opsv1alpha1.ComponentOps{ComponentName: synthesizedComp.Name},
+			Instances:      []opsv1alpha1.Instance{instance},
+			InPlace:        true,
+			SourceFromPeer: &opsv1alpha1.RebuildSourceFromPeer{},
+		}
+		return inPlaceHelper.rebuildInstanceFromPeer(reqCtx, cli, opsRes, progressDetail, rebuildFrom)
+	}
+	return inPlaceHelper.rebuildInstanceWithNoBackup(reqCtx, cli, opsRes, progressDetail)
+}
+
+// nextInstanceToMigrate returns the index of the instance to migrate in the progress details, or -1 if none can be migrated now.
+// The instance in migration takes precedence, and a pending instance can be migrated only if the availability of
+// the component allows, see maxUnavailableToMigrate.
+func nextInstanceToMigrate(synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod,
+	progressDetails []opsv1alpha1.ProgressStatusDetail,
+	ignoreRoleCheckAnnotation string) int {
+	if idx := slices.IndexFunc(progressDetails, func(detail opsv1alpha1.ProgressStatusDetail) bool {
+		return detail.Status == opsv1alpha1.ProcessingProgressStatus
+	}); idx >= 0 {
+		return idx
+	}
+	var unavailable []string
+	for _, pod := range pods {
+		if available, _ := instanceIsAvailable(synthesizedComp, pod, ignoreRoleCheckAnnotation); !available {
+			unavailable = append(unavailable, pod.Name)
+		}
+	}
+	for i, detail := range progressDetails {
+		if detail.Status != opsv1alpha1.PendingProgressStatus {
+			continue
+		}
+		name := instanceNameFromObjectKey(detail.ObjectKey)
+		// migrating an unavailable instance does not hurt the availability.
+		if slices.Contains(unavailable, name) || !slices.ContainsFunc(pods, func(pod *corev1.Pod) bool { return pod.Name == name }) {
+			return i
+		}
+		if int32(len(unavailable)+1) <= maxUnavailableToMigrate(synthesizedComp) {
+			return i
+		}
+	}
+	return -1
+}
+
+// maxUnavailableToMigrate returns the max number of the unavailable instances during the migration,
+// it follows the update strategy of the component:
+//   - Serial: only one instance can be unavailable.
+//   - BestEffortParallel: the majority of the instances should be kept available.
+//   - Parallel: all the instances can be unavailable.
+func maxUnavailableToMigrate(synthesizedComp *component.SynthesizedComponent) int32 {
+	replicas := synthesizedComp.Replicas
+	strategy := appsv1.SerialStrategy
+	if synthesizedComp.UpdateStrategy != nil {
+		strategy = *synthesizedComp.UpdateStrategy
+	}
+	switch strategy {
+	case appsv1.ParallelStrategy:
+		return max(replicas, 1)
+	case appsv1.BestEffortParallelStrategy:
+		return max(replicas-(replicas/2+1), 1)
+	default:
+		return 1
+	}
+}
+
+// sortInstancesToMigrate sorts the instances by name, and the leader is migrated last to reduce the switchovers.
+func sortInstancesToMigrate(synthesizedComp *component.SynthesizedComponent, pods []*corev1.Pod) []*corev1.Pod {
+	leader := leaderPod(synthesizedComp, pods)
+	slices.SortFunc(pods, func(a, b *corev1.Pod) int {
+		switch {
+		case leader != nil && a.Name == leader.Name:
+			return 1
+		case leader != nil && b.Name == leader.Name:
+			return -1
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+	return pods
+}
+
+// selectSwitchoverCandidate selects an available instance on other nodes to switch over the leader to,
+// it returns nil if there is no available one.
+func selectSwitchoverCandidate(synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod,
+	leader *corev1.Pod,
+	nodeNames sets.Set[string],
+	ignoreRoleCheckAnnotation string) *corev1.Pod {
+	var candidates []*corev1.Pod
+	for i, pod := range pods {
+		if pod.Name == leader.Name || pod.Spec.NodeName == "" || nodeNames.Has(pod.Spec.NodeName) {
+			continue
+		}
+		if available, _ := instanceIsAvailable(synthesizedComp, pod, ignoreRoleCheckAnnotation); available {
+			candidates = append(candidates, pods[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	slices.SortFunc(candidates, func(a, b *corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return candidates[0]
+}
+
+// hasNodeBoundVolumes checks whether the instance has the volumes which can not be attached to other nodes,
+// such as the local persistent volumes.
+func hasNodeBoundVolumes(reqCtx intctrlutil.RequestCtx, cli client.Client, pod *corev1.Pod) (bool, error) {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: volume.PersistentVolumeClaim.ClaimName, Namespace: pod.Namespace}, pvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv := &corev1.PersistentVolume{}
+		if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if isNodeBoundPV(pv) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isNodeBoundPV checks whether the pv is bound to a node by the node affinity of hostname.
+func isNodeBoundPV(pv *corev1.PersistentVolume) bool {
+	if pv.Spec.Local != nil || pv.Spec.HostPath != nil {
+		return true
+	}
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return false
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelHostname {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isOwnedByInstanceSet(pod *corev1.Pod) bool {
+	return slices.ContainsFunc(pod.OwnerReferences, func(ref metav1.OwnerReference) bool {
+		return ref.Kind == workloads.Kind
+	})
+}
+
+func instanceNameFromObjectKey(objectKey string) string {
+	return strings.TrimPrefix(objectKey, constant.PodKind+"/")
+}
@@ -0,0 +1,204 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+var _ = Describe("Migrate instances test", func() {
+
+	newPod := func(name, role, nodeName string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{constant.RoleLabelKey: role},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.Now()},
+				},
+			},
+		}
+	}
+
+	newSynthesizedComp := func(replicas int32, strategy appsv1.UpdateStrategy) *component.SynthesizedComponent {
+		return &component.SynthesizedComponent{
+			Replicas:       replicas,
+			UpdateStrategy: &strategy,
+			Roles: []appsv1.ReplicaRole{
+				{Name: "leader", Serviceable: true, Writable: true},
+				{Name: "follower", Serviceable: true},
+			},
+		}
+	}
+
+	pendingDetails := func(names ...string) []opsv1alpha1.ProgressStatusDetail {
+		var details []opsv1alpha1.ProgressStatusDetail
+		for _, name := range names {
+			details = append(details, opsv1alpha1.ProgressStatusDetail{
+				ObjectKey: getProgressObjectKey(constant.PodKind, name),
+				Status:    opsv1alpha1.PendingProgressStatus,
+			})
+		}
+		return details
+	}
+
+	It("follows the update strategy of the component", func() {
+		Expect(maxUnavailableToMigrate(newSynthesizedComp(3, appsv1.SerialStrategy))).Should(BeEquivalentTo(1))
+		Expect(maxUnavailableToMigrate(newSynthesizedComp(5, appsv1.BestEffortParallelStrategy))).Should(BeEquivalentTo(2))
+		Expect(maxUnavailableToMigrate(newSynthesizedComp(2, appsv1.BestEffortParallelStrategy))).Should(BeEquivalentTo(1))
+		Expect(maxUnavailableToMigrate(newSynthesizedComp(3, appsv1.ParallelStrategy))).Should(BeEquivalentTo(3))
+		Expect(maxUnavailableToMigrate(&component.SynthesizedComponent{Replicas: 3})).Should(BeEquivalentTo(1))
+	})
+
+	It("migrates the leader last", func() {
+		synthesizedComp := newSynthesizedComp(3, appsv1.SerialStrategy)
+		pods := sortInstancesToMigrate(synthesizedComp, []*corev1.Pod{
+			newPod("pod-2", "follower", "node-0", true),
+			newPod("pod-0", "leader", "node-0", true),
+			newPod("pod-1", "follower", "node-0", true),
+		})
+		Expect([]string{pods[0].Name, pods[1].Name, pods[2].Name}).Should(Equal([]string{"pod-1", "pod-2", "pod-0"}))
+	})
+
+	It("migrates the instances one at a time without breaking the availability", func() {
+		synthesizedComp := newSynthesizedComp(3, appsv1.SerialStrategy)
+		pods := []*corev1.Pod{
+			newPod("pod-0", "leader", "node-1", true),
+			newPod("pod-1", "follower", "node-0", true),
+			newPod("pod-2", "follower", "node-0", true),
+		}
+		details := pendingDetails("pod-1", "pod-2")
+		Expect(nextInstanceToMigrate(synthesizedComp, pods, details, "")).Should(Equal(0))
+
+		By("the instance in migration takes precedence")
+		details[1].Status = opsv1alpha1.ProcessingProgressStatus
+		Expect(nextInstanceToMigrate(synthesizedComp, pods, details, "")).Should(Equal(1))
+
+		By("wait for the unavailable instance to recover")
+		details[1].Status = opsv1alpha1.SucceedProgressStatus
+		details[0].Status = opsv1alpha1.PendingProgressStatus
+		pods[0] = newPod("pod-0", "leader", "node-1", false)
+		Expect(nextInstanceToMigrate(synthesizedComp, pods, details, "")).Should(Equal(-1))
+
+		By("migrate the unavailable instance first")
+		pods[1] = newPod("pod-1", "follower", "node-0", false)
+		Expect(nextInstanceToMigrate(synthesizedComp, pods, details, "")).Should(Equal(0))
+	})
+
+	It("switches over the leader to an available instance on other nodes", func() {
+		synthesizedComp := newSynthesizedComp(4, appsv1.SerialStrategy)
+		pods := []*corev1.Pod{
+			newPod("pod-0", "leader", "node-0", true),
+			newPod("pod-1", "follower", "node-0", true),
+			newPod("pod-2", "follower", "node-1", false),
+			newPod("pod-3", "follower", "node-2", true),
+		}
+		nodeNames := sets.New("node-0")
+		candidate := selectSwitchoverCandidate(synthesizedComp, pods, pods[0], nodeNames, "")
+		Expect(candidate.Name).Should(Equal("pod-3"))
+
+		Expect(selectSwitchoverCandidate(synthesizedComp, pods[:3], pods[0], nodeNames, "")).Should(BeNil())
+	})
+
+	It("times out the switchover of the leader", func() {
+		now := time.Now()
+		message := fmt.Sprintf(`%s "pod-0" to "pod-3"%s%s`, switchingOverLeaderPrefix, switchoverSinceSeparator, now.UTC().Format(time.RFC3339))
+		Expect(isSwitchoverLeaderTimedOut(message, now.Add(time.Minute))).Should(BeFalse())
+		Expect(isSwitchoverLeaderTimedOut(message, now.Add(switchoverLeaderTimeout+time.Second))).Should(BeTrue())
+		Expect(isSwitchoverLeaderTimedOut(switchingOverLeaderPrefix, now)).Should(BeFalse())
+	})
+
+	It("lists the other clusters on the nodes", func() {
+		newClusterPod := func(namespace, name, clusterName, nodeName string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+					Labels: map[string]string{
+						constant.AppManagedByLabelKey: constant.AppName,
+						constant.AppInstanceLabelKey:  clusterName,
+					},
+				},
+				Spec: corev1.PodSpec{NodeName: nodeName},
+			}
+		}
+		cli := fake.NewClientBuilder().WithObjects(
+			newClusterPod("default", "mycluster-mysql-0", "mycluster", "node-0"),
+			newClusterPod("default", "other-mysql-0", "other", "node-0"),
+			newClusterPod("default", "another-mysql-0", "another", "node-1"),
+			newClusterPod("test", "mycluster-mysql-0", "mycluster", "node-0"),
+		).Build()
+		opsRes := &OpsResource{
+			Cluster: &appsv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster"}},
+		}
+		reqCtx := intctrlutil.RequestCtx{Ctx: context.Background()}
+		clusters, err := migrateInstancesOpsHandler{}.listOtherClustersOnNodes(reqCtx, cli, opsRes, sets.New("node-0"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(clusters).Should(Equal([]string{"default/other", "test/mycluster"}))
+	})
+
+	It("checks whether the volume is bound to the node", func() {
+		pv := &corev1.PersistentVolume{}
+		Expect(isNodeBoundPV(pv)).Should(BeFalse())
+
+		pv.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{
+			Required: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"zone-a"}},
+					},
+				}},
+			},
+		}
+		Expect(isNodeBoundPV(pv)).Should(BeFalse())
+
+		pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions = append(pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions,
+			corev1.NodeSelectorRequirement{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-0"}})
+		Expect(isNodeBoundPV(pv)).Should(BeTrue())
+
+		Expect(isNodeBoundPV(&corev1.PersistentVolume{Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{Local: &corev1.LocalVolumeSource{Path: "/data"}},
+		}})).Should(BeTrue())
+	})
+})
@@ -135,6 +135,51 @@ func (c componentOpsHelper) cancelComponentOps(ctx context.Context,
 	return cli.Update(ctx, opsRes.Cluster)
 }
 
+// planComponentOps builds the plans of the components and the sharding components operated by the OpsRequest.
+func (c componentOpsHelper) planComponentOps(ctx context.Context,
+	cli client.Client,
+	opsRes *OpsResource,
+	planComponent func(compSpec *appsv1.ClusterComponentSpec, compOps ComponentOpsInterface, fullComponentName string) (*opsv1alpha1.ComponentPlan, error)) (*opsv1alpha1.OpsPlan, error) {
+	plan := &opsv1alpha1.OpsPlan{}
+	appendComponentPlan := func(compSpec *appsv1.ClusterComponentSpec, compOps ComponentOpsInterface, fullComponentName string) error {
+		compPlan, err := planComponent(compSpec, compOps, fullComponentName)
+		if err != nil {
+			return err
+		}
+		plan.Components = append(plan.Components, *compPlan)
+		return nil
+	}
+	// 1. plan the components
+	for i := range opsRes.Cluster.Spec.ComponentSpecs {
+		compSpec := &opsRes.Cluster.Spec.ComponentSpecs[i]
+		compOps, ok := c.componentOpsSet[compSpec.Name]
+		if !ok {
+			continue
+		}
+		if err := appendComponentPlan(compSpec, compOps, compSpec.Name); err != nil {
+			return nil, err
+		}
+	}
+	// 2. plan the components of the shardings
+	for i := range opsRes.Cluster.Spec.Shardings {
+		sharding := &opsRes.Cluster.Spec.Shardings[i]
+		compOps, ok := c.componentOpsSet[sharding.Name]
+		if !ok {
+			continue
+		}
+		shardingComps, err := intctrlutil.ListShardingComponents(ctx, cli, opsRes.Cluster, sharding.Name)
+		if err != nil {
+			return nil, err
+		}
+		for j := range shardingComps {
+			if err = appendComponentPlan(&sharding.Template, compOps, shardingComps[j].Labels[constant.KBAppComponentLabelKey]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return plan, nil
+}
+
 func (c componentOpsHelper) existFailure(ops *opsv1alpha1.OpsRequest, componentName string) bool {
 	for _, v := range ops.Status.Components[componentName].ProgressDetails {
 		if v.Status == opsv1alpha1.FailedProgressStatus {
@@ -71,16 +71,25 @@ func (opsMgr *OpsManager) Do(reqCtx intctrlutil.RequestCtx, cli client.Client, o
 		return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
 	}
 
-	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase {
+	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase || opsRequest.Status.Phase == opsv1alpha1.OpsScheduledPhase {
 		if opsRequest.Spec.Cancel {
 			return &ctrl.Result{}, PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsCancelledPhase)
 		}
+		if opsRequest.Spec.DryRun {
+			return &ctrl.Result{}, opsMgr.planOpsRequest(reqCtx, cli, opsRes, opsBehaviour)
+		}
+		if res, err := opsMgr.waitForScheduledTime(reqCtx, cli, opsRes, opsBehaviour); res != nil || err != nil {
+			return res, err
+		}
 		if err = opsMgr.doPreConditionAndTransPhaseToCreating(reqCtx, cli, opsRes, opsBehaviour); intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
 			return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
 		} else if err != nil {
 			if _, ok := err.(*WaitForClusterPhaseErr); ok {
 				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(time.Second, reqCtx.Log, "wait cluster to a right phase"))
 			}
+			if intctrlutil.IsRequeueError(err) {
+				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(err.(intctrlutil.RequeueError).RequeueAfter(), reqCtx.Log, err.Error()))
+			}
 			return nil, err
 		}
 		return intctrlutil.ResultToP(intctrlutil.Reconciled())
@@ -141,6 +150,12 @@ func (opsMgr *OpsManager) doPreConditionAndTransPhaseToCreating(reqCtx intctrlut
 			return intctrlutil.NewFatalError(err.Error())
 		}
 	}
+	if opsRes.OpsRequest.Spec.Type == opsv1alpha1.CustomType {
+		// check the CEL preConditions of the OpsDefinition before the operation is started
+		if err = checkCELPreConditions(reqCtx, cli, opsRes); err != nil {
+			return err
+		}
+	}
 	opsDeepCopy := opsRes.OpsRequest.DeepCopy()
 	// save last configuration into status.lastConfiguration
 	if err = opsBehaviour.OpsHandler.SaveLastConfiguration(reqCtx, cli, opsRes); err != nil {
@@ -204,7 +219,9 @@ func (opsMgr *OpsManager) handleOpsCompleted(reqCtx intctrlutil.RequestCtx,
 		return err
 	}
 	if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
-		return PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsCancelledPhase, cancelledCondition)
+		opsDeepCopy := opsRes.OpsRequest.DeepCopy()
+		setCancelledComponentsStatus(opsRes.OpsRequest)
+		return PatchOpsStatusWithOpsDeepCopy(reqCtx.Ctx, cli, opsRes, opsDeepCopy, opsv1alpha1.OpsCancelledPhase, cancelledCondition)
 	}
 	return PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsRequestPhase, completedCondition)
 }
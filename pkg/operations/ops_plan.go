@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// planOpsRequest computes the execution plan of the dry-run OpsRequest and completes it in the Planned phase.
+// the Cluster is not mutated and the OpsRequest is never enqueued.
+func (opsMgr *OpsManager) planOpsRequest(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	opsBehaviour OpsBehaviour) error {
+	if opsBehaviour.PlanFunc == nil {
+		return patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes,
+			fmt.Sprintf(`dry-run is not supported for the OpsRequest of type "%s"`, opsRes.OpsRequest.Spec.Type))
+	}
+	plan, err := opsBehaviour.PlanFunc(reqCtx, cli, opsRes)
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
+		}
+		return err
+	}
+	opsDeepCopy := opsRes.OpsRequest.DeepCopy()
+	opsRes.OpsRequest.Status.Plan = plan
+	return PatchOpsStatusWithOpsDeepCopy(reqCtx.Ctx, cli, opsRes, opsDeepCopy, opsv1alpha1.OpsPlannedPhase,
+		opsv1alpha1.NewPlannedCondition(opsRes.OpsRequest))
+}
+
+// buildComponentPlan lists the instances of the component in the order they will be updated by the InstanceSet.
+// podAction returns the action taken on the pod, the restarted pods are grouped into the update steps of the InstanceSet,
+// and the reloaded pods are reloaded in a single step.
+func buildComponentPlan(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1.Cluster,
+	compName string,
+	podAction func(pod *corev1.Pod) opsv1alpha1.PlanAction) (*opsv1alpha1.ComponentPlan, error) {
+	compPlan := &opsv1alpha1.ComponentPlan{
+		ComponentName: compName,
+		Action:        opsv1alpha1.NonePlanAction,
+	}
+	its := &workloads.InstanceSet{}
+	itsKey := client.ObjectKey{Namespace: cluster.Namespace, Name: constant.GenerateWorkloadNamePattern(cluster.Name, compName)}
+	if err := cli.Get(ctx, itsKey, its); err != nil {
+		if apierrors.IsNotFound(err) {
+			compPlan.Message = fmt.Sprintf(`the instanceSet workload is not found for the component "%s"`, compName)
+			return compPlan, nil
+		}
+		return nil, err
+	}
+	if its.Spec.MemberUpdateStrategy != nil {
+		compPlan.UpdateStrategy = appsv1.UpdateStrategy(*its.Spec.MemberUpdateStrategy)
+	}
+	pods, err := component.ListOwnedPods(ctx, cli, cluster.Namespace, cluster.Name, compName)
+	if err != nil {
+		return nil, err
+	}
+
+	var restartPods, reloadPods, untouchedPods []*corev1.Pod
+	for _, pod := range pods {
+		switch podAction(pod) {
+		case opsv1alpha1.RestartPlanAction:
+			restartPods = append(restartPods, pod)
+		case opsv1alpha1.ReloadPlanAction:
+			reloadPods = append(reloadPods, pod)
+		default:
+			untouchedPods = append(untouchedPods, pod)
+		}
+	}
+	appendInstances := func(pods []*corev1.Pod, action opsv1alpha1.PlanAction, step int32) {
+		for _, pod := range pods {
+			compPlan.Instances = append(compPlan.Instances, opsv1alpha1.InstancePlan{
+				Name:   pod.Name,
+				Role:   pod.Labels[constant.RoleLabelKey],
+				Action: action,
+				Step:   step,
+			})
+		}
+	}
+	steps, err := instanceset.BuildUpdateSteps(its, restartPods)
+	if err != nil {
+		return nil, err
+	}
+	for i, stepPods := range steps {
+		appendInstances(stepPods, opsv1alpha1.RestartPlanAction, int32(i+1))
+	}
+	if len(reloadPods) > 0 {
+		appendInstances(reloadPods, opsv1alpha1.ReloadPlanAction, int32(len(steps)+1))
+	}
+	appendInstances(untouchedPods, opsv1alpha1.NonePlanAction, 0)
+
+	switch {
+	case len(restartPods) > 0:
+		compPlan.Action = opsv1alpha1.RestartPlanAction
+	case len(reloadPods) > 0:
+		compPlan.Action = opsv1alpha1.ReloadPlanAction
+	}
+	return compPlan, nil
+}
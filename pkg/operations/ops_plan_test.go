@@ -0,0 +1,185 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testops "github.com/apecloud/kubeblocks/pkg/testutil/operations"
+)
+
+var _ = Describe("OpsRequest DryRun", func() {
+	var (
+		randomStr   = testCtx.GetRandomStr()
+		compDefName = "test-compdef-" + randomStr
+		clusterName = "test-cluster-" + randomStr
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.InstanceSetSignature, true, inNS, ml)
+	}
+
+	Context("Test OpsRequest with dryRun", func() {
+		var (
+			opsRes *OpsResource
+			reqCtx intctrlutil.RequestCtx
+		)
+
+		BeforeEach(cleanEnv)
+
+		AfterEach(cleanEnv)
+
+		BeforeEach(func() {
+			reqCtx = intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			By("init operations resources")
+			opsRes, _, _ = initOperationsResources(compDefName, clusterName)
+			its := testapps.MockInstanceSetComponent(&testCtx, clusterName, defaultCompName)
+			testapps.MockInstanceSetPods(&testCtx, its, opsRes.Cluster, defaultCompName)
+		})
+
+		expectPlanned := func(ops *opsv1alpha1.OpsRequest) *opsv1alpha1.OpsPlan {
+			ops.Spec.DryRun = true
+			opsRes.OpsRequest = testops.CreateOpsRequest(ctx, testCtx, ops)
+			opsRes.OpsRequest.Status.Phase = opsv1alpha1.OpsPendingPhase
+			clusterGeneration := opsRes.Cluster.Generation
+
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testops.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(opsv1alpha1.OpsPlannedPhase))
+
+			By("expect the cluster is not mutated and the opsRequest is not enqueued")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Generation).Should(Equal(clusterGeneration))
+				g.Expect(cluster.Annotations[constant.OpsRequestAnnotationKey]).Should(BeEmpty())
+			})).Should(Succeed())
+
+			plan := &opsv1alpha1.OpsRequest{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(opsRes.OpsRequest), plan)).Should(Succeed())
+			Expect(plan.Status.Plan).ShouldNot(BeNil())
+			return plan.Status.Plan
+		}
+
+		It("plans the restart in the role-priority order", func() {
+			ops := testops.NewOpsRequestObj("restart-ops-"+randomStr, testCtx.DefaultNamespace,
+				clusterName, opsv1alpha1.RestartType)
+			ops.Spec.RestartList = []opsv1alpha1.ComponentOps{{ComponentName: defaultCompName}}
+			plan := expectPlanned(ops)
+
+			Expect(plan.Components).Should(HaveLen(1))
+			compPlan := plan.Components[0]
+			Expect(compPlan.ComponentName).Should(Equal(defaultCompName))
+			Expect(compPlan.Action).Should(Equal(opsv1alpha1.RestartPlanAction))
+			Expect(compPlan.Instances).Should(HaveLen(3))
+			for i, instance := range compPlan.Instances {
+				Expect(instance.Action).Should(Equal(opsv1alpha1.RestartPlanAction))
+				Expect(instance.Step).Should(BeEquivalentTo(i + 1))
+			}
+			By("expect the leader is restarted at last")
+			Expect(compPlan.Instances[2].Role).Should(Equal("leader"))
+		})
+
+		It("plans the vertical scaling without updating the cluster", func() {
+			ops := testops.NewOpsRequestObj("vscaling-ops-"+randomStr, testCtx.DefaultNamespace,
+				clusterName, opsv1alpha1.VerticalScalingType)
+			ops.Spec.VerticalScalingList = []opsv1alpha1.VerticalScaling{
+				{
+					ComponentOps: opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+					ResourceRequirements: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("400m")},
+					},
+				},
+			}
+			plan := expectPlanned(ops)
+
+			Expect(plan.Components).Should(HaveLen(1))
+			Expect(plan.Components[0].Action).Should(Equal(opsv1alpha1.RestartPlanAction))
+			Expect(plan.Components[0].Instances).Should(HaveLen(3))
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Spec.ComponentSpecs[0].Resources.Requests.Cpu().String()).ShouldNot(Equal("400m"))
+			})).Should(Succeed())
+		})
+	})
+
+	Context("Test reconfiguring plan parameters", func() {
+		It("classifies the parameters by the config constraint", func() {
+			dynamicParam := "max_connections"
+			configPatch := &cfgcore.ConfigPatchInfo{
+				IsModify: true,
+				UpdateConfig: map[string][]byte{
+					"my.cnf": []byte(`{"mysqld":{"max_connections":"1000","innodb_buffer_pool_size":"1G"}}`),
+				},
+			}
+			cc := &appsv1beta1.ConfigConstraint{
+				Spec: appsv1beta1.ConfigConstraintSpec{
+					DynamicParameters: []string{dynamicParam},
+					FileFormatConfig: &appsv1beta1.FileFormatConfig{
+						Format: appsv1beta1.Ini,
+						FormatterAction: appsv1beta1.FormatterAction{
+							IniConfig: &appsv1beta1.IniConfig{SectionName: "mysqld"},
+						},
+					},
+				},
+			}
+			item := opsv1alpha1.ConfigurationItem{Name: "mysql-config"}
+			parameters := planReconfigureParameters(item, configPatch, cc)
+			Expect(parameters).Should(Equal([]opsv1alpha1.ParameterPlan{
+				{ConfigSpecName: "mysql-config", Key: "my.cnf", Name: "innodb_buffer_pool_size", Value: pointer.String("1G"), Action: opsv1alpha1.RestartPlanAction},
+				{ConfigSpecName: "mysql-config", Key: "my.cnf", Name: dynamicParam, Value: pointer.String("1000"), Action: opsv1alpha1.ReloadPlanAction},
+			}))
+
+			By("restart all parameters if the policy restarts the pods")
+			policy := appsv1alpha1.RollingPolicy
+			item.Policy = &policy
+			for _, parameter := range planReconfigureParameters(item, configPatch, cc) {
+				Expect(parameter.Action).Should(Equal(opsv1alpha1.RestartPlanAction))
+			}
+		})
+	})
+})
@@ -195,12 +195,13 @@ func handleComponentStatusProgress(
 	if err != nil {
 		return expectReplicas, completedCount, err
 	}
-	if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+	rollback := opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase && !pgRes.noRollbackOnCancel
+	if rollback {
 		completedCount = handleCancelProgressForPodsRollingUpdate(opsRes, pods, pgRes, compStatus, minReadySeconds, podApplyOps)
 	} else {
 		completedCount = handleProgressForPodsRollingUpdate(opsRes, pods, pgRes, compStatus, minReadySeconds, podApplyOps)
 	}
-	if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+	if rollback {
 		// only rollback the actual re-created pod during cancelling.
 		expectReplicas = int32(len(compStatus.ProgressDetails))
 	}
@@ -271,6 +272,32 @@ func handleCancelProgressForPodsRollingUpdate(
 	return completedCount
 }
 
+// setCancelledComponentsStatus reports the outcome of the cancelled opsRequest for the components
+// whose outcome has not been reported by the OpsHandler.
+// It only applies to the opsRequests that roll back the updated pods when cancelled, i.e. Upgrade, Reconfiguring and Restart,
+// the outcome of other types is unchanged.
+func setCancelledComponentsStatus(opsRequest *opsv1alpha1.OpsRequest) {
+	switch opsRequest.Spec.Type {
+	case opsv1alpha1.UpgradeType, opsv1alpha1.ReconfiguringType, opsv1alpha1.RestartType:
+	default:
+		return
+	}
+	for compName, compStatus := range opsRequest.Status.Components {
+		if compStatus.Reason != "" {
+			continue
+		}
+		var rolledBackCount int
+		for _, v := range compStatus.ProgressDetails {
+			if v.Status == opsv1alpha1.SucceedProgressStatus {
+				rolledBackCount += 1
+			}
+		}
+		compStatus.Reason = opsv1alpha1.ReasonOpsCancelledWithRollback
+		compStatus.Message = fmt.Sprintf("Cancelled with rollback, %d instance(s) rolled back in Component: %s", rolledBackCount, compName)
+		opsRequest.Status.Components[compName] = compStatus
+	}
+}
+
 func needToCheckRole(pgRes *progressResource) bool {
 	if pgRes.componentDef == nil {
 		panic("componentDef is nil")
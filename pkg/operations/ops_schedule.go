@@ -0,0 +1,182 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// waitForScheduledTime holds the OpsRequest in the Scheduled phase until the scheduled time arrives or the maintenance window opens.
+// it returns a nil result if the OpsRequest can be processed now.
+func (opsMgr *OpsManager) waitForScheduledTime(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	opsBehaviour OpsBehaviour) (*ctrl.Result, error) {
+	opsRequest := opsRes.OpsRequest
+	now := time.Now()
+	startTime, reason, err := getScheduledStartTime(opsRes, opsBehaviour, now)
+	if err != nil {
+		return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
+	}
+	if !startTime.After(now) {
+		if opsRequest.Status.Phase != opsv1alpha1.OpsScheduledPhase {
+			return nil, nil
+		}
+		// the scheduled time arrives, hand the opsRequest over to the normal process.
+		if err = releaseScheduledOpsRequest(reqCtx.Ctx, cli, opsRes, opsBehaviour); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{}, PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsPendingPhase,
+			opsv1alpha1.NewWaitForProcessingCondition(opsRequest))
+	}
+
+	if err = enqueueScheduledOpsRequest(reqCtx.Ctx, cli, opsRes, opsBehaviour, startTime); err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
+		}
+		return nil, err
+	}
+	if opsRequest.Status.Phase != opsv1alpha1.OpsScheduledPhase || !opsRequest.Status.ScheduledTimestamp.Time.Equal(startTime) {
+		opsDeepCopy := opsRequest.DeepCopy()
+		opsRequest.Status.ScheduledTimestamp = metav1.NewTime(startTime)
+		if err = PatchOpsStatusWithOpsDeepCopy(reqCtx.Ctx, cli, opsRes, opsDeepCopy, opsv1alpha1.OpsScheduledPhase,
+			opsv1alpha1.NewScheduledCondition(opsRequest, reason, startTime)); err != nil {
+			return nil, err
+		}
+	}
+	return intctrlutil.ResultToP(intctrlutil.RequeueAfter(startTime.Sub(now), reqCtx.Log, "wait for the scheduled time"))
+}
+
+// getScheduledStartTime returns the earliest time at which the OpsRequest is allowed to start, and the reason why it waits.
+func getScheduledStartTime(opsRes *OpsResource, opsBehaviour OpsBehaviour, now time.Time) (time.Time, string, error) {
+	var (
+		opsRequest = opsRes.OpsRequest
+		startTime  = now
+		reason     string
+	)
+	if opsRequest.Spec.ScheduledAt != nil && opsRequest.Spec.ScheduledAt.After(now) {
+		startTime = opsRequest.Spec.ScheduledAt.Time
+		reason = opsv1alpha1.ReasonWaitForScheduledTime
+	}
+	window := opsRequest.Spec.MaintenanceWindow
+	if window == nil && opsBehaviour.WaitForMaintenanceWindow && !opsRequest.Force() && opsRes.Cluster != nil {
+		window = opsRes.Cluster.Spec.MaintenanceWindow
+	}
+	if window == nil {
+		return startTime, reason, nil
+	}
+	windowTime, err := nextMaintenanceWindow(window, startTime)
+	if err != nil {
+		return startTime, reason, err
+	}
+	if windowTime.After(startTime) {
+		return windowTime, opsv1alpha1.ReasonWaitForMaintenance, nil
+	}
+	return startTime, reason, nil
+}
+
+// nextMaintenanceWindow returns the earliest time at or after the given time at which the maintenance window is open.
+func nextMaintenanceWindow(window *appsv1.MaintenanceWindow, from time.Time) (time.Time, error) {
+	if window.Duration.Duration <= 0 {
+		return from, fmt.Errorf("the duration of the maintenance window must be greater than 0")
+	}
+	location, err := parseZoneOffset(window.TimeZone)
+	if err != nil {
+		return from, err
+	}
+	hour, minute, err := parseClockTime(window.StartTime)
+	if err != nil {
+		return from, err
+	}
+	weekdays := map[time.Weekday]bool{}
+	for _, day := range window.DaysOfWeek {
+		weekday, err := parseWeekday(day)
+		if err != nil {
+			return from, err
+		}
+		weekdays[weekday] = true
+	}
+
+	local := from.In(location)
+	// the windows opened in the previous days may still be open.
+	lookback := int(window.Duration.Duration/(24*time.Hour)) + 1
+	for i := -lookback; i <= 7; i++ {
+		openTime := time.Date(local.Year(), local.Month(), local.Day()+i, hour, minute, 0, 0, location)
+		if len(weekdays) > 0 && !weekdays[openTime.Weekday()] {
+			continue
+		}
+		if openTime.After(from) {
+			return openTime, nil
+		}
+		if from.Before(openTime.Add(window.Duration.Duration)) {
+			return from, nil
+		}
+	}
+	return from, fmt.Errorf("no maintenance window is found after %s", from.Format(time.RFC3339))
+}
+
+func parseClockTime(clock string) (int, int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, 0, fmt.Errorf(`invalid startTime "%s" of the maintenance window, expected format is "HH:MM"`, clock)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// parseZoneOffset parses the zone offset like "+08:00", UTC is used if it is empty.
+func parseZoneOffset(zone string) (*time.Location, error) {
+	if zone == "" {
+		return time.UTC, nil
+	}
+	invalidErr := fmt.Errorf(`invalid timeZone "%s" of the maintenance window, expected format is "+HH:MM" or "-HH:MM"`, zone)
+	if len(zone) != 6 || (zone[0] != '+' && zone[0] != '-') {
+		return nil, invalidErr
+	}
+	hours, hErr := strconv.Atoi(zone[1:3])
+	minutes, mErr := strconv.Atoi(zone[4:])
+	if hErr != nil || mErr != nil || zone[3] != ':' {
+		return nil, invalidErr
+	}
+	offset := hours*3600 + minutes*60
+	if zone[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone(zone, offset), nil
+}
+
+func parseWeekday(day appsv1.Weekday) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), string(day)) {
+			return weekday, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf(`invalid day "%s" of the maintenance window`, day)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+)
+
+var _ = Describe("OpsRequest Schedule", func() {
+	// 2024-06-05 is a Wednesday.
+	mustParse := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		Expect(err).ShouldNot(HaveOccurred())
+		return t
+	}
+
+	Context("maintenance window", func() {
+		It("returns the next opening of a daily window", func() {
+			window := &appsv1.MaintenanceWindow{
+				StartTime: "02:00",
+				Duration:  metav1.Duration{Duration: 2 * time.Hour},
+			}
+			next, err := nextMaintenanceWindow(window, mustParse("2024-06-05T10:00:00Z"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(next).Should(Equal(mustParse("2024-06-06T02:00:00Z")))
+
+			By("the window is open")
+			from := mustParse("2024-06-05T03:30:00Z")
+			next, err = nextMaintenanceWindow(window, from)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(next).Should(Equal(from))
+		})
+
+		It("respects the days of week and the time zone", func() {
+			window := &appsv1.MaintenanceWindow{
+				DaysOfWeek: []appsv1.Weekday{"Saturday", "Sunday"},
+				StartTime:  "01:00",
+				Duration:   metav1.Duration{Duration: time.Hour},
+				TimeZone:   "+08:00",
+			}
+			next, err := nextMaintenanceWindow(window, mustParse("2024-06-05T10:00:00Z"))
+			Expect(err).ShouldNot(HaveOccurred())
+			// Saturday 01:00 in +08:00 is Friday 17:00 in UTC.
+			Expect(next.UTC()).Should(Equal(mustParse("2024-06-07T17:00:00Z")))
+		})
+
+		It("handles the window which spans midnight", func() {
+			window := &appsv1.MaintenanceWindow{
+				DaysOfWeek: []appsv1.Weekday{"Tuesday"},
+				StartTime:  "23:00",
+				Duration:   metav1.Duration{Duration: 3 * time.Hour},
+			}
+			from := mustParse("2024-06-05T01:00:00Z")
+			next, err := nextMaintenanceWindow(window, from)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(next).Should(Equal(from))
+		})
+
+		It("rejects the invalid window", func() {
+			for _, window := range []*appsv1.MaintenanceWindow{
+				{StartTime: "02:00"},
+				{StartTime: "2am", Duration: metav1.Duration{Duration: time.Hour}},
+				{StartTime: "02:00", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "UTC"},
+				{StartTime: "02:00", Duration: metav1.Duration{Duration: time.Hour}, DaysOfWeek: []appsv1.Weekday{"Someday"}},
+			} {
+				_, err := nextMaintenanceWindow(window, time.Now())
+				Expect(err).Should(HaveOccurred())
+			}
+		})
+	})
+
+	Context("scheduled start time", func() {
+		var (
+			now        = mustParse("2024-06-05T10:00:00Z")
+			clusterWin = &appsv1.MaintenanceWindow{
+				StartTime: "02:00",
+				Duration:  metav1.Duration{Duration: time.Hour},
+			}
+			newOpsRes = func(spec opsv1alpha1.OpsRequestSpec) *OpsResource {
+				return &OpsResource{
+					OpsRequest: &opsv1alpha1.OpsRequest{Spec: spec},
+					Cluster: &appsv1.Cluster{Spec: appsv1.ClusterSpec{
+						MaintenanceWindow: clusterWin,
+					}},
+				}
+			}
+		)
+
+		It("waits for the scheduledAt", func() {
+			scheduledAt := metav1.NewTime(now.Add(time.Hour))
+			startTime, reason, err := getScheduledStartTime(newOpsRes(opsv1alpha1.OpsRequestSpec{ScheduledAt: &scheduledAt}), OpsBehaviour{}, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(startTime).Should(Equal(scheduledAt.Time))
+			Expect(reason).Should(Equal(opsv1alpha1.ReasonWaitForScheduledTime))
+		})
+
+		It("waits for the maintenance window of the cluster for disruptive operations", func() {
+			opsRes := newOpsRes(opsv1alpha1.OpsRequestSpec{Type: opsv1alpha1.RestartType})
+			startTime, reason, err := getScheduledStartTime(opsRes, OpsBehaviour{WaitForMaintenanceWindow: true}, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(startTime).Should(Equal(mustParse("2024-06-06T02:00:00Z")))
+			Expect(reason).Should(Equal(opsv1alpha1.ReasonWaitForMaintenance))
+
+			By("the other operations start immediately")
+			startTime, _, err = getScheduledStartTime(opsRes, OpsBehaviour{}, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(startTime).Should(Equal(now))
+
+			By("force ignores the maintenance window of the cluster")
+			opsRes.OpsRequest.Spec.Force = true
+			startTime, _, err = getScheduledStartTime(opsRes, OpsBehaviour{WaitForMaintenanceWindow: true}, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(startTime).Should(Equal(now))
+		})
+
+		It("prefers the maintenance window of the OpsRequest", func() {
+			scheduledAt := metav1.NewTime(mustParse("2024-06-06T12:00:00Z"))
+			opsRes := newOpsRes(opsv1alpha1.OpsRequestSpec{
+				ScheduledAt: &scheduledAt,
+				MaintenanceWindow: &appsv1.MaintenanceWindow{
+					StartTime: "20:00",
+					Duration:  metav1.Duration{Duration: time.Hour},
+				},
+			})
+			startTime, reason, err := getScheduledStartTime(opsRes, OpsBehaviour{WaitForMaintenanceWindow: true}, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(startTime).Should(Equal(mustParse("2024-06-06T20:00:00Z")))
+			Expect(reason).Should(Equal(opsv1alpha1.ReasonWaitForMaintenance))
+		})
+	})
+})
@@ -349,3 +349,22 @@ func getComponentSpecOrShardingTemplate(cluster *appsv1.Cluster, componentName s
 	}
 	return nil
 }
+
+// getFullComponentNames returns the name of the component, or the names of the components of the sharding.
+func getFullComponentNames(ctx context.Context, cli client.Client, cluster *appsv1.Cluster, componentName string) ([]string, error) {
+	for _, v := range cluster.Spec.Shardings {
+		if v.Name != componentName {
+			continue
+		}
+		shardingComps, err := intctrlutil.ListShardingComponents(ctx, cli, cluster, componentName)
+		if err != nil {
+			return nil, err
+		}
+		var compNames []string
+		for _, comp := range shardingComps {
+			compNames = append(compNames, comp.Labels[constant.KBAppComponentLabelKey])
+		}
+		return compNames, nil
+	}
+	return []string{componentName}, nil
+}
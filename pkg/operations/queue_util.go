@@ -54,8 +54,8 @@ func DequeueOpsRequestInClusterAnnotation(ctx context.Context, cli client.Client
 		var newOpsRequestSlice []opsv1alpha1.OpsRecorder
 		// 1. update all pending opsRequest phase to Cancelled if the head opsRequest is Failed.
 		for i := 1; i < len(opsRequestSlice); i++ {
-			if !opsRequestSlice[i].InQueue {
-				// ignore the running opsRequests.
+			if !opsRequestSlice[i].InQueue || opsRequestSlice[i].ScheduledTime != nil {
+				// ignore the running and scheduled opsRequests.
 				newOpsRequestSlice = append(newOpsRequestSlice, opsRequestSlice[i])
 				continue
 			}
@@ -161,3 +161,59 @@ func existOtherRunningOps(opsRecorderSlice []opsv1alpha1.OpsRecorder, opsType op
 	}
 	return false
 }
+
+// enqueueScheduledOpsRequest adds the scheduled OpsRequest to the ops queue of the Cluster,
+// it does not block other opsRequests until the scheduled time arrives.
+func enqueueScheduledOpsRequest(ctx context.Context, cli client.Client, opsRes *OpsResource, opsBehaviour OpsBehaviour, startTime time.Time) error {
+	if opsBehaviour.IsClusterCreation || !opsRes.OpsRequest.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	opsRequestSlice, err := opsutil.GetOpsRequestSliceFromCluster(opsRes.Cluster)
+	if err != nil {
+		return err
+	}
+	scheduledTime := metav1.NewTime(startTime)
+	index, opsRecorder := GetOpsRecorderFromSlice(opsRequestSlice, opsRes.OpsRequest.Name)
+	switch index {
+	case -1:
+		if len(opsRequestSlice) >= opsRequestQueueLimitSize {
+			return intctrlutil.NewFatalError(fmt.Sprintf("The opsRequest queue is limited to a size of %d", opsRequestQueueLimitSize))
+		}
+		opsRequestSlice = append(opsRequestSlice, opsv1alpha1.OpsRecorder{
+			Name:          opsRes.OpsRequest.Name,
+			Type:          opsRes.OpsRequest.Spec.Type,
+			QueueBySelf:   opsBehaviour.QueueBySelf,
+			InQueue:       true,
+			ScheduledTime: &scheduledTime,
+		})
+	default:
+		if opsRecorder.InQueue && opsRecorder.ScheduledTime.Equal(&scheduledTime) {
+			return nil
+		}
+		opsRequestSlice[index].InQueue = true
+		opsRequestSlice[index].ScheduledTime = &scheduledTime
+	}
+	return opsutil.UpdateClusterOpsAnnotations(ctx, cli, opsRes.Cluster, opsRequestSlice)
+}
+
+// releaseScheduledOpsRequest clears the scheduled time of the OpsRequest in the ops queue of the Cluster when the scheduled time arrives,
+// then the opsRequest is queued as usual.
+func releaseScheduledOpsRequest(ctx context.Context, cli client.Client, opsRes *OpsResource, opsBehaviour OpsBehaviour) error {
+	if opsBehaviour.IsClusterCreation {
+		return nil
+	}
+	opsRequestSlice, err := opsutil.GetOpsRequestSliceFromCluster(opsRes.Cluster)
+	if err != nil {
+		return err
+	}
+	index, opsRecorder := GetOpsRecorderFromSlice(opsRequestSlice, opsRes.OpsRequest.Name)
+	if index == -1 || opsRecorder.ScheduledTime == nil {
+		return nil
+	}
+	if opsBehaviour.QueueByCluster || opsBehaviour.QueueBySelf {
+		opsRequestSlice[index].ScheduledTime = nil
+	} else {
+		opsRequestSlice = slices.Delete(opsRequestSlice, index, index+1)
+	}
+	return opsutil.UpdateClusterOpsAnnotations(ctx, cli, opsRes.Cluster, opsRequestSlice)
+}
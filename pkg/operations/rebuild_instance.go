@@ -107,6 +107,11 @@ func (r rebuildInstanceOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli cli
 			}
 			instanceNames = append(instanceNames, ins.Name)
 		}
+		if v.SourceFromPeer != nil {
+			if err = r.validateRebuildInstanceFromPeer(reqCtx, cli, opsRes, synthesizedComp, v); err != nil {
+				return err
+			}
+		}
 		if len(v.Instances) > 0 && !v.InPlace {
 			if synthesizedComp.Name != v.ComponentName {
 				return intctrlutil.NewFatalError("sharding cluster only supports to rebuild instance in place")
@@ -143,6 +148,36 @@ func (r rebuildInstanceOpsHandler) validateRebuildInstanceWithHScale(reqCtx intc
 		"may you can rebuild instances in place with backup by set 'inPlace' to 'true'.")
 }
 
+// validateRebuildInstanceFromPeer checks that there is an available peer to rebuild from before the instances are wiped.
+func (r rebuildInstanceOpsHandler) validateRebuildInstanceFromPeer(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	rebuildFrom opsv1alpha1.RebuildInstance) error {
+	if !rebuildFrom.InPlace || rebuildFrom.BackupName != "" {
+		return intctrlutil.NewFatalError("sourceFromPeer only works with inPlace rebuilding, and can not be specified together with backupName")
+	}
+	if synthesizedComp == nil {
+		return nil
+	}
+	if synthesizedComp.LifecycleActions == nil || synthesizedComp.LifecycleActions.DataDump == nil || synthesizedComp.LifecycleActions.DataLoad == nil ||
+		synthesizedComp.LifecycleActions.DataDump.Exec == nil || synthesizedComp.LifecycleActions.DataLoad.Exec == nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the component "%s" must define the exec actions of dataDump and dataLoad to rebuild instances from peer`, rebuildFrom.ComponentName))
+	}
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, synthesizedComp.Name)
+	if err != nil {
+		return err
+	}
+	source, err := selectPeerSource(synthesizedComp, pods, rebuildFrom, opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey])
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`no available peer to rebuild the instances of component "%s" from`, rebuildFrom.ComponentName))
+	}
+	return nil
+}
+
 func (r rebuildInstanceOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
 	compOpsHelper := newComponentOpsHelper(opsRes.OpsRequest.Spec.RebuildFrom)
 	getLastComponentInfo := func(compSpec appsv1.ClusterComponentSpec, comOps ComponentOpsInterface) opsv1alpha1.LastComponentConfiguration {
@@ -220,6 +255,10 @@ func (r rebuildInstanceOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx
 	}
 	// check if the ops has been finished.
 	if completedCount != expectCount {
+		if isTransferringDataFromPeer(opsRes.OpsRequest) {
+			// the transfer runs in kb-agent, no event will trigger the reconciliation to refresh the progress.
+			return opsRequestPhase, peerTransferRequeueDuration, nil
+		}
 		return opsRequestPhase, 0, nil
 	}
 	if failedCount == 0 {
@@ -282,6 +321,9 @@ func (r rebuildInstanceOpsHandler) rebuildInstanceInPlace(reqCtx intctrlutil.Req
 		return false, err
 	}
 
+	if rebuildFrom.SourceFromPeer != nil {
+		return inPlaceHelper.rebuildInstanceFromPeer(reqCtx, cli, opsRes, progressDetail, rebuildFrom)
+	}
 	if rebuildFrom.BackupName == "" {
 		return inPlaceHelper.rebuildInstanceWithNoBackup(reqCtx, cli, opsRes, progressDetail)
 	}
@@ -0,0 +1,225 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	transferringDataFromPeerPrefix = "Transferring data from peer"
+	dataLoadedFromPeerPrefix       = "Data loaded from peer"
+
+	reasonDataLoadedFromPeer = "DataLoadedFromPeer"
+
+	peerTransferRequeueDuration = 5 * time.Second
+)
+
+// rebuildInstanceFromPeer rebuilds the instance with empty volumes, and loads the data dumped by a healthy peer into it.
+func (inPlaceHelper *inplaceRebuildHelper) rebuildInstanceFromPeer(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	progressDetail *opsv1alpha1.ProgressStatusDetail,
+	rebuildFrom opsv1alpha1.RebuildInstance) (bool, error) {
+	// 1. restore the new empty pvs.
+	completed, err := inPlaceHelper.rebuildInstancePVByPod(reqCtx, cli, opsRes, progressDetail)
+	if err != nil || !completed {
+		return false, err
+	}
+	if progressDetail.Message != waitingForInstanceReadyMessage &&
+		!strings.HasPrefix(progressDetail.Message, transferringDataFromPeerPrefix) &&
+		!strings.HasPrefix(progressDetail.Message, dataLoadedFromPeerPrefix) {
+		// 2. rebuild source pvcs and recreate the instance by deleting it.
+		return false, inPlaceHelper.rebuildSourcePVCsAndRecreateInstance(reqCtx, cli, opsRes.OpsRequest, progressDetail)
+	}
+	if !strings.HasPrefix(progressDetail.Message, dataLoadedFromPeerPrefix) {
+		// 3. stream the data from peer into the recreated instance.
+		return false, inPlaceHelper.loadDataFromPeer(reqCtx, cli, opsRes, progressDetail, rebuildFrom)
+	}
+	// 4. waiting for new instance is available.
+	return instanceIsAvailable(inPlaceHelper.synthesizedComp, inPlaceHelper.targetPod, opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey])
+}
+
+// loadDataFromPeer calls the dataLoad action of the recreated instance to load the data dumped by the peer,
+// the transfer runs in the kb-agent asynchronously, and its progress is reported in the message of progressDetail.
+func (inPlaceHelper *inplaceRebuildHelper) loadDataFromPeer(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	progressDetail *opsv1alpha1.ProgressStatusDetail,
+	rebuildFrom opsv1alpha1.RebuildInstance) error {
+	targetPod := inPlaceHelper.targetPod
+	// waiting for the instance to be recreated with the new pvs and its kb-agent to be serving.
+	if notRecreatedDuringOperation(opsRes.OpsRequest.Status.StartTimestamp, targetPod) ||
+		!targetPod.DeletionTimestamp.IsZero() || targetPod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+	source, err := inPlaceHelper.peerSource(reqCtx, cli, opsRes, progressDetail, rebuildFrom)
+	if err != nil {
+		return err
+	}
+	lfa, err := lifecycle.New(inPlaceHelper.synthesizedComp, targetPod)
+	if err != nil {
+		return err
+	}
+	transferred, err := lfa.DataLoadFrom(reqCtx.Ctx, cli, &lifecycle.Options{NonBlocking: pointer.Bool(true)}, source)
+	switch {
+	case errors.Is(err, lifecycle.ErrActionInProgress):
+		progressDetail.Message = fmt.Sprintf(`%s "%s": %s transferred`, transferringDataFromPeerPrefix, source.Name, formatTransferredBytes(transferred))
+		return nil
+	case errors.Is(err, lifecycle.ErrActionNotDefined), errors.Is(err, lifecycle.ErrActionNotImplemented),
+		errors.Is(err, lifecycle.ErrActionFailed), errors.Is(err, lifecycle.ErrActionTimedOut):
+		return intctrlutil.NewFatalError(fmt.Sprintf(`failed to load the data from peer "%s" into instance "%s": %s`, source.Name, targetPod.Name, err.Error()))
+	case err != nil:
+		return err
+	}
+	// verify the transfer, an empty dump means the data of peer is lost or the dataDump action is broken.
+	if transferred == 0 {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`no data is transferred from peer "%s" into instance "%s"`, source.Name, targetPod.Name))
+	}
+	progressDetail.Message = fmt.Sprintf(`%s "%s": %s transferred, waiting for the instance to be available`,
+		dataLoadedFromPeerPrefix, source.Name, formatTransferredBytes(transferred))
+	opsRes.Recorder.Eventf(opsRes.OpsRequest, corev1.EventTypeNormal, reasonDataLoadedFromPeer,
+		`instance "%s" has loaded %s data from peer "%s"`, targetPod.Name, formatTransferredBytes(transferred), source.Name)
+	return nil
+}
+
+// peerSource returns the peer to dump the data from, the selected peer is kept in the message of progressDetail
+// to stick to it during the transfer.
+func (inPlaceHelper *inplaceRebuildHelper) peerSource(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	progressDetail *opsv1alpha1.ProgressStatusDetail,
+	rebuildFrom opsv1alpha1.RebuildInstance) (*corev1.Pod, error) {
+	if name := peerNameFromMessage(progressDetail.Message); len(name) > 0 {
+		source := &corev1.Pod{}
+		if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: name, Namespace: opsRes.Cluster.Namespace}, source); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the peer "%s" is gone during the transfer`, name))
+			}
+			return nil, err
+		}
+		return source, nil
+	}
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, inPlaceHelper.synthesizedComp.Name)
+	if err != nil {
+		return nil, err
+	}
+	source, err := selectPeerSource(inPlaceHelper.synthesizedComp, pods, rebuildFrom, opsRes.OpsRequest.Annotations[ignoreRoleCheckAnnotationKey])
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		// the peers may be recovering, wait for them.
+		return nil, fmt.Errorf(`no available peer to rebuild instance "%s" from`, inPlaceHelper.targetPod.Name)
+	}
+	return source, nil
+}
+
+// selectPeerSource selects an available peer other than the instances to rebuild, the specified one takes precedence,
+// and the non-leader instances are preferred. It returns nil if there is no available peer.
+func selectPeerSource(synthesizedComp *component.SynthesizedComponent,
+	pods []*corev1.Pod,
+	rebuildFrom opsv1alpha1.RebuildInstance,
+	ignoreRoleCheckAnnotation string) (*corev1.Pod, error) {
+	isTarget := func(pod *corev1.Pod) bool {
+		return slices.ContainsFunc(rebuildFrom.Instances, func(ins opsv1alpha1.Instance) bool {
+			return ins.Name == pod.Name
+		})
+	}
+	if name := rebuildFrom.SourceFromPeer.InstanceName; len(name) > 0 {
+		if slices.ContainsFunc(rebuildFrom.Instances, func(ins opsv1alpha1.Instance) bool { return ins.Name == name }) {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the peer "%s" to rebuild from is being rebuilt`, name))
+		}
+		idx := slices.IndexFunc(pods, func(pod *corev1.Pod) bool { return pod.Name == name })
+		if idx < 0 {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the peer "%s" to rebuild from is not found`, name))
+		}
+		if available, _ := instanceIsAvailable(synthesizedComp, pods[idx], ignoreRoleCheckAnnotation); !available {
+			return nil, nil
+		}
+		return pods[idx], nil
+	}
+
+	var candidates []*corev1.Pod
+	for i, pod := range pods {
+		if isTarget(pod) {
+			continue
+		}
+		if available, _ := instanceIsAvailable(synthesizedComp, pod, ignoreRoleCheckAnnotation); available {
+			candidates = append(candidates, pods[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(candidates, func(a, b *corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	if leader := leaderPod(synthesizedComp, candidates); leader != nil && len(candidates) > 1 {
+		candidates = slices.DeleteFunc(candidates, func(pod *corev1.Pod) bool {
+			return pod.Name == leader.Name
+		})
+	}
+	return candidates[0], nil
+}
+
+// peerNameFromMessage parses the name of peer from the message of transfer.
+func peerNameFromMessage(message string) string {
+	if !strings.HasPrefix(message, transferringDataFromPeerPrefix) {
+		return ""
+	}
+	parts := strings.SplitN(message, `"`, 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+func formatTransferredBytes(n int64) string {
+	return resource.NewQuantity(n, resource.BinarySI).String()
+}
+
+// isTransferringDataFromPeer checks whether any instance of the opsRequest is transferring data from its peer.
+func isTransferringDataFromPeer(opsRequest *opsv1alpha1.OpsRequest) bool {
+	for _, compStatus := range opsRequest.Status.Components {
+		for _, progressDetail := range compStatus.ProgressDetails {
+			if progressDetail.Status == opsv1alpha1.ProcessingProgressStatus &&
+				strings.HasPrefix(progressDetail.Message, transferringDataFromPeerPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -504,3 +504,99 @@ var _ = Describe("OpsUtil functions", func() {
 
 	})
 })
+
+var _ = Describe("Rebuild instance from peer test", func() {
+
+	newPod := func(name, role string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{constant.RoleLabelKey: role},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.Now()},
+				},
+			},
+		}
+	}
+
+	synthesizedComp := &component.SynthesizedComponent{
+		Roles: []appsv1.ReplicaRole{
+			{Name: "leader", Serviceable: true, Writable: true},
+			{Name: "follower", Serviceable: true},
+		},
+	}
+
+	It("prefers the available non-leader peers", func() {
+		pods := []*corev1.Pod{
+			newPod("pod-0", "leader", true),
+			newPod("pod-1", "follower", false),
+			newPod("pod-2", "follower", true),
+			newPod("pod-3", "follower", true),
+		}
+		rebuildFrom := opsv1alpha1.RebuildInstance{
+			Instances:      []opsv1alpha1.Instance{{Name: "pod-2"}},
+			InPlace:        true,
+			SourceFromPeer: &opsv1alpha1.RebuildSourceFromPeer{},
+		}
+		source, err := selectPeerSource(synthesizedComp, pods, rebuildFrom, "")
+		Expect(err).Should(BeNil())
+		Expect(source.Name).Should(Equal("pod-3"))
+
+		By("fall back to the leader")
+		rebuildFrom.Instances = []opsv1alpha1.Instance{{Name: "pod-2"}, {Name: "pod-3"}}
+		source, err = selectPeerSource(synthesizedComp, pods, rebuildFrom, "")
+		Expect(err).Should(BeNil())
+		Expect(source.Name).Should(Equal("pod-0"))
+
+		By("no available peer")
+		source, err = selectPeerSource(synthesizedComp, pods[1:], rebuildFrom, "")
+		Expect(err).Should(BeNil())
+		Expect(source).Should(BeNil())
+	})
+
+	It("uses the specified peer", func() {
+		pods := []*corev1.Pod{
+			newPod("pod-0", "leader", true),
+			newPod("pod-1", "follower", false),
+			newPod("pod-2", "follower", true),
+		}
+		rebuildFrom := opsv1alpha1.RebuildInstance{
+			Instances:      []opsv1alpha1.Instance{{Name: "pod-1"}},
+			InPlace:        true,
+			SourceFromPeer: &opsv1alpha1.RebuildSourceFromPeer{InstanceName: "pod-0"},
+		}
+		source, err := selectPeerSource(synthesizedComp, pods, rebuildFrom, "")
+		Expect(err).Should(BeNil())
+		Expect(source.Name).Should(Equal("pod-0"))
+
+		rebuildFrom.SourceFromPeer.InstanceName = "pod-1"
+		_, err = selectPeerSource(synthesizedComp, pods, rebuildFrom, "")
+		Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+
+		rebuildFrom.SourceFromPeer.InstanceName = "pod-9"
+		_, err = selectPeerSource(synthesizedComp, pods, rebuildFrom, "")
+		Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+	})
+
+	It("keeps the peer in the progress message", func() {
+		message := fmt.Sprintf(`%s "%s": %s transferred`, transferringDataFromPeerPrefix, "pod-2", formatTransferredBytes(2048))
+		Expect(message).Should(HaveSuffix("2Ki transferred"))
+		Expect(peerNameFromMessage(message)).Should(Equal("pod-2"))
+		Expect(peerNameFromMessage(waitingForInstanceReadyMessage)).Should(BeEmpty())
+
+		opsRequest := &opsv1alpha1.OpsRequest{}
+		opsRequest.Status.Components = map[string]opsv1alpha1.OpsRequestComponentStatus{
+			"mysql": {ProgressDetails: []opsv1alpha1.ProgressStatusDetail{
+				{Status: opsv1alpha1.ProcessingProgressStatus, Message: message},
+			}},
+		}
+		Expect(isTransferringDataFromPeer(opsRequest)).Should(BeTrue())
+	})
+})
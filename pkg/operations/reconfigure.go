@@ -21,6 +21,8 @@ package operations
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -49,6 +51,8 @@ func init() {
 		ToClusterPhase: appsv1.UpdatingClusterPhase,
 		QueueByCluster: true,
 		OpsHandler:     &reAction,
+		CancelFunc:     reAction.Cancel,
+		PlanFunc:       reAction.Plan,
 	}
 	opsManager.RegisterOps(opsv1alpha1.ReconfiguringType, reconfigureBehaviour)
 }
@@ -58,10 +62,89 @@ func (r *reconfigureAction) ActionStartedCondition(reqCtx intctrlutil.RequestCtx
 	return opsv1alpha1.NewReconfigureCondition(opsRes.OpsRequest), nil
 }
 
+// SaveLastConfiguration records the parameters of the configuration templates to be updated,
+// which will be restored if the opsRequest is cancelled.
 func (r *reconfigureAction) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	lastConfiguration := &opsRes.OpsRequest.Status.LastConfiguration
+	lastConfiguration.Components = map[string]opsv1alpha1.LastComponentConfiguration{}
+	for _, params := range fromReconfigureOperations(opsRes.OpsRequest.Spec, reqCtx, cli, opsRes) {
+		configuration, err := r.getConfiguration(reqCtx, cli, opsRes, params.componentName)
+		if err != nil {
+			return err
+		}
+		if configuration == nil {
+			continue
+		}
+		item := configuration.Spec.GetConfigurationItem(params.configurationItem.Name)
+		if item == nil {
+			continue
+		}
+		lastCompConfiguration := lastConfiguration.Components[params.componentName]
+		lastCompConfiguration.Configurations = append(lastCompConfiguration.Configurations, opsv1alpha1.LastConfigurationItem{
+			Name:             item.Name,
+			ConfigFileParams: item.DeepCopy().ConfigFileParams,
+		})
+		lastConfiguration.Components[params.componentName] = lastCompConfiguration
+	}
 	return nil
 }
 
+// Cancel restores the parameters of the configuration templates recorded in the status.lastConfiguration,
+// the instances already reconfigured will be reconfigured with the previous parameters.
+func (r *reconfigureAction) Cancel(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	for compName, lastCompConfiguration := range opsRes.OpsRequest.Status.LastConfiguration.Components {
+		configuration, err := r.getConfiguration(reqCtx, cli, opsRes, compName)
+		if err != nil {
+			return err
+		}
+		if configuration == nil {
+			continue
+		}
+		newConfiguration := configuration.DeepCopy()
+		for _, lastItem := range lastCompConfiguration.Configurations {
+			if item := newConfiguration.Spec.GetConfigurationItem(lastItem.Name); item != nil {
+				item.ConfigFileParams = lastItem.DeepCopy().ConfigFileParams
+			}
+		}
+		if err = cli.Patch(reqCtx.Ctx, newConfiguration, client.MergeFrom(configuration)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconfigureAction) getConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, componentName string) (*appsv1alpha1.Configuration, error) {
+	fetcher := configctrl.NewResourceFetcher(&configctrl.ResourceCtx{
+		Context:       reqCtx.Ctx,
+		Client:        cli,
+		Namespace:     opsRes.Cluster.Namespace,
+		ClusterName:   opsRes.Cluster.Name,
+		ComponentName: componentName,
+	})
+	if err := fetcher.Configuration().Complete(); err != nil {
+		return nil, err
+	}
+	return fetcher.ConfigurationObj, nil
+}
+
+// setCancelledStatus reports the restored configuration templates of the components after cancelling.
+func (r *reconfigureAction) setCancelledStatus(opsRequest *opsv1alpha1.OpsRequest) {
+	if opsRequest.Status.Components == nil {
+		opsRequest.Status.Components = map[string]opsv1alpha1.OpsRequestComponentStatus{}
+	}
+	for compName, lastCompConfiguration := range opsRequest.Status.LastConfiguration.Components {
+		var configNames []string
+		for _, v := range lastCompConfiguration.Configurations {
+			configNames = append(configNames, v.Name)
+		}
+		compStatus := opsRequest.Status.Components[compName]
+		compStatus.Reason = opsv1alpha1.ReasonOpsCancelledWithRollback
+		compStatus.Message = fmt.Sprintf("Cancelled with rollback, the parameters of configuration: %s are restored in Component: %s",
+			strings.Join(configNames, ","), compName)
+		opsRequest.Status.Components[compName] = compStatus
+	}
+}
+
 func handleReconfigureStatusProgress(result *appsv1alpha1.ReconcileDetail, opsStatus *opsv1alpha1.OpsRequestStatus, phase appsv1alpha1.ConfigurationPhase) handleReconfigureOpsStatus {
 	return func(cmStatus *opsv1alpha1.ConfigurationItemStatus) (err error) {
 		// the Pending phase is waiting to be executed, and there is currently no valid ReconcileDetail information.
@@ -137,6 +220,9 @@ func (r *reconfigureAction) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli c
 	phase := opsv1alpha1.OpsRunningPhase
 	if isFinished {
 		phase = opsv1alpha1.OpsSucceedPhase
+		if resource.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+			r.setCancelledStatus(resource.OpsRequest)
+		}
 	}
 	return syncReconfigureForOps(reqCtx, cli, resource, statusAsComponents, opsDeepCopy, phase)
 }
@@ -198,6 +284,11 @@ func (r *reconfigureAction) doSyncReconfigureStatus(params reconfigureParams) (o
 	if item == nil || itemStatus == nil {
 		return opsv1alpha1.OpsRunningPhase, nil
 	}
+	// waits for the restored parameters to be handled by the configuration controller during cancelling.
+	if params.opsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase &&
+		itemStatus.UpdateRevision != strconv.FormatInt(resource.ConfigurationObj.Generation, 10) {
+		return opsv1alpha1.OpsRunningPhase, nil
+	}
 
 	switch phase := reconfiguringPhase(resource, *item, itemStatus); phase {
 	case appsv1alpha1.CCreatingPhase, appsv1alpha1.CInitPhase:
@@ -269,6 +360,68 @@ func (r *reconfigureAction) doReconfiguring(params reconfigureParams) error {
 	return nil
 }
 
+// Plan computes the parameters to be updated and how they take effect without updating the configuration.
+func (r *reconfigureAction) Plan(reqCtx intctrlutil.RequestCtx, cli client.Client, resource *OpsResource) (*opsv1alpha1.OpsPlan, error) {
+	plan := &opsv1alpha1.OpsPlan{}
+	for _, reconfigureParams := range fromReconfigureOperations(resource.OpsRequest.Spec, reqCtx, cli, resource) {
+		compPlan, err := r.planReconfiguring(reconfigureParams)
+		if err != nil {
+			return nil, err
+		}
+		plan.Components = append(plan.Components, *compPlan)
+	}
+	return plan, nil
+}
+
+func (r *reconfigureAction) planReconfiguring(params reconfigureParams) (*opsv1alpha1.ComponentPlan, error) {
+	item := params.configurationItem
+	opsPipeline := newPipeline(reconfigureContext{
+		cli:           params.cli,
+		reqCtx:        params.reqCtx,
+		resource:      params.resource,
+		config:        item,
+		clusterName:   params.clusterName,
+		componentName: params.componentName,
+	})
+
+	// merge the parameters without syncing them to the configuration.
+	result := opsPipeline.
+		Configuration().
+		Validate().
+		ConfigMap(item.Name).
+		ConfigConstraints().
+		Merge().
+		Complete()
+	if result.err != nil {
+		if result.failed {
+			return nil, intctrlutil.NewFatalError(result.err.Error())
+		}
+		return nil, result.err
+	}
+
+	parameters := planReconfigureParameters(item, result.configPatch, opsPipeline.configConstraint)
+	action := opsv1alpha1.NonePlanAction
+	if opsPipeline.isFileUpdated {
+		action = opsv1alpha1.RestartPlanAction
+	}
+	for _, parameter := range parameters {
+		if parameter.Action == opsv1alpha1.RestartPlanAction {
+			action = opsv1alpha1.RestartPlanAction
+			break
+		}
+		action = opsv1alpha1.ReloadPlanAction
+	}
+	compPlan, err := buildComponentPlan(params.reqCtx.Ctx, params.cli, params.resource.Cluster, params.componentName,
+		func(pod *corev1.Pod) opsv1alpha1.PlanAction {
+			return action
+		})
+	if err != nil {
+		return nil, err
+	}
+	compPlan.Parameters = parameters
+	return compPlan, nil
+}
+
 func needReconfigure(request *opsv1alpha1.OpsRequest, status *opsv1alpha1.ReconfiguringStatus) bool {
 	// Update params to configmap
 	if request.Spec.Type != opsv1alpha1.ReconfiguringType {
@@ -21,6 +21,7 @@ package operations
 
 import (
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -276,6 +277,60 @@ var _ = Describe("Reconfigure OpsRequest", func() {
 			Expect(opsRes.OpsRequest.Status.Phase).Should(Equal(opsv1alpha1.OpsSucceedPhase))
 		})
 
+		It("cancel Reconfigure OpsRequest and restore the parameters", func() {
+			opsRes, configuration, _ := assureMockReconfigureData("simple")
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx:      testCtx.Ctx,
+				Log:      log.FromContext(ctx).WithName("Reconfigure"),
+				Recorder: opsRes.Recorder,
+			}
+
+			By("create Reconfiguring opsRequest")
+			ops := testops.NewOpsRequestObj("reconfigure-ops-"+randomStr+"-cancel", testCtx.DefaultNamespace,
+				clusterName, opsv1alpha1.ReconfiguringType)
+			ops.Spec.Reconfigures = []opsv1alpha1.Reconfigure{
+				{
+					Configurations: []opsv1alpha1.ConfigurationItem{{
+						Name: "mysql-test",
+						Keys: []opsv1alpha1.ParameterConfig{{
+							Key: "my.cnf",
+							Parameters: []opsv1alpha1.ParameterPair{
+								{
+									Key:   "binlog_stmt_cache_size",
+									Value: func() *string { v := "4096"; return &v }(),
+								}},
+						}},
+					}},
+					ComponentOps: opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+				},
+			}
+			opsRes.OpsRequest = ops
+			Expect(testCtx.CheckedCreateObj(ctx, ops)).Should(Succeed())
+			initClusterForOps(opsRes)
+
+			By("reconfigure and expect the previous parameters are recorded")
+			opsManager := GetOpsManager()
+			opsRes.OpsRequest.Status.Phase = opsv1alpha1.OpsPendingPhase
+			_, err := opsManager.Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = opsManager.Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			lastConfigurations := opsRes.OpsRequest.Status.LastConfiguration.Components[defaultCompName].Configurations
+			Expect(lastConfigurations).Should(HaveLen(1))
+			Expect(lastConfigurations[0].Name).Should(Equal("mysql-test"))
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(configuration), func(g Gomega, config *appsv1alpha1.Configuration) {
+				item := config.Spec.GetConfigurationItem("mysql-test")
+				g.Expect(item.ConfigFileParams["my.cnf"].Parameters).Should(HaveKey("binlog_stmt_cache_size"))
+			})).Should(Succeed())
+
+			By("cancel the opsRequest and expect the parameters are restored")
+			cancelOpsRequest(reqCtx, opsRes, time.Now())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(configuration), func(g Gomega, config *appsv1alpha1.Configuration) {
+				item := config.Spec.GetConfigurationItem("mysql-test")
+				g.Expect(item.ConfigFileParams["my.cnf"].Parameters).ShouldNot(HaveKey("binlog_stmt_cache_size"))
+			})).Should(Succeed())
+		})
+
 		It("Test Reconfigure OpsRequest with autoReload", func() {
 			opsRes, _, _ := assureMockReconfigureData("autoReload")
 			reqCtx := intctrlutil.RequestCtx{
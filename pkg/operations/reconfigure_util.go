@@ -22,6 +22,8 @@ package operations
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cast"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -263,3 +265,42 @@ func hasFileUpdate(config opsv1alpha1.ConfigurationItem) bool {
 	}
 	return false
 }
+
+// planReconfigureParameters classifies the updated parameters into the ones reloaded dynamically and the ones requiring a restart.
+func planReconfigureParameters(item opsv1alpha1.ConfigurationItem,
+	configPatch *core.ConfigPatchInfo,
+	cc *appsv1beta1.ConfigConstraint) []opsv1alpha1.ParameterPlan {
+	if configPatch == nil || cc == nil {
+		return nil
+	}
+	// the parameters are restarted if the policy is specified to restart the pods.
+	restartPolicy := item.Policy != nil && !slices.Contains([]appsv1alpha1.UpgradePolicy{
+		appsv1alpha1.NonePolicy,
+		appsv1alpha1.AsyncDynamicReloadPolicy,
+		appsv1alpha1.SyncDynamicReloadPolicy,
+	}, *item.Policy)
+
+	var parameters []opsv1alpha1.ParameterPlan
+	for _, param := range core.GenerateVisualizedParamsList(configPatch, cc.Spec.FileFormatConfig, nil) {
+		for _, p := range param.Parameters {
+			action := opsv1alpha1.RestartPlanAction
+			if !restartPolicy && param.UpdateType == core.UpdatedType && core.IsDynamicParameter(p.Key, &cc.Spec) {
+				action = opsv1alpha1.ReloadPlanAction
+			}
+			parameters = append(parameters, opsv1alpha1.ParameterPlan{
+				ConfigSpecName: item.Name,
+				Key:            param.Key,
+				Name:           p.Key,
+				Value:          p.Value,
+				Action:         action,
+			})
+		}
+	}
+	slices.SortStableFunc(parameters, func(a, b opsv1alpha1.ParameterPlan) int {
+		if a.Key != b.Key {
+			return strings.Compare(a.Key, b.Key)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return parameters
+}
@@ -21,9 +21,11 @@ package operations
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -44,10 +46,13 @@ var _ OpsHandler = restartOpsHandler{}
 func init() {
 	restartBehaviour := OpsBehaviour{
 		// if cluster is Abnormal or Failed, new opsRequest may repair it.
-		FromClusterPhases: appsv1.GetClusterUpRunningPhases(),
-		ToClusterPhase:    appsv1.UpdatingClusterPhase,
-		QueueByCluster:    true,
-		OpsHandler:        restartOpsHandler{},
+		FromClusterPhases:        appsv1.GetClusterUpRunningPhases(),
+		ToClusterPhase:           appsv1.UpdatingClusterPhase,
+		QueueByCluster:           true,
+		OpsHandler:               restartOpsHandler{},
+		CancelFunc:               restartOpsHandler{}.Cancel,
+		PlanFunc:                 restartOpsHandler{}.Plan,
+		WaitForMaintenanceWindow: true,
 	}
 
 	opsMgr := GetOpsManager()
@@ -92,13 +97,20 @@ func (r restartOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cl
 		opsRes *OpsResource,
 		pgRes *progressResource,
 		compStatus *opsv1alpha1.OpsRequestComponentStatus) (expectProgressCount int32, completedCount int32, err error) {
+		if opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+			skipped, err := r.handleCancelProgress(reqCtx, cli, opsRes, pgRes, compStatus)
+			if err != nil || skipped {
+				return 0, 0, err
+			}
+		}
 		return handleComponentStatusProgress(reqCtx, cli, opsRes, pgRes, compStatus, r.podApplyCompOps)
 	}
 	orderedComps, err := r.getComponentOrders(reqCtx, cli, opsRes)
 	if err != nil {
 		return "", 0, err
 	}
-	if len(orderedComps) > 0 {
+	// no more components will be restarted after cancelling.
+	if len(orderedComps) > 0 && opsRes.OpsRequest.Status.Phase != opsv1alpha1.OpsCancellingPhase {
 		if err = r.restartComponents(reqCtx, cli, opsRes, orderedComps, true); err != nil {
 			return "", 0, err
 		}
@@ -107,6 +119,67 @@ func (r restartOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cl
 		"restart", handleRestartProgress)
 }
 
+// Plan computes the order in which the components and their pods are restarted without restarting them.
+func (r restartOpsHandler) Plan(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*opsv1alpha1.OpsPlan, error) {
+	r.compOpsHelper = newComponentOpsHelper(opsRes.OpsRequest.Spec.RestartList)
+	plan, err := r.compOpsHelper.planComponentOps(reqCtx.Ctx, cli, opsRes, func(compSpec *appsv1.ClusterComponentSpec,
+		compOps ComponentOpsInterface, fullComponentName string) (*opsv1alpha1.ComponentPlan, error) {
+		return buildComponentPlan(reqCtx.Ctx, cli, opsRes.Cluster, fullComponentName, func(pod *corev1.Pod) opsv1alpha1.PlanAction {
+			return opsv1alpha1.RestartPlanAction
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	orderedComps, err := r.getComponentOrders(reqCtx, cli, opsRes)
+	if err != nil {
+		return nil, err
+	}
+	// the ordered components are restarted one after another, ahead of the others.
+	orderIndex := func(compName string) int {
+		for i := range orderedComps {
+			if orderedComps[i].ComponentName == compName {
+				return i
+			}
+		}
+		return len(orderedComps)
+	}
+	slices.SortStableFunc(plan.Components, func(a, b opsv1alpha1.ComponentPlan) int {
+		return orderIndex(a.ComponentName) - orderIndex(b.ComponentName)
+	})
+	return plan, nil
+}
+
+// Cancel stops restarting the components which have not started restarting.
+// the restarted pods can not be rolled back, so the components in restarting will continue to complete.
+func (r restartOpsHandler) Cancel(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	return nil
+}
+
+// handleCancelProgress handles the progress of the component during cancelling,
+// and returns true if the component is skipped as it has not started restarting.
+func (r restartOpsHandler) handleCancelProgress(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	pgRes *progressResource,
+	compStatus *opsv1alpha1.OpsRequestComponentStatus) (bool, error) {
+	its := &workloads.InstanceSet{}
+	itsKey := client.ObjectKey{Namespace: opsRes.Cluster.Namespace, Name: constant.GenerateWorkloadNamePattern(opsRes.Cluster.Name, pgRes.fullComponentName)}
+	if err := cli.Get(reqCtx.Ctx, itsKey, its); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	compStatus.Reason = opsv1alpha1.ReasonOpsCancelledWithoutRollback
+	if its.Name == "" || !r.hasRestartAnnotation(opsRes, &its.Spec.Template) {
+		compStatus.ProgressDetails = nil
+		compStatus.Message = fmt.Sprintf("Skipped to restart Component: %s as the opsRequest is cancelled", pgRes.fullComponentName)
+		pgRes.noWaitComponentCompleted = true
+		return true, nil
+	}
+	compStatus.Message = fmt.Sprintf("The restarted pods in Component: %s can not be rolled back", pgRes.fullComponentName)
+	pgRes.noRollbackOnCancel = true
+	return false, nil
+}
+
 // SaveLastConfiguration this operation only restart the pods of the component, no changes for Cluster.spec.
 // empty implementation here.
 func (r restartOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
@@ -229,11 +302,16 @@ func (r restartOpsHandler) isRestarted(opsRes *OpsResource, object client.Object
 		podTemplate.Annotations = map[string]string{}
 	}
 	hasRestarted := true
-	startTimestamp := opsRes.OpsRequest.Status.StartTimestamp
-	workloadRestartTimeStamp := podTemplate.Annotations[constant.RestartAnnotationKey]
-	if res, _ := time.Parse(time.RFC3339, workloadRestartTimeStamp); startTimestamp.After(res) {
-		podTemplate.Annotations[constant.RestartAnnotationKey] = startTimestamp.Format(time.RFC3339)
+	if !r.hasRestartAnnotation(opsRes, podTemplate) {
+		podTemplate.Annotations[constant.RestartAnnotationKey] = opsRes.OpsRequest.Status.StartTimestamp.Format(time.RFC3339)
 		hasRestarted = false
 	}
 	return hasRestarted
 }
+
+// hasRestartAnnotation checks whether the pod template has been annotated to restart by the opsRequest.
+func (r restartOpsHandler) hasRestartAnnotation(opsRes *OpsResource, podTemplate *corev1.PodTemplateSpec) bool {
+	workloadRestartTimeStamp := podTemplate.Annotations[constant.RestartAnnotationKey]
+	res, _ := time.Parse(time.RFC3339, workloadRestartTimeStamp)
+	return !opsRes.OpsRequest.Status.StartTimestamp.After(res)
+}
@@ -162,6 +162,41 @@ var _ = Describe("Restart OpsRequest", func() {
 			ExpectCompRestarted(opsRes.OpsRequest, thirdCompName, false)
 		})
 
+		It("cancel restart OpsRequest with existing update orders", func() {
+			By("init operations resources")
+			opsRes, _, cluster = initOperationsResourcesWithTopology(clusterDefName, compDefName, clusterName)
+
+			By("create Restart opsRequest")
+			opsRes.OpsRequest = createRestartOpsObj(clusterName, "restart-ops-"+randomStr,
+				defaultCompName, secondaryCompName, thirdCompName)
+			mockComponentIsOperating(opsRes.Cluster, appsv1.UpdatingClusterCompPhase,
+				defaultCompName, secondaryCompName, thirdCompName)
+
+			By("mock restart OpsRequest to Creating and restart the first component")
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testops.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(opsv1alpha1.OpsCreatingPhase))
+			rHandler := restartOpsHandler{}
+			_ = rHandler.Action(reqCtx, k8sClient, opsRes)
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			ExpectCompRestarted(opsRes.OpsRequest, defaultCompName, true)
+
+			By("cancel the restart opsRequest")
+			cancelOpsRequest(reqCtx, opsRes, time.Now())
+
+			By("expect the rest components are skipped")
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			ExpectCompRestarted(opsRes.OpsRequest, secondaryCompName, false)
+			ExpectCompRestarted(opsRes.OpsRequest, thirdCompName, false)
+			for _, compName := range []string{defaultCompName, secondaryCompName, thirdCompName} {
+				Expect(opsRes.OpsRequest.Status.Components[compName].Reason).Should(Equal(opsv1alpha1.ReasonOpsCancelledWithoutRollback))
+			}
+			Expect(opsRes.OpsRequest.Status.Components[secondaryCompName].Message).Should(ContainSubstring("Skipped to restart"))
+			Expect(opsRes.OpsRequest.Status.Components[thirdCompName].Message).Should(ContainSubstring("Skipped to restart"))
+		})
+
 		It("expect failed when cluster is stopped", func() {
 			By("init operations resources ")
 			opsRes, _, cluster = initOperationsResources(compDefName, clusterName)
@@ -0,0 +1,536 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	// rolloutGateCheckInterval is the interval to check the gate of a rollout stage again if it does not pass.
+	rolloutGateCheckInterval = 10 * time.Second
+	rolloutQueryTimeout      = 10 * time.Second
+	rolloutRoleProbeAction   = "roleProbe"
+)
+
+// rolloutQueryClient is the HTTP client to query the endpoints of the query gates, it doesn't follow the redirects
+// to keep the queries within the allowed endpoints.
+var rolloutQueryClient = &http.Client{
+	Timeout: rolloutQueryTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// rolloutInstanceSet is an InstanceSet updated by the staged rollout.
+type rolloutInstanceSet struct {
+	*workloads.InstanceSet
+	fullComponentName string
+}
+
+// listRolloutInstanceSets lists the InstanceSets of the components and shardings updated by the OpsRequest.
+func listRolloutInstanceSets(ctx context.Context, cli client.Client, opsRes *OpsResource, compOpsHelper componentOpsHelper) ([]rolloutInstanceSet, error) {
+	var compNames []string
+	for compName := range compOpsHelper.componentOpsSet {
+		if getComponentSpecOrShardingTemplate(opsRes.Cluster, compName) == nil {
+			continue
+		}
+		fullCompNames, err := getFullComponentNames(ctx, cli, opsRes.Cluster, compName)
+		if err != nil {
+			return nil, err
+		}
+		compNames = append(compNames, fullCompNames...)
+	}
+	slices.Sort(compNames)
+	var result []rolloutInstanceSet
+	for _, compName := range compNames {
+		itsList, err := component.ListOwnedWorkloads(ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, compName)
+		if err != nil {
+			return nil, err
+		}
+		for _, its := range itsList {
+			result = append(result, rolloutInstanceSet{InstanceSet: its, fullComponentName: compName})
+		}
+	}
+	return result, nil
+}
+
+// buildInstanceSetRolloutStage builds the rollout stage of the InstanceSet for the specified stage index.
+// The instances selected by the earlier stages remain selected, and nil is returned if all the stages have passed.
+func buildInstanceSetRolloutStage(strategy *opsv1alpha1.RolloutStrategy, stageIndex int, its *workloads.InstanceSet) (*workloads.RolloutStage, error) {
+	if strategy == nil || stageIndex < 0 || stageIndex >= len(strategy.Stages) {
+		return nil, nil
+	}
+	itsStage := &workloads.RolloutStage{Name: strategy.Stages[stageIndex].Name}
+	itsReplicas := 1
+	if its.Spec.Replicas != nil {
+		itsReplicas = int(*its.Spec.Replicas)
+	}
+	for _, stage := range strategy.Stages[:stageIndex+1] {
+		if stage.Replicas != nil {
+			replicas, err := intstr.GetScaledValueFromIntOrPercent(stage.Replicas, itsReplicas, true)
+			if err != nil {
+				return nil, err
+			}
+			if itsStage.Replicas == nil || int32(replicas) > *itsStage.Replicas {
+				itsStage.Replicas = pointer.Int32(int32(replicas))
+			}
+		}
+		for _, name := range stage.InstanceTemplates {
+			if !slices.Contains(itsStage.InstanceTemplates, name) {
+				itsStage.InstanceTemplates = append(itsStage.InstanceTemplates, name)
+			}
+		}
+	}
+	return itsStage, nil
+}
+
+// applyRolloutStage sets the rollout stage of the InstanceSets updated by the OpsRequest to the specified stage.
+// The rollout stage is removed if the OpsRequest has no staged rollout or all the stages have passed.
+func applyRolloutStage(ctx context.Context, cli client.Client, opsRes *OpsResource, compOpsHelper componentOpsHelper, stageIndex int) error {
+	itsList, err := listRolloutInstanceSets(ctx, cli, opsRes, compOpsHelper)
+	if err != nil {
+		return err
+	}
+	for _, its := range itsList {
+		itsStage, err := buildInstanceSetRolloutStage(opsRes.OpsRequest.Spec.Rollout, stageIndex, its.InstanceSet)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(its.Spec.RolloutStage, itsStage) {
+			continue
+		}
+		patch := client.MergeFrom(its.DeepCopy())
+		its.Spec.RolloutStage = itsStage
+		if err = cli.Patch(ctx, its.InstanceSet, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseRolloutStage removes the rollout stage set by the OpsRequest from the InstanceSets, it's called when the
+// OpsRequest is deleted before it completes, otherwise the InstanceSets are held at the stage.
+func ReleaseRolloutStage(ctx context.Context, cli client.Client, opsRes *OpsResource) error {
+	opsRequest := opsRes.OpsRequest
+	if opsRequest.Spec.Rollout == nil || opsRequest.IsComplete() {
+		return nil
+	}
+	var compOpsHelper componentOpsHelper
+	switch opsRequest.Spec.Type {
+	case opsv1alpha1.UpgradeType:
+		compOpsHelper = newComponentOpsHelper(opsRequest.Spec.Upgrade.Components)
+	case opsv1alpha1.VerticalScalingType:
+		compOpsHelper = newComponentOpsHelper(opsRequest.Spec.VerticalScalingList)
+	default:
+		return nil
+	}
+	return applyRolloutStage(ctx, cli, opsRes, compOpsHelper, len(opsRequest.Spec.Rollout.Stages))
+}
+
+// reconcileActionWithRollout drives the staged rollout of the OpsRequest and then reconciles the progress of the components.
+// The OpsRequest keeps running until all the stages have passed, and fails if the gate of a stage fails.
+func reconcileActionWithRollout(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compOpsHelper componentOpsHelper,
+	reconcileAction func() (opsv1alpha1.OpsPhase, time.Duration, error)) (opsv1alpha1.OpsPhase, time.Duration, error) {
+	if opsRes.OpsRequest.Spec.Rollout == nil {
+		return reconcileAction()
+	}
+	// releases the InstanceSets from the rollout stage if the OpsRequest fails, otherwise they are held at the stage.
+	releaseOnFailure := func(phase opsv1alpha1.OpsPhase, requeueAfter time.Duration, err error) (opsv1alpha1.OpsPhase, time.Duration, error) {
+		if phase != opsv1alpha1.OpsFailedPhase {
+			return phase, requeueAfter, err
+		}
+		if releaseErr := applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsHelper, len(opsRes.OpsRequest.Spec.Rollout.Stages)); releaseErr != nil {
+			return opsRes.OpsRequest.Status.Phase, 0, releaseErr
+		}
+		return phase, requeueAfter, err
+	}
+	finished, rolloutRequeueAfter, err := reconcileRollout(reqCtx, cli, opsRes, compOpsHelper)
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return releaseOnFailure(opsv1alpha1.OpsFailedPhase, 0, err)
+		}
+		return opsRes.OpsRequest.Status.Phase, 0, err
+	}
+	phase, requeueAfter, err := reconcileAction()
+	if err != nil || finished {
+		return releaseOnFailure(phase, requeueAfter, err)
+	}
+	if phase == opsv1alpha1.OpsSucceedPhase {
+		// the instances may be updated before the gate of the last stage passes.
+		phase = opsv1alpha1.OpsRunningPhase
+	}
+	if phase == opsv1alpha1.OpsRunningPhase && requeueAfter == 0 {
+		requeueAfter = rolloutRequeueAfter
+	}
+	return phase, requeueAfter, nil
+}
+
+// reconcileRollout moves the staged rollout forward and records the progress in the status of the OpsRequest.
+// It returns whether all the stages have passed, and a fatal error if the gate of the current stage fails.
+func reconcileRollout(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, compOpsHelper componentOpsHelper) (bool, time.Duration, error) {
+	opsRequest := opsRes.OpsRequest
+	stages := opsRequest.Spec.Rollout.Stages
+	if opsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+		// roll back all the instances.
+		return true, 0, applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsHelper, len(stages))
+	}
+	oldOpsRequest := opsRequest.DeepCopy()
+	now := metav1.Now()
+	if opsRequest.Status.Rollout == nil {
+		opsRequest.Status.Rollout = &opsv1alpha1.RolloutStatus{
+			CurrentStage: stages[0].Name,
+			Stages: []opsv1alpha1.RolloutStageStatus{
+				{Name: stages[0].Name, Phase: opsv1alpha1.RolloutStageUpdatingPhase, StartTime: now},
+			},
+		}
+	}
+	rolloutStatus := opsRequest.Status.Rollout
+	stageIndex := slices.IndexFunc(stages, func(stage opsv1alpha1.RolloutStage) bool {
+		return stage.Name == rolloutStatus.CurrentStage
+	})
+	if stageIndex < 0 {
+		stageIndex = len(stages)
+	}
+	var (
+		finished     bool
+		requeueAfter time.Duration
+		err          error
+	)
+	for {
+		if err = applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsHelper, stageIndex); err != nil {
+			return false, 0, err
+		}
+		if stageIndex >= len(stages) {
+			finished = true
+			break
+		}
+		stageStatus := &rolloutStatus.Stages[len(rolloutStatus.Stages)-1]
+		if requeueAfter, err = reconcileRolloutStage(reqCtx, cli, opsRes, compOpsHelper, stages[stageIndex], stageStatus, now); err != nil {
+			break
+		}
+		if stageStatus.Phase != opsv1alpha1.RolloutStagePassedPhase {
+			break
+		}
+		// move to the next stage.
+		stageIndex++
+		rolloutStatus.CurrentStage = ""
+		if stageIndex < len(stages) {
+			rolloutStatus.CurrentStage = stages[stageIndex].Name
+			rolloutStatus.Stages = append(rolloutStatus.Stages, opsv1alpha1.RolloutStageStatus{
+				Name:      stages[stageIndex].Name,
+				Phase:     opsv1alpha1.RolloutStageUpdatingPhase,
+				StartTime: now,
+			})
+		}
+	}
+	if !reflect.DeepEqual(oldOpsRequest.Status, opsRequest.Status) {
+		if patchErr := cli.Status().Patch(reqCtx.Ctx, opsRequest, client.MergeFrom(oldOpsRequest)); patchErr != nil {
+			return false, 0, patchErr
+		}
+	}
+	return finished, requeueAfter, err
+}
+
+// reconcileRolloutStage checks whether the instances of the stage are updated and evaluates the gate of the stage.
+func reconcileRolloutStage(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	compOpsHelper componentOpsHelper,
+	stage opsv1alpha1.RolloutStage,
+	stageStatus *opsv1alpha1.RolloutStageStatus,
+	now metav1.Time) (time.Duration, error) {
+	itsList, err := listRolloutInstanceSets(reqCtx.Ctx, cli, opsRes, compOpsHelper)
+	if err != nil {
+		return 0, err
+	}
+	completeStage := func(phase opsv1alpha1.RolloutStagePhase, message string) {
+		stageStatus.Phase = phase
+		stageStatus.Message = message
+		stageStatus.CompletionTime = now
+	}
+	switch stageStatus.Phase {
+	case opsv1alpha1.RolloutStageUpdatingPhase:
+		for _, its := range itsList {
+			updated, err := isRolloutStageUpdated(reqCtx.Ctx, cli, opsRes, its, stage.Name)
+			if err != nil || !updated {
+				return 0, err
+			}
+		}
+		stageStatus.UpdatedTime = now
+		stageStatus.Phase = opsv1alpha1.RolloutStageGatingPhase
+		fallthrough
+	case opsv1alpha1.RolloutStageGatingPhase:
+		if stage.Gate == nil {
+			completeStage(opsv1alpha1.RolloutStagePassedPhase, "")
+			break
+		}
+		passed, message, requeueAfter, err := evaluateRolloutGate(reqCtx.Ctx, cli, opsRes, itsList, stage.Gate, stageStatus.UpdatedTime.Time, now.Time)
+		if err != nil {
+			return 0, err
+		}
+		stageStatus.Message = message
+		if !passed {
+			timeout := stage.Gate.Timeout
+			if timeout != nil && now.Time.After(stageStatus.UpdatedTime.Add(timeout.Duration)) {
+				completeStage(opsv1alpha1.RolloutStageFailedPhase, fmt.Sprintf("the gate is not passed within %s: %s", timeout.Duration, message))
+				break
+			}
+			return requeueAfter, nil
+		}
+		if stage.Gate.Approval == opsv1alpha1.ManualRolloutApproval && !isRolloutStageApproved(opsRes.OpsRequest, stage.Name) {
+			stageStatus.Phase = opsv1alpha1.RolloutStageWaitingForApprovalPhase
+			stageStatus.Message = "the gate is passed, waiting for the approval"
+			break
+		}
+		completeStage(opsv1alpha1.RolloutStagePassedPhase, "the gate is passed")
+	case opsv1alpha1.RolloutStageWaitingForApprovalPhase:
+		if isRolloutStageApproved(opsRes.OpsRequest, stage.Name) {
+			completeStage(opsv1alpha1.RolloutStagePassedPhase, "the gate is passed and approved")
+		}
+	}
+	if stageStatus.Phase == opsv1alpha1.RolloutStageFailedPhase {
+		return 0, intctrlutil.NewFatalError(fmt.Sprintf(`rollout stage "%s" failed: %s`, stage.Name, stageStatus.Message))
+	}
+	return 0, nil
+}
+
+// isRolloutStageUpdated checks whether the instances allowed by the stage are updated and available.
+// The Cluster, the Component and the InstanceSet must have observed their latest specs, to make sure the InstanceSet
+// has been updated by the OpsRequest.
+func isRolloutStageUpdated(ctx context.Context, cli client.Client, opsRes *OpsResource, its rolloutInstanceSet, stageName string) (bool, error) {
+	if opsRes.Cluster.Status.ObservedGeneration != opsRes.Cluster.Generation {
+		return false, nil
+	}
+	compObj, err := component.GetComponentByName(ctx, cli, opsRes.Cluster.Namespace,
+		constant.GenerateClusterComponentName(opsRes.Cluster.Name, its.fullComponentName))
+	if err != nil {
+		return false, err
+	}
+	if compObj.Status.ObservedGeneration != compObj.Generation || its.Status.ObservedGeneration != its.Generation {
+		return false, nil
+	}
+	stageStatus := its.Status.RolloutStage
+	return stageStatus != nil && stageStatus.Name == stageName && stageStatus.UpdatedReplicas == stageStatus.Replicas, nil
+}
+
+// isRolloutStageApproved checks whether the stage is approved by the annotation of the OpsRequest.
+func isRolloutStageApproved(opsRequest *opsv1alpha1.OpsRequest, stageName string) bool {
+	approvedStages := opsRequest.Annotations[constant.ApprovedRolloutStagesAnnotationKey]
+	for _, name := range strings.Split(approvedStages, ",") {
+		if strings.TrimSpace(name) == stageName {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateRolloutGate evaluates the checks of the gate in the order of soak, action and query.
+// It returns whether the checks are passed, the result message and the duration to check again.
+func evaluateRolloutGate(ctx context.Context,
+	cli client.Client,
+	opsRes *OpsResource,
+	itsList []rolloutInstanceSet,
+	gate *opsv1alpha1.RolloutGate,
+	updatedTime, now time.Time) (bool, string, time.Duration, error) {
+	if gate.SoakDuration != nil {
+		if remaining := updatedTime.Add(gate.SoakDuration.Duration).Sub(now); remaining > 0 {
+			return false, fmt.Sprintf("soaking, %s remaining", remaining.Round(time.Second)), remaining, nil
+		}
+	}
+	if gate.Action != nil {
+		for _, its := range itsList {
+			passed, message, err := evaluateRolloutActionGate(ctx, cli, opsRes, its, gate.Action)
+			if err != nil {
+				return false, "", 0, err
+			}
+			if !passed {
+				return false, message, rolloutGateCheckInterval, nil
+			}
+		}
+	}
+	if gate.Query != nil {
+		if passed, message := evaluateRolloutQueryGate(ctx, gate.Query); !passed {
+			return false, message, rolloutGateCheckInterval, nil
+		}
+	}
+	return true, "", 0, nil
+}
+
+// evaluateRolloutActionGate calls the lifecycle action on the updated instances of the InstanceSet,
+// the check passes if the action succeeds on all of them.
+func evaluateRolloutActionGate(ctx context.Context, cli client.Client, opsRes *OpsResource,
+	its rolloutInstanceSet, actionGate *opsv1alpha1.RolloutActionGate) (bool, string, error) {
+	compObj, compDefObj, err := component.GetCompNCompDefByName(ctx, cli, opsRes.Cluster.Namespace,
+		constant.GenerateClusterComponentName(opsRes.Cluster.Name, its.fullComponentName))
+	if err != nil {
+		return false, "", err
+	}
+	synthesizedComp, err := component.BuildSynthesizedComponent(ctx, cli, compDefObj, compObj, opsRes.Cluster)
+	if err != nil {
+		return false, "", err
+	}
+	if !lifecycle.ActionDefined(synthesizedComp, actionGate.Name) {
+		return false, fmt.Sprintf(`the action "%s" is not defined in the ComponentDefinition`, actionGate.Name), nil
+	}
+	pods, err := component.ListOwnedPods(ctx, cli, opsRes.Cluster.Namespace, opsRes.Cluster.Name, its.fullComponentName)
+	if err != nil {
+		return false, "", err
+	}
+	var updatedPods []*corev1.Pod
+	for _, pod := range pods {
+		updated, err := instanceset.IsPodUpdated(its.InstanceSet, pod)
+		if err != nil {
+			return false, "", err
+		}
+		if updated {
+			updatedPods = append(updatedPods, pod)
+		}
+	}
+	for _, pod := range updatedPods {
+		lfa, err := lifecycle.New(synthesizedComp, pod, pods...)
+		if err != nil {
+			return false, "", err
+		}
+		output, err := lifecycle.CallAction(ctx, cli, lfa, nil, actionGate.Name)
+		if errors.Is(err, lifecycle.ErrActionNotDefined) {
+			return false, fmt.Sprintf(`the action "%s" is not defined in the ComponentDefinition`, actionGate.Name), nil
+		}
+		if err != nil {
+			return false, fmt.Sprintf(`the action "%s" failed on the instance "%s": %s`, actionGate.Name, pod.Name, err.Error()), nil
+		}
+		if actionGate.Name == rolloutRoleProbeAction && len(synthesizedComp.Roles) > 0 && len(strings.TrimSpace(string(output))) == 0 {
+			return false, fmt.Sprintf(`the instance "%s" has no role`, pod.Name), nil
+		}
+	}
+	return true, "", nil
+}
+
+// isRolloutQueryEndpointAllowed checks whether the endpoint is one of the endpoints allowed by the manager config.
+func isRolloutQueryEndpointAllowed(endpoint string) bool {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	for _, allowed := range strings.Split(viper.GetString(constant.CfgKeyRolloutQueryEndpoints), ",") {
+		if allowed = strings.TrimSuffix(strings.TrimSpace(allowed), "/"); allowed != "" && allowed == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateRolloutQueryGate evaluates the instant query against the Prometheus-compatible HTTP API.
+// The check passes if the query returns at least one sample and the values of all samples are non-zero.
+func evaluateRolloutQueryGate(ctx context.Context, queryGate *opsv1alpha1.RolloutQueryGate) (bool, string) {
+	if !isRolloutQueryEndpointAllowed(queryGate.Endpoint) {
+		return false, fmt.Sprintf(`the endpoint "%s" is not allowed to query`, queryGate.Endpoint)
+	}
+	queryURL := strings.TrimSuffix(queryGate.Endpoint, "/") + "/api/v1/query?query=" + url.QueryEscape(queryGate.Query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return false, fmt.Sprintf("failed to build the query request: %s", err.Error())
+	}
+	resp, err := rolloutQueryClient.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("failed to query: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Sprintf("failed to decode the query response with status %d: %s", resp.StatusCode, err.Error())
+	}
+	if result.Status != "success" {
+		return false, fmt.Sprintf("the query failed: %s", result.Error)
+	}
+	var samples [][]any
+	switch result.Data.ResultType {
+	case "scalar":
+		var sample []any
+		if err = json.Unmarshal(result.Data.Result, &sample); err != nil {
+			return false, fmt.Sprintf("failed to decode the query result: %s", err.Error())
+		}
+		samples = append(samples, sample)
+	case "vector":
+		var vector []struct {
+			Value []any `json:"value"`
+		}
+		if err = json.Unmarshal(result.Data.Result, &vector); err != nil {
+			return false, fmt.Sprintf("failed to decode the query result: %s", err.Error())
+		}
+		for _, v := range vector {
+			samples = append(samples, v.Value)
+		}
+	default:
+		return false, fmt.Sprintf(`the result type "%s" of the query is not supported`, result.Data.ResultType)
+	}
+	if len(samples) == 0 {
+		return false, "the query returns no sample"
+	}
+	for _, sample := range samples {
+		if len(sample) != 2 {
+			return false, "the query returns an invalid sample"
+		}
+		value, ok := sample[1].(string)
+		if !ok {
+			return false, "the query returns an invalid sample"
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, fmt.Sprintf(`the query returns an invalid value "%s"`, value)
+		}
+		if v == 0 {
+			return false, "the query returns a zero value"
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,237 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testops "github.com/apecloud/kubeblocks/pkg/testutil/operations"
+)
+
+var _ = Describe("Rollout util test", func() {
+
+	var (
+		randomStr   = testCtx.GetRandomStr()
+		compDefName = "test-compdef-" + randomStr
+		clusterName = "test-cluster-" + randomStr
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.InstanceSetSignature, inNS, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	Context("rollout stages", func() {
+		It("builds the cumulative rollout stage of the InstanceSet", func() {
+			percent := intstr.FromString("30%")
+			one := intstr.FromInt32(1)
+			strategy := &opsv1alpha1.RolloutStrategy{
+				Stages: []opsv1alpha1.RolloutStage{
+					{Name: "canary", Replicas: &one},
+					{Name: "template", InstanceTemplates: []string{"foo"}},
+					{Name: "percent", Replicas: &percent},
+				},
+			}
+			its := &workloads.InstanceSet{Spec: workloads.InstanceSetSpec{Replicas: pointer.Int32(5)}}
+
+			stage, err := buildInstanceSetRolloutStage(strategy, 0, its)
+			Expect(err).Should(BeNil())
+			Expect(stage).Should(Equal(&workloads.RolloutStage{Name: "canary", Replicas: pointer.Int32(1)}))
+
+			stage, err = buildInstanceSetRolloutStage(strategy, 1, its)
+			Expect(err).Should(BeNil())
+			Expect(stage).Should(Equal(&workloads.RolloutStage{Name: "template", Replicas: pointer.Int32(1), InstanceTemplates: []string{"foo"}}))
+
+			By("a percentage is rounded up")
+			stage, err = buildInstanceSetRolloutStage(strategy, 2, its)
+			Expect(err).Should(BeNil())
+			Expect(stage).Should(Equal(&workloads.RolloutStage{Name: "percent", Replicas: pointer.Int32(2), InstanceTemplates: []string{"foo"}}))
+
+			By("no rollout stage after all the stages have passed")
+			stage, err = buildInstanceSetRolloutStage(strategy, 3, its)
+			Expect(err).Should(BeNil())
+			Expect(stage).Should(BeNil())
+		})
+
+		It("checks the approval of the rollout stage", func() {
+			ops := &opsv1alpha1.OpsRequest{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constant.ApprovedRolloutStagesAnnotationKey: "canary, template"},
+			}}
+			Expect(isRolloutStageApproved(ops, "canary")).Should(BeTrue())
+			Expect(isRolloutStageApproved(ops, "template")).Should(BeTrue())
+			Expect(isRolloutStageApproved(ops, "percent")).Should(BeFalse())
+		})
+	})
+
+	Context("rollout gates", func() {
+		var response string
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).Should(Equal("/api/v1/query"))
+				Expect(r.URL.Query().Get("query")).Should(Equal(`up{job="mysql"}`))
+				_, _ = w.Write([]byte(response))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+			viper.Set(constant.CfgKeyRolloutQueryEndpoints, "")
+		})
+
+		It("evaluates the query gate", func() {
+			queryGate := &opsv1alpha1.RolloutQueryGate{Endpoint: server.URL + "/", Query: `up{job="mysql"}`}
+			passed, message := evaluateRolloutQueryGate(context.Background(), queryGate)
+			Expect(passed).Should(BeFalse())
+			Expect(message).Should(ContainSubstring("is not allowed"))
+
+			viper.Set(constant.CfgKeyRolloutQueryEndpoints, "http://prometheus:9090, "+server.URL)
+			evaluate := func(resp string) bool {
+				response = resp
+				passed, _ := evaluateRolloutQueryGate(context.Background(), queryGate)
+				return passed
+			}
+			Expect(evaluate(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]},{"metric":{},"value":[1,"0.5"]}]}}`)).Should(BeTrue())
+			Expect(evaluate(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]},{"metric":{},"value":[1,"0"]}]}}`)).Should(BeFalse())
+			Expect(evaluate(`{"status":"success","data":{"resultType":"vector","result":[]}}`)).Should(BeFalse())
+			Expect(evaluate(`{"status":"success","data":{"resultType":"scalar","result":[1,"2"]}}`)).Should(BeTrue())
+			Expect(evaluate(`{"status":"error","error":"bad query"}`)).Should(BeFalse())
+			Expect(evaluate(`{"status":"success","data":{"resultType":"matrix","result":[]}}`)).Should(BeFalse())
+		})
+
+		It("evaluates the soak duration of the gate", func() {
+			gate := &opsv1alpha1.RolloutGate{SoakDuration: &metav1.Duration{Duration: time.Minute}}
+			updatedTime := time.Now()
+			passed, _, requeueAfter, err := evaluateRolloutGate(context.Background(), nil, nil, nil, gate, updatedTime, updatedTime.Add(20*time.Second))
+			Expect(err).Should(BeNil())
+			Expect(passed).Should(BeFalse())
+			Expect(requeueAfter).Should(Equal(40 * time.Second))
+
+			passed, _, _, err = evaluateRolloutGate(context.Background(), nil, nil, nil, gate, updatedTime, updatedTime.Add(time.Minute))
+			Expect(err).Should(BeNil())
+			Expect(passed).Should(BeTrue())
+		})
+	})
+
+	Context("rollout release", func() {
+		It("releases the InstanceSets from the rollout stage if the OpsRequest fails or is deleted", func() {
+			By("init operations resources")
+			reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+			opsRes, _, _ := initOperationsResources(compDefName, clusterName)
+			its := testapps.MockInstanceSetComponent(&testCtx, clusterName, defaultCompName)
+
+			By("create VerticalScaling ops with the staged rollout")
+			one := intstr.FromInt32(1)
+			ops := testops.NewOpsRequestObj("rollout-ops-"+randomStr, testCtx.DefaultNamespace,
+				clusterName, opsv1alpha1.VerticalScalingType)
+			ops.Spec.VerticalScalingList = []opsv1alpha1.VerticalScaling{
+				{ComponentOps: opsv1alpha1.ComponentOps{ComponentName: defaultCompName}},
+			}
+			ops.Spec.Rollout = &opsv1alpha1.RolloutStrategy{
+				Stages: []opsv1alpha1.RolloutStage{
+					{
+						Name:     "canary",
+						Replicas: &one,
+						Gate: &opsv1alpha1.RolloutGate{
+							Action:  &opsv1alpha1.RolloutActionGate{Name: "readwrite"},
+							Timeout: &metav1.Duration{Duration: time.Second},
+						},
+					},
+				},
+			}
+			opsRes.OpsRequest = testops.CreateOpsRequest(ctx, testCtx, ops)
+			compOpsHelper := newComponentOpsHelper(ops.Spec.VerticalScalingList)
+
+			expectRolloutStage := func(stageName string) {
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(its), func(g Gomega, its *workloads.InstanceSet) {
+					if stageName == "" {
+						g.Expect(its.Spec.RolloutStage).Should(BeNil())
+						return
+					}
+					g.Expect(its.Spec.RolloutStage).ShouldNot(BeNil())
+					g.Expect(its.Spec.RolloutStage.Name).Should(Equal(stageName))
+				})).Should(Succeed())
+			}
+
+			By("the InstanceSet is released if the OpsRequest is deleted while running")
+			Expect(applyRolloutStage(ctx, k8sClient, opsRes, compOpsHelper, 0)).Should(Succeed())
+			expectRolloutStage("canary")
+			opsRes.OpsRequest.Status.Phase = opsv1alpha1.OpsRunningPhase
+			Expect(ReleaseRolloutStage(ctx, k8sClient, opsRes)).Should(Succeed())
+			expectRolloutStage("")
+
+			By("the InstanceSet is released if the gate of the stage fails")
+			Expect(applyRolloutStage(ctx, k8sClient, opsRes, compOpsHelper, 0)).Should(Succeed())
+			expectRolloutStage("canary")
+			updatedTime := metav1.NewTime(time.Now().Add(-time.Minute))
+			Expect(testapps.ChangeObjStatus(&testCtx, opsRes.OpsRequest, func() {
+				opsRes.OpsRequest.Status.Phase = opsv1alpha1.OpsRunningPhase
+				opsRes.OpsRequest.Status.Rollout = &opsv1alpha1.RolloutStatus{
+					CurrentStage: "canary",
+					Stages: []opsv1alpha1.RolloutStageStatus{
+						{Name: "canary", Phase: opsv1alpha1.RolloutStageGatingPhase, StartTime: updatedTime, UpdatedTime: updatedTime},
+					},
+				}
+			})).Should(Succeed())
+			phase, _, err := reconcileActionWithRollout(reqCtx, k8sClient, opsRes, compOpsHelper, func() (opsv1alpha1.OpsPhase, time.Duration, error) {
+				return opsv1alpha1.OpsRunningPhase, 0, nil
+			})
+			Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+			Expect(phase).Should(Equal(opsv1alpha1.OpsFailedPhase))
+			Expect(opsRes.OpsRequest.Status.Rollout.Stages[0].Phase).Should(Equal(opsv1alpha1.RolloutStageFailedPhase))
+			expectRolloutStage("")
+		})
+	})
+})
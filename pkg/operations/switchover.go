@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -53,10 +54,11 @@ type SwitchoverMessage struct {
 
 func init() {
 	switchoverBehaviour := OpsBehaviour{
-		FromClusterPhases: appsv1.GetClusterUpRunningPhases(),
-		ToClusterPhase:    appsv1.UpdatingClusterPhase,
-		QueueByCluster:    true,
-		OpsHandler:        switchoverOpsHandler{},
+		FromClusterPhases:        appsv1.GetClusterUpRunningPhases(),
+		ToClusterPhase:           appsv1.UpdatingClusterPhase,
+		QueueByCluster:           true,
+		OpsHandler:               switchoverOpsHandler{},
+		WaitForMaintenanceWindow: true,
 	}
 
 	opsMgr := GetOpsManager()
@@ -228,6 +230,22 @@ func handleSwitchover(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *
 		return handleError(reqCtx, opsRequest, &detail, switchover.ComponentName, fmt.Sprintf("build synthesizedComponent template vars failed: %s", err.Error()), failedCount)
 	}
 
+	if switchover.InstanceName == KBSwitchoverCandidateInstanceForAnyPod {
+		candidate, err := selectSwitchoverCandidateByLag(reqCtx, cli, opsRes, synthesizedComp, switchover)
+		if errors.Is(err, lifecycle.ErrNoQualifiedCandidate) {
+			// refuse to switch over, the decision has been recorded.
+			*completedCount++
+			return handleError(reqCtx, opsRequest, &detail, switchover.ComponentName, fmt.Sprintf("refuse to switchover: %s", err.Error()), failedCount)
+		}
+		if err != nil {
+			return handleError(reqCtx, opsRequest, &detail, switchover.ComponentName, fmt.Sprintf("select the switchover candidate failed: %s", err.Error()), failedCount)
+		}
+		if candidate != "" {
+			switchover = switchover.DeepCopy()
+			switchover.InstanceName = candidate
+		}
+	}
+
 	if err = doSwitchover(reqCtx.Ctx, cli, synthesizedComp, switchover, switchoverCondition); err != nil {
 		return handleError(reqCtx, opsRequest, &detail, switchover.ComponentName, fmt.Sprintf("call switchover action and check role label failed: %s", err.Error()), failedCount)
 	}
@@ -265,7 +283,19 @@ func doSwitchover(ctx context.Context, cli client.Reader, synthesizedComp *compo
 	} else {
 		candidate = switchover.InstanceName
 	}
-	err = lfa.Switchover(ctx, cli, nil, candidate)
+	callSwitchover := func() error {
+		return lfa.Switchover(ctx, cli, nil, candidate)
+	}
+	if leader := leaderPod(synthesizedComp, pods); leader != nil {
+		// stop writes on the current leader before the switchover, it will be brought back if the switchover fails
+		leaderLfa, err1 := lifecycle.New(synthesizedComp, leader, pods...)
+		if err1 != nil {
+			return err1
+		}
+		err = lifecycle.WithReadonly(ctx, cli, leaderLfa, callSwitchover)
+	} else {
+		err = callSwitchover()
+	}
 	if err != nil {
 		return err
 	} else {
@@ -273,6 +303,87 @@ func doSwitchover(ctx context.Context, cli client.Reader, synthesizedComp *compo
 	}
 }
 
+// selectSwitchoverCandidateByLag selects the most up-to-date replica as the candidate by the replication lag,
+// the decision is recorded in the status of the component and it is kept during the switchover.
+// It returns an empty candidate if the replicationLag action is not defined, the engine chooses the candidate then.
+func selectSwitchoverCandidateByLag(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	switchover *opsv1alpha1.Switchover) (string, error) {
+	opsRequest := opsRes.OpsRequest
+	compStatus := opsRequest.Status.Components[switchover.ComponentName]
+	if compStatus.SwitchoverDecision != nil && compStatus.SwitchoverDecision.Candidate != "" {
+		return compStatus.SwitchoverDecision.Candidate, nil
+	}
+	pods, err := component.ListOwnedPods(reqCtx.Ctx, cli, synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name)
+	if err != nil {
+		return "", err
+	}
+	decision, err := lifecycle.SelectSwitchoverCandidate(reqCtx.Ctx, cli, synthesizedComp, pods, buildCandidatePolicy(switchover.CandidatePolicy))
+	if errors.Is(err, lifecycle.ErrActionNotDefined) {
+		return "", nil
+	}
+	if decision != nil {
+		compStatus.SwitchoverDecision = buildSwitchoverDecision(decision)
+		opsRequest.Status.Components[switchover.ComponentName] = compStatus
+		if decision.Candidate != "" {
+			opsRes.Recorder.Eventf(opsRequest, corev1.EventTypeNormal, reasonSwitchoverCandidateSelected,
+				"component %s: %s", switchover.ComponentName, decision.Message)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return decision.Candidate, nil
+}
+
+func buildCandidatePolicy(policy *opsv1alpha1.SwitchoverCandidatePolicy) lifecycle.CandidatePolicy {
+	candidatePolicy := lifecycle.CandidatePolicy{PreferSameZone: true}
+	if policy != nil {
+		candidatePolicy.MaxLag = policy.MaxReplicationLag
+		if policy.PreferSameZone != nil {
+			candidatePolicy.PreferSameZone = *policy.PreferSameZone
+		}
+	}
+	return candidatePolicy
+}
+
+func buildSwitchoverDecision(decision *lifecycle.CandidateDecision) *opsv1alpha1.SwitchoverDecision {
+	switchoverDecision := &opsv1alpha1.SwitchoverDecision{
+		Candidate:    decision.Candidate,
+		Message:      decision.Message,
+		DecisionTime: metav1.Now(),
+	}
+	for _, reading := range decision.Readings {
+		lagReading := opsv1alpha1.ReplicationLagReading{
+			InstanceName: reading.PodName,
+			Zone:         reading.Zone,
+			Lag:          reading.Lag,
+		}
+		if reading.Error != nil {
+			lagReading.Message = reading.Error.Error()
+		}
+		switchoverDecision.Readings = append(switchoverDecision.Readings, lagReading)
+	}
+	return switchoverDecision
+}
+
+// leaderPod returns the pod which has the writable and serviceable role, or nil if there is none.
+func leaderPod(synthesizedComp *component.SynthesizedComponent, pods []*corev1.Pod) *corev1.Pod {
+	for _, role := range synthesizedComp.Roles {
+		if !role.Serviceable || !role.Writable {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Labels[constant.RoleLabelKey] == role.Name {
+				return pod
+			}
+		}
+	}
+	return nil
+}
+
 // setComponentSwitchoverProgressDetails sets component switchover progress details.
 func setComponentSwitchoverProgressDetails(recorder record.EventRecorder,
 	opsRequest *opsv1alpha1.OpsRequest,
@@ -282,8 +393,9 @@ func setComponentSwitchoverProgressDetails(recorder record.EventRecorder,
 	componentProcessDetails := opsRequest.Status.Components[componentName].ProgressDetails
 	setComponentStatusProgressDetail(recorder, opsRequest, &componentProcessDetails, processDetail)
 	opsRequest.Status.Components[componentName] = opsv1alpha1.OpsRequestComponentStatus{
-		Phase:           phase,
-		ProgressDetails: componentProcessDetails,
+		Phase:              phase,
+		ProgressDetails:    componentProcessDetails,
+		SwitchoverDecision: opsRequest.Status.Components[componentName].SwitchoverDecision,
 	}
 }
 
@@ -26,11 +26,14 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
@@ -191,3 +194,55 @@ var _ = Describe("", func() {
 		})
 	})
 })
+
+var _ = Describe("Switchover candidate selection test", func() {
+	It("builds the candidate policy", func() {
+		policy := buildCandidatePolicy(nil)
+		Expect(policy.PreferSameZone).Should(BeTrue())
+		Expect(policy.MaxLag).Should(BeNil())
+
+		policy = buildCandidatePolicy(&opsv1alpha1.SwitchoverCandidatePolicy{
+			MaxReplicationLag: pointer.Int64(100),
+			PreferSameZone:    pointer.Bool(false),
+		})
+		Expect(policy.PreferSameZone).Should(BeFalse())
+		Expect(*policy.MaxLag).Should(BeEquivalentTo(100))
+	})
+
+	It("records the decision and keeps it during the switchover", func() {
+		decision := buildSwitchoverDecision(&lifecycle.CandidateDecision{
+			Candidate: "pod-2",
+			Message:   "select the replica pod-2 with the replication lag 10",
+			Readings: []lifecycle.LagReading{
+				{PodName: "pod-1", Zone: "zone-a", Error: lifecycle.ErrActionFailed},
+				{PodName: "pod-2", Zone: "zone-a", Lag: pointer.Int64(10)},
+			},
+		})
+		Expect(decision.Readings).Should(HaveLen(2))
+		Expect(decision.Readings[0].Lag).Should(BeNil())
+		Expect(decision.Readings[0].Message).Should(ContainSubstring(lifecycle.ErrActionFailed.Error()))
+		Expect(*decision.Readings[1].Lag).Should(BeEquivalentTo(10))
+
+		opsRes := &OpsResource{
+			OpsRequest: &opsv1alpha1.OpsRequest{
+				Status: opsv1alpha1.OpsRequestStatus{
+					Components: map[string]opsv1alpha1.OpsRequestComponentStatus{
+						"mysql": {SwitchoverDecision: decision},
+					},
+				},
+			},
+		}
+		switchover := &opsv1alpha1.Switchover{
+			ComponentOps: opsv1alpha1.ComponentOps{ComponentName: "mysql"},
+			InstanceName: KBSwitchoverCandidateInstanceForAnyPod,
+		}
+		candidate, err := selectSwitchoverCandidateByLag(intctrlutil.RequestCtx{}, nil, opsRes, nil, switchover)
+		Expect(err).Should(BeNil())
+		Expect(candidate).Should(Equal("pod-2"))
+
+		By("keep the decision when the progress is updated")
+		setComponentSwitchoverProgressDetails(record.NewFakeRecorder(10), opsRes.OpsRequest, appsv1.UpdatingClusterCompPhase,
+			opsv1alpha1.ProgressStatusDetail{ObjectKey: "mysql", Status: opsv1alpha1.ProcessingProgressStatus}, "mysql")
+		Expect(opsRes.OpsRequest.Status.Components["mysql"].SwitchoverDecision).Should(Equal(decision))
+	})
+})
@@ -38,6 +38,8 @@ const (
 	OpsReasonForSkipSwitchover             = "SkipSwitchover"
 	KBSwitchoverCandidateInstanceForAnyPod = "*"
 	KBSwitchoverDoNCheckRoleChangeKey      = "DoSwitchoverAndCheckRoleChange"
+
+	reasonSwitchoverCandidateSelected = "SwitchoverCandidateSelected"
 )
 
 // needDoSwitchover checks whether we need to perform a switchover.
@@ -60,6 +60,9 @@ type OpsBehaviour struct {
 	// only update the opsRequest object, then opsRequest controller will update uniformly.
 	CancelFunc func(reqCtx intctrlutil.RequestCtx, cli client.Client, opsResource *OpsResource) error
 
+	// PlanFunc this function computes the execution plan of the dry-run opsRequest, it must not mutate the cluster.
+	PlanFunc func(reqCtx intctrlutil.RequestCtx, cli client.Client, opsResource *OpsResource) (*opsv1alpha1.OpsPlan, error)
+
 	// IsClusterCreation indicates whether the opsRequest will create a new cluster.
 	IsClusterCreation bool
 
@@ -69,6 +72,9 @@ type OpsBehaviour struct {
 	// QueueWithSelf indicates that the operation is queued for execution within opsType scope.
 	QueueBySelf bool
 
+	// WaitForMaintenanceWindow indicates that the operation is disruptive and waits for the maintenance window of the cluster.
+	WaitForMaintenanceWindow bool
+
 	OpsHandler OpsHandler
 }
 
@@ -117,4 +123,7 @@ type progressResource struct {
 	// checks if it needs to wait the component to complete.
 	// if only updates a part of pods, set it to false.
 	noWaitComponentCompleted bool
+	// checks if the processed pods are kept when cancelling the opsRequest,
+	// the pods in progress will continue to complete instead of being rolled back.
+	noRollbackOnCancel bool
 }
@@ -40,10 +40,13 @@ var _ OpsHandler = upgradeOpsHandler{}
 func init() {
 	upgradeBehaviour := OpsBehaviour{
 		// if cluster is Abnormal or Failed, new opsRequest may can repair it.
-		FromClusterPhases: appsv1.GetClusterUpRunningPhases(),
-		ToClusterPhase:    appsv1.UpdatingClusterPhase,
-		QueueByCluster:    true,
-		OpsHandler:        upgradeOpsHandler{},
+		FromClusterPhases:        appsv1.GetClusterUpRunningPhases(),
+		ToClusterPhase:           appsv1.UpdatingClusterPhase,
+		QueueByCluster:           true,
+		OpsHandler:               upgradeOpsHandler{},
+		CancelFunc:               upgradeOpsHandler{}.Cancel,
+		PlanFunc:                 upgradeOpsHandler{}.Plan,
+		WaitForMaintenanceWindow: true,
 	}
 
 	opsMgr := GetOpsManager()
@@ -84,6 +87,10 @@ func (u upgradeOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Clie
 		}); err != nil {
 		return err
 	}
+	// pause the instances out of the first rollout stage before updating the cluster.
+	if err := applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsHelper, 0); err != nil {
+		return err
+	}
 	return cli.Update(reqCtx.Ctx, opsRes.Cluster)
 }
 
@@ -100,6 +107,9 @@ func (u upgradeOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cl
 	if componentDefMap, err = u.getComponentDefMapWithUpdatedImages(reqCtx, cli, opsRes); err != nil {
 		return opsRes.OpsRequest.Status.Phase, 0, err
 	}
+	if opsRes.OpsRequest.Status.Rollback != nil {
+		return u.reconcileRollback(reqCtx, cli, opsRes, componentDefMap)
+	}
 	componentUpgraded := func(cluster *appsv1.Cluster,
 		lastCompConfiguration opsv1alpha1.LastComponentConfiguration,
 		upgradeComp opsv1alpha1.UpgradeComponent) bool {
@@ -135,7 +145,56 @@ func (u upgradeOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cl
 		compStatus *opsv1alpha1.OpsRequestComponentStatus) (expectProgressCount int32, completedCount int32, err error) {
 		return handleComponentStatusProgress(reqCtx, cli, opsRes, pgRes, compStatus, podApplyCompOps)
 	}
-	return compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "upgrade", handleUpgradeProgress)
+	phase, requeueAfter, err := reconcileActionWithRollout(reqCtx, cli, opsRes, compOpsHelper, func() (opsv1alpha1.OpsPhase, time.Duration, error) {
+		return compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "upgrade", handleUpgradeProgress)
+	})
+	if err != nil || phase != opsv1alpha1.OpsRunningPhase || upgradeSpec.RollbackPolicy == nil ||
+		opsRes.OpsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
+		return phase, requeueAfter, err
+	}
+	return u.checkRollbackDeadline(reqCtx, cli, opsRes, componentDefMap, requeueAfter)
+}
+
+// Plan computes the pods to be restarted by the upgrade without updating the cluster.
+func (u upgradeOpsHandler) Plan(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*opsv1alpha1.OpsPlan, error) {
+	compOpsHelper := newComponentOpsHelper(opsRes.OpsRequest.Spec.Upgrade.Components)
+	return compOpsHelper.planComponentOps(reqCtx.Ctx, cli, opsRes, func(compSpec *appsv1.ClusterComponentSpec,
+		compOps ComponentOpsInterface, fullComponentName string) (*opsv1alpha1.ComponentPlan, error) {
+		upgradeComp := compOps.(opsv1alpha1.UpgradeComponent)
+		compDefName := compSpec.ComponentDef
+		if u.needUpdateCompDef(upgradeComp, opsRes.Cluster) {
+			compDefName = *upgradeComp.ComponentDefinitionName
+		}
+		serviceVersion := compSpec.ServiceVersion
+		if upgradeComp.ServiceVersion != nil {
+			serviceVersion = *upgradeComp.ServiceVersion
+		}
+		podAction := func(pod *corev1.Pod) opsv1alpha1.PlanAction {
+			return opsv1alpha1.RestartPlanAction
+		}
+		// the pods are updated if the componentDefinition changes, otherwise only the pods whose images
+		// change with the service version are restarted.
+		if compDefName == compSpec.ComponentDef {
+			var containers []corev1.Container
+			if serviceVersion != compSpec.ServiceVersion && compDefName != "" {
+				compDef, err := component.GetCompDefByName(reqCtx.Ctx, cli, compDefName)
+				if err != nil {
+					return nil, err
+				}
+				if err = component.UpdateCompDefinitionImages4ServiceVersion(reqCtx.Ctx, cli, compDef, serviceVersion); err != nil {
+					return nil, err
+				}
+				containers = compDef.Spec.Runtime.Containers
+			}
+			podAction = func(pod *corev1.Pod) opsv1alpha1.PlanAction {
+				if len(containers) == 0 || u.podImageApplied(pod, containers) {
+					return opsv1alpha1.NonePlanAction
+				}
+				return opsv1alpha1.RestartPlanAction
+			}
+		}
+		return buildComponentPlan(reqCtx.Ctx, cli, opsRes.Cluster, fullComponentName, podAction)
+	})
 }
 
 // SaveLastConfiguration records last configuration to the OpsRequest.status.lastConfiguration
@@ -150,6 +209,16 @@ func (u upgradeOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx,
 	return nil
 }
 
+// Cancel restores the componentDefinition and serviceVersion of the components,
+// the upgraded pods will be rolled back to the images of the previous service version.
+func (u upgradeOpsHandler) Cancel(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	compOpsHelper := newComponentOpsHelper(opsRes.OpsRequest.Spec.Upgrade.Components)
+	return compOpsHelper.cancelComponentOps(reqCtx.Ctx, cli, opsRes, func(lastConfig *opsv1alpha1.LastComponentConfiguration, comp *appsv1.ClusterComponentSpec) {
+		comp.ComponentDef = lastConfig.ComponentDefinitionName
+		comp.ServiceVersion = lastConfig.ServiceVersion
+	})
+}
+
 // getComponentDefMapWithUpdatedImages gets the desired componentDefinition map
 // that is updated with the corresponding images of the ComponentDefinition and service version.
 func (u upgradeOpsHandler) getComponentDefMapWithUpdatedImages(reqCtx intctrlutil.RequestCtx,
@@ -0,0 +1,240 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const upgradeRollbackCheckInterval = 5 * time.Second
+
+// checkRollbackDeadline rolls back the upgrade if the upgraded instances are not healthy after the readiness deadline.
+func (u upgradeOpsHandler) checkRollbackDeadline(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	componentDefMap map[string]*appsv1.ComponentDefinition,
+	requeueAfter time.Duration) (opsv1alpha1.OpsPhase, time.Duration, error) {
+	policy := opsRes.OpsRequest.Spec.Upgrade.RollbackPolicy
+	deadline := opsRes.OpsRequest.Status.StartTimestamp.Add(policy.ReadinessDeadline.Duration)
+	if remaining := time.Until(deadline); remaining > 0 {
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+		return opsv1alpha1.OpsRunningPhase, requeueAfter, nil
+	}
+	var reasons []string
+	for _, upgradeComp := range opsRes.OpsRequest.Spec.Upgrade.Components {
+		compDef, ok := componentDefMap[upgradeComp.ComponentName]
+		if !ok {
+			continue
+		}
+		healthy, reason, err := u.checkComponentHealth(reqCtx.Ctx, cli, opsRes.Cluster, upgradeComp.ComponentName, compDef, false)
+		if err != nil {
+			return opsv1alpha1.OpsRunningPhase, 0, err
+		}
+		if !healthy {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return opsv1alpha1.OpsRunningPhase, requeueAfter, nil
+	}
+	reason := fmt.Sprintf("the upgraded instances are not healthy within %s: %s",
+		policy.ReadinessDeadline.Duration, strings.Join(reasons, "; "))
+	return opsv1alpha1.OpsRunningPhase, upgradeRollbackCheckInterval, u.triggerRollback(reqCtx, cli, opsRes, reason)
+}
+
+// triggerRollback restores the componentDefinition and serviceVersion of the components,
+// the upgraded instances will be rolled back to the images of the previous release.
+func (u upgradeOpsHandler) triggerRollback(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, reason string) error {
+	opsRequest := opsRes.OpsRequest
+	rollbackStatus := &opsv1alpha1.RollbackStatus{
+		Phase:     opsv1alpha1.RollingBackRollbackPhase,
+		Reason:    reason,
+		StartTime: metav1.Now(),
+	}
+	for _, upgradeComp := range opsRequest.Spec.Upgrade.Components {
+		compSpec := getComponentSpecOrShardingTemplate(opsRes.Cluster, upgradeComp.ComponentName)
+		lastConfig, ok := opsRequest.Status.LastConfiguration.Components[upgradeComp.ComponentName]
+		if compSpec == nil || !ok {
+			continue
+		}
+		rollbackStatus.Components = append(rollbackStatus.Components, opsv1alpha1.RollbackComponentStatus{
+			Name:                    upgradeComp.ComponentName,
+			FromComponentDefinition: compSpec.ComponentDef,
+			FromServiceVersion:      compSpec.ServiceVersion,
+			ToComponentDefinition:   lastConfig.ComponentDefinitionName,
+			ToServiceVersion:        lastConfig.ServiceVersion,
+		})
+	}
+	if opsRequest.Spec.Rollout != nil {
+		// roll back all the instances regardless of the rollout stage.
+		compOpsHelper := newComponentOpsHelper(opsRequest.Spec.Upgrade.Components)
+		if err := applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsHelper, len(opsRequest.Spec.Rollout.Stages)); err != nil {
+			return err
+		}
+	}
+	if err := u.Cancel(reqCtx, cli, opsRes); err != nil {
+		return err
+	}
+	opsRes.Recorder.Event(opsRequest, corev1.EventTypeWarning, opsv1alpha1.ReasonRollbackTriggered, reason)
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	opsRequest.Status.Rollback = rollbackStatus
+	opsRequest.SetStatusCondition(*opsv1alpha1.NewRollbackCondition(opsv1alpha1.ReasonRollbackTriggered, reason))
+	return cli.Status().Patch(reqCtx.Ctx, opsRequest, patch)
+}
+
+// reconcileRollback waits for the rolled back components to be healthy, and then fails the OpsRequest.
+func (u upgradeOpsHandler) reconcileRollback(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	componentDefMap map[string]*appsv1.ComponentDefinition) (opsv1alpha1.OpsPhase, time.Duration, error) {
+	opsRequest := opsRes.OpsRequest
+	oldOpsRequest := opsRequest.DeepCopy()
+	rollbackStatus := opsRequest.Status.Rollback
+	var reasons []string
+	for _, rollbackComp := range rollbackStatus.Components {
+		compDef, ok := componentDefMap[rollbackComp.Name]
+		if !ok {
+			continue
+		}
+		healthy, reason, err := u.checkComponentHealth(reqCtx.Ctx, cli, opsRes.Cluster, rollbackComp.Name, compDef, true)
+		if err != nil {
+			return opsv1alpha1.OpsRunningPhase, 0, err
+		}
+		if !healthy {
+			reasons = append(reasons, reason)
+		}
+	}
+	policy := opsRequest.Spec.Upgrade.RollbackPolicy
+	timeout := policy.ReadinessDeadline.Duration
+	if policy.HealthCheckTimeout != nil {
+		timeout = policy.HealthCheckTimeout.Duration
+	}
+	var (
+		opsPhase = opsv1alpha1.OpsRunningPhase
+		opsErr   error
+	)
+	switch {
+	case len(reasons) == 0:
+		rollbackStatus.Phase = opsv1alpha1.SucceedRollbackPhase
+		rollbackStatus.Message = "the rolled back components are healthy"
+		opsPhase = opsv1alpha1.OpsFailedPhase
+		opsErr = fmt.Errorf("the upgrade is rolled back: %s", rollbackStatus.Reason)
+	case time.Since(rollbackStatus.StartTime.Time) > timeout:
+		rollbackStatus.Phase = opsv1alpha1.FailedRollbackPhase
+		rollbackStatus.Message = fmt.Sprintf("the rolled back components are not healthy within %s: %s", timeout, strings.Join(reasons, "; "))
+		opsPhase = opsv1alpha1.OpsFailedPhase
+		opsErr = fmt.Errorf("the upgrade is rolled back but the health check failed: %s", rollbackStatus.Message)
+	default:
+		rollbackStatus.Message = strings.Join(reasons, "; ")
+	}
+	if opsPhase == opsv1alpha1.OpsFailedPhase {
+		rollbackStatus.CompletionTime = metav1.Now()
+		conditionReason := opsv1alpha1.ReasonRollbackSucceed
+		if rollbackStatus.Phase == opsv1alpha1.FailedRollbackPhase {
+			conditionReason = opsv1alpha1.ReasonRollbackFailed
+		}
+		opsRequest.SetStatusCondition(*opsv1alpha1.NewRollbackCondition(conditionReason, rollbackStatus.Message))
+	}
+	if !reflect.DeepEqual(oldOpsRequest.Status, opsRequest.Status) {
+		if err := cli.Status().Patch(reqCtx.Ctx, opsRequest, client.MergeFrom(oldOpsRequest)); err != nil {
+			return opsv1alpha1.OpsRunningPhase, 0, err
+		}
+	}
+	return opsPhase, upgradeRollbackCheckInterval, opsErr
+}
+
+// checkComponentHealth checks the instances with the images of the componentDefinition are ready,
+// and an instance reports the serviceable and writable role if the componentDefinition defines one.
+// If allUpdated is true, all the instances are required to have the images of the componentDefinition.
+func (u upgradeOpsHandler) checkComponentHealth(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1.Cluster,
+	compName string,
+	compDef *appsv1.ComponentDefinition,
+	allUpdated bool) (bool, string, error) {
+	fullCompNames, err := getFullComponentNames(ctx, cli, cluster, compName)
+	if err != nil {
+		return false, "", err
+	}
+	hasLeaderRole := false
+	for _, role := range compDef.Spec.Roles {
+		if role.Serviceable && role.Writable {
+			hasLeaderRole = true
+		}
+	}
+	isLeader := func(pod *corev1.Pod) bool {
+		for _, role := range compDef.Spec.Roles {
+			if role.Serviceable && role.Writable && pod.Labels[constant.RoleLabelKey] == role.Name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, fullCompName := range fullCompNames {
+		pods, err := component.ListOwnedPods(ctx, cli, cluster.Namespace, cluster.Name, fullCompName)
+		if err != nil {
+			return false, "", err
+		}
+		leaderFound := false
+		for _, pod := range pods {
+			if isLeader(pod) {
+				leaderFound = true
+			}
+			if !u.podImageApplied(pod, compDef.Spec.Runtime.Containers) {
+				if allUpdated {
+					return false, fmt.Sprintf(`the instance "%s" is not updated`, pod.Name), nil
+				}
+				continue
+			}
+			if !intctrlutil.PodIsReady(pod) {
+				return false, fmt.Sprintf(`the instance "%s" is not ready`, pod.Name), nil
+			}
+		}
+		if hasLeaderRole && !leaderFound {
+			return false, fmt.Sprintf(`no leader is reported in the component "%s"`, fullCompName), nil
+		}
+	}
+	if allUpdated {
+		compStatus, ok := cluster.Status.Components[compName]
+		if !ok {
+			compStatus = cluster.Status.Shardings[compName]
+		}
+		if compStatus.Phase != appsv1.RunningClusterCompPhase {
+			return false, fmt.Sprintf(`the component "%s" is not running`, compName), nil
+		}
+	}
+	return true, "", nil
+}
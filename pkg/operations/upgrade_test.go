@@ -20,10 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package operations
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -395,6 +398,98 @@ var _ = Describe("Upgrade OpsRequest", func() {
 				g.Expect(cluster.Spec.ComponentSpecs[0].ServiceVersion).Should(Equal(""))
 			})).Should(Succeed())
 		})
+
+		It("cancel upgrade OpsRequest and roll back the upgraded pods", func() {
+			By("init operations resources")
+			compDef1, compDef2, opsRes := initOpsResWithComponentDef(true)
+
+			By("create Upgrade Ops")
+			opsRes.OpsRequest = createUpgradeOpsRequest(opsRes.Cluster, opsv1alpha1.Upgrade{
+				Components: []opsv1alpha1.UpgradeComponent{
+					{
+						ComponentOps:            opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+						ServiceVersion:          pointer.String(serviceVer2),
+						ComponentDefinitionName: &compDef2.Name,
+					},
+				},
+			})
+
+			By("expect for this opsRequest is Running and the pods are upgraded")
+			reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+			makeUpgradeOpsIsRunning(reqCtx, opsRes)
+			mockPodsAppliedImage(opsRes.Cluster, release3)
+			_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("cancel the upgrade opsRequest and expect the componentDef and serviceVersion are restored")
+			cancelOpsRequest(reqCtx, opsRes, time.Now())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Spec.ComponentSpecs[0].ComponentDef).Should(Equal(compDef1.Name))
+				g.Expect(cluster.Spec.ComponentSpecs[0].ServiceVersion).Should(Equal(serviceVer0))
+			})).Should(Succeed())
+
+			By("mock the pods are rolled back and expect the opsRequest is Cancelled with rollback")
+			mockPodsAppliedImage(opsRes.Cluster, release0)
+			mockComponentIsOperating(opsRes.Cluster, appsv1.RunningClusterCompPhase, defaultCompName)
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest), func(g Gomega, ops *opsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.Phase).Should(Equal(opsv1alpha1.OpsCancelledPhase))
+				g.Expect(ops.Status.Components[defaultCompName].Reason).Should(Equal(opsv1alpha1.ReasonOpsCancelledWithRollback))
+			})).Should(Succeed())
+		})
+
+		It("roll back the upgrade automatically if the upgraded pods are not ready before the deadline", func() {
+			By("init operations resources")
+			compDef1, compDef2, opsRes := initOpsResWithComponentDef(true)
+
+			By("create Upgrade Ops with a rollback policy")
+			opsRes.OpsRequest = createUpgradeOpsRequest(opsRes.Cluster, opsv1alpha1.Upgrade{
+				Components: []opsv1alpha1.UpgradeComponent{
+					{
+						ComponentOps:            opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+						ServiceVersion:          pointer.String(serviceVer2),
+						ComponentDefinitionName: &compDef2.Name,
+					},
+				},
+				RollbackPolicy: &opsv1alpha1.UpgradeRollbackPolicy{
+					ReadinessDeadline: metav1.Duration{Duration: time.Millisecond},
+				},
+			})
+
+			By("mock the upgraded pods are not ready")
+			reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+			makeUpgradeOpsIsRunning(reqCtx, opsRes)
+			mockPodsAppliedImage(opsRes.Cluster, release3)
+			pods := testapps.MockInstanceSetPods(&testCtx, nil, opsRes.Cluster, defaultCompName)
+			Expect(testapps.ChangeObjStatus(&testCtx, pods[0], func() {
+				pods[0].Status.Conditions = nil
+			})).Should(Succeed())
+
+			By("expect the componentDef and serviceVersion are rolled back after the deadline")
+			_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Spec.ComponentSpecs[0].ComponentDef).Should(Equal(compDef1.Name))
+				g.Expect(cluster.Spec.ComponentSpecs[0].ServiceVersion).Should(Equal(serviceVer0))
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest), func(g Gomega, ops *opsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.Rollback).ShouldNot(BeNil())
+				g.Expect(ops.Status.Rollback.Phase).Should(Equal(opsv1alpha1.RollingBackRollbackPhase))
+				g.Expect(ops.Status.Rollback.Components).Should(HaveLen(1))
+				g.Expect(ops.Status.Rollback.Components[0].ToServiceVersion).Should(Equal(serviceVer0))
+			})).Should(Succeed())
+
+			By("mock the pods are rolled back and healthy, expect the opsRequest is Failed with the rollback record")
+			mockPodsAppliedImage(opsRes.Cluster, release0)
+			mockComponentIsOperating(opsRes.Cluster, appsv1.RunningClusterCompPhase, defaultCompName)
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest), func(g Gomega, ops *opsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.Phase).Should(Equal(opsv1alpha1.OpsFailedPhase))
+				g.Expect(ops.Status.Rollback.Phase).Should(Equal(opsv1alpha1.SucceedRollbackPhase))
+			})).Should(Succeed())
+		})
 		// TODO: add case with ClusterDefinition and topology
 	})
 })
@@ -41,11 +41,13 @@ func init() {
 	vsHandler := verticalScalingHandler{}
 	verticalScalingBehaviour := OpsBehaviour{
 		// if cluster is Abnormal or Failed, new opsRequest may can repair it.
-		FromClusterPhases: appsv1.GetClusterUpRunningPhases(),
-		ToClusterPhase:    appsv1.UpdatingClusterPhase,
-		OpsHandler:        vsHandler,
-		QueueByCluster:    true,
-		CancelFunc:        vsHandler.Cancel,
+		FromClusterPhases:        appsv1.GetClusterUpRunningPhases(),
+		ToClusterPhase:           appsv1.UpdatingClusterPhase,
+		OpsHandler:               vsHandler,
+		QueueByCluster:           true,
+		CancelFunc:               vsHandler.Cancel,
+		PlanFunc:                 vsHandler.Plan,
+		WaitForMaintenanceWindow: true,
 	}
 
 	opsMgr := GetOpsManager()
@@ -92,6 +94,10 @@ func (vs verticalScalingHandler) Action(reqCtx intctrlutil.RequestCtx, cli clien
 	if err := compOpsSet.updateClusterComponentsAndShardings(opsRes.Cluster, applyVerticalScaling); err != nil {
 		return err
 	}
+	// pause the instances out of the first rollout stage before updating the cluster.
+	if err := applyRolloutStage(reqCtx.Ctx, cli, opsRes, compOpsSet, 0); err != nil {
+		return err
+	}
 	return cli.Update(reqCtx.Ctx, opsRes.Cluster)
 }
 
@@ -141,7 +147,34 @@ func (vs verticalScalingHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx,
 		}
 		return handleComponentStatusProgress(reqCtx, cli, opsRes, pgRes, compStatus, vs.podApplyCompOps)
 	}
-	return compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "vertical scale", handleComponentStatusProgressForVS)
+	return reconcileActionWithRollout(reqCtx, cli, opsRes, compOpsHelper, func() (opsv1alpha1.OpsPhase, time.Duration, error) {
+		return compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "vertical scale", handleComponentStatusProgressForVS)
+	})
+}
+
+// Plan computes the pods to be restarted by the vertical scaling without updating the cluster.
+func (vs verticalScalingHandler) Plan(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*opsv1alpha1.OpsPlan, error) {
+	compOpsHelper := newComponentOpsHelper(opsRes.OpsRequest.Spec.VerticalScalingList)
+	return compOpsHelper.planComponentOps(reqCtx.Ctx, cli, opsRes, func(compSpec *appsv1.ClusterComponentSpec,
+		compOps ComponentOpsInterface, fullComponentName string) (*opsv1alpha1.ComponentPlan, error) {
+		verticalScaling := compOps.(opsv1alpha1.VerticalScaling)
+		vsInsMap := vs.covertInsResourcesToMap(verticalScaling)
+		templates := map[string]appsv1.InstanceTemplate{}
+		for _, template := range compSpec.Instances {
+			templates[template.Name] = template
+		}
+		return buildComponentPlan(reqCtx.Ctx, cli, opsRes.Cluster, fullComponentName, func(pod *corev1.Pod) opsv1alpha1.PlanAction {
+			scaled := vs.verticalScalingComp(verticalScaling)
+			templateName := pod.Labels[constant.KBAppComponentInstanceTemplateLabelKey]
+			if template, ok := templates[templateName]; ok {
+				scaled = vs.verticalScalingInsTemplate(verticalScaling, template, vsInsMap[templateName])
+			}
+			if scaled {
+				return opsv1alpha1.RestartPlanAction
+			}
+			return opsv1alpha1.NonePlanAction
+		})
+	})
 }
 
 func (vs verticalScalingHandler) covertInsResourcesToMap(verticalScaling opsv1alpha1.VerticalScaling) map[string]*opsv1alpha1.InstanceResourceTemplate {
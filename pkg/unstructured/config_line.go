@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"strings"
+)
+
+// configLine is a line of a line-oriented configuration file.
+// It keeps the raw text, so that the lines which are not updated are written back as they are.
+type configLine struct {
+	raw string
+
+	// the name and the value of the parameter, both are empty for blank lines, comments, sections and directives.
+	key   string
+	value string
+
+	// the section which the line belongs to, only used by the formats with sections.
+	section   string
+	isSection bool
+	// the directive of the line, e.g. include.
+	directive string
+	// bare is true if the parameter is a flag without value.
+	bare bool
+
+	// the text before and after the value, e.g. "max_connections = " and "	# comment".
+	prefix string
+	suffix string
+	quote  byte
+
+	updated bool
+}
+
+func (l *configLine) isParameter() bool {
+	return l.key != ""
+}
+
+// configLines holds the lines of a file and remembers whether the file ends with a newline.
+type configLines struct {
+	lines           []*configLine
+	trailingNewline bool
+}
+
+func splitConfigLines(str string) ([]string, bool) {
+	if str == "" {
+		return nil, false
+	}
+	trailingNewline := strings.HasSuffix(str, "\n")
+	return strings.Split(strings.TrimSuffix(str, "\n"), "\n"), trailingNewline
+}
+
+func (c *configLines) marshal(render func(l *configLine) string) string {
+	buffer := &strings.Builder{}
+	for i, l := range c.lines {
+		if i > 0 {
+			buffer.WriteByte('\n')
+		}
+		if l.updated {
+			buffer.WriteString(render(l))
+		} else {
+			buffer.WriteString(l.raw)
+		}
+	}
+	if c.trailingNewline && len(c.lines) > 0 {
+		buffer.WriteByte('\n')
+	}
+	return buffer.String()
+}
+
+// newLinePrefix returns the prefix of a new parameter line, which follows the style of the sibling line if any.
+func newLinePrefix(sibling *configLine, key, defaultSep string) string {
+	if sibling == nil || sibling.bare {
+		return key + defaultSep
+	}
+	indent := sibling.raw[:skipSpaces(sibling.raw, 0)]
+	return indent + key + sibling.prefix[len(indent)+len(sibling.key):]
+}
+
+func (c *configLines) insert(index int, l *configLine) {
+	c.lines = append(c.lines, nil)
+	copy(c.lines[index+1:], c.lines[index:])
+	c.lines[index] = l
+}
+
+func (c *configLines) remove(match func(l *configLine) bool) {
+	lines := c.lines[:0]
+	for _, l := range c.lines {
+		if !match(l) {
+			lines = append(lines, l)
+		}
+	}
+	c.lines = lines
+}
+
+// skipSpaces returns the index of the first non-space character of the line from the start position.
+func skipSpaces(line string, start int) int {
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t' || line[start] == '\r') {
+		start++
+	}
+	return start
+}
+
+// scanQuoted scans the quoted string which starts at the position of the quote character,
+// and returns the unquoted string and the position after the closing quote.
+func scanQuoted(line string, start int, unescape func(line string, pos int) (string, int, bool)) (string, int, bool) {
+	quote := line[start]
+	buffer := &strings.Builder{}
+	for pos := start + 1; pos < len(line); {
+		if s, next, ok := unescape(line, pos); ok {
+			buffer.WriteString(s)
+			pos = next
+			continue
+		}
+		if line[pos] == quote {
+			return buffer.String(), pos + 1, true
+		}
+		buffer.WriteByte(line[pos])
+		pos++
+	}
+	return "", len(line), false
+}
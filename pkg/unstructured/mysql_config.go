@@ -0,0 +1,336 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+// mysqlConfig is the parser of the MySQL option file, e.g. my.cnf.
+// reference: https://dev.mysql.com/doc/refman/8.0/en/option-files.html
+//
+// The parameters are grouped by sections, e.g. the key of max_connections in the [mysqld] section is "mysqld.max_connections",
+// and the options which are not in any section are keyed by their names.
+// The !include and !includedir directives are kept as they are, but the included files are not followed, since they
+// are not part of the config template and can only be resolved in the pod.
+type mysqlConfig struct {
+	name string
+	configLines
+}
+
+var mysqlIncludeDirectives = []string{"!include", "!includedir"}
+
+func init() {
+	CfgObjectRegistry().RegisterConfigCreator(appsv1beta1.MyCnf, func(name string) ConfigObject {
+		return &mysqlConfig{name: name}
+	})
+}
+
+func splitMySQLKey(key string) (string, string) {
+	if index := strings.Index(key, DelimiterDot); index >= 0 {
+		return key[:index], key[index+1:]
+	}
+	return "", key
+}
+
+// isSameMySQLOption reports whether two option names are the same, the dash and the underscore are interchangeable in option names.
+func isSameMySQLOption(name1, name2 string) bool {
+	return strings.ReplaceAll(name1, "-", "_") == strings.ReplaceAll(name2, "-", "_")
+}
+
+func (m *mysqlConfig) matchOption(section, name string) func(l *configLine) bool {
+	return func(l *configLine) bool {
+		return l.isParameter() && strings.EqualFold(l.section, section) && isSameMySQLOption(l.key, name)
+	}
+}
+
+func (m *mysqlConfig) Update(key string, value any) error {
+	section, name := splitMySQLKey(key)
+	v := cast.ToString(value)
+	if l := m.getLine(section, name); l != nil {
+		if l.bare && v != "" {
+			l.bare = false
+			l.prefix += "="
+		}
+		l.value = v
+		l.updated = true
+		return nil
+	}
+
+	index, sibling := m.insertPosition(section)
+	if index < 0 {
+		m.lines = append(m.lines, &configLine{raw: fmt.Sprintf("[%s]", section), section: section, isSection: true})
+		index = len(m.lines)
+	}
+	m.insert(index, &configLine{
+		key:     name,
+		value:   v,
+		section: section,
+		prefix:  newLinePrefix(sibling, name, "="),
+		updated: true,
+	})
+	return nil
+}
+
+// insertPosition returns the position to insert a new option into the section, and the last option of the section.
+// The position is -1 if the section does not exist.
+func (m *mysqlConfig) insertPosition(section string) (int, *configLine) {
+	var (
+		index   = -1
+		sibling *configLine
+	)
+	if section == "" {
+		// the options which are not in any section are placed before the first section
+		index = len(m.lines)
+		for i, l := range m.lines {
+			if l.isSection {
+				index = i
+				break
+			}
+		}
+	}
+	for i, l := range m.lines {
+		if !strings.EqualFold(l.section, section) {
+			continue
+		}
+		if l.isSection {
+			index = i + 1
+		}
+		if l.isParameter() {
+			index, sibling = i+1, l
+		}
+	}
+	return index, sibling
+}
+
+func (m *mysqlConfig) RemoveKey(key string) error {
+	m.remove(m.matchOption(splitMySQLKey(key)))
+	return nil
+}
+
+func (m *mysqlConfig) getLine(section, name string) *configLine {
+	// the last one takes effect if an option is set more than once.
+	match := m.matchOption(section, name)
+	for i := len(m.lines) - 1; i >= 0; i-- {
+		if match(m.lines[i]) {
+			return m.lines[i]
+		}
+	}
+	return nil
+}
+
+func (m *mysqlConfig) Get(key string) interface{} {
+	if l := m.getLine(splitMySQLKey(key)); l != nil {
+		return l.value
+	}
+	return nil
+}
+
+func (m *mysqlConfig) GetString(key string) (string, error) {
+	if l := m.getLine(splitMySQLKey(key)); l != nil {
+		return l.value, nil
+	}
+	return "", nil
+}
+
+func (m *mysqlConfig) GetAllParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	for _, l := range m.lines {
+		switch {
+		case l.isSection:
+			if _, ok := params[l.section]; !ok {
+				params[l.section] = make(map[string]interface{})
+			}
+		case l.isParameter() && l.section == "":
+			params[l.key] = l.value
+		case l.isParameter():
+			params[l.section].(map[string]interface{})[l.key] = l.value
+		}
+	}
+	return params
+}
+
+func (m *mysqlConfig) SubConfig(key string) ConfigObject {
+	for _, l := range m.lines {
+		if l.isSection && strings.EqualFold(l.section, key) {
+			return &mysqlSectionConfig{mysqlConfig: m, section: l.section}
+		}
+	}
+	return nil
+}
+
+func (m *mysqlConfig) Marshal() (string, error) {
+	return m.marshal(func(l *configLine) string {
+		if l.bare {
+			return l.prefix + l.suffix
+		}
+		return l.prefix + quoteMySQLValue(l.value, l.quote) + l.suffix
+	}), nil
+}
+
+func (m *mysqlConfig) Unmarshal(str string) error {
+	lines, trailingNewline := splitConfigLines(str)
+	m.configLines = configLines{trailingNewline: trailingNewline}
+	section := ""
+	for i, raw := range lines {
+		l, err := parseMySQLLine(raw, section)
+		if err != nil {
+			return fmt.Errorf("syntax error in line %d of file[%s]: %s", i+1, m.name, err.Error())
+		}
+		section = l.section
+		m.lines = append(m.lines, l)
+	}
+	return nil
+}
+
+func parseMySQLLine(raw string, section string) (*configLine, error) {
+	l := &configLine{raw: raw, section: section}
+	start := skipSpaces(raw, 0)
+	switch {
+	case start == len(raw) || raw[start] == '#' || raw[start] == ';':
+		return l, nil
+	case raw[start] == '[':
+		end := strings.IndexByte(raw[start:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated section: %s", raw)
+		}
+		l.section, l.isSection = strings.TrimSpace(raw[start+1:start+end]), true
+		return l, nil
+	case raw[start] == '!':
+		fields := strings.Fields(raw[start:])
+		if len(fields) != 2 || !isMySQLIncludeDirective(fields[0]) {
+			return nil, fmt.Errorf("invalid directive: %s", raw)
+		}
+		l.directive, l.value = fields[0], fields[1]
+		return l, nil
+	}
+
+	end := start
+	for end < len(raw) && raw[end] != '=' && raw[end] != '#' && !isLineSpace(raw[end]) {
+		end++
+	}
+	l.key = raw[start:end]
+	pos := skipSpaces(raw, end)
+	if pos == len(raw) || raw[pos] == '#' {
+		l.bare, l.prefix, l.suffix = true, raw[:end], raw[end:]
+		return l, nil
+	}
+	if raw[pos] != '=' {
+		return nil, fmt.Errorf("invalid option: %s", raw)
+	}
+
+	pos = skipSpaces(raw, pos+1)
+	valueStart := pos
+	if pos < len(raw) && (raw[pos] == quotes || raw[pos] == singleQuotes) {
+		v, next, ok := scanQuoted(raw, pos, unescapeMySQLString)
+		if !ok {
+			return nil, fmt.Errorf("unterminated quoted string: %s", raw)
+		}
+		l.value, l.quote, pos = v, raw[pos], next
+	} else {
+		valueEnd := len(raw)
+		if index := strings.IndexByte(raw[pos:], '#'); index >= 0 {
+			valueEnd = pos + index
+		}
+		l.value = strings.TrimRight(raw[pos:valueEnd], trimChars)
+		pos += len(l.value)
+	}
+	l.prefix, l.suffix = raw[:valueStart], raw[pos:]
+	return l, nil
+}
+
+func isMySQLIncludeDirective(directive string) bool {
+	for _, d := range mysqlIncludeDirectives {
+		if directive == d {
+			return true
+		}
+	}
+	return false
+}
+
+func unescapeMySQLString(line string, pos int) (string, int, bool) {
+	if pos+1 >= len(line) || !isEscape(rune(line[pos])) {
+		return "", pos, false
+	}
+	switch c := line[pos+1]; c {
+	case 'b':
+		return "\b", pos + 2, true
+	case 'n':
+		return "\n", pos + 2, true
+	case 'r':
+		return "\r", pos + 2, true
+	case 't':
+		return "\t", pos + 2, true
+	case 's':
+		return " ", pos + 2, true
+	default:
+		return string(c), pos + 2, true
+	}
+}
+
+func quoteMySQLValue(v string, quote byte) string {
+	if quote == 0 && (strings.ContainsAny(v, "#\"'\\") || strings.TrimSpace(v) != v) {
+		quote = quotes
+	}
+	if quote == 0 {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	return string(quote) + strings.ReplaceAll(v, string(quote), `\`+string(quote)) + string(quote)
+}
+
+// mysqlSectionConfig is the view of a section of the MySQL option file.
+type mysqlSectionConfig struct {
+	*mysqlConfig
+	section string
+}
+
+func (s *mysqlSectionConfig) sectionKey(key string) string {
+	return strings.Join([]string{s.section, key}, DelimiterDot)
+}
+
+func (s *mysqlSectionConfig) Update(key string, value any) error {
+	return s.mysqlConfig.Update(s.sectionKey(key), value)
+}
+
+func (s *mysqlSectionConfig) RemoveKey(key string) error {
+	return s.mysqlConfig.RemoveKey(s.sectionKey(key))
+}
+
+func (s *mysqlSectionConfig) Get(key string) interface{} {
+	return s.mysqlConfig.Get(s.sectionKey(key))
+}
+
+func (s *mysqlSectionConfig) GetString(key string) (string, error) {
+	return s.mysqlConfig.GetString(s.sectionKey(key))
+}
+
+func (s *mysqlSectionConfig) GetAllParameters() map[string]interface{} {
+	return cast.ToStringMap(s.mysqlConfig.GetAllParameters()[s.section])
+}
+
+func (s *mysqlSectionConfig) SubConfig(key string) ConfigObject {
+	return nil
+}
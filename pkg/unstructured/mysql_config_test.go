@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+func TestMySQLConfigFormat(t *testing.T) {
+	const mysqlContext = `[client]
+port=3306
+socket = /var/run/mysqld/mysqld.sock
+
+[mysqld]
+# the max connections
+max_connections=100
+skip-name-resolve
+plugin-load-add=rpl_semi_sync_source.so
+plugin-load-add=rpl_semi_sync_replica.so
+init_connect = "SET NAMES utf8mb4"	# run for each client
+innodb_buffer_pool_size=128M
+
+!include /etc/mysql/extra.cnf
+!includedir /etc/mysql/conf.d/
+`
+	mysqlConfigObj, err := LoadConfig("mysql_test", mysqlContext, appsv1beta1.MyCnf)
+	assert.Nil(t, err)
+
+	assert.EqualValues(t, "3306", mysqlConfigObj.Get("client.port"))
+	assert.EqualValues(t, "100", mysqlConfigObj.Get("mysqld.max_connections"))
+	assert.EqualValues(t, "100", mysqlConfigObj.Get("mysqld.max-connections"))
+	assert.EqualValues(t, "", mysqlConfigObj.Get("mysqld.skip_name_resolve"))
+	assert.EqualValues(t, "rpl_semi_sync_replica.so", mysqlConfigObj.Get("mysqld.plugin-load-add"))
+	assert.EqualValues(t, "SET NAMES utf8mb4", mysqlConfigObj.Get("mysqld.init_connect"))
+	assert.Nil(t, mysqlConfigObj.Get("mysqld.port"))
+
+	params := mysqlConfigObj.GetAllParameters()
+	assert.Len(t, params, 2)
+	assert.EqualValues(t, map[string]interface{}{
+		"max_connections":         "100",
+		"skip-name-resolve":       "",
+		"plugin-load-add":         "rpl_semi_sync_replica.so",
+		"init_connect":            "SET NAMES utf8mb4",
+		"innodb_buffer_pool_size": "128M",
+	}, params["mysqld"])
+
+	// round-trip without any change
+	dumpContext, err := mysqlConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, mysqlContext, dumpContext)
+
+	section := mysqlConfigObj.SubConfig("mysqld")
+	assert.NotNil(t, section)
+	assert.Nil(t, mysqlConfigObj.SubConfig("mysqldump"))
+	assert.EqualValues(t, params["mysqld"], section.GetAllParameters())
+
+	assert.Nil(t, section.Update("max_connections", 1000))
+	assert.Nil(t, mysqlConfigObj.Update("mysqld.skip_name_resolve", "ON"))
+	assert.Nil(t, mysqlConfigObj.Update("mysqld.init_connect", "SET NAMES utf8"))
+	assert.Nil(t, mysqlConfigObj.Update("mysqld.long_query_time", "2"))
+	assert.Nil(t, mysqlConfigObj.Update("client.default-character-set", "utf8mb4"))
+	assert.Nil(t, mysqlConfigObj.Update("mysqldump.quick", ""))
+	assert.Nil(t, mysqlConfigObj.RemoveKey("mysqld.innodb-buffer-pool-size"))
+	dumpContext, err = mysqlConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, `[client]
+port=3306
+socket = /var/run/mysqld/mysqld.sock
+default-character-set = utf8mb4
+
+[mysqld]
+# the max connections
+max_connections=1000
+skip-name-resolve=ON
+plugin-load-add=rpl_semi_sync_source.so
+plugin-load-add=rpl_semi_sync_replica.so
+init_connect = "SET NAMES utf8"	# run for each client
+long_query_time=2
+
+!include /etc/mysql/extra.cnf
+!includedir /etc/mysql/conf.d/
+[mysqldump]
+quick=
+`, dumpContext)
+
+	newObj, err := LoadConfig("mysql_test", dumpContext, appsv1beta1.MyCnf)
+	assert.Nil(t, err)
+	assert.EqualValues(t, mysqlConfigObj.GetAllParameters(), newObj.GetAllParameters())
+}
+
+func TestMySQLConfigBadCase(t *testing.T) {
+	for _, content := range []string{
+		"[mysqld",
+		"[mysqld]\ninit_connect = \"SET NAMES utf8mb4",
+		"[mysqld]\nmax_connections 100",
+		"!source /etc/mysql/extra.cnf",
+	} {
+		_, err := LoadConfig("mysql_test", content, appsv1beta1.MyCnf)
+		assert.NotNil(t, err, content)
+	}
+}
@@ -0,0 +1,468 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+// nginxConfig is the parser of the block-structured configuration file of nginx.
+// reference: https://nginx.org/en/docs/beginners_guide.html#conf_structure
+//
+// The key of a directive is the path of the names of its enclosing blocks and itself, e.g. "http.server.listen".
+// If several sibling directives share the same name, their names are suffixed with the index, e.g. "http.server[1].listen".
+// The value of a directive is its arguments joined by spaces.
+type nginxConfig struct {
+	name string
+	text string
+	root *nginxDirective
+}
+
+type nginxDirective struct {
+	name     string
+	args     []string
+	parent   *nginxDirective
+	children []*nginxDirective
+	isBlock  bool
+
+	// the offsets in the original text
+	start      int
+	nameEnd    int
+	argsEnd    int
+	end        int
+	closeBrace int
+
+	updated bool
+	removed bool
+	added   bool
+}
+
+type nginxToken struct {
+	value string
+	start int
+	end   int
+	// the delimiter of the token: ';', '{' or '}', zero for words and eof for the end of the text.
+	delimiter rune
+}
+
+type nginxEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+const nginxIndent = "    "
+
+func init() {
+	CfgObjectRegistry().RegisterConfigCreator(appsv1beta1.Nginx, func(name string) ConfigObject {
+		return &nginxConfig{name: name}
+	})
+}
+
+func (n *nginxConfig) Update(key string, value any) error {
+	args, err := parseNginxArgs(cast.ToString(value))
+	if err != nil {
+		return err
+	}
+	if d := n.find(key); d != nil {
+		d.args = args
+		d.updated = true
+		return nil
+	}
+
+	path := strings.Split(key, DelimiterDot)
+	parent := n.root
+	if len(path) > 1 {
+		parent = n.find(strings.Join(path[:len(path)-1], DelimiterDot))
+	}
+	name := path[len(path)-1]
+	if parent == nil || !parent.isBlock {
+		return fmt.Errorf("the block of the directive[%s] does not exist", key)
+	}
+	if strings.ContainsAny(name, "[]") {
+		return fmt.Errorf("the directive[%s] does not exist", key)
+	}
+	parent.children = append(parent.children, &nginxDirective{
+		name:   name,
+		args:   args,
+		parent: parent,
+		added:  true,
+	})
+	return nil
+}
+
+func (n *nginxConfig) RemoveKey(key string) error {
+	if d := n.find(key); d != nil {
+		d.removed = true
+	}
+	return nil
+}
+
+func (n *nginxConfig) find(key string) *nginxDirective {
+	current := n.root
+	for _, segment := range strings.Split(key, DelimiterDot) {
+		var next *nginxDirective
+		for child, childKey := range nginxChildKeys(current) {
+			if childKey == segment {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// nginxChildKeys returns the keys of the children which are not removed.
+func nginxChildKeys(d *nginxDirective) map[*nginxDirective]string {
+	counts := make(map[string]int)
+	for _, child := range d.children {
+		if !child.removed {
+			counts[child.name]++
+		}
+	}
+	keys := make(map[*nginxDirective]string)
+	indexes := make(map[string]int)
+	for _, child := range d.children {
+		if child.removed {
+			continue
+		}
+		if counts[child.name] == 1 {
+			keys[child] = child.name
+		} else {
+			keys[child] = fmt.Sprintf("%s[%d]", child.name, indexes[child.name])
+			indexes[child.name]++
+		}
+	}
+	return keys
+}
+
+func (n *nginxConfig) Get(key string) interface{} {
+	if d := n.find(key); d != nil && d != n.root {
+		return encodeNginxArgs(d.args)
+	}
+	return nil
+}
+
+func (n *nginxConfig) GetString(key string) (string, error) {
+	if v := n.Get(key); v != nil {
+		return v.(string), nil
+	}
+	return "", nil
+}
+
+func (n *nginxConfig) GetAllParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	var walk func(d *nginxDirective, prefix string)
+	walk = func(d *nginxDirective, prefix string) {
+		for child, key := range nginxChildKeys(d) {
+			if prefix != "" {
+				key = prefix + DelimiterDot + key
+			}
+			if !child.isBlock || len(child.args) > 0 {
+				params[key] = encodeNginxArgs(child.args)
+			}
+			if child.isBlock {
+				walk(child, key)
+			}
+		}
+	}
+	walk(n.root, "")
+	return params
+}
+
+func (n *nginxConfig) SubConfig(key string) ConfigObject {
+	return nil
+}
+
+func (n *nginxConfig) Marshal() (string, error) {
+	var edits []nginxEdit
+	n.collectEdits(n.root, &edits)
+	if len(edits) == 0 {
+		return n.text, nil
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	buffer := &strings.Builder{}
+	pos := 0
+	for _, edit := range edits {
+		buffer.WriteString(n.text[pos:edit.start])
+		buffer.WriteString(edit.text)
+		pos = edit.end
+	}
+	buffer.WriteString(n.text[pos:])
+	return buffer.String(), nil
+}
+
+func (n *nginxConfig) collectEdits(block *nginxDirective, edits *[]nginxEdit) {
+	var added []*nginxDirective
+	for _, child := range block.children {
+		switch {
+		case child.added && !child.removed:
+			added = append(added, child)
+		case child.added:
+		case child.removed:
+			start, end := n.lineSpan(child.start, child.end)
+			*edits = append(*edits, nginxEdit{start: start, end: end})
+		default:
+			if child.updated {
+				*edits = append(*edits, nginxEdit{start: child.nameEnd, end: child.argsEnd, text: prefixNginxArgs(child.args)})
+			}
+			if child.isBlock {
+				n.collectEdits(child, edits)
+			}
+		}
+	}
+	if len(added) > 0 {
+		*edits = append(*edits, n.insertEdit(block, added))
+	}
+}
+
+// lineSpan expands the span to the whole line if there is nothing else in the line.
+func (n *nginxConfig) lineSpan(start, end int) (int, int) {
+	lineStart := strings.LastIndexByte(n.text[:start], '\n') + 1
+	lineEnd := len(n.text)
+	if index := strings.IndexByte(n.text[end:], '\n'); index >= 0 {
+		lineEnd = end + index + 1
+	}
+	if strings.TrimSpace(n.text[lineStart:start]) != "" || strings.TrimSpace(n.text[end:lineEnd]) != "" {
+		return start, end
+	}
+	return lineStart, lineEnd
+}
+
+func (n *nginxConfig) insertEdit(block *nginxDirective, added []*nginxDirective) nginxEdit {
+	indent := ""
+	if block != n.root {
+		indent = n.indentOf(block.start) + nginxIndent
+	}
+	for _, child := range block.children {
+		if !child.added && !child.removed && n.startsLine(child.start) {
+			indent = n.indentOf(child.start)
+		}
+	}
+
+	pos := block.closeBrace
+	lineStart := strings.LastIndexByte(n.text[:pos], '\n') + 1
+	if strings.TrimSpace(n.text[lineStart:pos]) == "" {
+		// insert the directives before the line of the closing brace, or at the end of the file
+		return nginxEdit{start: lineStart, end: lineStart, text: renderNginxDirectives(added, indent, "", "\n")}
+	}
+	if block == n.root {
+		return nginxEdit{start: pos, end: pos, text: renderNginxDirectives(added, indent, "\n", "")}
+	}
+	return nginxEdit{start: pos, end: pos, text: renderNginxDirectives(added, indent, "\n", "\n") + n.indentOf(block.start)}
+}
+
+// renderNginxDirectives renders the directives line by line, the first line is prefixed with the head, and the last line is followed by the tail.
+func renderNginxDirectives(directives []*nginxDirective, indent, head, tail string) string {
+	lines := make([]string, 0, len(directives))
+	for _, d := range directives {
+		lines = append(lines, indent+d.name+prefixNginxArgs(d.args)+";")
+	}
+	return head + strings.Join(lines, "\n") + tail
+}
+
+func (n *nginxConfig) startsLine(pos int) bool {
+	lineStart := strings.LastIndexByte(n.text[:pos], '\n') + 1
+	return strings.TrimSpace(n.text[lineStart:pos]) == ""
+}
+
+func (n *nginxConfig) indentOf(pos int) string {
+	lineStart := strings.LastIndexByte(n.text[:pos], '\n') + 1
+	return n.text[lineStart:skipSpaces(n.text, lineStart)]
+}
+
+func (n *nginxConfig) Unmarshal(str string) error {
+	n.text = str
+	n.root = &nginxDirective{isBlock: true, closeBrace: len(str), end: len(str)}
+
+	var (
+		current = n.root
+		stmt    *nginxDirective
+		pos     = 0
+	)
+	for {
+		token, next, err := nextNginxToken(str, pos)
+		if err != nil {
+			return n.syntaxError(pos, err.Error())
+		}
+		pos = next
+		switch token.delimiter {
+		case eof:
+			if stmt != nil {
+				return n.syntaxError(stmt.start, "directive is not terminated by \";\"")
+			}
+			if current != n.root {
+				return n.syntaxError(current.start, "block is not terminated by \"}\"")
+			}
+			return nil
+		case 0:
+			if stmt == nil {
+				stmt = &nginxDirective{name: token.value, parent: current, start: token.start, nameEnd: token.end, argsEnd: token.end}
+			} else {
+				stmt.args = append(stmt.args, token.value)
+				stmt.argsEnd = token.end
+			}
+		case ';':
+			if stmt == nil {
+				return n.syntaxError(token.start, "unexpected \";\"")
+			}
+			stmt.end = token.end
+			current.children = append(current.children, stmt)
+			stmt = nil
+		case '{':
+			if stmt == nil {
+				return n.syntaxError(token.start, "unexpected \"{\"")
+			}
+			stmt.isBlock = true
+			current.children = append(current.children, stmt)
+			current, stmt = stmt, nil
+		case '}':
+			if stmt != nil || current == n.root {
+				return n.syntaxError(token.start, "unexpected \"}\"")
+			}
+			current.closeBrace, current.end = token.start, token.end
+			current = current.parent
+		}
+	}
+}
+
+func (n *nginxConfig) syntaxError(pos int, message string) error {
+	return fmt.Errorf("syntax error in line %d of file[%s]: %s", strings.Count(n.text[:pos], "\n")+1, n.name, message)
+}
+
+func nextNginxToken(text string, pos int) (nginxToken, int, error) {
+	for pos < len(text) {
+		switch c := text[pos]; {
+		case isSplitCharacter(rune(c)):
+			pos++
+		case c == '#':
+			if index := strings.IndexByte(text[pos:], '\n'); index >= 0 {
+				pos += index + 1
+			} else {
+				pos = len(text)
+			}
+		case c == ';' || c == '{' || c == '}':
+			return nginxToken{start: pos, end: pos + 1, delimiter: rune(c)}, pos + 1, nil
+		case c == quotes || c == singleQuotes:
+			value, end, ok := scanQuoted(text, pos, unescapeNginxString)
+			if !ok {
+				return nginxToken{}, pos, fmt.Errorf("unterminated quoted string")
+			}
+			return nginxToken{value: value, start: pos, end: end}, end, nil
+		default:
+			end := scanNginxWord(text, pos)
+			return nginxToken{value: text[pos:end], start: pos, end: end}, end, nil
+		}
+	}
+	return nginxToken{start: pos, end: pos, delimiter: eof}, pos, nil
+}
+
+func scanNginxWord(text string, pos int) int {
+	for pos < len(text) {
+		c := text[pos]
+		switch {
+		case isEscape(rune(c)) && pos+1 < len(text):
+			pos += 2
+			continue
+		case c == '{' && pos > 0 && text[pos-1] == '$':
+			// variables like ${name}
+			if index := strings.IndexByte(text[pos:], '}'); index >= 0 {
+				pos += index + 1
+				continue
+			}
+		case isSplitCharacter(rune(c)) || c == ';' || c == '{' || c == '}':
+			return pos
+		}
+		pos++
+	}
+	return pos
+}
+
+func unescapeNginxString(line string, pos int) (string, int, bool) {
+	if pos+1 >= len(line) || !isEscape(rune(line[pos])) {
+		return "", pos, false
+	}
+	switch c := line[pos+1]; c {
+	case 'n':
+		return "\n", pos + 2, true
+	case 'r':
+		return "\r", pos + 2, true
+	case 't':
+		return "\t", pos + 2, true
+	case quotes, singleQuotes, escape:
+		return string(c), pos + 2, true
+	default:
+		return line[pos : pos+2], pos + 2, true
+	}
+}
+
+func parseNginxArgs(value string) ([]string, error) {
+	var args []string
+	for pos := 0; ; {
+		token, next, err := nextNginxToken(value, pos)
+		if err != nil {
+			return nil, err
+		}
+		if token.delimiter == eof {
+			return args, nil
+		}
+		if token.delimiter != 0 {
+			return nil, fmt.Errorf("invalid value of directive: %s", value)
+		}
+		args = append(args, token.value)
+		pos = next
+	}
+}
+
+func encodeNginxArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\r\n;#\"'") || strings.ContainsAny(arg, "{}") && !strings.Contains(arg, "${") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
+
+func encodeNginxArgs(args []string) string {
+	encoded := make([]string, 0, len(args))
+	for _, arg := range args {
+		encoded = append(encoded, encodeNginxArg(arg))
+	}
+	return strings.Join(encoded, " ")
+}
+
+func prefixNginxArgs(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return " " + encodeNginxArgs(args)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+func TestNginxConfigFormat(t *testing.T) {
+	const nginxContext = `user nginx;
+worker_processes auto; # the number of workers
+
+events {
+    worker_connections 1024;
+}
+
+http {
+    include /etc/nginx/mime.types;
+    log_format main '$remote_addr - [$time_local] "$request"';
+
+    server {
+        listen 80;
+        location / {
+            root /usr/share/nginx/html;
+        }
+    }
+
+    server {
+        listen 443 ssl;
+        # the name of the server
+        server_name example.com;
+    }
+}
+`
+	nginxConfigObj, err := LoadConfig("nginx_test", nginxContext, appsv1beta1.Nginx)
+	assert.Nil(t, err)
+
+	assert.EqualValues(t, "nginx", nginxConfigObj.Get("user"))
+	assert.EqualValues(t, "1024", nginxConfigObj.Get("events.worker_connections"))
+	assert.EqualValues(t, `main "$remote_addr - [$time_local] \"$request\""`, nginxConfigObj.Get("http.log_format"))
+	assert.EqualValues(t, "80", nginxConfigObj.Get("http.server[0].listen"))
+	assert.EqualValues(t, "443 ssl", nginxConfigObj.Get("http.server[1].listen"))
+	assert.EqualValues(t, "/", nginxConfigObj.Get("http.server[0].location"))
+	assert.EqualValues(t, "/usr/share/nginx/html", nginxConfigObj.Get("http.server[0].location.root"))
+	assert.Nil(t, nginxConfigObj.Get("http.server.listen"))
+	assert.Len(t, nginxConfigObj.GetAllParameters(), 10)
+
+	// round-trip without any change
+	dumpContext, err := nginxConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, nginxContext, dumpContext)
+
+	assert.Nil(t, nginxConfigObj.Update("worker_processes", 4))
+	assert.Nil(t, nginxConfigObj.Update("events.worker_connections", "4096"))
+	assert.Nil(t, nginxConfigObj.Update("events.multi_accept", "on"))
+	assert.Nil(t, nginxConfigObj.Update("http.server[1].server_name", "example.com www.example.com"))
+	assert.Nil(t, nginxConfigObj.Update("http.server[0].location.index", "index.html"))
+	assert.Nil(t, nginxConfigObj.Update("pid", "/var/run/nginx.pid"))
+	assert.Nil(t, nginxConfigObj.RemoveKey("http.include"))
+	assert.NotNil(t, nginxConfigObj.Update("stream.server.listen", "3306"))
+	dumpContext, err = nginxConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, `user nginx;
+worker_processes 4; # the number of workers
+
+events {
+    worker_connections 4096;
+    multi_accept on;
+}
+
+http {
+    log_format main '$remote_addr - [$time_local] "$request"';
+
+    server {
+        listen 80;
+        location / {
+            root /usr/share/nginx/html;
+            index index.html;
+        }
+    }
+
+    server {
+        listen 443 ssl;
+        # the name of the server
+        server_name example.com www.example.com;
+    }
+}
+pid /var/run/nginx.pid;
+`, dumpContext)
+
+	newObj, err := LoadConfig("nginx_test", dumpContext, appsv1beta1.Nginx)
+	assert.Nil(t, err)
+	assert.EqualValues(t, nginxConfigObj.GetAllParameters(), newObj.GetAllParameters())
+	assert.Nil(t, newObj.SubConfig("http"))
+}
+
+func TestNginxConfigBadCase(t *testing.T) {
+	for _, content := range []string{
+		"user nginx",
+		"events {\n    worker_connections 1024;\n",
+		"events {\n    worker_connections 1024;\n}\n}",
+		"log_format main 'unterminated;",
+		"; user nginx;",
+	} {
+		_, err := LoadConfig("nginx_test", content, appsv1beta1.Nginx)
+		assert.NotNil(t, err, content)
+	}
+
+	nginxConfigObj, err := LoadConfig("nginx_test", "events { worker_connections 1024; }", appsv1beta1.Nginx)
+	assert.Nil(t, err)
+	assert.Nil(t, nginxConfigObj.Update("events.use", "epoll"))
+	assert.Nil(t, nginxConfigObj.Update("user", "nginx"))
+	dumpContext, err := nginxConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, "events { worker_connections 1024; \n    use epoll;\n}\nuser nginx;", dumpContext)
+}
@@ -0,0 +1,260 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+// pgConfig is the parser of the PostgreSQL configuration file, e.g. postgresql.conf.
+// reference: https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-CONFIGURATION-FILE
+//
+// The include directives are kept as they are, but the included files are not followed, since they are not part of
+// the config template and can only be resolved in the pod.
+type pgConfig struct {
+	name string
+	configLines
+}
+
+var pgIncludeDirectives = []string{"include", "include_if_exists", "include_dir"}
+
+func init() {
+	CfgObjectRegistry().RegisterConfigCreator(appsv1beta1.PGConf, func(name string) ConfigObject {
+		return &pgConfig{name: name}
+	})
+}
+
+func (p *pgConfig) Update(key string, value any) error {
+	v := unquotePGValue(cast.ToString(value))
+	if l := p.getLine(key); l != nil {
+		l.value = v
+		l.updated = true
+		return nil
+	}
+	var sibling *configLine
+	for _, l := range p.lines {
+		if l.isParameter() {
+			sibling = l
+		}
+	}
+	p.lines = append(p.lines, &configLine{
+		key:     key,
+		value:   v,
+		prefix:  newLinePrefix(sibling, key, " = "),
+		updated: true,
+	})
+	return nil
+}
+
+func (p *pgConfig) RemoveKey(key string) error {
+	p.remove(func(l *configLine) bool {
+		return l.isParameter() && strings.EqualFold(l.key, key)
+	})
+	return nil
+}
+
+func (p *pgConfig) getLine(key string) *configLine {
+	// the names of parameters are case-insensitive, and the last one takes effect if a parameter is set more than once.
+	for i := len(p.lines) - 1; i >= 0; i-- {
+		if l := p.lines[i]; l.isParameter() && strings.EqualFold(l.key, key) {
+			return l
+		}
+	}
+	return nil
+}
+
+func (p *pgConfig) Get(key string) interface{} {
+	if l := p.getLine(key); l != nil {
+		return l.value
+	}
+	return nil
+}
+
+func (p *pgConfig) GetString(key string) (string, error) {
+	if l := p.getLine(key); l != nil {
+		return l.value, nil
+	}
+	return "", nil
+}
+
+func (p *pgConfig) GetAllParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	keys := make(map[string]string)
+	for _, l := range p.lines {
+		if !l.isParameter() {
+			continue
+		}
+		lowerKey := strings.ToLower(l.key)
+		if key, ok := keys[lowerKey]; ok {
+			delete(params, key)
+		}
+		keys[lowerKey] = l.key
+		params[l.key] = l.value
+	}
+	return params
+}
+
+func (p *pgConfig) SubConfig(key string) ConfigObject {
+	return nil
+}
+
+func (p *pgConfig) Marshal() (string, error) {
+	return p.marshal(func(l *configLine) string {
+		return l.prefix + quotePGValue(l.value, l.quote != 0) + l.suffix
+	}), nil
+}
+
+func (p *pgConfig) Unmarshal(str string) error {
+	lines, trailingNewline := splitConfigLines(str)
+	p.configLines = configLines{trailingNewline: trailingNewline}
+	for i, raw := range lines {
+		l, err := parsePGLine(raw)
+		if err != nil {
+			return fmt.Errorf("syntax error in line %d of file[%s]: %s", i+1, p.name, err.Error())
+		}
+		p.lines = append(p.lines, l)
+	}
+	return nil
+}
+
+func parsePGLine(raw string) (*configLine, error) {
+	l := &configLine{raw: raw}
+	start := skipSpaces(raw, 0)
+	if start == len(raw) || raw[start] == '#' {
+		return l, nil
+	}
+
+	end := start
+	for end < len(raw) && isPGNameChar(raw[end]) {
+		end++
+	}
+	if end == start {
+		return nil, fmt.Errorf("invalid parameter name: %s", raw)
+	}
+	pos := skipSpaces(raw, end)
+	if pos < len(raw) && raw[pos] == '=' {
+		pos = skipSpaces(raw, pos+1)
+	}
+
+	valueStart := pos
+	var value string
+	if pos < len(raw) && raw[pos] == singleQuotes {
+		v, next, ok := scanQuoted(raw, pos, unescapePGString)
+		if !ok {
+			return nil, fmt.Errorf("unterminated quoted string: %s", raw)
+		}
+		value, pos, l.quote = v, next, singleQuotes
+	} else {
+		for pos < len(raw) && !isLineSpace(raw[pos]) && raw[pos] != '#' {
+			pos++
+		}
+		value = raw[valueStart:pos]
+		if value == "" {
+			return nil, fmt.Errorf("missing value of parameter: %s", raw)
+		}
+	}
+	if rest := skipSpaces(raw, pos); rest < len(raw) && raw[rest] != '#' {
+		return nil, fmt.Errorf("unexpected characters after the value: %s", raw)
+	}
+
+	name := raw[start:end]
+	if isPGIncludeDirective(name) {
+		l.directive, l.value = name, value
+		return l, nil
+	}
+	l.key, l.value = name, value
+	l.prefix, l.suffix = raw[:valueStart], raw[pos:]
+	return l, nil
+}
+
+func isPGIncludeDirective(name string) bool {
+	for _, directive := range pgIncludeDirectives {
+		if strings.EqualFold(name, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPGNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_.$-", c) >= 0
+}
+
+func isLineSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
+
+func unescapePGString(line string, pos int) (string, int, bool) {
+	if pos+1 >= len(line) {
+		return "", pos, false
+	}
+	switch {
+	case line[pos] == singleQuotes && line[pos+1] == singleQuotes:
+		return "'", pos + 2, true
+	case isEscape(rune(line[pos])):
+		switch c := line[pos+1]; c {
+		case 'b':
+			return "\b", pos + 2, true
+		case 'f':
+			return "\f", pos + 2, true
+		case 'n':
+			return "\n", pos + 2, true
+		case 'r':
+			return "\r", pos + 2, true
+		case 't':
+			return "\t", pos + 2, true
+		default:
+			return string(c), pos + 2, true
+		}
+	}
+	return "", pos, false
+}
+
+// unquotePGValue accepts the value which is already quoted, e.g. '128MB'.
+func unquotePGValue(v string) string {
+	if len(v) < 2 || v[0] != singleQuotes || v[len(v)-1] != singleQuotes {
+		return v
+	}
+	if unquoted, end, ok := scanQuoted(v, 0, unescapePGString); ok && end == len(v) {
+		return unquoted
+	}
+	return v
+}
+
+func quotePGValue(v string, quote bool) string {
+	if !quote && v != "" {
+		for i := 0; i < len(v); i++ {
+			if !isPGNameChar(v[i]) && strings.IndexByte("+:/", v[i]) < 0 {
+				quote = true
+				break
+			}
+		}
+	}
+	if !quote {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package unstructured
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+)
+
+func TestPGConfigFormat(t *testing.T) {
+	const pgContext = `# -----------------------------
+# PostgreSQL configuration file
+# -----------------------------
+
+listen_addresses = '*'		# what IP address(es) to listen on;
+max_connections = 100			# (change requires restart)
+shared_buffers = '128MB'
+auto_explain.log_analyze = 'True'
+log_line_prefix = '%m [%p] ''%q''%u@%d '
+#work_mem = 4MB
+max_connections 200
+
+include_dir 'conf.d'
+include_if_exists 'exists.conf'
+`
+	pgConfigObj, err := LoadConfig("pg_test", pgContext, appsv1beta1.PGConf)
+	assert.Nil(t, err)
+
+	// the last one takes effect
+	assert.EqualValues(t, "200", pgConfigObj.Get("max_connections"))
+	assert.EqualValues(t, "200", pgConfigObj.Get("MAX_CONNECTIONS"))
+	assert.EqualValues(t, "*", pgConfigObj.Get("listen_addresses"))
+	assert.EqualValues(t, "True", pgConfigObj.Get("auto_explain.log_analyze"))
+	assert.EqualValues(t, "%m [%p] '%q'%u@%d ", pgConfigObj.Get("log_line_prefix"))
+	assert.Nil(t, pgConfigObj.Get("work_mem"))
+	assert.Nil(t, pgConfigObj.Get("include_dir"))
+	assert.Len(t, pgConfigObj.GetAllParameters(), 5)
+
+	// round-trip without any change
+	dumpContext, err := pgConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, pgContext, dumpContext)
+
+	assert.Nil(t, pgConfigObj.Update("shared_buffers", "256MB"))
+	assert.Nil(t, pgConfigObj.Update("listen_addresses", "localhost"))
+	assert.Nil(t, pgConfigObj.Update("max_connections", "1000"))
+	assert.Nil(t, pgConfigObj.Update("work_mem", "'8MB'"))
+	assert.Nil(t, pgConfigObj.Update("search_path", `"$user", public`))
+	assert.Nil(t, pgConfigObj.RemoveKey("auto_explain.log_analyze"))
+	dumpContext, err = pgConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, `# -----------------------------
+# PostgreSQL configuration file
+# -----------------------------
+
+listen_addresses = 'localhost'		# what IP address(es) to listen on;
+max_connections = 100			# (change requires restart)
+shared_buffers = '256MB'
+log_line_prefix = '%m [%p] ''%q''%u@%d '
+#work_mem = 4MB
+max_connections 1000
+
+include_dir 'conf.d'
+include_if_exists 'exists.conf'
+work_mem 8MB
+search_path '"$user", public'
+`, dumpContext)
+
+	newObj, err := LoadConfig("pg_test", dumpContext, appsv1beta1.PGConf)
+	assert.Nil(t, err)
+	assert.EqualValues(t, pgConfigObj.GetAllParameters(), newObj.GetAllParameters())
+	assert.Nil(t, newObj.SubConfig("test"))
+}
+
+func TestPGConfigBadCase(t *testing.T) {
+	for _, content := range []string{
+		"shared_buffers = '128MB",
+		"shared_buffers =",
+		"shared_buffers = 128MB 256MB",
+		"= 128MB",
+	} {
+		_, err := LoadConfig("pg_test", content, appsv1beta1.PGConf)
+		assert.NotNil(t, err, content)
+	}
+
+	pgConfigObj, err := LoadConfig("pg_test", "", appsv1beta1.PGConf)
+	assert.Nil(t, err)
+	v, err := pgConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, "", v)
+	assert.Nil(t, pgConfigObj.Update("max_connections", 100))
+	v, err = pgConfigObj.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, "max_connections = 100", v)
+}